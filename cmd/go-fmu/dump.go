@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"go-fmu/pkg/fmu"
+	"slices"
+	"strings"
+)
+
+// dump prints the model info and the variables with the causalities of an FMU of any FMI version
+func dump(filename string, causalities []string) error {
+
+	model, err := fmu.Open(filename)
+	if err != nil {
+		return err
+	}
+
+	defer model.Close()
+
+	fmiTypes := make([]string, 0)
+	for _, interfaceType := range model.InterfaceTypes() {
+		fmiTypes = append(fmiTypes, interfaceType.String())
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("Model Info\n\n")
+	sb.WriteString(fmt.Sprintf("  FMI Version        %s\n", model.FmiVersion()))
+	sb.WriteString(fmt.Sprintf("  FMI Type           %s\n", strings.Join(fmiTypes, ", ")))
+	sb.WriteString(fmt.Sprintf("  Model Name         %s\n", model.ModelName()))
+	sb.WriteString(fmt.Sprintf("  Description        %s\n", model.Description()))
+	sb.WriteString(fmt.Sprintf("  Platforms          %s\n", strings.Join(model.Platforms(), ", ")))
+	sb.WriteString(fmt.Sprintf("  Continuous States  %d\n", model.NumberOfContinuousStates()))
+	sb.WriteString(fmt.Sprintf("  Event Indicators   %d\n", model.NumberOfEventIndicators()))
+	sb.WriteString(fmt.Sprintf("  Variables          %d\n", len(model.Variables())))
+	sb.WriteString(fmt.Sprintf("  Generation Tool    %s\n", model.GenerationTool()))
+
+	if experiment := model.DefaultExperiment(); experiment != nil {
+		sb.WriteString("\nDefault Experiment\n\n")
+		if experiment.StartTime != nil {
+			sb.WriteString(fmt.Sprintf("  Start Time    %g\n", *experiment.StartTime))
+		}
+		if experiment.StopTime != nil {
+			sb.WriteString(fmt.Sprintf("  Stop Time     %g\n", *experiment.StopTime))
+		}
+		if experiment.Tolerance != nil {
+			sb.WriteString(fmt.Sprintf("  Tolerance     %g\n", *experiment.Tolerance))
+		}
+		if experiment.StepSize != nil {
+			sb.WriteString(fmt.Sprintf("  Step Size     %g\n", *experiment.StepSize))
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("Variables (%s)\n\n", strings.Join(causalities, ", ")))
+	sb.WriteString(fmt.Sprintf("  %-18s %-10s %-12s %-8s %s\n", "Name", "Causality", "Start Value", "Unit", "Description"))
+	for _, v := range model.Variables() {
+		if !slices.Contains(causalities, v.Causality) {
+			continue
+		}
+
+		name := v.Name
+		if len(name) > 18 {
+			name = "..." + name[18-3:]
+		}
+
+		startValue := ""
+		switch start := v.Start.(type) {
+		case nil:
+		case []byte:
+			startValue = hex.EncodeToString(start)
+		default:
+			startValue = fmt.Sprint(start)
+		}
+
+		units := v.DeclaredType
+		if v.Type == fmu.Real {
+			units = v.Unit
+		}
+
+		sb.WriteString(fmt.Sprintf("  %-18s %-10s %-12s %-8s %s\n", name, v.Causality, startValue, units, v.Description))
+	}
+
+	fmt.Print(sb.String())
+
+	return nil
+}
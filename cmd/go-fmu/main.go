@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmi2/results"
+	"go-fmu/pkg/fmi2/worker"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 func Run() error {
 	if len(os.Args) < 2 {
-		fmt.Println("expected 'dump' or 'simulate' subcommands")
+		fmt.Println("expected 'dump', 'simulate', 'compile' or 'worker' subcommands")
 		os.Exit(1)
 	}
 
@@ -18,6 +23,15 @@ func Run() error {
 
 	simulateCmd := flag.NewFlagSet("simulate", flag.ExitOnError)
 	simulateFilename := simulateCmd.String("filename", "", "filename")
+	simulateOutput := simulateCmd.String("output", "", "result file (.csv, .mat or .parquet)")
+	simulateRemotePlatform := simulateCmd.String("remote-platform", "", "platform of the binary to run in a worker process ('auto': if the current platform is not supported)")
+	simulateRemoteWorker := simulateCmd.String("remote-worker", "", "go-fmu executable built for the remote platform, e.g. with GOARCH=386 for linux32")
+	simulateCompile := simulateCmd.Bool("compile", false, "compile the sources if the FMU contains no binary for the current platform")
+
+	compileCmd := flag.NewFlagSet("compile", flag.ExitOnError)
+	compileFilename := compileCmd.String("filename", "", "filename")
+	compileOutput := compileCmd.String("output", "", "filename of the rebuilt FMU (default: <name>.compiled.fmu)")
+	compileInPlace := compileCmd.Bool("in-place", false, "overwrite the FMU with the rebuilt FMU")
 
 	switch os.Args[1] {
 	case "dump":
@@ -27,8 +41,7 @@ func Run() error {
 			os.Exit(1)
 		}
 
-		err := fmi2.Dump(*dumpFilename, []string{"input", "output", "independent"})
-		if err != nil {
+		if err := dump(*dumpFilename, []string{"input", "output", "independent"}); err != nil {
 			return err
 		}
 
@@ -39,13 +52,67 @@ func Run() error {
 			os.Exit(1)
 		}
 
-		fmi2.SimulateFmu(*simulateFilename, fmi2.SimulationOptions{
-			Initialize:   true,
-			DebugLogging: true,
-		})
+		if *simulateOutput != "" {
+			if _, err := results.FormatFromFilename(*simulateOutput); err != nil {
+				return err
+			}
+		}
+
+		if *simulateRemoteWorker != "" {
+			if *simulateRemotePlatform == "" || *simulateRemotePlatform == "auto" {
+				return errors.New("-remote-worker requires the platform of the worker as -remote-platform")
+			}
+			fmi2.RegisterRemotingBackend(worker.NewRemoting(*simulateRemotePlatform, worker.WithCommand(*simulateRemoteWorker, "worker")))
+		}
+
+		var (
+			result *fmi2.Result
+			err    error
+		)
+
+		if *simulateRemotePlatform != "" || *simulateCompile {
+			result, err = simulateFmi2(*simulateFilename, *simulateRemotePlatform, *simulateCompile)
+		} else {
+			result, err = simulate(*simulateFilename)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if *simulateOutput != "" {
+			if err := results.WriteFile(*simulateOutput, result); err != nil {
+				return err
+			}
+		}
+
+	case "compile":
+		compileCmd.Parse(os.Args[2:])
+		if *compileFilename == "" {
+			compileCmd.Usage()
+			os.Exit(1)
+		}
+
+		output := *compileOutput
+		switch {
+		case *compileInPlace && output != "":
+			return errors.New("-in-place and -output cannot be used together")
+		case *compileInPlace:
+			output = *compileFilename
+		case output == "":
+			output = strings.TrimSuffix(*compileFilename, filepath.Ext(*compileFilename)) + ".compiled.fmu"
+		}
+
+		if err := fmi2.Compile(*compileFilename, output); err != nil {
+			return err
+		}
+
+	case "worker":
+		// serves the client that started the process, see package worker
+		return worker.Main()
 
 	default:
-		fmt.Println("expected 'dump' or 'simulate' subcommands")
+		fmt.Println("expected 'dump', 'simulate', 'compile' or 'worker' subcommands")
 		os.Exit(1)
 	}
 
@@ -55,6 +122,7 @@ func Run() error {
 func main() {
 
 	if err := Run(); err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 }
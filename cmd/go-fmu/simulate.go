@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmu"
+	"slices"
+)
+
+// simulate simulates an FMU of any FMI version over the default experiment and records the outputs.
+// Model Exchange is used if the FMU supports it.
+func simulate(filename string) (*fmi2.Result, error) {
+
+	model, err := fmu.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer model.Close()
+
+	interfaceType := fmu.CoSimulation
+	if slices.Contains(model.InterfaceTypes(), fmu.ModelExchange) {
+		interfaceType = fmu.ModelExchange
+	}
+
+	startTime, stopTime, outputInterval := experiment(model)
+
+	instance, err := model.Instantiate(model.ModelName(), interfaceType, fmu.WithDebugLogging())
+	if err != nil {
+		return nil, err
+	}
+
+	defer instance.Free()
+
+	opts := []fmu.InitializeOption{fmu.WithStopTime(stopTime)}
+	if e := model.DefaultExperiment(); e != nil && e.Tolerance != nil {
+		opts = append(opts, fmu.WithTolerance(*e.Tolerance))
+	}
+
+	if err := instance.Initialize(startTime, opts...); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]fmu.Variable, 0)
+	for _, v := range model.Variables() {
+		if v.Causality == "output" && v.Type != fmu.Binary && v.Type != fmu.Clock {
+			outputs = append(outputs, v)
+		}
+	}
+
+	result := &fmi2.Result{}
+	for _, v := range outputs {
+		result.Columns = append(result.Columns, &fmi2.Column{
+			Name:     v.Name,
+			Type:     string(v.Type),
+			Variable: &fmi2.ScalarVariable{Name: v.Name, Description: v.Description},
+		})
+	}
+
+	if err := record(instance, result, startTime); err != nil {
+		return nil, err
+	}
+
+	for i := 1; ; i++ {
+
+		t := startTime + float64(i-1)*outputInterval
+		if t >= stopTime || fmi2.Float64IsClose(t, stopTime) {
+			break
+		}
+
+		tNext := min(startTime+float64(i)*outputInterval, stopTime)
+
+		if err := instance.Step(t, tNext-t); err != nil {
+			if errors.Is(err, fmu.ErrTerminated) {
+				break
+			}
+			return nil, err
+		}
+
+		if err := record(instance, result, tNext); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := instance.Terminate(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// experiment returns the start time, stop time and output interval of the default experiment. The
+// output interval is increased until there are at most 1000 intervals.
+func experiment(model fmu.Model) (float64, float64, float64) {
+
+	startTime := 0.0
+	e := model.DefaultExperiment()
+
+	if e != nil && e.StartTime != nil {
+		startTime = *e.StartTime
+	}
+
+	stopTime := startTime + 1
+	if e != nil && e.StopTime != nil {
+		stopTime = *e.StopTime
+	}
+
+	if e == nil || e.StepSize == nil || *e.StepSize <= 0 {
+		return startTime, stopTime, fmi2.AutoInterval(stopTime - startTime)
+	}
+
+	outputInterval := *e.StepSize
+	for (stopTime-startTime)/outputInterval > 1000 {
+		outputInterval *= 2
+	}
+
+	return startTime, stopTime, outputInterval
+}
+
+// record appends the values of the outputs at time t to the result
+func record(instance fmu.Instance, result *fmi2.Result, t float64) error {
+
+	names := make([]string, 0, len(result.Columns))
+	for _, c := range result.Columns {
+		names = append(names, c.Name)
+	}
+
+	values, err := instance.GetValues(names...)
+	if err != nil {
+		return err
+	}
+
+	result.Time = append(result.Time, t)
+
+	for _, c := range result.Columns {
+		switch value := values[c.Name].(type) {
+		case float64:
+			c.Real = append(c.Real, value)
+		case int:
+			c.Integer = append(c.Integer, value)
+		case bool:
+			c.Boolean = append(c.Boolean, value)
+		case string:
+			c.String = append(c.String, value)
+		default:
+			return fmt.Errorf("cannot record the value of %s", c.Name)
+		}
+	}
+
+	return nil
+}
+
+// simulateFmi2 simulates an FMI 2.0 FMU with fmi2.SimulateFmu, which implements the remoting and the
+// compilation of the sources
+func simulateFmi2(filename string, remotePlatform string, compile bool) (*fmi2.Result, error) {
+
+	model, err := fmu.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	version := model.FmiVersion()
+	model.Close()
+
+	if version != "2.0" {
+		return nil, fmt.Errorf("-remote-platform and -compile are only supported for FMI 2.0 FMUs, %s is an FMI %s FMU", filename, version)
+	}
+
+	return fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		Initialize:     true,
+		DebugLogging:   true,
+		RemotePlatform: remotePlatform,
+		CompileSources: compile,
+	})
+}
@@ -0,0 +1,227 @@
+/* Allocator allocates and touches the number of bytes given by the parameter "bytes" in every step and
+   keeps the memory until the instance is freed, to test the memory limit of the worker process. */
+
+#include <stdlib.h>
+#include <string.h>
+
+#include "fmi2Functions.h"
+
+#define VR_BYTES 0
+#define VR_ALLOCATED 1
+
+typedef struct Block {
+	struct Block *next;
+} Block;
+
+typedef struct {
+	fmi2String instanceName;
+	fmi2CallbackLogger logger;
+	fmi2ComponentEnvironment environment;
+	fmi2Boolean loggingOn;
+	fmi2Integer bytes;
+	fmi2Real allocated;
+	fmi2Real time;
+	Block *blocks;
+} Instance;
+
+static void freeBlocks(Instance *instance) {
+	while (instance->blocks) {
+		Block *next = instance->blocks->next;
+		free(instance->blocks);
+		instance->blocks = next;
+	}
+	instance->allocated = 0;
+}
+
+const char *fmi2GetTypesPlatform(void) { return fmi2TypesPlatform; }
+
+const char *fmi2GetVersion(void) { return fmi2Version; }
+
+fmi2Status fmi2SetDebugLogging(fmi2Component c, fmi2Boolean loggingOn, size_t nCategories, const fmi2String categories[]) {
+	((Instance *)c)->loggingOn = loggingOn;
+	return fmi2OK;
+}
+
+fmi2Component fmi2Instantiate(fmi2String instanceName, fmi2Type fmuType, fmi2String fmuGUID, fmi2String fmuResourceLocation,
+                              const fmi2CallbackFunctions *functions, fmi2Boolean visible, fmi2Boolean loggingOn) {
+
+	if (fmuType != fmi2CoSimulation || strcmp(fmuGUID, "{8c4e810f-3df3-4a00-8276-176fa3c9f000}") != 0) {
+		return NULL;
+	}
+
+	Instance *instance = calloc(1, sizeof(Instance));
+	instance->instanceName = strdup(instanceName);
+	instance->logger = functions->logger;
+	instance->environment = functions->componentEnvironment;
+	instance->loggingOn = loggingOn;
+
+	return instance;
+}
+
+void fmi2FreeInstance(fmi2Component c) {
+	Instance *instance = c;
+	freeBlocks(instance);
+	free((void *)instance->instanceName);
+	free(instance);
+}
+
+fmi2Status fmi2SetupExperiment(fmi2Component c, fmi2Boolean toleranceDefined, fmi2Real tolerance, fmi2Real startTime, fmi2Boolean stopTimeDefined, fmi2Real stopTime) {
+	((Instance *)c)->time = startTime;
+	return fmi2OK;
+}
+
+fmi2Status fmi2EnterInitializationMode(fmi2Component c) { return fmi2OK; }
+
+fmi2Status fmi2ExitInitializationMode(fmi2Component c) { return fmi2OK; }
+
+fmi2Status fmi2Terminate(fmi2Component c) { return fmi2OK; }
+
+fmi2Status fmi2Reset(fmi2Component c) {
+	Instance *instance = c;
+	freeBlocks(instance);
+	instance->bytes = 0;
+	instance->time = 0;
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetReal(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Real value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_ALLOCATED) return fmi2Error;
+		value[i] = ((Instance *)c)->allocated;
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetInteger(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Integer value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_BYTES) return fmi2Error;
+		value[i] = ((Instance *)c)->bytes;
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetBoolean(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Boolean value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2GetString(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2String value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2SetReal(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Real value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2SetInteger(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_BYTES || value[i] < 0) return fmi2Error;
+		((Instance *)c)->bytes = value[i];
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2SetBoolean(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Boolean value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2SetString(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2String value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2GetFMUstate(fmi2Component c, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2SetFMUstate(fmi2Component c, fmi2FMUstate FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2FreeFMUstate(fmi2Component c, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2SerializedFMUstateSize(fmi2Component c, fmi2FMUstate FMUstate, size_t *size) { return fmi2Error; }
+
+fmi2Status fmi2SerializeFMUstate(fmi2Component c, fmi2FMUstate FMUstate, fmi2Byte serializedState[], size_t size) { return fmi2Error; }
+
+fmi2Status fmi2DeSerializeFMUstate(fmi2Component c, const fmi2Byte serializedState[], size_t size, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2GetDirectionalDerivative(fmi2Component c, const fmi2ValueReference vUnknown_ref[], size_t nUnknown,
+                                        const fmi2ValueReference vKnown_ref[], size_t nKnown, const fmi2Real dvKnown[], fmi2Real dvUnknown[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2SetRealInputDerivatives(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer order[], const fmi2Real value[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2GetRealOutputDerivatives(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer order[], fmi2Real value[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2DoStep(fmi2Component c, fmi2Real currentCommunicationPoint, fmi2Real communicationStepSize, fmi2Boolean noSetFMUStatePriorToCurrentCommunicationPoint) {
+
+	Instance *instance = c;
+
+	if (instance->bytes > 0) {
+
+		Block *block = malloc(sizeof(Block) + instance->bytes);
+		if (!block) {
+			instance->logger(instance->environment, instance->instanceName, fmi2Error, "logAll", "failed to allocate %d bytes", instance->bytes);
+			return fmi2Error;
+		}
+
+		/* the pages are only resident when they are written */
+		memset(block + 1, 1, instance->bytes);
+
+		block->next = instance->blocks;
+		instance->blocks = block;
+		instance->allocated += instance->bytes;
+
+		if (instance->loggingOn) {
+			instance->logger(instance->environment, instance->instanceName, fmi2OK, "logAll", "allocated %d bytes", instance->bytes);
+		}
+	}
+
+	instance->time = currentCommunicationPoint + communicationStepSize;
+
+	return fmi2OK;
+}
+
+fmi2Status fmi2CancelStep(fmi2Component c) { return fmi2Error; }
+
+fmi2Status fmi2GetStatus(fmi2Component c, const fmi2StatusKind s, fmi2Status *value) { return fmi2Discard; }
+
+fmi2Status fmi2GetRealStatus(fmi2Component c, const fmi2StatusKind s, fmi2Real *value) {
+	if (s != fmi2LastSuccessfulTime) return fmi2Discard;
+	*value = ((Instance *)c)->time;
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetIntegerStatus(fmi2Component c, const fmi2StatusKind s, fmi2Integer *value) { return fmi2Discard; }
+
+fmi2Status fmi2GetBooleanStatus(fmi2Component c, const fmi2StatusKind s, fmi2Boolean *value) {
+	if (s != fmi2Terminated) return fmi2Discard;
+	*value = fmi2False;
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetStringStatus(fmi2Component c, const fmi2StatusKind s, fmi2String *value) { return fmi2Discard; }
+
+/* Model Exchange is not supported */
+
+fmi2Status fmi2EnterEventMode(fmi2Component c) { return fmi2Error; }
+
+fmi2Status fmi2NewDiscreteStates(fmi2Component c, fmi2EventInfo *eventInfo) { return fmi2Error; }
+
+fmi2Status fmi2EnterContinuousTimeMode(fmi2Component c) { return fmi2Error; }
+
+fmi2Status fmi2CompletedIntegratorStep(fmi2Component c, fmi2Boolean noSetFMUStatePriorToCurrentPoint, fmi2Boolean *enterEventMode, fmi2Boolean *terminateSimulation) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2SetTime(fmi2Component c, fmi2Real time) { return fmi2Error; }
+
+fmi2Status fmi2SetContinuousStates(fmi2Component c, const fmi2Real x[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetDerivatives(fmi2Component c, fmi2Real derivatives[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetEventIndicators(fmi2Component c, fmi2Real eventIndicators[], size_t ni) { return fmi2Error; }
+
+fmi2Status fmi2GetContinuousStates(fmi2Component c, fmi2Real x[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetNominalsOfContinuousStates(fmi2Component c, fmi2Real x_nominal[], size_t nx) { return fmi2Error; }
@@ -0,0 +1,355 @@
+/* Async computes every step in a background thread that takes the time given by the parameter "duration" and
+   returns fmi2Pending from fmi2DoStep if the stepFinished callback is available. A canceled step calls
+   stepFinished (with fmi2Error) when its thread ends to test the handling of late notifications, unless the
+   parameter "notifyCanceled" is false when the step is started. If loggingOn is true, the instance logs its
+   creation and the synchronous steps without componentEnvironment like some non-conforming FMUs. */
+
+#include <pthread.h>
+#include <stdlib.h>
+#include <string.h>
+#include <time.h>
+
+#include "fmi2Functions.h"
+
+#define VR_DURATION 0
+#define VR_STEPS 1
+#define VR_NOTIFY_CANCELED 2
+
+#define MAX_THREADS 64
+
+typedef struct {
+	pthread_mutex_t mutex;
+	fmi2String instanceName;
+	fmi2CallbackFunctions functions;
+	fmi2Boolean loggingOn;
+	fmi2Real duration;
+	fmi2Integer steps;
+	fmi2Boolean notifyCanceled;
+	fmi2Real time;
+	fmi2Boolean running;     /* a step is being computed */
+	fmi2Status stepStatus;   /* the status of the last step */
+	unsigned long step;      /* the number of the current step */
+	unsigned long canceled;  /* the number of the last canceled step */
+	pthread_t threads[MAX_THREADS];
+	size_t nThreads;
+} Instance;
+
+typedef struct {
+	Instance *instance;
+	unsigned long step;
+	fmi2Real duration;
+	fmi2Boolean notifyCanceled;
+	fmi2Real time; /* the time at the end of the step */
+} Step;
+
+static void sleepFor(fmi2Real seconds) {
+	struct timespec t;
+	t.tv_sec = (time_t)seconds;
+	t.tv_nsec = (long)((seconds - (fmi2Real)t.tv_sec) * 1e9);
+	nanosleep(&t, NULL);
+}
+
+static void *compute(void *arg) {
+
+	Step *step = arg;
+	Instance *instance = step->instance;
+
+	sleepFor(step->duration);
+
+	pthread_mutex_lock(&instance->mutex);
+
+	fmi2Status status = fmi2OK;
+	fmi2Boolean notify = fmi2True;
+
+	if (instance->canceled == step->step) {
+		status = fmi2Error;
+		notify = step->notifyCanceled;
+	} else {
+		instance->time = step->time;
+		instance->steps++;
+	}
+
+	if (instance->step == step->step) {
+		instance->running = fmi2False;
+		instance->stepStatus = status;
+	}
+
+	pthread_mutex_unlock(&instance->mutex);
+
+	if (notify) {
+		instance->functions.stepFinished(instance->functions.componentEnvironment, status);
+	}
+
+	free(step);
+
+	return NULL;
+}
+
+static void joinThreads(Instance *instance) {
+	for (size_t i = 0; i < instance->nThreads; i++) {
+		pthread_join(instance->threads[i], NULL);
+	}
+	instance->nThreads = 0;
+}
+
+const char *fmi2GetTypesPlatform(void) { return fmi2TypesPlatform; }
+
+const char *fmi2GetVersion(void) { return fmi2Version; }
+
+fmi2Status fmi2SetDebugLogging(fmi2Component c, fmi2Boolean loggingOn, size_t nCategories, const fmi2String categories[]) {
+	return fmi2OK;
+}
+
+fmi2Component fmi2Instantiate(fmi2String instanceName, fmi2Type fmuType, fmi2String fmuGUID, fmi2String fmuResourceLocation,
+                              const fmi2CallbackFunctions *functions, fmi2Boolean visible, fmi2Boolean loggingOn) {
+
+	if (fmuType != fmi2CoSimulation || strcmp(fmuGUID, "{5e3a2c9d-1b7f-4f0e-9d6a-3c8b2a1f7e40}") != 0) {
+		return NULL;
+	}
+
+	Instance *instance = calloc(1, sizeof(Instance));
+	pthread_mutex_init(&instance->mutex, NULL);
+	instance->instanceName = strdup(instanceName);
+	instance->functions = *functions;
+	instance->loggingOn = loggingOn;
+	instance->duration = 0.05;
+	instance->notifyCanceled = fmi2True;
+
+	if (loggingOn) {
+		functions->logger(NULL, instanceName, fmi2OK, "logAll", "instantiating %s", instanceName);
+	}
+
+	return instance;
+}
+
+void fmi2FreeInstance(fmi2Component c) {
+	Instance *instance = c;
+	joinThreads(instance);
+	pthread_mutex_destroy(&instance->mutex);
+	free((void *)instance->instanceName);
+	free(instance);
+}
+
+fmi2Status fmi2SetupExperiment(fmi2Component c, fmi2Boolean toleranceDefined, fmi2Real tolerance, fmi2Real startTime, fmi2Boolean stopTimeDefined, fmi2Real stopTime) {
+	Instance *instance = c;
+	pthread_mutex_lock(&instance->mutex);
+	instance->time = startTime;
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2EnterInitializationMode(fmi2Component c) { return fmi2OK; }
+
+fmi2Status fmi2ExitInitializationMode(fmi2Component c) { return fmi2OK; }
+
+fmi2Status fmi2Terminate(fmi2Component c) { return fmi2OK; }
+
+/* the threads of canceled steps keep running until they have called stepFinished */
+fmi2Status fmi2Reset(fmi2Component c) {
+	Instance *instance = c;
+	pthread_mutex_lock(&instance->mutex);
+	instance->duration = 0.05;
+	instance->notifyCanceled = fmi2True;
+	instance->steps = 0;
+	instance->time = 0;
+	instance->running = fmi2False;
+	instance->stepStatus = fmi2OK;
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetReal(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Real value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_DURATION) return fmi2Error;
+		value[i] = instance->duration;
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetInteger(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Integer value[]) {
+	Instance *instance = c;
+	pthread_mutex_lock(&instance->mutex);
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_STEPS) {
+			pthread_mutex_unlock(&instance->mutex);
+			return fmi2Error;
+		}
+		value[i] = instance->steps;
+	}
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetBoolean(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2Boolean value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_NOTIFY_CANCELED) return fmi2Error;
+		value[i] = instance->notifyCanceled;
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetString(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, fmi2String value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2SetReal(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Real value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_DURATION || value[i] < 0) return fmi2Error;
+		instance->duration = value[i];
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2SetInteger(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2SetBoolean(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Boolean value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_NOTIFY_CANCELED) return fmi2Error;
+		instance->notifyCanceled = value[i];
+	}
+	return fmi2OK;
+}
+
+fmi2Status fmi2SetString(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2String value[]) {
+	return nvr == 0 ? fmi2OK : fmi2Error;
+}
+
+fmi2Status fmi2GetFMUstate(fmi2Component c, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2SetFMUstate(fmi2Component c, fmi2FMUstate FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2FreeFMUstate(fmi2Component c, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2SerializedFMUstateSize(fmi2Component c, fmi2FMUstate FMUstate, size_t *size) { return fmi2Error; }
+
+fmi2Status fmi2SerializeFMUstate(fmi2Component c, fmi2FMUstate FMUstate, fmi2Byte serializedState[], size_t size) { return fmi2Error; }
+
+fmi2Status fmi2DeSerializeFMUstate(fmi2Component c, const fmi2Byte serializedState[], size_t size, fmi2FMUstate *FMUstate) { return fmi2Error; }
+
+fmi2Status fmi2GetDirectionalDerivative(fmi2Component c, const fmi2ValueReference vUnknown_ref[], size_t nUnknown,
+                                        const fmi2ValueReference vKnown_ref[], size_t nKnown, const fmi2Real dvKnown[], fmi2Real dvUnknown[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2SetRealInputDerivatives(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer order[], const fmi2Real value[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2GetRealOutputDerivatives(fmi2Component c, const fmi2ValueReference vr[], size_t nvr, const fmi2Integer order[], fmi2Real value[]) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2DoStep(fmi2Component c, fmi2Real currentCommunicationPoint, fmi2Real communicationStepSize, fmi2Boolean noSetFMUStatePriorToCurrentCommunicationPoint) {
+
+	Instance *instance = c;
+
+	if (!instance->functions.stepFinished) {
+		/* compute the step synchronously */
+		if (instance->loggingOn) {
+			instance->functions.logger(NULL, instance->instanceName, fmi2OK, "logAll", "step at t=%g", currentCommunicationPoint);
+		}
+		sleepFor(instance->duration);
+		instance->time = currentCommunicationPoint + communicationStepSize;
+		instance->steps++;
+		return fmi2OK;
+	}
+
+	if (instance->nThreads == MAX_THREADS) {
+		return fmi2Error;
+	}
+
+	Step *step = malloc(sizeof(Step));
+	step->instance = instance;
+	step->duration = instance->duration;
+	step->notifyCanceled = instance->notifyCanceled;
+	step->time = currentCommunicationPoint + communicationStepSize;
+
+	pthread_mutex_lock(&instance->mutex);
+	step->step = ++instance->step;
+	instance->running = fmi2True;
+	pthread_mutex_unlock(&instance->mutex);
+
+	if (pthread_create(&instance->threads[instance->nThreads], NULL, compute, step) != 0) {
+		free(step);
+		return fmi2Error;
+	}
+
+	instance->nThreads++;
+
+	return fmi2Pending;
+}
+
+fmi2Status fmi2CancelStep(fmi2Component c) {
+	Instance *instance = c;
+	pthread_mutex_lock(&instance->mutex);
+	instance->canceled = instance->step;
+	instance->running = fmi2False;
+	instance->stepStatus = fmi2Error;
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetStatus(fmi2Component c, const fmi2StatusKind s, fmi2Status *value) {
+	Instance *instance = c;
+	if (s != fmi2DoStepStatus) return fmi2Discard;
+	pthread_mutex_lock(&instance->mutex);
+	*value = instance->running ? fmi2Pending : instance->stepStatus;
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetRealStatus(fmi2Component c, const fmi2StatusKind s, fmi2Real *value) {
+	Instance *instance = c;
+	if (s != fmi2LastSuccessfulTime) return fmi2Discard;
+	pthread_mutex_lock(&instance->mutex);
+	*value = instance->time;
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetIntegerStatus(fmi2Component c, const fmi2StatusKind s, fmi2Integer *value) { return fmi2Discard; }
+
+fmi2Status fmi2GetBooleanStatus(fmi2Component c, const fmi2StatusKind s, fmi2Boolean *value) {
+	if (s != fmi2Terminated) return fmi2Discard;
+	*value = fmi2False;
+	return fmi2OK;
+}
+
+fmi2Status fmi2GetStringStatus(fmi2Component c, const fmi2StatusKind s, fmi2String *value) {
+	Instance *instance = c;
+	if (s != fmi2PendingStatus) return fmi2Discard;
+	pthread_mutex_lock(&instance->mutex);
+	*value = instance->running ? "computing the step" : "idle";
+	pthread_mutex_unlock(&instance->mutex);
+	return fmi2OK;
+}
+
+/* Model Exchange is not supported */
+
+fmi2Status fmi2EnterEventMode(fmi2Component c) { return fmi2Error; }
+
+fmi2Status fmi2NewDiscreteStates(fmi2Component c, fmi2EventInfo *eventInfo) { return fmi2Error; }
+
+fmi2Status fmi2EnterContinuousTimeMode(fmi2Component c) { return fmi2Error; }
+
+fmi2Status fmi2CompletedIntegratorStep(fmi2Component c, fmi2Boolean noSetFMUStatePriorToCurrentPoint, fmi2Boolean *enterEventMode, fmi2Boolean *terminateSimulation) {
+	return fmi2Error;
+}
+
+fmi2Status fmi2SetTime(fmi2Component c, fmi2Real time) { return fmi2Error; }
+
+fmi2Status fmi2SetContinuousStates(fmi2Component c, const fmi2Real x[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetDerivatives(fmi2Component c, fmi2Real derivatives[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetEventIndicators(fmi2Component c, fmi2Real eventIndicators[], size_t ni) { return fmi2Error; }
+
+fmi2Status fmi2GetContinuousStates(fmi2Component c, fmi2Real x[], size_t nx) { return fmi2Error; }
+
+fmi2Status fmi2GetNominalsOfContinuousStates(fmi2Component c, fmi2Real x_nominal[], size_t nx) { return fmi2Error; }
@@ -0,0 +1,221 @@
+/* BouncingBall is an FMI 1.0 Model Exchange FMU of a ball that is dropped from the height h and bounces
+   off the ground with the coefficient of restitution e until it comes to rest. */
+
+#include <string.h>
+
+#include "fmiModelFunctions.h"
+
+#define GUID "{5e6a9d3c-2f4b-4e8a-9c1d-7b0f3a6e2d41}"
+
+#define VR_H 0
+#define VR_V 1
+#define VR_DER_V 2
+#define VR_G 3
+#define VR_E 4
+#define VR_BOUNCES 0
+
+#define V_MIN 0.1
+
+typedef struct {
+	fmiString instanceName;
+	fmiCallbackFunctions functions;
+	fmiBoolean loggingOn;
+	fmiBoolean initialized;
+	fmiReal time;
+	fmiReal h;
+	fmiReal v;
+	fmiReal g;
+	fmiReal e;
+	fmiInteger bounces;
+	fmiBoolean resting;
+} Instance;
+
+static void logError(Instance *instance, const char *message) {
+	instance->functions.logger(instance, instance->instanceName, fmiError, "error", message);
+}
+
+const char *fmiGetModelTypesPlatform() { return fmiModelTypesPlatform; }
+
+const char *fmiGetVersion() { return fmiVersion; }
+
+fmiComponent fmiInstantiateModel(fmiString instanceName, fmiString GUID_, fmiCallbackFunctions functions, fmiBoolean loggingOn) {
+
+	if (strcmp(GUID_, GUID) != 0) {
+		functions.logger(NULL, instanceName, fmiError, "error", "wrong GUID %s, expected %s", GUID_, GUID);
+		return NULL;
+	}
+
+	Instance *instance = functions.allocateMemory(1, sizeof(Instance));
+	instance->instanceName = strdup(instanceName);
+	instance->functions = functions;
+	instance->loggingOn = loggingOn;
+	instance->h = 1;
+	instance->v = 0;
+	instance->g = -9.81;
+	instance->e = 0.7;
+
+	return instance;
+}
+
+void fmiFreeModelInstance(fmiComponent c) {
+	Instance *instance = c;
+	free((void *)instance->instanceName);
+	instance->functions.freeMemory(instance);
+}
+
+fmiStatus fmiSetDebugLogging(fmiComponent c, fmiBoolean loggingOn) {
+	((Instance *)c)->loggingOn = loggingOn;
+	return fmiOK;
+}
+
+fmiStatus fmiSetTime(fmiComponent c, fmiReal time) {
+	((Instance *)c)->time = time;
+	return fmiOK;
+}
+
+fmiStatus fmiSetContinuousStates(fmiComponent c, const fmiReal x[], size_t nx) {
+	Instance *instance = c;
+	if (nx != 2) return fmiError;
+	instance->h = x[0];
+	instance->v = x[1];
+	return fmiOK;
+}
+
+fmiStatus fmiCompletedIntegratorStep(fmiComponent c, fmiBoolean *callEventUpdate) {
+	*callEventUpdate = fmiFalse;
+	return fmiOK;
+}
+
+fmiStatus fmiSetReal(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiReal value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		switch (vr[i]) {
+		case VR_H:
+			// the start value of h is fixed
+			if (!instance->initialized) {
+				logError(instance, "the start value of h is fixed and cannot be set");
+				return fmiError;
+			}
+			instance->h = value[i];
+			break;
+		case VR_V: instance->v = value[i]; break;
+		case VR_G: instance->g = value[i]; break;
+		case VR_E: instance->e = value[i]; break;
+		default: return fmiError;
+		}
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiSetInteger(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiSetBoolean(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiBoolean value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiSetString(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiString value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiInitialize(fmiComponent c, fmiBoolean toleranceControlled, fmiReal relativeTolerance, fmiEventInfo *eventInfo) {
+	Instance *instance = c;
+	instance->initialized = fmiTrue;
+	memset(eventInfo, 0, sizeof(fmiEventInfo));
+	eventInfo->iterationConverged = fmiTrue;
+	return fmiOK;
+}
+
+fmiStatus fmiGetDerivatives(fmiComponent c, fmiReal derivatives[], size_t nx) {
+	Instance *instance = c;
+	if (nx != 2) return fmiError;
+	derivatives[0] = instance->resting ? 0 : instance->v;
+	derivatives[1] = instance->resting ? 0 : instance->g;
+	return fmiOK;
+}
+
+fmiStatus fmiGetEventIndicators(fmiComponent c, fmiReal eventIndicators[], size_t ni) {
+	Instance *instance = c;
+	if (ni != 1) return fmiError;
+	eventIndicators[0] = instance->resting ? 1 : instance->h;
+	return fmiOK;
+}
+
+fmiStatus fmiGetReal(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiReal value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		switch (vr[i]) {
+		case VR_H: value[i] = instance->h; break;
+		case VR_V: value[i] = instance->v; break;
+		case VR_DER_V: value[i] = instance->resting ? 0 : instance->g; break;
+		case VR_G: value[i] = instance->g; break;
+		case VR_E: value[i] = instance->e; break;
+		default: return fmiError;
+		}
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetInteger(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiInteger value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_BOUNCES) return fmiError;
+		value[i] = ((Instance *)c)->bounces;
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetBoolean(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiBoolean value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiGetString(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiString value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiEventUpdate(fmiComponent c, fmiBoolean intermediateResults, fmiEventInfo *eventInfo) {
+	Instance *instance = c;
+
+	memset(eventInfo, 0, sizeof(fmiEventInfo));
+	eventInfo->iterationConverged = fmiTrue;
+
+	if (!instance->resting && instance->h <= 0 && instance->v < 0) {
+		instance->h = 0;
+		instance->v = -instance->e * instance->v;
+		instance->bounces++;
+
+		// the ball comes to rest when it is too slow to bounce again
+		if (instance->v < V_MIN) {
+			instance->v = 0;
+			instance->resting = fmiTrue;
+		}
+
+		eventInfo->stateValuesChanged = fmiTrue;
+	}
+
+	return fmiOK;
+}
+
+fmiStatus fmiGetContinuousStates(fmiComponent c, fmiReal states[], size_t nx) {
+	Instance *instance = c;
+	if (nx != 2) return fmiError;
+	states[0] = instance->h;
+	states[1] = instance->v;
+	return fmiOK;
+}
+
+fmiStatus fmiGetNominalContinuousStates(fmiComponent c, fmiReal x_nominal[], size_t nx) {
+	for (size_t i = 0; i < nx; i++) {
+		x_nominal[i] = 1;
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetStateValueReferences(fmiComponent c, fmiValueReference vrx[], size_t nx) {
+	if (nx != 2) return fmiError;
+	vrx[0] = VR_H;
+	vrx[1] = VR_V;
+	return fmiOK;
+}
+
+fmiStatus fmiTerminate(fmiComponent c) { return fmiOK; }
@@ -0,0 +1,827 @@
+/* Feedthrough passes the inputs of all variable types to the outputs. It integrates Float64_input to the
+   continuous state x, counts the time events every 0.5 s in ticks and the activations of the input clock
+   inClock in counter. Every third activation of inClock ticks the output clock outClock.
+
+   A Co-Simulation instance calls the intermediate update callback at the time events and returns early
+   if the importer requests it. A Scheduled Execution instance calls the clock update callback when
+   outClock ticks. */
+
+#include <stdarg.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#include "fmi3Functions.h"
+
+#define INSTANTIATION_TOKEN "{37b954f1-7a0b-4c3a-9a5d-3f7e2f1c8d21}"
+
+#define VR_TIME 0
+#define VR_FLOAT32_INPUT 1
+#define VR_FLOAT32_OUTPUT 2
+#define VR_FLOAT64_INPUT 3
+#define VR_FLOAT64_OUTPUT 4
+#define VR_INT8_INPUT 5
+#define VR_INT8_OUTPUT 6
+#define VR_UINT8_INPUT 7
+#define VR_UINT8_OUTPUT 8
+#define VR_INT16_INPUT 9
+#define VR_INT16_OUTPUT 10
+#define VR_UINT16_INPUT 11
+#define VR_UINT16_OUTPUT 12
+#define VR_INT32_INPUT 13
+#define VR_INT32_OUTPUT 14
+#define VR_UINT32_INPUT 15
+#define VR_UINT32_OUTPUT 16
+#define VR_INT64_INPUT 17
+#define VR_INT64_OUTPUT 18
+#define VR_UINT64_INPUT 19
+#define VR_UINT64_OUTPUT 20
+#define VR_BOOLEAN_INPUT 21
+#define VR_BOOLEAN_OUTPUT 22
+#define VR_STRING_INPUT 23
+#define VR_STRING_OUTPUT 24
+#define VR_BINARY_INPUT 25
+#define VR_BINARY_OUTPUT 26
+#define VR_X 27
+#define VR_DER_X 28
+#define VR_TICKS 29
+#define VR_IN_CLOCK 30
+#define VR_COUNTER 31
+#define VR_OUT_CLOCK 32
+
+#define TICK_INTERVAL 0.5
+#define CLOCK_INTERVAL 0.1
+#define EPSILON 1e-9
+
+typedef enum { ModelExchange, CoSimulation, ScheduledExecution } InterfaceType;
+
+typedef struct {
+	InterfaceType type;
+	char *instanceName;
+	fmi3InstanceEnvironment environment;
+	fmi3LogMessageCallback logMessage;
+	fmi3IntermediateUpdateCallback intermediateUpdate;
+	fmi3ClockUpdateCallback clockUpdate;
+	fmi3LockPreemptionCallback lockPreemption;
+	fmi3UnlockPreemptionCallback unlockPreemption;
+	fmi3Boolean loggingOn;
+	fmi3Boolean earlyReturnAllowed;
+
+	fmi3Float64 time;
+	fmi3Float32 float32;
+	fmi3Float64 float64;
+	fmi3Int8 int8;
+	fmi3UInt8 uint8;
+	fmi3Int16 int16;
+	fmi3UInt16 uint16;
+	fmi3Int32 int32;
+	fmi3UInt32 uint32;
+	fmi3Int64 int64;
+	fmi3UInt64 uint64;
+	fmi3Boolean boolean;
+	char *string;
+	fmi3Byte *binary;
+	size_t binarySize;
+
+	fmi3Float64 x;
+	fmi3Int32 ticks;
+	fmi3Float64 nextTick;
+	fmi3Clock inClock;
+	fmi3Int32 counter;
+	fmi3Clock outClock;
+} Instance;
+
+static void logEvent(Instance *instance, const char *format, ...) {
+
+	if (!instance->loggingOn || !instance->logMessage) {
+		return;
+	}
+
+	char message[256];
+	va_list args;
+	va_start(args, format);
+	vsnprintf(message, sizeof(message), format, args);
+	va_end(args);
+
+	instance->logMessage(instance->environment, fmi3OK, "logEvents", message);
+}
+
+static fmi3Status invalidValueReference(Instance *instance, fmi3ValueReference vr) {
+	if (instance->logMessage) {
+		char message[64];
+		snprintf(message, sizeof(message), "invalid value reference %u", vr);
+		instance->logMessage(instance->environment, fmi3Error, "logStatusError", message);
+	}
+	return fmi3Error;
+}
+
+static fmi3Status notSupported(Instance *instance, const char *function) {
+	if (instance->logMessage) {
+		char message[128];
+		snprintf(message, sizeof(message), "%s is not supported", function);
+		instance->logMessage(instance->environment, fmi3Error, "logStatusError", message);
+	}
+	return fmi3Error;
+}
+
+static void setBinary(Instance *instance, const fmi3Byte *value, size_t size) {
+	free(instance->binary);
+	instance->binary = malloc(size > 0 ? size : 1);
+	memcpy(instance->binary, value, size);
+	instance->binarySize = size;
+}
+
+static void setString(Instance *instance, const char *value) {
+	free(instance->string);
+	instance->string = strdup(value);
+}
+
+static void reset(Instance *instance) {
+	instance->time = 0;
+	instance->float32 = 0;
+	instance->float64 = 0;
+	instance->int8 = 0;
+	instance->uint8 = 0;
+	instance->int16 = 0;
+	instance->uint16 = 0;
+	instance->int32 = 0;
+	instance->uint32 = 0;
+	instance->int64 = 0;
+	instance->uint64 = 0;
+	instance->boolean = fmi3False;
+	setString(instance, "Set me!");
+	setBinary(instance, (const fmi3Byte *)"foo", 3);
+	instance->x = 0;
+	instance->ticks = 0;
+	instance->nextTick = TICK_INTERVAL;
+	instance->inClock = fmi3ClockInactive;
+	instance->counter = 0;
+	instance->outClock = fmi3ClockInactive;
+}
+
+static Instance *instantiate(InterfaceType type, fmi3String instanceName, fmi3String instantiationToken, fmi3Boolean loggingOn,
+                             fmi3InstanceEnvironment environment, fmi3LogMessageCallback logMessage) {
+
+	if (strcmp(instantiationToken, INSTANTIATION_TOKEN) != 0) {
+		if (logMessage) {
+			logMessage(environment, fmi3Error, "logStatusError", "wrong instantiation token");
+		}
+		return NULL;
+	}
+
+	Instance *instance = calloc(1, sizeof(Instance));
+	instance->type = type;
+	instance->instanceName = strdup(instanceName);
+	instance->environment = environment;
+	instance->logMessage = logMessage;
+	instance->loggingOn = loggingOn;
+
+	reset(instance);
+
+	return instance;
+}
+
+/* activateInClock counts the activation of inClock and ticks outClock with every third activation */
+static void activateInClock(Instance *instance) {
+
+	instance->counter++;
+	instance->inClock = fmi3ClockInactive;
+
+	if (instance->counter % 3 == 0) {
+		instance->outClock = fmi3ClockActive;
+		logEvent(instance, "outClock ticks at t=%g", instance->time);
+	}
+}
+
+/* tick handles the time event at nextTick */
+static void tick(Instance *instance) {
+	instance->ticks++;
+	instance->nextTick += TICK_INTERVAL;
+	logEvent(instance, "tick %d at t=%g", instance->ticks, instance->time);
+}
+
+/* Inquire version numbers and set debug logging */
+
+const char *fmi3GetVersion(void) { return fmi3Version; }
+
+fmi3Status fmi3SetDebugLogging(fmi3Instance c, fmi3Boolean loggingOn, size_t nCategories, const fmi3String categories[]) {
+	((Instance *)c)->loggingOn = loggingOn;
+	return fmi3OK;
+}
+
+/* Creation and destruction of FMU instances */
+
+fmi3Instance fmi3InstantiateModelExchange(fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible,
+                                          fmi3Boolean loggingOn, fmi3InstanceEnvironment instanceEnvironment, fmi3LogMessageCallback logMessage) {
+	return instantiate(ModelExchange, instanceName, instantiationToken, loggingOn, instanceEnvironment, logMessage);
+}
+
+fmi3Instance fmi3InstantiateCoSimulation(fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible,
+                                         fmi3Boolean loggingOn, fmi3Boolean eventModeUsed, fmi3Boolean earlyReturnAllowed,
+                                         const fmi3ValueReference requiredIntermediateVariables[], size_t nRequiredIntermediateVariables,
+                                         fmi3InstanceEnvironment instanceEnvironment, fmi3LogMessageCallback logMessage,
+                                         fmi3IntermediateUpdateCallback intermediateUpdate) {
+
+	Instance *instance = instantiate(CoSimulation, instanceName, instantiationToken, loggingOn, instanceEnvironment, logMessage);
+
+	if (instance) {
+		instance->earlyReturnAllowed = earlyReturnAllowed;
+		instance->intermediateUpdate = intermediateUpdate;
+	}
+
+	return instance;
+}
+
+fmi3Instance fmi3InstantiateScheduledExecution(fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible,
+                                               fmi3Boolean loggingOn, fmi3InstanceEnvironment instanceEnvironment, fmi3LogMessageCallback logMessage,
+                                               fmi3ClockUpdateCallback clockUpdate, fmi3LockPreemptionCallback lockPreemption,
+                                               fmi3UnlockPreemptionCallback unlockPreemption) {
+
+	Instance *instance = instantiate(ScheduledExecution, instanceName, instantiationToken, loggingOn, instanceEnvironment, logMessage);
+
+	if (instance) {
+		instance->clockUpdate = clockUpdate;
+		instance->lockPreemption = lockPreemption;
+		instance->unlockPreemption = unlockPreemption;
+	}
+
+	return instance;
+}
+
+void fmi3FreeInstance(fmi3Instance c) {
+	Instance *instance = c;
+	if (!instance) {
+		return;
+	}
+	free(instance->instanceName);
+	free(instance->string);
+	free(instance->binary);
+	free(instance);
+}
+
+/* Enter and exit initialization mode, enter event mode, terminate and reset */
+
+fmi3Status fmi3EnterInitializationMode(fmi3Instance c, fmi3Boolean toleranceDefined, fmi3Float64 tolerance, fmi3Float64 startTime,
+                                       fmi3Boolean stopTimeDefined, fmi3Float64 stopTime) {
+	Instance *instance = c;
+	instance->time = startTime;
+	instance->nextTick = startTime + TICK_INTERVAL;
+	return fmi3OK;
+}
+
+fmi3Status fmi3ExitInitializationMode(fmi3Instance c) { return fmi3OK; }
+
+fmi3Status fmi3EnterEventMode(fmi3Instance c) { return fmi3OK; }
+
+fmi3Status fmi3Terminate(fmi3Instance c) { return fmi3OK; }
+
+fmi3Status fmi3Reset(fmi3Instance c) {
+	reset(c);
+	return fmi3OK;
+}
+
+/* Getting and setting variable values */
+
+#define GET_VALUES(T, VR_OUTPUT, VR_INPUT, FIELD)                                                                          \
+	Instance *instance = c;                                                                                            \
+	if (nValues != nValueReferences) {                                                                                 \
+		return fmi3Error;                                                                                          \
+	}                                                                                                                  \
+	for (size_t i = 0; i < nValueReferences; i++) {                                                                    \
+		switch (valueReferences[i]) {                                                                              \
+		case VR_INPUT:                                                                                             \
+		case VR_OUTPUT:                                                                                            \
+			values[i] = (T)instance->FIELD;                                                                    \
+			break;                                                                                             \
+		default:                                                                                                   \
+			return invalidValueReference(instance, valueReferences[i]);                                        \
+		}                                                                                                          \
+	}                                                                                                                  \
+	return fmi3OK;
+
+#define SET_VALUES(VR_INPUT, FIELD)                                                                                        \
+	Instance *instance = c;                                                                                            \
+	if (nValues != nValueReferences) {                                                                                 \
+		return fmi3Error;                                                                                          \
+	}                                                                                                                  \
+	for (size_t i = 0; i < nValueReferences; i++) {                                                                    \
+		if (valueReferences[i] != VR_INPUT) {                                                                      \
+			return invalidValueReference(instance, valueReferences[i]);                                        \
+		}                                                                                                          \
+		instance->FIELD = values[i];                                                                               \
+	}                                                                                                                  \
+	return fmi3OK;
+
+fmi3Status fmi3GetFloat32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float32 values[], size_t nValues) {
+	GET_VALUES(fmi3Float32, VR_FLOAT32_OUTPUT, VR_FLOAT32_INPUT, float32)
+}
+
+fmi3Status fmi3GetFloat64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 values[], size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		switch (valueReferences[i]) {
+		case VR_TIME:
+			values[i] = instance->time;
+			break;
+		case VR_FLOAT64_INPUT:
+		case VR_FLOAT64_OUTPUT:
+		case VR_DER_X:
+			values[i] = instance->float64;
+			break;
+		case VR_X:
+			values[i] = instance->x;
+			break;
+		default:
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetInt8(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int8 values[], size_t nValues) {
+	GET_VALUES(fmi3Int8, VR_INT8_OUTPUT, VR_INT8_INPUT, int8)
+}
+
+fmi3Status fmi3GetUInt8(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt8 values[], size_t nValues) {
+	GET_VALUES(fmi3UInt8, VR_UINT8_OUTPUT, VR_UINT8_INPUT, uint8)
+}
+
+fmi3Status fmi3GetInt16(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int16 values[], size_t nValues) {
+	GET_VALUES(fmi3Int16, VR_INT16_OUTPUT, VR_INT16_INPUT, int16)
+}
+
+fmi3Status fmi3GetUInt16(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt16 values[], size_t nValues) {
+	GET_VALUES(fmi3UInt16, VR_UINT16_OUTPUT, VR_UINT16_INPUT, uint16)
+}
+
+fmi3Status fmi3GetInt32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int32 values[], size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		switch (valueReferences[i]) {
+		case VR_INT32_INPUT:
+		case VR_INT32_OUTPUT:
+			values[i] = instance->int32;
+			break;
+		case VR_TICKS:
+			values[i] = instance->ticks;
+			break;
+		case VR_COUNTER:
+			values[i] = instance->counter;
+			break;
+		default:
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetUInt32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt32 values[], size_t nValues) {
+	GET_VALUES(fmi3UInt32, VR_UINT32_OUTPUT, VR_UINT32_INPUT, uint32)
+}
+
+fmi3Status fmi3GetInt64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int64 values[], size_t nValues) {
+	GET_VALUES(fmi3Int64, VR_INT64_OUTPUT, VR_INT64_INPUT, int64)
+}
+
+fmi3Status fmi3GetUInt64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 values[], size_t nValues) {
+	GET_VALUES(fmi3UInt64, VR_UINT64_OUTPUT, VR_UINT64_INPUT, uint64)
+}
+
+fmi3Status fmi3GetBoolean(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Boolean values[], size_t nValues) {
+	GET_VALUES(fmi3Boolean, VR_BOOLEAN_OUTPUT, VR_BOOLEAN_INPUT, boolean)
+}
+
+fmi3Status fmi3GetString(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3String values[], size_t nValues) {
+	GET_VALUES(fmi3String, VR_STRING_OUTPUT, VR_STRING_INPUT, string)
+}
+
+/* the values point to the buffer of the instance, which is valid until the binary is set again */
+fmi3Status fmi3GetBinary(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, size_t valueSizes[], fmi3Binary values[],
+                         size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_BINARY_INPUT && valueReferences[i] != VR_BINARY_OUTPUT) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		valueSizes[i] = instance->binarySize;
+		values[i] = instance->binary;
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetClock(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Clock values[]) {
+
+	Instance *instance = c;
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		switch (valueReferences[i]) {
+		case VR_IN_CLOCK:
+			values[i] = instance->inClock;
+			break;
+		case VR_OUT_CLOCK:
+			// the output clock is deactivated when it has been read
+			values[i] = instance->outClock;
+			instance->outClock = fmi3ClockInactive;
+			break;
+		default:
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3SetFloat32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float32 values[], size_t nValues) {
+	SET_VALUES(VR_FLOAT32_INPUT, float32)
+}
+
+fmi3Status fmi3SetFloat64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 values[], size_t nValues) {
+	SET_VALUES(VR_FLOAT64_INPUT, float64)
+}
+
+fmi3Status fmi3SetInt8(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int8 values[], size_t nValues) {
+	SET_VALUES(VR_INT8_INPUT, int8)
+}
+
+fmi3Status fmi3SetUInt8(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt8 values[], size_t nValues) {
+	SET_VALUES(VR_UINT8_INPUT, uint8)
+}
+
+fmi3Status fmi3SetInt16(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int16 values[], size_t nValues) {
+	SET_VALUES(VR_INT16_INPUT, int16)
+}
+
+fmi3Status fmi3SetUInt16(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt16 values[], size_t nValues) {
+	SET_VALUES(VR_UINT16_INPUT, uint16)
+}
+
+fmi3Status fmi3SetInt32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 values[], size_t nValues) {
+	SET_VALUES(VR_INT32_INPUT, int32)
+}
+
+fmi3Status fmi3SetUInt32(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt32 values[], size_t nValues) {
+	SET_VALUES(VR_UINT32_INPUT, uint32)
+}
+
+fmi3Status fmi3SetInt64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int64 values[], size_t nValues) {
+	SET_VALUES(VR_INT64_INPUT, int64)
+}
+
+fmi3Status fmi3SetUInt64(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 values[], size_t nValues) {
+	SET_VALUES(VR_UINT64_INPUT, uint64)
+}
+
+fmi3Status fmi3SetBoolean(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Boolean values[], size_t nValues) {
+	SET_VALUES(VR_BOOLEAN_INPUT, boolean)
+}
+
+fmi3Status fmi3SetString(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3String values[], size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_STRING_INPUT) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		setString(instance, values[i]);
+	}
+
+	return fmi3OK;
+}
+
+/* the values are copied, so the importer may free them after the call */
+fmi3Status fmi3SetBinary(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const size_t valueSizes[],
+                         const fmi3Binary values[], size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_BINARY_INPUT) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		setBinary(instance, values[i], valueSizes[i]);
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3SetClock(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Clock values[]) {
+
+	Instance *instance = c;
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_IN_CLOCK) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		instance->inClock = values[i];
+	}
+
+	return fmi3OK;
+}
+
+/* Getting Variable Dependency Information */
+
+fmi3Status fmi3GetNumberOfVariableDependencies(fmi3Instance c, fmi3ValueReference valueReference, size_t *nDependencies) {
+	return notSupported(c, "fmi3GetNumberOfVariableDependencies");
+}
+
+fmi3Status fmi3GetVariableDependencies(fmi3Instance c, fmi3ValueReference dependent, size_t elementIndicesOfDependent[], fmi3ValueReference independents[],
+                                       size_t elementIndicesOfIndependents[], fmi3DependencyKind dependencyKinds[], size_t nDependencies) {
+	return notSupported(c, "fmi3GetVariableDependencies");
+}
+
+/* Getting and setting the internal FMU state */
+
+fmi3Status fmi3GetFMUState(fmi3Instance c, fmi3FMUState *FMUState) { return notSupported(c, "fmi3GetFMUState"); }
+
+fmi3Status fmi3SetFMUState(fmi3Instance c, fmi3FMUState FMUState) { return notSupported(c, "fmi3SetFMUState"); }
+
+fmi3Status fmi3FreeFMUState(fmi3Instance c, fmi3FMUState *FMUState) { return notSupported(c, "fmi3FreeFMUState"); }
+
+fmi3Status fmi3SerializedFMUStateSize(fmi3Instance c, fmi3FMUState FMUState, size_t *size) { return notSupported(c, "fmi3SerializedFMUStateSize"); }
+
+fmi3Status fmi3SerializeFMUState(fmi3Instance c, fmi3FMUState FMUState, fmi3Byte serializedState[], size_t size) {
+	return notSupported(c, "fmi3SerializeFMUState");
+}
+
+fmi3Status fmi3DeserializeFMUState(fmi3Instance c, const fmi3Byte serializedState[], size_t size, fmi3FMUState *FMUState) {
+	return notSupported(c, "fmi3DeserializeFMUState");
+}
+
+/* Getting partial derivatives */
+
+fmi3Status fmi3GetDirectionalDerivative(fmi3Instance c, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[],
+                                        size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity) {
+	return notSupported(c, "fmi3GetDirectionalDerivative");
+}
+
+fmi3Status fmi3GetAdjointDerivative(fmi3Instance c, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[],
+                                    size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity) {
+	return notSupported(c, "fmi3GetAdjointDerivative");
+}
+
+/* Entering and exiting the Configuration or Reconfiguration Mode */
+
+fmi3Status fmi3EnterConfigurationMode(fmi3Instance c) { return notSupported(c, "fmi3EnterConfigurationMode"); }
+
+fmi3Status fmi3ExitConfigurationMode(fmi3Instance c) { return notSupported(c, "fmi3ExitConfigurationMode"); }
+
+/* Clock related functions */
+
+fmi3Status fmi3GetIntervalDecimal(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 intervals[],
+                                  fmi3IntervalQualifier qualifiers[]) {
+
+	Instance *instance = c;
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_IN_CLOCK) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		intervals[i] = CLOCK_INTERVAL;
+		qualifiers[i] = fmi3IntervalUnchanged;
+	}
+
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetIntervalFraction(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[],
+                                   fmi3UInt64 resolutions[], fmi3IntervalQualifier qualifiers[]) {
+	return notSupported(c, "fmi3GetIntervalFraction");
+}
+
+fmi3Status fmi3GetShiftDecimal(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 shifts[]) {
+	return notSupported(c, "fmi3GetShiftDecimal");
+}
+
+fmi3Status fmi3GetShiftFraction(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[],
+                                fmi3UInt64 resolutions[]) {
+	return notSupported(c, "fmi3GetShiftFraction");
+}
+
+fmi3Status fmi3SetIntervalDecimal(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 intervals[]) {
+	return notSupported(c, "fmi3SetIntervalDecimal");
+}
+
+fmi3Status fmi3SetIntervalFraction(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[],
+                                   const fmi3UInt64 resolutions[]) {
+	return notSupported(c, "fmi3SetIntervalFraction");
+}
+
+fmi3Status fmi3SetShiftDecimal(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 shifts[]) {
+	return notSupported(c, "fmi3SetShiftDecimal");
+}
+
+fmi3Status fmi3SetShiftFraction(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[],
+                                const fmi3UInt64 resolutions[]) {
+	return notSupported(c, "fmi3SetShiftFraction");
+}
+
+fmi3Status fmi3EvaluateDiscreteStates(fmi3Instance c) { return notSupported(c, "fmi3EvaluateDiscreteStates"); }
+
+/* handles the time event and the activation of inClock in Event Mode */
+fmi3Status fmi3UpdateDiscreteStates(fmi3Instance c, fmi3Boolean *discreteStatesNeedUpdate, fmi3Boolean *terminateSimulation,
+                                    fmi3Boolean *nominalsOfContinuousStatesChanged, fmi3Boolean *valuesOfContinuousStatesChanged,
+                                    fmi3Boolean *nextEventTimeDefined, fmi3Float64 *nextEventTime) {
+
+	Instance *instance = c;
+
+	if (instance->time >= instance->nextTick - EPSILON) {
+		tick(instance);
+	}
+
+	if (instance->inClock) {
+		activateInClock(instance);
+	}
+
+	*discreteStatesNeedUpdate = fmi3False;
+	*terminateSimulation = fmi3False;
+	*nominalsOfContinuousStatesChanged = fmi3False;
+	*valuesOfContinuousStatesChanged = fmi3False;
+	*nextEventTimeDefined = fmi3True;
+	*nextEventTime = instance->nextTick;
+
+	return fmi3OK;
+}
+
+/* Functions for Model Exchange */
+
+fmi3Status fmi3EnterContinuousTimeMode(fmi3Instance c) { return fmi3OK; }
+
+fmi3Status fmi3CompletedIntegratorStep(fmi3Instance c, fmi3Boolean noSetFMUStatePriorToCurrentPoint, fmi3Boolean *enterEventMode,
+                                       fmi3Boolean *terminateSimulation) {
+	*enterEventMode = fmi3False;
+	*terminateSimulation = fmi3False;
+	return fmi3OK;
+}
+
+fmi3Status fmi3SetTime(fmi3Instance c, fmi3Float64 time) {
+	((Instance *)c)->time = time;
+	return fmi3OK;
+}
+
+fmi3Status fmi3SetContinuousStates(fmi3Instance c, const fmi3Float64 continuousStates[], size_t nContinuousStates) {
+	if (nContinuousStates != 1) {
+		return fmi3Error;
+	}
+	((Instance *)c)->x = continuousStates[0];
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetContinuousStateDerivatives(fmi3Instance c, fmi3Float64 derivatives[], size_t nContinuousStates) {
+	if (nContinuousStates != 1) {
+		return fmi3Error;
+	}
+	derivatives[0] = ((Instance *)c)->float64;
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetEventIndicators(fmi3Instance c, fmi3Float64 eventIndicators[], size_t nEventIndicators) {
+	return nEventIndicators == 0 ? fmi3OK : fmi3Error;
+}
+
+fmi3Status fmi3GetContinuousStates(fmi3Instance c, fmi3Float64 continuousStates[], size_t nContinuousStates) {
+	if (nContinuousStates != 1) {
+		return fmi3Error;
+	}
+	continuousStates[0] = ((Instance *)c)->x;
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetNominalsOfContinuousStates(fmi3Instance c, fmi3Float64 nominals[], size_t nContinuousStates) {
+	if (nContinuousStates != 1) {
+		return fmi3Error;
+	}
+	nominals[0] = 1;
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetNumberOfEventIndicators(fmi3Instance c, size_t *nEventIndicators) {
+	*nEventIndicators = 0;
+	return fmi3OK;
+}
+
+fmi3Status fmi3GetNumberOfContinuousStates(fmi3Instance c, size_t *nContinuousStates) {
+	*nContinuousStates = 1;
+	return fmi3OK;
+}
+
+/* Functions for Co-Simulation */
+
+fmi3Status fmi3EnterStepMode(fmi3Instance c) { return fmi3OK; }
+
+fmi3Status fmi3GetOutputDerivatives(fmi3Instance c, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 orders[],
+                                    fmi3Float64 values[], size_t nValues) {
+
+	Instance *instance = c;
+
+	if (nValues != nValueReferences) {
+		return fmi3Error;
+	}
+
+	for (size_t i = 0; i < nValueReferences; i++) {
+		if (valueReferences[i] != VR_X || orders[i] != 1) {
+			return invalidValueReference(instance, valueReferences[i]);
+		}
+		values[i] = instance->float64;
+	}
+
+	return fmi3OK;
+}
+
+/* integrates x to the end of the step and handles the time events on the way. At every time event the
+   intermediate update callback is called and the step returns early if the importer requests it. */
+fmi3Status fmi3DoStep(fmi3Instance c, fmi3Float64 currentCommunicationPoint, fmi3Float64 communicationStepSize, fmi3Boolean noSetFMUStatePriorToCurrentPoint,
+                      fmi3Boolean *eventHandlingNeeded, fmi3Boolean *terminateSimulation, fmi3Boolean *earlyReturn, fmi3Float64 *lastSuccessfulTime) {
+
+	Instance *instance = c;
+
+	const fmi3Float64 end = currentCommunicationPoint + communicationStepSize;
+
+	*eventHandlingNeeded = fmi3False;
+	*terminateSimulation = fmi3False;
+	*earlyReturn = fmi3False;
+
+	while (instance->nextTick <= end + EPSILON) {
+
+		instance->x += instance->float64 * (instance->nextTick - instance->time);
+		instance->time = instance->nextTick;
+
+		tick(instance);
+
+		if (instance->intermediateUpdate) {
+
+			fmi3Boolean earlyReturnRequested = fmi3False;
+			fmi3Float64 earlyReturnTime = 0;
+
+			const fmi3Boolean canReturnEarly = instance->earlyReturnAllowed && instance->time < end - EPSILON;
+
+			instance->intermediateUpdate(instance->environment, instance->time, fmi3False, fmi3True, fmi3True, canReturnEarly,
+			                             &earlyReturnRequested, &earlyReturnTime);
+
+			if (canReturnEarly && earlyReturnRequested) {
+				*earlyReturn = fmi3True;
+				*lastSuccessfulTime = instance->time;
+				return fmi3OK;
+			}
+		}
+	}
+
+	instance->x += instance->float64 * (end - instance->time);
+	instance->time = end;
+
+	*lastSuccessfulTime = end;
+
+	return fmi3OK;
+}
+
+/* Functions for Scheduled Execution */
+
+fmi3Status fmi3ActivateModelPartition(fmi3Instance c, fmi3ValueReference clockReference, fmi3Float64 activationTime) {
+
+	Instance *instance = c;
+
+	if (clockReference != VR_IN_CLOCK) {
+		return invalidValueReference(instance, clockReference);
+	}
+
+	instance->lockPreemption();
+	instance->time = activationTime;
+	activateInClock(instance);
+	const fmi3Boolean ticked = instance->outClock;
+	instance->unlockPreemption();
+
+	if (ticked) {
+		instance->clockUpdate(instance->environment);
+	}
+
+	return fmi3OK;
+}
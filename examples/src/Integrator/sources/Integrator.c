@@ -0,0 +1,210 @@
+/* Integrator is an FMI 1.0 Co-Simulation FMU that integrates the input u multiplied by the gain k with the
+   explicit Euler method. The input is extrapolated with its derivative and the steps that end after
+   discardTime are discarded. */
+
+#include <string.h>
+
+#include "fmiFunctions.h"
+
+#define GUID "{9d2b7c41-6e3a-4f85-b0c8-1a4e5f7d3b92}"
+
+#define VR_U 0
+#define VR_Y 1
+#define VR_K 2
+#define VR_DISCARD_TIME 3
+#define VR_STEPS 0
+#define VR_ENABLED 0
+#define VR_LABEL 0
+
+typedef struct {
+	fmiString instanceName;
+	fmiCallbackFunctions functions;
+	fmiBoolean loggingOn;
+	fmiReal time;
+	fmiReal u;
+	fmiReal du;
+	fmiReal y;
+	fmiReal k;
+	fmiReal discardTime;
+	fmiInteger steps;
+	fmiBoolean enabled;
+	char *label;
+} Instance;
+
+const char *fmiGetTypesPlatform() { return fmiPlatform; }
+
+const char *fmiGetVersion() { return fmiVersion; }
+
+fmiStatus fmiSetDebugLogging(fmiComponent c, fmiBoolean loggingOn) {
+	((Instance *)c)->loggingOn = loggingOn;
+	return fmiOK;
+}
+
+fmiComponent fmiInstantiateSlave(fmiString instanceName, fmiString fmuGUID, fmiString fmuLocation, fmiString mimeType, fmiReal timeout,
+                                 fmiBoolean visible, fmiBoolean interactive, fmiCallbackFunctions functions, fmiBoolean loggingOn) {
+
+	if (strcmp(fmuGUID, GUID) != 0) {
+		functions.logger(NULL, instanceName, fmiError, "error", "wrong GUID %s, expected %s", fmuGUID, GUID);
+		return NULL;
+	}
+
+	Instance *instance = functions.allocateMemory(1, sizeof(Instance));
+	instance->instanceName = strdup(instanceName);
+	instance->functions = functions;
+	instance->loggingOn = loggingOn;
+	instance->u = 1;
+	instance->k = 1;
+	instance->discardTime = 1e10;
+	instance->enabled = fmiTrue;
+	instance->label = strdup("integrator");
+
+	return instance;
+}
+
+fmiStatus fmiInitializeSlave(fmiComponent c, fmiReal tStart, fmiBoolean StopTimeDefined, fmiReal tStop) {
+	((Instance *)c)->time = tStart;
+	return fmiOK;
+}
+
+fmiStatus fmiTerminateSlave(fmiComponent c) { return fmiOK; }
+
+fmiStatus fmiResetSlave(fmiComponent c) {
+	Instance *instance = c;
+	instance->time = 0;
+	instance->y = 0;
+	instance->du = 0;
+	instance->steps = 0;
+	return fmiOK;
+}
+
+void fmiFreeSlaveInstance(fmiComponent c) {
+	Instance *instance = c;
+	free((void *)instance->instanceName);
+	free(instance->label);
+	instance->functions.freeMemory(instance);
+}
+
+fmiStatus fmiGetReal(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiReal value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		switch (vr[i]) {
+		case VR_U: value[i] = instance->u; break;
+		case VR_Y: value[i] = instance->y; break;
+		case VR_K: value[i] = instance->k; break;
+		case VR_DISCARD_TIME: value[i] = instance->discardTime; break;
+		default: return fmiError;
+		}
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetInteger(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiInteger value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_STEPS) return fmiError;
+		value[i] = ((Instance *)c)->steps;
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetBoolean(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiBoolean value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_ENABLED) return fmiError;
+		value[i] = ((Instance *)c)->enabled;
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetString(fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiString value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_LABEL) return fmiError;
+		value[i] = ((Instance *)c)->label;
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiSetReal(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiReal value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		switch (vr[i]) {
+		case VR_U:
+			instance->u = value[i];
+			instance->du = 0;
+			break;
+		case VR_Y: instance->y = value[i]; break;
+		case VR_K: instance->k = value[i]; break;
+		case VR_DISCARD_TIME: instance->discardTime = value[i]; break;
+		default: return fmiError;
+		}
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiSetInteger(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger value[]) {
+	return nvr == 0 ? fmiOK : fmiError;
+}
+
+fmiStatus fmiSetBoolean(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiBoolean value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_ENABLED) return fmiError;
+		((Instance *)c)->enabled = value[i];
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiSetString(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiString value[]) {
+	Instance *instance = c;
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_LABEL) return fmiError;
+		free(instance->label);
+		instance->label = strdup(value[i]);
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiSetRealInputDerivatives(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], const fmiReal value[]) {
+	for (size_t i = 0; i < nvr; i++) {
+		if (vr[i] != VR_U || order[i] != 1) return fmiError;
+		((Instance *)c)->du = value[i];
+	}
+	return fmiOK;
+}
+
+fmiStatus fmiGetRealOutputDerivatives(fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], fmiReal value[]) {
+	return fmiError;
+}
+
+fmiStatus fmiCancelStep(fmiComponent c) { return fmiError; }
+
+fmiStatus fmiDoStep(fmiComponent c, fmiReal currentCommunicationPoint, fmiReal communicationStepSize, fmiBoolean newStep) {
+	Instance *instance = c;
+
+	if (currentCommunicationPoint + communicationStepSize > instance->discardTime + 1e-9) {
+		return fmiDiscard;
+	}
+
+	if (instance->enabled) {
+		// the integral of the extrapolated input over the step
+		fmiReal h = communicationStepSize;
+		instance->y += instance->k * (instance->u * h + 0.5 * instance->du * h * h);
+	}
+
+	instance->u += instance->du * communicationStepSize;
+	instance->time = currentCommunicationPoint + communicationStepSize;
+	instance->steps++;
+
+	return fmiOK;
+}
+
+fmiStatus fmiGetStatus(fmiComponent c, const fmiStatusKind s, fmiStatus *value) { return fmiDiscard; }
+
+fmiStatus fmiGetRealStatus(fmiComponent c, const fmiStatusKind s, fmiReal *value) {
+	if (s != fmiLastSuccessfulTime) return fmiDiscard;
+	*value = ((Instance *)c)->time;
+	return fmiOK;
+}
+
+fmiStatus fmiGetIntegerStatus(fmiComponent c, const fmiStatusKind s, fmiInteger *value) { return fmiDiscard; }
+
+fmiStatus fmiGetBooleanStatus(fmiComponent c, const fmiStatusKind s, fmiBoolean *value) { return fmiDiscard; }
+
+fmiStatus fmiGetStringStatus(fmiComponent c, const fmiStatusKind s, fmiString *value) { return fmiDiscard; }
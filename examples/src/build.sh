@@ -0,0 +1,45 @@
+#!/bin/sh
+#
+# Builds the test FMUs in this directory into ../<Name>.fmu. Every FMU contains its sources and the binary
+# for linux64 (x86_64-linux for FMI 3.0), which is compiled with cc and the FMI headers of the go-fmu packages.
+#
+# Usage: ./build.sh [name...]
+
+set -e
+
+cd "$(dirname "$0")"
+
+root=$(cd ../.. && pwd)
+names=${*:-$(ls -d */ | tr -d /)}
+
+for name in $names; do
+
+	version=$(sed -n 's/.*fmiVersion="\([0-9.]*\)".*/\1/p' "$name/modelDescription.xml")
+	identifiers=$(sed -n 's/.*modelIdentifier="\([^"]*\)".*/\1/p' "$name/modelDescription.xml" | sort -u)
+
+	case $version in
+	1.0) headers=$root/pkg/fmi1/headers platform=linux64 ;;
+	2.0) headers=$root/pkg/fmi2/headers platform=linux64 ;;
+	3.0) headers=$root/pkg/fmi3/headers platform=x86_64-linux ;;
+	*) echo "$name: unsupported FMI version $version" >&2; exit 1 ;;
+	esac
+
+	build=$(mktemp -d)
+	trap 'rm -rf "$build"' EXIT
+
+	cp -r "$name/." "$build"
+	mkdir -p "$build/binaries/$platform"
+
+	for identifier in $identifiers; do
+		${CC:-cc} -shared -fPIC -O2 -Wall -I "$build/sources" -I "$headers" \
+			-DMODEL_IDENTIFIER="$identifier" -o "$build/binaries/$platform/$identifier.so" "$build"/sources/*.c -lm -lpthread
+	done
+
+	rm -f "../$name.fmu"
+	(cd "$build" && zip -q -r -X "$root/examples/$name.fmu" modelDescription.xml binaries sources)
+
+	rm -rf "$build"
+	trap - EXIT
+
+	echo "built $name.fmu"
+done
@@ -0,0 +1,75 @@
+package fmi1
+
+/*
+#include "core.h"
+*/
+import "C"
+
+import (
+	"go-fmu/pkg/internal/fmi"
+	"log/slog"
+	"sync"
+)
+
+// the logger of FMI 1.0 receives the instance instead of an environment pointer, so the environments
+// are looked up by instance
+var environments sync.Map // C.fmiComponent as uintptr -> *instanceEnvironment
+
+// Logger receives the log messages of an FMU instance
+type Logger = fmi.Logger[Status]
+
+// NewSlogLogger returns a Logger that passes the messages to handler. The level of the records
+// is derived from the status, the instance name, status and category are added as attributes.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return fmi.NewSlogLogger[Status](handler)
+}
+
+type InstantiateOption func(*InstantiateOptions)
+
+type InstantiateOptions struct {
+	log fmi.LogOptions[Status]
+}
+
+// WithLogger sets the logger that receives the messages of the instance instead of stdout
+func WithLogger(logger Logger) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Logger = logger
+	}
+}
+
+// WithLogCategories passes only the messages of the given categories to the logger.
+// FMI 1.0 does not declare the categories in the model description.
+func WithLogCategories(categories ...string) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Categories = categories
+	}
+}
+
+// instanceEnvironment holds the callbacks of an instance
+type instanceEnvironment struct {
+	logger Logger // passes only the messages of the categories to log
+}
+
+func newInstanceEnvironment(options *InstantiateOptions) *instanceEnvironment {
+	return &instanceEnvironment{logger: options.log.NewLogger()}
+}
+
+//export goFmi1Logger
+func goFmi1Logger(component C.uintptr_t, caller C.uintptr_t, instanceName C.fmiString, status C.fmiStatus, category C.fmiString, message *C.cchar_t) {
+
+	var env *instanceEnvironment
+
+	if e, ok := environments.Load(uintptr(component)); ok {
+		env = e.(*instanceEnvironment)
+	} else {
+		// the instance logs before it is returned by the instantiate function
+		env, _ = fmi.InstantiatingEnvironment(uintptr(caller), C.GoString(instanceName)).(*instanceEnvironment)
+	}
+
+	if env == nil {
+		fmi.DefaultLogger(C.GoString(instanceName), Status(status), C.GoString(category), C.GoString(message))
+		return
+	}
+
+	env.logger(C.GoString(instanceName), Status(status), C.GoString(category), C.GoString(message))
+}
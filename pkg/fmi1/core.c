@@ -0,0 +1,203 @@
+#include "core.h"
+
+extern void goFmi1Logger(uintptr_t component, uintptr_t caller, fmiString instanceName, fmiStatus status, fmiString category, cchar_t *message);
+
+void Fmi1Logger(fmiComponent c, fmiString instanceName, fmiStatus status, fmiString category, fmiString message, ...) {
+
+	va_list ap, aq;
+	va_start(ap, message);
+
+	// format the message into a buffer of the required size, so long messages are not truncated
+	va_copy(aq, ap);
+	int length = vsnprintf(NULL, 0, message, aq);
+	va_end(aq);
+
+	char *buffer = length < 0 ? NULL : malloc(length + 1);
+	if (buffer) {
+		vsnprintf(buffer, length + 1, message, ap);
+	}
+
+	va_end(ap);
+
+	// the library of the caller identifies the FMU if the instance has not been returned yet
+	goFmi1Logger((uintptr_t)c, (uintptr_t)__builtin_return_address(0), instanceName, status, category, buffer ? buffer : message);
+	free(buffer);
+}
+
+// Fmi1OpenLibrary loads the shared library and returns the message of dlerror if loading fails.
+// dlerror has to be called on the same thread as dlopen, which cannot be guaranteed from Go.
+void *Fmi1OpenLibrary(const char *filename, const char **error) {
+	void *handle = dlopen(filename, RTLD_LAZY);
+	*error = handle ? NULL : dlerror();
+	return handle;
+}
+
+const char *Fmi1GetTypesPlatform(void *f) {
+	return ((const char *(*)(void))f)();
+}
+
+const char *Fmi1GetVersion(void *f) {
+	return ((const char *(*)(void))f)();
+}
+
+fmiStatus Fmi1SetDebugLogging(void *f, fmiComponent c, fmiBoolean loggingOn) {
+	return ((fmiStatus(*)(fmiComponent, fmiBoolean))f)(c, loggingOn);
+}
+
+fmiStatus Fmi1GetReal(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiReal value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, fmiReal[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1GetInteger(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiInteger value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, fmiInteger[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1GetBoolean(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiBoolean value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, fmiBoolean[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1GetString(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiString value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, fmiString[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1SetReal(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiReal value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiReal[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1SetInteger(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiInteger[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1SetBoolean(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiBoolean value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiBoolean[]))f)(c, vr, nvr, value);
+}
+
+fmiStatus Fmi1SetString(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiString value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiString[]))f)(c, vr, nvr, value);
+}
+
+fmiComponent Fmi1InstantiateModel(void *f, fmiString instanceName, fmiString GUID, fmiBoolean loggingOn) {
+
+	// the FMU copies the callback functions
+	fmiMECallbackFunctions functions = {
+		.logger         = Fmi1Logger,
+		.allocateMemory = calloc,
+		.freeMemory     = free,
+	};
+
+	return ((fmiComponent(*)(fmiString, fmiString, fmiMECallbackFunctions, fmiBoolean))f)(instanceName, GUID, functions, loggingOn);
+}
+
+void Fmi1FreeModelInstance(void *f, fmiComponent c) {
+	((void (*)(fmiComponent))f)(c);
+}
+
+fmiStatus Fmi1SetTime(void *f, fmiComponent c, fmiReal time) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal))f)(c, time);
+}
+
+fmiStatus Fmi1SetContinuousStates(void *f, fmiComponent c, const fmiReal x[], size_t nx) {
+	return ((fmiStatus(*)(fmiComponent, const fmiReal[], size_t))f)(c, x, nx);
+}
+
+fmiStatus Fmi1CompletedIntegratorStep(void *f, fmiComponent c, fmiBoolean *callEventUpdate) {
+	return ((fmiStatus(*)(fmiComponent, fmiBoolean *))f)(c, callEventUpdate);
+}
+
+fmiStatus Fmi1Initialize(void *f, fmiComponent c, fmiBoolean toleranceControlled, fmiReal relativeTolerance, fmiEventInfo *eventInfo) {
+	return ((fmiStatus(*)(fmiComponent, fmiBoolean, fmiReal, fmiEventInfo *))f)(c, toleranceControlled, relativeTolerance, eventInfo);
+}
+
+fmiStatus Fmi1GetDerivatives(void *f, fmiComponent c, fmiReal derivatives[], size_t nx) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal[], size_t))f)(c, derivatives, nx);
+}
+
+fmiStatus Fmi1GetEventIndicators(void *f, fmiComponent c, fmiReal eventIndicators[], size_t ni) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal[], size_t))f)(c, eventIndicators, ni);
+}
+
+fmiStatus Fmi1EventUpdate(void *f, fmiComponent c, fmiBoolean intermediateResults, fmiEventInfo *eventInfo) {
+	return ((fmiStatus(*)(fmiComponent, fmiBoolean, fmiEventInfo *))f)(c, intermediateResults, eventInfo);
+}
+
+fmiStatus Fmi1GetContinuousStates(void *f, fmiComponent c, fmiReal states[], size_t nx) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal[], size_t))f)(c, states, nx);
+}
+
+fmiStatus Fmi1GetNominalContinuousStates(void *f, fmiComponent c, fmiReal x_nominal[], size_t nx) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal[], size_t))f)(c, x_nominal, nx);
+}
+
+fmiStatus Fmi1GetStateValueReferences(void *f, fmiComponent c, fmiValueReference vrx[], size_t nx) {
+	return ((fmiStatus(*)(fmiComponent, fmiValueReference[], size_t))f)(c, vrx, nx);
+}
+
+fmiStatus Fmi1Terminate(void *f, fmiComponent c) {
+	return ((fmiStatus(*)(fmiComponent))f)(c);
+}
+
+fmiComponent Fmi1InstantiateSlave(void *f, fmiString instanceName, fmiString fmuGUID, fmiString fmuLocation, fmiString mimeType, fmiReal timeout, fmiBoolean visible, fmiBoolean interactive, fmiBoolean loggingOn) {
+
+	// the FMU copies the callback functions, steps are always computed synchronously
+	fmiCSCallbackFunctions functions = {
+		.logger         = Fmi1Logger,
+		.allocateMemory = calloc,
+		.freeMemory     = free,
+		.stepFinished   = NULL,
+	};
+
+	return ((fmiComponent(*)(fmiString, fmiString, fmiString, fmiString, fmiReal, fmiBoolean, fmiBoolean, fmiCSCallbackFunctions, fmiBoolean))f)(
+		instanceName, fmuGUID, fmuLocation, mimeType, timeout, visible, interactive, functions, loggingOn);
+}
+
+fmiStatus Fmi1InitializeSlave(void *f, fmiComponent c, fmiReal tStart, fmiBoolean StopTimeDefined, fmiReal tStop) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal, fmiBoolean, fmiReal))f)(c, tStart, StopTimeDefined, tStop);
+}
+
+fmiStatus Fmi1TerminateSlave(void *f, fmiComponent c) {
+	return ((fmiStatus(*)(fmiComponent))f)(c);
+}
+
+fmiStatus Fmi1ResetSlave(void *f, fmiComponent c) {
+	return ((fmiStatus(*)(fmiComponent))f)(c);
+}
+
+void Fmi1FreeSlaveInstance(void *f, fmiComponent c) {
+	((void (*)(fmiComponent))f)(c);
+}
+
+fmiStatus Fmi1SetRealInputDerivatives(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], const fmiReal value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiInteger[], const fmiReal[]))f)(c, vr, nvr, order, value);
+}
+
+fmiStatus Fmi1GetRealOutputDerivatives(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], fmiReal value[]) {
+	return ((fmiStatus(*)(fmiComponent, const fmiValueReference[], size_t, const fmiInteger[], fmiReal[]))f)(c, vr, nvr, order, value);
+}
+
+fmiStatus Fmi1CancelStep(void *f, fmiComponent c) {
+	return ((fmiStatus(*)(fmiComponent))f)(c);
+}
+
+fmiStatus Fmi1DoStep(void *f, fmiComponent c, fmiReal currentCommunicationPoint, fmiReal communicationStepSize, fmiBoolean newStep) {
+	return ((fmiStatus(*)(fmiComponent, fmiReal, fmiReal, fmiBoolean))f)(c, currentCommunicationPoint, communicationStepSize, newStep);
+}
+
+fmiStatus Fmi1GetStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiStatus *value) {
+	return ((fmiStatus(*)(fmiComponent, const fmiStatusKind, fmiStatus *))f)(c, s, value);
+}
+
+fmiStatus Fmi1GetRealStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiReal *value) {
+	return ((fmiStatus(*)(fmiComponent, const fmiStatusKind, fmiReal *))f)(c, s, value);
+}
+
+fmiStatus Fmi1GetIntegerStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiInteger *value) {
+	return ((fmiStatus(*)(fmiComponent, const fmiStatusKind, fmiInteger *))f)(c, s, value);
+}
+
+fmiStatus Fmi1GetBooleanStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiBoolean *value) {
+	return ((fmiStatus(*)(fmiComponent, const fmiStatusKind, fmiBoolean *))f)(c, s, value);
+}
+
+fmiStatus Fmi1GetStringStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiString *value) {
+	return ((fmiStatus(*)(fmiComponent, const fmiStatusKind, fmiString *))f)(c, s, value);
+}
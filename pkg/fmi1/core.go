@@ -0,0 +1,940 @@
+package fmi1
+
+/*
+#cgo LDFLAGS: -ldl
+#include "core.h"
+*/
+import "C"
+
+import (
+	"archive/zip"
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+type Fmu1 struct {
+	Directory     string
+	ownsDirectory bool // the directory is removed by Close
+	moduleHandle  unsafe.Pointer
+	library       *fmi.Library // the loaded library, which attributes the messages of the instances that are being created
+
+	modelDescription *ModelDescription
+
+	// Common Functions
+	getVersionPtr      unsafe.Pointer
+	getTypesPlatform   unsafe.Pointer
+	setDebugLoggingPtr unsafe.Pointer
+	getRealPtr         unsafe.Pointer
+	getIntegerPtr      unsafe.Pointer
+	getBooleanPtr      unsafe.Pointer
+	getStringPtr       unsafe.Pointer
+	setRealPtr         unsafe.Pointer
+	setIntegerPtr      unsafe.Pointer
+	setBooleanPtr      unsafe.Pointer
+	setStringPtr       unsafe.Pointer
+
+	// Functions for Model Exchange
+	instantiateModelPtr           unsafe.Pointer
+	freeModelInstancePtr          unsafe.Pointer
+	setTimePtr                    unsafe.Pointer
+	setContinuousStatesPtr        unsafe.Pointer
+	completedIntegratorStepPtr    unsafe.Pointer
+	initializePtr                 unsafe.Pointer
+	getDerivativesPtr             unsafe.Pointer
+	getEventIndicatorsPtr         unsafe.Pointer
+	eventUpdatePtr                unsafe.Pointer
+	getContinuousStatesPtr        unsafe.Pointer
+	getNominalContinuousStatesPtr unsafe.Pointer
+	getStateValueReferencesPtr    unsafe.Pointer
+	terminatePtr                  unsafe.Pointer
+
+	// Functions for Co-Simulation
+	instantiateSlavePtr         unsafe.Pointer
+	initializeSlavePtr          unsafe.Pointer
+	terminateSlavePtr           unsafe.Pointer
+	resetSlavePtr               unsafe.Pointer
+	freeSlaveInstancePtr        unsafe.Pointer
+	setRealInputDerivativesPtr  unsafe.Pointer
+	getRealOutputDerivativesPtr unsafe.Pointer
+	cancelStepPtr               unsafe.Pointer
+	doStepPtr                   unsafe.Pointer
+	getStatusPtr                unsafe.Pointer
+	getRealStatusPtr            unsafe.Pointer
+	getIntegerStatusPtr         unsafe.Pointer
+	getBooleanStatusPtr         unsafe.Pointer
+	getStringStatusPtr          unsafe.Pointer
+
+	warningHandler WarningHandler
+}
+
+// ModelDescription returns the model description of the FMU
+func (f *Fmu1) ModelDescription() *ModelDescription {
+	return f.modelDescription
+}
+
+// Type returns the interface type of the FMU
+func (f *Fmu1) Type() Type {
+	return f.modelDescription.Type()
+}
+
+// Close unloads the shared library and removes the extracted files if they were extracted by New
+func (f *Fmu1) Close() error {
+	if f.library != nil {
+		f.library.Close()
+		f.library = nil
+	}
+
+	if f.moduleHandle != nil {
+		C.dlclose(f.moduleHandle)
+		f.moduleHandle = nil
+	}
+
+	if f.ownsDirectory {
+		f.ownsDirectory = false
+		return os.RemoveAll(f.Directory)
+	}
+
+	return nil
+}
+
+func toBoolean(b bool) C.fmiBoolean {
+	if b {
+		return C.fmiTrue
+	}
+	return C.fmiFalse
+}
+
+/* GetVersion returns the version of the header files which were used to compile the functions of the FMU, e.g. "1.0". */
+func (f *Fmu1) GetVersion() string {
+	return C.GoString(C.Fmi1GetVersion(f.getVersionPtr))
+}
+
+/* GetTypesPlatform returns the platform of the types that are used by the FMU, which is "standard32"
+ * (fmiGetModelTypesPlatform for Model Exchange and fmiGetTypesPlatform for Co-Simulation).
+ */
+func (f *Fmu1) GetTypesPlatform() string {
+	return C.GoString(C.Fmi1GetTypesPlatform(f.getTypesPlatform))
+}
+
+/* SetDebugLogging controls the debug logging that is output via the logger callback function by the FMU. */
+func (f *Fmu1) SetDebugLogging(c *Instance, loggingOn bool) error {
+	if err := f.available(c, f.setDebugLoggingPtr, "fmiSetDebugLogging"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiSetDebugLogging", C.Fmi1SetDebugLogging(f.setDebugLoggingPtr, c.component, toBoolean(loggingOn)))
+}
+
+// instantiate creates the environment of a new instance and calls the instantiate function
+func (f *Fmu1) instantiate(fmuType Type, instanceName string, opts []InstantiateOption, call func() C.fmiComponent) *Instance {
+
+	options := &InstantiateOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	env := newInstanceEnvironment(options)
+
+	var component C.fmiComponent
+
+	// the FMU may log before the instance is returned
+	f.library.Instantiate(instanceName, env, func() {
+		component = call()
+		if component != nil {
+			environments.Store(uintptr(component), env)
+		}
+	})
+
+	if component == nil {
+		return nil
+	}
+
+	return &Instance{
+		fmu:       f,
+		component: component,
+		fmuType:   fmuType,
+	}
+}
+
+/* InstantiateModel returns a new instance of a Model Exchange FMU. If nil is returned, then instantiation failed.
+ * In that case, the logger is called with detailed information about the reason.
+ * The messages of the instance are printed to stdout unless a logger is passed with WithLogger.
+ */
+func (f *Fmu1) InstantiateModel(instanceName string, guid string, loggingOn bool, opts ...InstantiateOption) *Instance {
+
+	if f.instantiateModelPtr == nil {
+		return nil
+	}
+
+	cInstanceName := C.CString(instanceName)
+	defer C.free(unsafe.Pointer(cInstanceName))
+	cGuid := C.CString(guid)
+	defer C.free(unsafe.Pointer(cGuid))
+
+	return f.instantiate(ModelExchangeType, instanceName, opts, func() C.fmiComponent {
+		return C.Fmi1InstantiateModel(f.instantiateModelPtr, cInstanceName, cGuid, toBoolean(loggingOn))
+	})
+}
+
+/* InstantiateSlave returns a new instance of a Co-Simulation slave. If nil is returned, then instantiation failed.
+ * fmuLocation is the URI of the extracted FMU, e.g. "file:///tmp/MyFMU". timeout is the time in milliseconds
+ * the slave waits for an external tool to start (0: wait indefinitely). The slave is instantiated with the
+ * MIME type "application/x-fmu-sharedlibrary".
+ */
+func (f *Fmu1) InstantiateSlave(instanceName string, guid string, fmuLocation string, timeout float64, visible bool, interactive bool, loggingOn bool, opts ...InstantiateOption) *Instance {
+
+	if f.instantiateSlavePtr == nil {
+		return nil
+	}
+
+	cInstanceName := C.CString(instanceName)
+	defer C.free(unsafe.Pointer(cInstanceName))
+	cGuid := C.CString(guid)
+	defer C.free(unsafe.Pointer(cGuid))
+	cLocation := C.CString(fmuLocation)
+	defer C.free(unsafe.Pointer(cLocation))
+	cMimeType := C.CString("application/x-fmu-sharedlibrary")
+	defer C.free(unsafe.Pointer(cMimeType))
+
+	return f.instantiate(CoSimulationType, instanceName, opts, func() C.fmiComponent {
+		return C.Fmi1InstantiateSlave(f.instantiateSlavePtr, cInstanceName, cGuid, cLocation, cMimeType, C.fmiReal(timeout),
+			toBoolean(visible), toBoolean(interactive), toBoolean(loggingOn))
+	})
+}
+
+// release removes the environment of the instance
+func (c *Instance) release() {
+	environments.Delete(uintptr(c.component))
+	c.component = nil
+}
+
+/* FreeModelInstance disposes the given instance of a Model Exchange FMU and frees all the allocated memory and other resources.
+ * If the passed instance is nil, the function call is ignored (does not have an effect).
+ */
+func (f *Fmu1) FreeModelInstance(c *Instance) {
+	if c == nil || c.component == nil {
+		return
+	}
+
+	// after Fatal no function of the instance may be called
+	if !c.fatal && f.freeModelInstancePtr != nil {
+		C.Fmi1FreeModelInstance(f.freeModelInstancePtr, c.component)
+	}
+
+	c.release()
+}
+
+/* FreeSlaveInstance disposes the given instance of a Co-Simulation slave and frees all the allocated memory and other resources.
+ * If the passed instance is nil, the function call is ignored (does not have an effect).
+ */
+func (f *Fmu1) FreeSlaveInstance(c *Instance) {
+	if c == nil || c.component == nil {
+		return
+	}
+
+	// after Fatal no function of the instance may be called
+	if !c.fatal && f.freeSlaveInstancePtr != nil {
+		C.Fmi1FreeSlaveInstance(f.freeSlaveInstancePtr, c.component)
+	}
+
+	c.release()
+}
+
+/* GetReal gets actual values of variables by providing their variable references. */
+func (f *Fmu1) GetReal(c *Instance, vr []ValueReference) ([]float64, error) {
+
+	if err := f.available(c, f.getRealPtr, "fmiGetReal"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := make([]C.fmiReal, len(vr))
+
+	if err := f.check(c, "fmiGetReal", C.Fmi1GetReal(f.getRealPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v C.fmiReal) float64 { return float64(v) }), nil
+}
+
+/* GetInteger gets actual values of variables by providing their variable references. */
+func (f *Fmu1) GetInteger(c *Instance, vr []ValueReference) ([]int, error) {
+
+	if err := f.available(c, f.getIntegerPtr, "fmiGetInteger"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := make([]C.fmiInteger, len(vr))
+
+	if err := f.check(c, "fmiGetInteger", C.Fmi1GetInteger(f.getIntegerPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v C.fmiInteger) int { return int(v) }), nil
+}
+
+/* GetBoolean gets actual values of variables by providing their variable references. */
+func (f *Fmu1) GetBoolean(c *Instance, vr []ValueReference) ([]bool, error) {
+
+	if err := f.available(c, f.getBooleanPtr, "fmiGetBoolean"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := make([]C.fmiBoolean, len(vr))
+
+	if err := f.check(c, "fmiGetBoolean", C.Fmi1GetBoolean(f.getBooleanPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v C.fmiBoolean) bool { return v != C.fmiFalse }), nil
+}
+
+/* GetString gets actual values of variables by providing their variable references. */
+func (f *Fmu1) GetString(c *Instance, vr []ValueReference) ([]string, error) {
+
+	if err := f.available(c, f.getStringPtr, "fmiGetString"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := make([]C.fmiString, len(vr))
+
+	if err := f.check(c, "fmiGetString", C.Fmi1GetString(f.getStringPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	// the strings are owned by the FMU and copied before the next call
+	return fmi.Transform(values, func(i int, v C.fmiString) string { return C.GoString(v) }), nil
+}
+
+/* SetReal sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables. */
+func (f *Fmu1) SetReal(c *Instance, vr []ValueReference, value []float64) error {
+
+	if err := f.available(c, f.setRealPtr, "fmiSetReal"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := fmi.Transform(value, func(i int, v float64) C.fmiReal { return C.fmiReal(v) })
+
+	return f.check(c, "fmiSetReal", C.Fmi1SetReal(f.setRealPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+/* SetInteger sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables. */
+func (f *Fmu1) SetInteger(c *Instance, vr []ValueReference, value []int) error {
+
+	if err := f.available(c, f.setIntegerPtr, "fmiSetInteger"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := fmi.Transform(value, func(i int, v int) C.fmiInteger { return C.fmiInteger(v) })
+
+	return f.check(c, "fmiSetInteger", C.Fmi1SetInteger(f.setIntegerPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+/* SetBoolean sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables. */
+func (f *Fmu1) SetBoolean(c *Instance, vr []ValueReference, value []bool) error {
+
+	if err := f.available(c, f.setBooleanPtr, "fmiSetBoolean"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := fmi.Transform(value, func(i int, v bool) C.fmiBoolean { return toBoolean(v) })
+
+	return f.check(c, "fmiSetBoolean", C.Fmi1SetBoolean(f.setBooleanPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+/* SetString sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables. */
+func (f *Fmu1) SetString(c *Instance, vr []ValueReference, value []string) error {
+
+	if err := f.available(c, f.setStringPtr, "fmiSetString"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	values := fmi.Transform(value, func(i int, v string) C.fmiString { return C.CString(v) })
+
+	defer func() {
+		for _, v := range values {
+			C.free(unsafe.Pointer(v))
+		}
+	}()
+
+	return f.check(c, "fmiSetString", C.Fmi1SetString(f.setStringPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+func eventInfoOf(eventInfo *C.fmiEventInfo) *EventInfo {
+	return &EventInfo{
+		IterationConverged:          eventInfo.iterationConverged != C.fmiFalse,
+		StateValueReferencesChanged: eventInfo.stateValueReferencesChanged != C.fmiFalse,
+		StateValuesChanged:          eventInfo.stateValuesChanged != C.fmiFalse,
+		TerminateSimulation:         eventInfo.terminateSimulation != C.fmiFalse,
+		UpcomingTimeEvent:           eventInfo.upcomingTimeEvent != C.fmiFalse,
+		NextEventTime:               float64(eventInfo.nextEventTime),
+	}
+}
+
+/* SetTime sets a new time instant and re-initializes caching of variables that depend on time. */
+func (f *Fmu1) SetTime(c *Instance, time float64) error {
+	if err := f.available(c, f.setTimePtr, "fmiSetTime"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiSetTime", C.Fmi1SetTime(f.setTimePtr, c.component, C.fmiReal(time)))
+}
+
+/* SetContinuousStates sets a new (continuous) state vector and re-initializes caching of variables that depend on the states. */
+func (f *Fmu1) SetContinuousStates(c *Instance, x []float64) error {
+
+	if err := f.available(c, f.setContinuousStatesPtr, "fmiSetContinuousStates"); err != nil {
+		return err
+	}
+
+	if len(x) == 0 {
+		return nil
+	}
+
+	states := fmi.Transform(x, func(i int, v float64) C.fmiReal { return C.fmiReal(v) })
+
+	return f.check(c, "fmiSetContinuousStates", C.Fmi1SetContinuousStates(f.setContinuousStatesPtr, c.component, &states[0], C.size_t(len(x))))
+}
+
+/* CompletedIntegratorStep must be called by the environment after every completed step of the integrator.
+ * It returns true if EventUpdate must be called.
+ */
+func (f *Fmu1) CompletedIntegratorStep(c *Instance) (bool, error) {
+
+	if err := f.available(c, f.completedIntegratorStepPtr, "fmiCompletedIntegratorStep"); err != nil {
+		return false, err
+	}
+
+	var callEventUpdate C.fmiBoolean
+
+	if err := f.check(c, "fmiCompletedIntegratorStep", C.Fmi1CompletedIntegratorStep(f.completedIntegratorStepPtr, c.component, &callEventUpdate)); err != nil {
+		return false, err
+	}
+
+	return callEventUpdate != C.fmiFalse, nil
+}
+
+/* Initialize initializes the model, which computes the initial values of the variables and performs the event iteration
+ * at the start time. The start time is set with SetTime before. If toleranceControlled is true, the model uses
+ * relativeTolerance for its internal computations.
+ */
+func (f *Fmu1) Initialize(c *Instance, toleranceControlled bool, relativeTolerance float64) (*EventInfo, error) {
+
+	if err := f.available(c, f.initializePtr, "fmiInitialize"); err != nil {
+		return nil, err
+	}
+
+	var eventInfo C.fmiEventInfo
+
+	if err := f.check(c, "fmiInitialize", C.Fmi1Initialize(f.initializePtr, c.component, toBoolean(toleranceControlled), C.fmiReal(relativeTolerance), &eventInfo)); err != nil {
+		return nil, err
+	}
+
+	return eventInfoOf(&eventInfo), nil
+}
+
+// getReals calls one of the Model Exchange functions that return nx values
+func (f *Fmu1) getReals(c *Instance, ptr unsafe.Pointer, function string, nx int, get func(values *C.fmiReal, n C.size_t) C.fmiStatus) ([]float64, error) {
+
+	if err := f.available(c, ptr, function); err != nil {
+		return nil, err
+	}
+
+	if nx == 0 {
+		return []float64{}, nil
+	}
+
+	values := make([]C.fmiReal, nx)
+
+	if err := f.check(c, function, get(&values[0], C.size_t(nx))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v C.fmiReal) float64 { return float64(v) }), nil
+}
+
+/* GetDerivatives computes the state derivatives at the current time instant and for the current states. */
+func (f *Fmu1) GetDerivatives(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getDerivativesPtr, "fmiGetDerivatives", nx, func(values *C.fmiReal, n C.size_t) C.fmiStatus {
+		return C.Fmi1GetDerivatives(f.getDerivativesPtr, c.component, values, n)
+	})
+}
+
+/* GetEventIndicators computes the event indicators at the current time instant and for the current states. */
+func (f *Fmu1) GetEventIndicators(c *Instance, ni int) ([]float64, error) {
+	return f.getReals(c, f.getEventIndicatorsPtr, "fmiGetEventIndicators", ni, func(values *C.fmiReal, n C.size_t) C.fmiStatus {
+		return C.Fmi1GetEventIndicators(f.getEventIndicatorsPtr, c.component, values, n)
+	})
+}
+
+/* GetContinuousStates returns the (continuous) state vector with "nx" elements. */
+func (f *Fmu1) GetContinuousStates(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getContinuousStatesPtr, "fmiGetContinuousStates", nx, func(values *C.fmiReal, n C.size_t) C.fmiStatus {
+		return C.Fmi1GetContinuousStates(f.getContinuousStatesPtr, c.component, values, n)
+	})
+}
+
+/* GetNominalContinuousStates returns the nominal values of the continuous states. */
+func (f *Fmu1) GetNominalContinuousStates(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getNominalContinuousStatesPtr, "fmiGetNominalContinuousStates", nx, func(values *C.fmiReal, n C.size_t) C.fmiStatus {
+		return C.Fmi1GetNominalContinuousStates(f.getNominalContinuousStatesPtr, c.component, values, n)
+	})
+}
+
+/* GetStateValueReferences returns the value references of the continuous states. */
+func (f *Fmu1) GetStateValueReferences(c *Instance, nx int) ([]ValueReference, error) {
+
+	if err := f.available(c, f.getStateValueReferencesPtr, "fmiGetStateValueReferences"); err != nil {
+		return nil, err
+	}
+
+	refs := make([]C.fmiValueReference, nx)
+
+	if err := f.check(c, "fmiGetStateValueReferences", C.Fmi1GetStateValueReferences(f.getStateValueReferencesPtr, c.component, fmi.First(refs), C.size_t(nx))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(refs, func(i int, v C.fmiValueReference) ValueReference { return ValueReference(v) }), nil
+}
+
+/* EventUpdate is called after a time, state or step event to perform the event iteration.
+ * If intermediateResults is true, the function returns after every iteration and must be called
+ * again until IterationConverged is true.
+ */
+func (f *Fmu1) EventUpdate(c *Instance, intermediateResults bool) (*EventInfo, error) {
+
+	if err := f.available(c, f.eventUpdatePtr, "fmiEventUpdate"); err != nil {
+		return nil, err
+	}
+
+	var eventInfo C.fmiEventInfo
+
+	if err := f.check(c, "fmiEventUpdate", C.Fmi1EventUpdate(f.eventUpdatePtr, c.component, toBoolean(intermediateResults), &eventInfo)); err != nil {
+		return nil, err
+	}
+
+	return eventInfoOf(&eventInfo), nil
+}
+
+/* Terminate terminates the simulation run of a Model Exchange FMU. */
+func (f *Fmu1) Terminate(c *Instance) error {
+	if err := f.available(c, f.terminatePtr, "fmiTerminate"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiTerminate", C.Fmi1Terminate(f.terminatePtr, c.component))
+}
+
+type InitializeSlaveOption func(*InitializeSlaveOptions)
+
+type InitializeSlaveOptions struct {
+	stopTimeDefined bool
+	stopTime        float64
+}
+
+// WithStopTime passes the stop time of the simulation to the slave
+func WithStopTime(stopTime float64) InitializeSlaveOption {
+	return func(o *InitializeSlaveOptions) {
+		o.stopTimeDefined = true
+		o.stopTime = stopTime
+	}
+}
+
+/* InitializeSlave initializes the slave for a simulation run that starts at tStart. */
+func (f *Fmu1) InitializeSlave(c *Instance, tStart float64, opts ...InitializeSlaveOption) error {
+
+	if err := f.available(c, f.initializeSlavePtr, "fmiInitializeSlave"); err != nil {
+		return err
+	}
+
+	options := &InitializeSlaveOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return f.check(c, "fmiInitializeSlave", C.Fmi1InitializeSlave(f.initializeSlavePtr, c.component, C.fmiReal(tStart), toBoolean(options.stopTimeDefined), C.fmiReal(options.stopTime)))
+}
+
+/* TerminateSlave terminates the simulation run of the slave. */
+func (f *Fmu1) TerminateSlave(c *Instance) error {
+	if err := f.available(c, f.terminateSlavePtr, "fmiTerminateSlave"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiTerminateSlave", C.Fmi1TerminateSlave(f.terminateSlavePtr, c.component))
+}
+
+/* ResetSlave resets the slave to the state after instantiation, so another simulation run can be performed. */
+func (f *Fmu1) ResetSlave(c *Instance) error {
+	if err := f.available(c, f.resetSlavePtr, "fmiResetSlave"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiResetSlave", C.Fmi1ResetSlave(f.resetSlavePtr, c.component))
+}
+
+/* SetRealInputDerivatives sets the n-th time derivative of real input variables.
+ * order contains the orders of the respective derivative (1 means the first derivative, 0 is not allowed).
+ */
+func (f *Fmu1) SetRealInputDerivatives(c *Instance, vr []ValueReference, order []int, value []float64) error {
+
+	if err := f.available(c, f.setRealInputDerivativesPtr, "fmiSetRealInputDerivatives"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	orders := fmi.Transform(order, func(i int, v int) C.fmiInteger { return C.fmiInteger(v) })
+	values := fmi.Transform(value, func(i int, v float64) C.fmiReal { return C.fmiReal(v) })
+
+	return f.check(c, "fmiSetRealInputDerivatives", C.Fmi1SetRealInputDerivatives(f.setRealInputDerivativesPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(orders), fmi.First(values)))
+}
+
+/* GetRealOutputDerivatives retrieves the n-th derivative of output values. */
+func (f *Fmu1) GetRealOutputDerivatives(c *Instance, vr []ValueReference, order []int) ([]float64, error) {
+
+	if err := f.available(c, f.getRealOutputDerivativesPtr, "fmiGetRealOutputDerivatives"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmiValueReference { return C.fmiValueReference(v) })
+	orders := fmi.Transform(order, func(i int, v int) C.fmiInteger { return C.fmiInteger(v) })
+	values := make([]C.fmiReal, len(vr))
+
+	if err := f.check(c, "fmiGetRealOutputDerivatives", C.Fmi1GetRealOutputDerivatives(f.getRealOutputDerivativesPtr, c.component, fmi.First(refs), C.size_t(len(refs)), fmi.First(orders), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v C.fmiReal) float64 { return float64(v) }), nil
+}
+
+/* CancelStep cancels a step that returned Pending. */
+func (f *Fmu1) CancelStep(c *Instance) error {
+	if err := f.available(c, f.cancelStepPtr, "fmiCancelStep"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiCancelStep", C.Fmi1CancelStep(f.cancelStepPtr, c.component))
+}
+
+/* DoStep computes a communication step from currentCommunicationPoint to currentCommunicationPoint + communicationStepSize.
+ * newStep is false if the step is repeated from the same communication point after it was rejected.
+ */
+func (f *Fmu1) DoStep(c *Instance, currentCommunicationPoint float64, communicationStepSize float64, newStep bool) error {
+	if err := f.available(c, f.doStepPtr, "fmiDoStep"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmiDoStep", C.Fmi1DoStep(f.doStepPtr, c.component, C.fmiReal(currentCommunicationPoint), C.fmiReal(communicationStepSize), toBoolean(newStep)))
+}
+
+/* GetStatus returns the status of the slave, e.g. of an asynchronous step with DoStepStatus. */
+func (f *Fmu1) GetStatus(c *Instance, s StatusKind) (Status, error) {
+
+	if err := f.available(c, f.getStatusPtr, "fmiGetStatus"); err != nil {
+		return Error, err
+	}
+
+	var value C.fmiStatus
+
+	if err := f.check(c, "fmiGetStatus", C.Fmi1GetStatus(f.getStatusPtr, c.component, C.fmiStatusKind(s), &value)); err != nil {
+		return Error, err
+	}
+
+	return Status(value), nil
+}
+
+/* GetRealStatus returns a real status of the slave, e.g. the LastSuccessfulTime after a rejected step. */
+func (f *Fmu1) GetRealStatus(c *Instance, s StatusKind) (float64, error) {
+
+	if err := f.available(c, f.getRealStatusPtr, "fmiGetRealStatus"); err != nil {
+		return 0, err
+	}
+
+	var value C.fmiReal
+
+	if err := f.check(c, "fmiGetRealStatus", C.Fmi1GetRealStatus(f.getRealStatusPtr, c.component, C.fmiStatusKind(s), &value)); err != nil {
+		return 0, err
+	}
+
+	return float64(value), nil
+}
+
+/* GetIntegerStatus returns an integer status of the slave. */
+func (f *Fmu1) GetIntegerStatus(c *Instance, s StatusKind) (int, error) {
+
+	if err := f.available(c, f.getIntegerStatusPtr, "fmiGetIntegerStatus"); err != nil {
+		return 0, err
+	}
+
+	var value C.fmiInteger
+
+	if err := f.check(c, "fmiGetIntegerStatus", C.Fmi1GetIntegerStatus(f.getIntegerStatusPtr, c.component, C.fmiStatusKind(s), &value)); err != nil {
+		return 0, err
+	}
+
+	return int(value), nil
+}
+
+/* GetBooleanStatus returns a boolean status of the slave. */
+func (f *Fmu1) GetBooleanStatus(c *Instance, s StatusKind) (bool, error) {
+
+	if err := f.available(c, f.getBooleanStatusPtr, "fmiGetBooleanStatus"); err != nil {
+		return false, err
+	}
+
+	var value C.fmiBoolean
+
+	if err := f.check(c, "fmiGetBooleanStatus", C.Fmi1GetBooleanStatus(f.getBooleanStatusPtr, c.component, C.fmiStatusKind(s), &value)); err != nil {
+		return false, err
+	}
+
+	return value != C.fmiFalse, nil
+}
+
+/* GetStringStatus returns a string status of the slave, e.g. the description of a pending step with PendingStatus. */
+func (f *Fmu1) GetStringStatus(c *Instance, s StatusKind) (string, error) {
+
+	if err := f.available(c, f.getStringStatusPtr, "fmiGetStringStatus"); err != nil {
+		return "", err
+	}
+
+	var value C.fmiString
+
+	if err := f.check(c, "fmiGetStringStatus", C.Fmi1GetStringStatus(f.getStringStatusPtr, c.component, C.fmiStatusKind(s), &value)); err != nil {
+		return "", err
+	}
+
+	return C.GoString(value), nil
+}
+
+func resolveFunction(handle unsafe.Pointer, name string) unsafe.Pointer {
+	str := C.CString(name)
+	defer C.free(unsafe.Pointer(str))
+	return C.dlsym(handle, str)
+}
+
+// libraryPath returns the path of the shared library in the FMU
+func libraryPath(md *ModelDescription, machine Machine) string {
+	return path.Join("binaries", machine.Platform, md.ModelIdentifier+"."+machine.LibrarySuffix)
+}
+
+// selectLibrary reads the model description and returns the path of the shared library
+func selectLibrary(fsys fs.FS) (*ModelDescription, string, error) {
+
+	md, err := ReadModelDescriptionFS(fsys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	library := libraryPath(md, CurrentMachine())
+
+	if _, err := fs.Stat(fsys, library); err != nil {
+		return nil, "", fmt.Errorf("the FMU contains no shared library for %s (tried %s, the FMU contains binaries for the platforms: %s)",
+			CurrentMachine().Platform, library, strings.Join(SupportedPlatformsFS(fsys), ", "))
+	}
+
+	return md, library, nil
+}
+
+// New loads the shared library of the FMU. The FMU is extracted into a temporary directory that is removed by Close.
+func New(filename string) (*Fmu1, error) {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	md, library, err := selectLibrary(r)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, _, err := fmi.Extract(&r.Reader, fmi.ExtractOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	fmu, err := load(md, directory, library)
+	if err != nil {
+		os.RemoveAll(directory)
+		return nil, err
+	}
+
+	fmu.ownsDirectory = true
+
+	return fmu, nil
+}
+
+// NewFromDirectory loads an FMU that has already been extracted into directory.
+// The directory is not removed by Close.
+func NewFromDirectory(directory string) (*Fmu1, error) {
+
+	md, library, err := selectLibrary(os.DirFS(directory))
+	if err != nil {
+		return nil, err
+	}
+
+	return load(md, directory, library)
+}
+
+// load loads the shared library of the FMU that has been extracted into directory. The functions
+// are exported with the model identifier as prefix, e.g. "MyModel_fmiDoStep".
+func load(md *ModelDescription, directory string, library string) (*Fmu1, error) {
+
+	modulePath := filepath.Join(directory, filepath.FromSlash(library))
+	moduleString := C.CString(modulePath)
+	defer C.free(unsafe.Pointer(moduleString))
+
+	var loadError *C.char
+	handle := C.Fmi1OpenLibrary(moduleString, &loadError)
+	if handle == nil {
+		return nil, fmt.Errorf("error loading %s: %s", library, C.GoString(loadError))
+	}
+
+	resolve := func(name string) unsafe.Pointer {
+		return resolveFunction(handle, md.ModelIdentifier+"_"+name)
+	}
+
+	fmu := &Fmu1{
+		Directory:        directory,
+		moduleHandle:     handle,
+		modelDescription: md,
+
+		// Common Functions
+		getVersionPtr:      resolve("fmiGetVersion"),
+		setDebugLoggingPtr: resolve("fmiSetDebugLogging"),
+		getRealPtr:         resolve("fmiGetReal"),
+		getIntegerPtr:      resolve("fmiGetInteger"),
+		getBooleanPtr:      resolve("fmiGetBoolean"),
+		getStringPtr:       resolve("fmiGetString"),
+		setRealPtr:         resolve("fmiSetReal"),
+		setIntegerPtr:      resolve("fmiSetInteger"),
+		setBooleanPtr:      resolve("fmiSetBoolean"),
+		setStringPtr:       resolve("fmiSetString"),
+	}
+
+	if md.Type() == ModelExchangeType {
+		fmu.getTypesPlatform = resolve("fmiGetModelTypesPlatform")
+		fmu.instantiateModelPtr = resolve("fmiInstantiateModel")
+		fmu.freeModelInstancePtr = resolve("fmiFreeModelInstance")
+		fmu.setTimePtr = resolve("fmiSetTime")
+		fmu.setContinuousStatesPtr = resolve("fmiSetContinuousStates")
+		fmu.completedIntegratorStepPtr = resolve("fmiCompletedIntegratorStep")
+		fmu.initializePtr = resolve("fmiInitialize")
+		fmu.getDerivativesPtr = resolve("fmiGetDerivatives")
+		fmu.getEventIndicatorsPtr = resolve("fmiGetEventIndicators")
+		fmu.eventUpdatePtr = resolve("fmiEventUpdate")
+		fmu.getContinuousStatesPtr = resolve("fmiGetContinuousStates")
+		fmu.getNominalContinuousStatesPtr = resolve("fmiGetNominalContinuousStates")
+		fmu.getStateValueReferencesPtr = resolve("fmiGetStateValueReferences")
+		fmu.terminatePtr = resolve("fmiTerminate")
+	} else {
+		fmu.getTypesPlatform = resolve("fmiGetTypesPlatform")
+		fmu.instantiateSlavePtr = resolve("fmiInstantiateSlave")
+		fmu.initializeSlavePtr = resolve("fmiInitializeSlave")
+		fmu.terminateSlavePtr = resolve("fmiTerminateSlave")
+		fmu.resetSlavePtr = resolve("fmiResetSlave")
+		fmu.freeSlaveInstancePtr = resolve("fmiFreeSlaveInstance")
+		fmu.setRealInputDerivativesPtr = resolve("fmiSetRealInputDerivatives")
+		fmu.getRealOutputDerivativesPtr = resolve("fmiGetRealOutputDerivatives")
+		fmu.cancelStepPtr = resolve("fmiCancelStep")
+		fmu.doStepPtr = resolve("fmiDoStep")
+		fmu.getStatusPtr = resolve("fmiGetStatus")
+		fmu.getRealStatusPtr = resolve("fmiGetRealStatus")
+		fmu.getIntegerStatusPtr = resolve("fmiGetIntegerStatus")
+		fmu.getBooleanStatusPtr = resolve("fmiGetBooleanStatus")
+		fmu.getStringStatusPtr = resolve("fmiGetStringStatus")
+	}
+
+	if err := fmu.checkFunctions(); err != nil {
+		fmu.Close()
+		return nil, err
+	}
+
+	if fmu.Type() == ModelExchangeType {
+		fmu.library = fmi.OpenLibrary(uintptr(fmu.instantiateModelPtr))
+	} else {
+		fmu.library = fmi.OpenLibrary(uintptr(fmu.instantiateSlavePtr))
+	}
+
+	runtime.SetFinalizer(fmu, (*Fmu1).Close)
+
+	return fmu, nil
+}
+
+// checkFunctions returns an error if the library does not export the functions that are needed to simulate the FMU.
+// The functions to get the nominal values and the value references of the states and the functions of
+// Co-Simulation slaves that depend on capability flags are optional.
+func (f *Fmu1) checkFunctions() error {
+
+	functions := map[string]unsafe.Pointer{
+		"fmiGetVersion":      f.getVersionPtr,
+		"fmiSetDebugLogging": f.setDebugLoggingPtr,
+		"fmiGetReal":         f.getRealPtr,
+		"fmiGetInteger":      f.getIntegerPtr,
+		"fmiGetBoolean":      f.getBooleanPtr,
+		"fmiGetString":       f.getStringPtr,
+		"fmiSetReal":         f.setRealPtr,
+		"fmiSetInteger":      f.setIntegerPtr,
+		"fmiSetBoolean":      f.setBooleanPtr,
+		"fmiSetString":       f.setStringPtr,
+	}
+
+	if f.Type() == ModelExchangeType {
+		functions["fmiInstantiateModel"] = f.instantiateModelPtr
+		functions["fmiFreeModelInstance"] = f.freeModelInstancePtr
+		functions["fmiSetTime"] = f.setTimePtr
+		functions["fmiSetContinuousStates"] = f.setContinuousStatesPtr
+		functions["fmiCompletedIntegratorStep"] = f.completedIntegratorStepPtr
+		functions["fmiInitialize"] = f.initializePtr
+		functions["fmiGetDerivatives"] = f.getDerivativesPtr
+		functions["fmiGetEventIndicators"] = f.getEventIndicatorsPtr
+		functions["fmiEventUpdate"] = f.eventUpdatePtr
+		functions["fmiGetContinuousStates"] = f.getContinuousStatesPtr
+		functions["fmiTerminate"] = f.terminatePtr
+	} else {
+		functions["fmiInstantiateSlave"] = f.instantiateSlavePtr
+		functions["fmiInitializeSlave"] = f.initializeSlavePtr
+		functions["fmiTerminateSlave"] = f.terminateSlavePtr
+		functions["fmiFreeSlaveInstance"] = f.freeSlaveInstancePtr
+		functions["fmiDoStep"] = f.doStepPtr
+	}
+
+	var missing []string
+	for name, ptr := range functions {
+		if ptr == nil {
+			missing = append(missing, f.modelDescription.ModelIdentifier+"_"+name)
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the shared library of %s does not export the mandatory functions: %s", f.modelDescription.ModelIdentifier, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Extract extracts the FMU into a new temporary directory
+func Extract(filename string) (string, error) {
+	return fmi.ExtractFile(filename)
+}
@@ -0,0 +1,99 @@
+#ifndef CORE_H_
+#define CORE_H_
+
+#include "headers/fmiPlatformTypes.h"
+#include <dlfcn.h>
+#include <stdarg.h>
+#include <stddef.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+
+typedef const char cchar_t;
+
+// the headers fmiModelFunctions.h (Model Exchange) and fmiFunctions.h (Co-Simulation) of FMI 1.0 define
+// different versions of fmiStatus and fmiCallbackFunctions and cannot be included together, so the
+// types that are needed to call the functions of both interfaces are declared here
+
+typedef enum { fmiOK, fmiWarning, fmiDiscard, fmiError, fmiFatal, fmiPending } fmiStatus;
+
+typedef enum { fmiDoStepStatus, fmiPendingStatus, fmiLastSuccessfulTime } fmiStatusKind;
+
+typedef void (*fmiCallbackLogger)(fmiComponent c, fmiString instanceName, fmiStatus status, fmiString category, fmiString message, ...);
+typedef void *(*fmiCallbackAllocateMemory)(size_t nobj, size_t size);
+typedef void (*fmiCallbackFreeMemory)(void *obj);
+typedef void (*fmiStepFinished)(fmiComponent c, fmiStatus status);
+
+// fmiCallbackFunctions of fmiModelFunctions.h
+typedef struct {
+	fmiCallbackLogger logger;
+	fmiCallbackAllocateMemory allocateMemory;
+	fmiCallbackFreeMemory freeMemory;
+} fmiMECallbackFunctions;
+
+// fmiCallbackFunctions of fmiFunctions.h
+typedef struct {
+	fmiCallbackLogger logger;
+	fmiCallbackAllocateMemory allocateMemory;
+	fmiCallbackFreeMemory freeMemory;
+	fmiStepFinished stepFinished;
+} fmiCSCallbackFunctions;
+
+typedef struct {
+	fmiBoolean iterationConverged;
+	fmiBoolean stateValueReferencesChanged;
+	fmiBoolean stateValuesChanged;
+	fmiBoolean terminateSimulation;
+	fmiBoolean upcomingTimeEvent;
+	fmiReal nextEventTime;
+} fmiEventInfo;
+
+extern void Fmi1Logger(fmiComponent c, fmiString instanceName, fmiStatus status, fmiString category, fmiString message, ...);
+
+// the functions are prefixed with Fmi1 because the C symbols of all FMI versions are linked into the same binary
+extern void *Fmi1OpenLibrary(const char *filename, const char **error);
+extern const char *Fmi1GetTypesPlatform(void *f);
+extern const char *Fmi1GetVersion(void *f);
+extern fmiStatus Fmi1SetDebugLogging(void *f, fmiComponent c, fmiBoolean loggingOn);
+
+extern fmiStatus Fmi1GetReal(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiReal value[]);
+extern fmiStatus Fmi1GetInteger(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiInteger value[]);
+extern fmiStatus Fmi1GetBoolean(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiBoolean value[]);
+extern fmiStatus Fmi1GetString(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, fmiString value[]);
+extern fmiStatus Fmi1SetReal(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiReal value[]);
+extern fmiStatus Fmi1SetInteger(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger value[]);
+extern fmiStatus Fmi1SetBoolean(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiBoolean value[]);
+extern fmiStatus Fmi1SetString(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiString value[]);
+
+// Model Exchange
+extern fmiComponent Fmi1InstantiateModel(void *f, fmiString instanceName, fmiString GUID, fmiBoolean loggingOn);
+extern void Fmi1FreeModelInstance(void *f, fmiComponent c);
+extern fmiStatus Fmi1SetTime(void *f, fmiComponent c, fmiReal time);
+extern fmiStatus Fmi1SetContinuousStates(void *f, fmiComponent c, const fmiReal x[], size_t nx);
+extern fmiStatus Fmi1CompletedIntegratorStep(void *f, fmiComponent c, fmiBoolean *callEventUpdate);
+extern fmiStatus Fmi1Initialize(void *f, fmiComponent c, fmiBoolean toleranceControlled, fmiReal relativeTolerance, fmiEventInfo *eventInfo);
+extern fmiStatus Fmi1GetDerivatives(void *f, fmiComponent c, fmiReal derivatives[], size_t nx);
+extern fmiStatus Fmi1GetEventIndicators(void *f, fmiComponent c, fmiReal eventIndicators[], size_t ni);
+extern fmiStatus Fmi1EventUpdate(void *f, fmiComponent c, fmiBoolean intermediateResults, fmiEventInfo *eventInfo);
+extern fmiStatus Fmi1GetContinuousStates(void *f, fmiComponent c, fmiReal states[], size_t nx);
+extern fmiStatus Fmi1GetNominalContinuousStates(void *f, fmiComponent c, fmiReal x_nominal[], size_t nx);
+extern fmiStatus Fmi1GetStateValueReferences(void *f, fmiComponent c, fmiValueReference vrx[], size_t nx);
+extern fmiStatus Fmi1Terminate(void *f, fmiComponent c);
+
+// Co-Simulation
+extern fmiComponent Fmi1InstantiateSlave(void *f, fmiString instanceName, fmiString fmuGUID, fmiString fmuLocation, fmiString mimeType, fmiReal timeout, fmiBoolean visible, fmiBoolean interactive, fmiBoolean loggingOn);
+extern fmiStatus Fmi1InitializeSlave(void *f, fmiComponent c, fmiReal tStart, fmiBoolean StopTimeDefined, fmiReal tStop);
+extern fmiStatus Fmi1TerminateSlave(void *f, fmiComponent c);
+extern fmiStatus Fmi1ResetSlave(void *f, fmiComponent c);
+extern void Fmi1FreeSlaveInstance(void *f, fmiComponent c);
+extern fmiStatus Fmi1SetRealInputDerivatives(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], const fmiReal value[]);
+extern fmiStatus Fmi1GetRealOutputDerivatives(void *f, fmiComponent c, const fmiValueReference vr[], size_t nvr, const fmiInteger order[], fmiReal value[]);
+extern fmiStatus Fmi1CancelStep(void *f, fmiComponent c);
+extern fmiStatus Fmi1DoStep(void *f, fmiComponent c, fmiReal currentCommunicationPoint, fmiReal communicationStepSize, fmiBoolean newStep);
+extern fmiStatus Fmi1GetStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiStatus *value);
+extern fmiStatus Fmi1GetRealStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiReal *value);
+extern fmiStatus Fmi1GetIntegerStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiInteger *value);
+extern fmiStatus Fmi1GetBooleanStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiBoolean *value);
+extern fmiStatus Fmi1GetStringStatus(void *f, fmiComponent c, const fmiStatusKind s, fmiString *value);
+
+#endif
@@ -0,0 +1,128 @@
+package fmi1_test
+
+import (
+	"go-fmu/pkg/fmi1"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelExchange(t *testing.T) {
+
+	fmu, err := fmi1.New("../../examples/BouncingBall.fmu")
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	md := fmu.ModelDescription()
+	require.Equal(t, fmi1.ModelExchangeType, fmu.Type())
+
+	var messages []string
+
+	c := fmu.InstantiateModel("ball", md.Guid, false, fmi1.WithLogger(func(instanceName string, status fmi1.Status, category string, message string) {
+		messages = append(messages, message)
+	}))
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.Nil(t, fmu.InstantiateModel("ball", "{wrong}", false))
+
+	require.NoError(t, c.SetTime(0))
+
+	// the start value of h is fixed and cannot be set before the initialization
+	require.Error(t, c.SetReal([]fmi1.ValueReference{0}, []float64{2}))
+	require.Equal(t, []string{"the start value of h is fixed and cannot be set"}, messages)
+
+	require.NoError(t, c.SetReal([]fmi1.ValueReference{1, 4}, []float64{0, 0.5}))
+
+	eventInfo, err := c.Initialize(false, 0)
+	require.NoError(t, err)
+	require.True(t, eventInfo.IterationConverged)
+	require.False(t, eventInfo.UpcomingTimeEvent)
+
+	x, err := c.GetContinuousStates(2)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1, 0}, x)
+
+	vrx, err := c.GetStateValueReferences(2)
+	require.NoError(t, err)
+	require.Equal(t, []fmi1.ValueReference{0, 1}, vrx)
+
+	dx, err := c.GetDerivatives(2)
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float64{0, -9.81}, dx, 1e-9)
+
+	// the ball hits the ground and bounces off with the coefficient of restitution e
+	require.NoError(t, c.SetTime(0.45))
+	require.NoError(t, c.SetContinuousStates([]float64{-0.01, -4}))
+
+	z, err := c.GetEventIndicators(1)
+	require.NoError(t, err)
+	require.InDelta(t, -0.01, z[0], 1e-9)
+
+	callEventUpdate, err := c.CompletedIntegratorStep()
+	require.NoError(t, err)
+	require.False(t, callEventUpdate)
+
+	eventInfo, err = c.EventUpdate(false)
+	require.NoError(t, err)
+	require.True(t, eventInfo.StateValuesChanged)
+
+	v, err := c.GetReal([]fmi1.ValueReference{1})
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, v[0], 1e-9)
+
+	bounces, err := c.GetInteger([]fmi1.ValueReference{0})
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, bounces)
+
+	require.NoError(t, c.Terminate())
+}
+
+func TestCoSimulation(t *testing.T) {
+
+	fmu, err := fmi1.New("../../examples/Integrator.fmu")
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	md := fmu.ModelDescription()
+	require.Equal(t, fmi1.CoSimulationType, fmu.Type())
+
+	c := fmu.InstantiateSlave("integrator", md.Guid, "", 0, false, false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.NoError(t, c.SetReal([]fmi1.ValueReference{0, 2, 3}, []float64{2, 0.5, 1}))
+	require.NoError(t, c.SetString([]fmi1.ValueReference{0}, []string{"test"}))
+	require.NoError(t, c.InitializeSlave(0, fmi1.WithStopTime(2)))
+
+	for i := range 10 {
+		require.NoError(t, c.DoStep(float64(i)*0.1, 0.1, true))
+	}
+
+	y, err := c.GetReal([]fmi1.ValueReference{1})
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, y[0], 1e-9)
+
+	steps, err := c.GetInteger([]fmi1.ValueReference{0})
+	require.NoError(t, err)
+	require.Equal(t, []int{10}, steps)
+
+	label, err := c.GetString([]fmi1.ValueReference{0})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test"}, label)
+
+	// the derivatives of the inputs are set before the next step
+	require.NoError(t, c.SetBoolean([]fmi1.ValueReference{0}, []bool{false}))
+	require.NoError(t, c.SetRealInputDerivatives([]fmi1.ValueReference{0}, []int{1}, []float64{1}))
+
+	// the steps after the discard time are discarded
+	err = c.DoStep(1, 0.1, true)
+	require.True(t, fmi1.IsDiscard(err))
+
+	lastSuccessfulTime, err := c.GetRealStatus(fmi1.LastSuccessfulTime)
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, lastSuccessfulTime, 1e-9)
+
+	require.NoError(t, c.ResetSlave())
+	require.NoError(t, c.Terminate())
+}
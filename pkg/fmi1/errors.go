@@ -0,0 +1,76 @@
+package fmi1
+
+/*
+#include "core.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"unsafe"
+)
+
+// ErrFunctionNotAvailable is matched by errors.Is for every FunctionNotAvailableError
+var ErrFunctionNotAvailable = fmi.ErrFunctionNotAvailable
+
+// FunctionNotAvailableError is returned when the FMU does not export the FMI function that is called
+type FunctionNotAvailableError = fmi.FunctionNotAvailableError
+
+// ErrFatal is matched by errors.Is for the errors of all calls to an instance after it returned Fatal
+var ErrFatal = fmi.ErrFatal
+
+// StatusError is returned when an FMI function returns a status other than OK or Warning
+type StatusError = fmi.StatusError[Status]
+
+// IsDiscard returns whether err is a StatusError with the status Discard
+func IsDiscard(err error) bool {
+	return fmi.HasStatus(err, Discard)
+}
+
+// IsPending returns whether err is a StatusError with the status Pending
+func IsPending(err error) bool {
+	return fmi.HasStatus(err, Pending)
+}
+
+// WarningHandler is called when an FMI function returns Warning
+type WarningHandler = fmi.WarningHandler[*Instance]
+
+// SetWarningHandler sets the function that is called when an FMI function returns Warning.
+// Warnings are treated as success, the details are reported by the FMU via the logger.
+func (f *Fmu1) SetWarningHandler(handler WarningHandler) {
+	f.warningHandler = handler
+}
+
+// available returns an error if the FMU does not export the function or the instance returned Fatal before
+func (f *Fmu1) available(instance *Instance, ptr unsafe.Pointer, function string) error {
+	if ptr == nil {
+		return &FunctionNotAvailableError{Function: function}
+	}
+
+	if instance != nil && instance.fatal {
+		return fmt.Errorf("cannot call %s: %w", function, ErrFatal)
+	}
+
+	return nil
+}
+
+// check converts the status that the function returned to an error. Warning is treated as success
+// and Fatal prevents any further calls to the instance.
+func (f *Fmu1) check(instance *Instance, function string, status C.fmiStatus) error {
+	switch Status(status) {
+	case OK:
+		return nil
+	case Warning:
+		if f.warningHandler != nil {
+			f.warningHandler(instance, function)
+		}
+		return nil
+	case Fatal:
+		if instance != nil {
+			instance.fatal = true
+		}
+	}
+
+	return &StatusError{Function: function, Status: Status(status)}
+}
@@ -0,0 +1,41 @@
+#ifndef fmiModelTypes_h
+#define fmiModelTypes_h
+
+/* Standard header file to define the argument types of the
+   functions of the Model Execution Interface.
+   This header file must be utilized both by the model and
+   by the simulation engine.
+
+   Copyright © 2008-2009, MODELISAR consortium. All rights reserved.
+   This file is licensed by the copyright holders under the BSD License
+   (http://www.opensource.org/licenses/bsd-license.html)
+*/
+
+/* Platform (combination of machine, compiler, operating system) */
+#define fmiModelTypesPlatform "standard32"
+
+/* Type definitions of variables passed as arguments
+   Version "standard32" means:
+
+   fmiComponent     : 32 bit pointer
+   fmiValueReference: 32 bit
+   fmiReal          : 64 bit
+   fmiInteger       : 32 bit
+   fmiBoolean       :  8 bit
+   fmiString        : 32 bit pointer
+*/
+typedef void*        fmiComponent;
+typedef unsigned int fmiValueReference;
+typedef double       fmiReal;
+typedef int          fmiInteger;
+typedef char         fmiBoolean;
+typedef const char*  fmiString;
+
+/* Values for fmiBoolean  */
+#define fmiTrue  1
+#define fmiFalse 0
+
+/* Undefined value for fmiValueReference (largest unsigned int value) */
+#define fmiUndefinedValueReference (fmiValueReference)(-1)
+
+#endif
@@ -0,0 +1,169 @@
+package fmi1
+
+/*
+#include "core.h"
+*/
+import "C"
+
+type Instance struct {
+	fmu       *Fmu1
+	component C.fmiComponent
+	fmuType   Type
+	fatal     bool // the instance returned Fatal and must not be called anymore
+}
+
+// Type returns the interface type the instance was instantiated as
+func (c *Instance) Type() Type {
+	return c.fmuType
+}
+
+/* SetDebugLogging controls the debug logging that is output via the logger callback function by the FMU. */
+func (c *Instance) SetDebugLogging(loggingOn bool) error {
+	return c.fmu.SetDebugLogging(c, loggingOn)
+}
+
+/* Terminate terminates the simulation run (fmiTerminate for Model Exchange and fmiTerminateSlave for Co-Simulation). */
+func (c *Instance) Terminate() error {
+	if c.fmuType == ModelExchangeType {
+		return c.fmu.Terminate(c)
+	}
+	return c.fmu.TerminateSlave(c)
+}
+
+/* FreeInstance disposes the instance (fmiFreeModelInstance for Model Exchange and fmiFreeSlaveInstance for Co-Simulation). */
+func (c *Instance) FreeInstance() {
+	if c.fmuType == ModelExchangeType {
+		c.fmu.FreeModelInstance(c)
+	} else {
+		c.fmu.FreeSlaveInstance(c)
+	}
+}
+
+func (c *Instance) GetReal(vr []ValueReference) ([]float64, error) {
+	return c.fmu.GetReal(c, vr)
+}
+
+func (c *Instance) GetInteger(vr []ValueReference) ([]int, error) {
+	return c.fmu.GetInteger(c, vr)
+}
+
+func (c *Instance) GetBoolean(vr []ValueReference) ([]bool, error) {
+	return c.fmu.GetBoolean(c, vr)
+}
+
+func (c *Instance) GetString(vr []ValueReference) ([]string, error) {
+	return c.fmu.GetString(c, vr)
+}
+
+func (c *Instance) SetReal(vr []ValueReference, value []float64) error {
+	return c.fmu.SetReal(c, vr, value)
+}
+
+func (c *Instance) SetInteger(vr []ValueReference, value []int) error {
+	return c.fmu.SetInteger(c, vr, value)
+}
+
+func (c *Instance) SetBoolean(vr []ValueReference, value []bool) error {
+	return c.fmu.SetBoolean(c, vr, value)
+}
+
+func (c *Instance) SetString(vr []ValueReference, value []string) error {
+	return c.fmu.SetString(c, vr, value)
+}
+
+// Functions for Model Exchange
+
+/* SetTime sets a new time instant and re-initializes caching of variables that depend on time. */
+func (c *Instance) SetTime(time float64) error {
+	return c.fmu.SetTime(c, time)
+}
+
+/* SetContinuousStates sets a new (continuous) state vector. */
+func (c *Instance) SetContinuousStates(x []float64) error {
+	return c.fmu.SetContinuousStates(c, x)
+}
+
+/* CompletedIntegratorStep returns true if EventUpdate must be called after the completed integrator step. */
+func (c *Instance) CompletedIntegratorStep() (bool, error) {
+	return c.fmu.CompletedIntegratorStep(c)
+}
+
+/* Initialize initializes the model at the time that was set with SetTime. */
+func (c *Instance) Initialize(toleranceControlled bool, relativeTolerance float64) (*EventInfo, error) {
+	return c.fmu.Initialize(c, toleranceControlled, relativeTolerance)
+}
+
+func (c *Instance) GetDerivatives(nx int) ([]float64, error) {
+	return c.fmu.GetDerivatives(c, nx)
+}
+
+func (c *Instance) GetEventIndicators(ni int) ([]float64, error) {
+	return c.fmu.GetEventIndicators(c, ni)
+}
+
+/* EventUpdate performs the event iteration after a time, state or step event. */
+func (c *Instance) EventUpdate(intermediateResults bool) (*EventInfo, error) {
+	return c.fmu.EventUpdate(c, intermediateResults)
+}
+
+func (c *Instance) GetContinuousStates(nx int) ([]float64, error) {
+	return c.fmu.GetContinuousStates(c, nx)
+}
+
+func (c *Instance) GetNominalContinuousStates(nx int) ([]float64, error) {
+	return c.fmu.GetNominalContinuousStates(c, nx)
+}
+
+func (c *Instance) GetStateValueReferences(nx int) ([]ValueReference, error) {
+	return c.fmu.GetStateValueReferences(c, nx)
+}
+
+// Functions for Co-Simulation
+
+/* InitializeSlave initializes the slave for a simulation run that starts at tStart. */
+func (c *Instance) InitializeSlave(tStart float64, opts ...InitializeSlaveOption) error {
+	return c.fmu.InitializeSlave(c, tStart, opts...)
+}
+
+/* ResetSlave resets the slave to the state after instantiation. */
+func (c *Instance) ResetSlave() error {
+	return c.fmu.ResetSlave(c)
+}
+
+func (c *Instance) SetRealInputDerivatives(vr []ValueReference, order []int, value []float64) error {
+	return c.fmu.SetRealInputDerivatives(c, vr, order, value)
+}
+
+func (c *Instance) GetRealOutputDerivatives(vr []ValueReference, order []int) ([]float64, error) {
+	return c.fmu.GetRealOutputDerivatives(c, vr, order)
+}
+
+/* CancelStep cancels a step that returned Pending. */
+func (c *Instance) CancelStep() error {
+	return c.fmu.CancelStep(c)
+}
+
+/* DoStep computes a communication step. */
+func (c *Instance) DoStep(currentCommunicationPoint float64, communicationStepSize float64, newStep bool) error {
+	return c.fmu.DoStep(c, currentCommunicationPoint, communicationStepSize, newStep)
+}
+
+func (c *Instance) GetStatus(s StatusKind) (Status, error) {
+	return c.fmu.GetStatus(c, s)
+}
+
+func (c *Instance) GetRealStatus(s StatusKind) (float64, error) {
+	return c.fmu.GetRealStatus(c, s)
+}
+
+func (c *Instance) GetIntegerStatus(s StatusKind) (int, error) {
+	return c.fmu.GetIntegerStatus(c, s)
+}
+
+func (c *Instance) GetBooleanStatus(s StatusKind) (bool, error) {
+	return c.fmu.GetBooleanStatus(c, s)
+}
+
+func (c *Instance) GetStringStatus(s StatusKind) (string, error) {
+	return c.fmu.GetStringStatus(c, s)
+}
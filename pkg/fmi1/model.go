@@ -0,0 +1,115 @@
+package fmi1
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+/*
+Read the model description from an FMU without extracting it
+
+Parameters:
+
+	filename filename of the FMU
+
+Returns:
+
+	a ModelDescription object
+*/
+func ReadModelDescription(filename string) (*ModelDescription, error) {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return ReadModelDescriptionFS(r)
+}
+
+/*
+Read the model description from the files of an FMU
+
+Parameters:
+
+	fsys the files of the FMU, e.g. a *zip.Reader for an FMU in memory or os.DirFS for an extracted FMU
+
+Returns:
+
+	a ModelDescription object
+*/
+func ReadModelDescriptionFS(fsys fs.FS) (*ModelDescription, error) {
+
+	f, err := fsys.Open("modelDescription.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var md ModelDescription
+	reader := bufio.NewReader(f)
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err = decoder.Decode(&md); err != nil {
+		return nil, err
+	}
+
+	if md.FmiVersion != "1.0" {
+		return nil, fmt.Errorf("unsupported FMI version: %s", md.FmiVersion)
+	}
+
+	if md.ModelIdentifier == "" {
+		return nil, fmt.Errorf("attribute modelIdentifier is missing")
+	}
+
+	if md.Implementation != nil && md.Capabilities() == nil {
+		return nil, fmt.Errorf("the Implementation element must contain CoSimulation_StandAlone or CoSimulation_Tool")
+	}
+
+	return &md, nil
+}
+
+/*
+Determine the supported platforms from the files of an FMU
+
+Parameters:
+
+	fsys the files of the FMU, e.g. a *zip.Reader for an FMU in memory or os.DirFS for an extracted FMU
+
+Returns:
+
+	a slice of platforms, e.g. linux64, or an empty slice on error
+*/
+func SupportedPlatformsFS(fsys fs.FS) []string {
+
+	platforms := make([]string, 0)
+
+	fs.WalkDir(fsys, "binaries", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		dir := path.Dir(name)
+		base := path.Base(dir)
+
+		switch {
+		case dir == "binaries":
+		case strings.HasSuffix(name, ".dylib"), strings.HasSuffix(name, ".so"), strings.HasSuffix(name, ".dll"):
+			platforms = append(platforms, base)
+		}
+
+		return nil
+	})
+
+	return platforms
+}
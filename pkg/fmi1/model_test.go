@@ -0,0 +1,136 @@
+package fmi1_test
+
+import (
+	"go-fmu/pkg/fmi1"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const modelExchangeDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="1.0" modelName="bouncingBall" modelIdentifier="bouncingBall" guid="{8c4e810f-3df3-4a00-8276-176fa3c9f003}" numberOfContinuousStates="2" numberOfEventIndicators="1">
+  <UnitDefinitions>
+    <BaseUnit unit="m">
+      <DisplayUnitDefinition displayUnit="mm" gain="1000"/>
+    </BaseUnit>
+  </UnitDefinitions>
+  <TypeDefinitions>
+    <Type name="Mode">
+      <EnumerationType>
+        <Item name="off"/>
+        <Item name="on"/>
+      </EnumerationType>
+    </Type>
+  </TypeDefinitions>
+  <DefaultExperiment startTime="0" stopTime="3" tolerance="1e-4"/>
+  <ModelVariables>
+    <ScalarVariable name="h" valueReference="0" description="height">
+      <Real unit="m" start="1" fixed="true"/>
+    </ScalarVariable>
+    <ScalarVariable name="der(h)" valueReference="1">
+      <Real/>
+    </ScalarVariable>
+    <ScalarVariable name="e" valueReference="2" variability="parameter">
+      <Real start="0.7"/>
+    </ScalarVariable>
+    <ScalarVariable name="height" valueReference="0" alias="alias" causality="output">
+      <Real unit="m"/>
+    </ScalarVariable>
+    <ScalarVariable name="mode" valueReference="3" variability="discrete" causality="input">
+      <Enumeration declaredType="Mode" start="2"/>
+      <DirectDependency/>
+    </ScalarVariable>
+  </ModelVariables>
+</fmiModelDescription>
+`
+
+const coSimulationDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="1.0" modelName="inc" modelIdentifier="inc" guid="{8c4e810f-3df3-4a00-8276-176fa3c9f008}">
+  <ModelVariables>
+    <ScalarVariable name="counter" valueReference="0" variability="discrete" causality="output">
+      <Integer start="1"/>
+    </ScalarVariable>
+  </ModelVariables>
+  <Implementation>
+    <CoSimulation_StandAlone>
+      <Capabilities canHandleVariableCommunicationStepSize="true" canHandleEvents="true" maxOutputDerivativeOrder="1"/>
+    </CoSimulation_StandAlone>
+  </Implementation>
+</fmiModelDescription>
+`
+
+func TestReadModelDescriptionFS(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml":             {Data: []byte(modelExchangeDescription)},
+		"binaries/linux64/bouncingBall.so": {},
+		"binaries/win64/bouncingBall.dll":  {},
+	}
+
+	md, err := fmi1.ReadModelDescriptionFS(fsys)
+
+	require.NoError(t, err)
+	require.Equal(t, "1.0", md.FmiVersion)
+	require.Equal(t, "bouncingBall", md.ModelIdentifier)
+	require.Equal(t, fmi1.ModelExchangeType, md.Type())
+	require.Nil(t, md.Capabilities())
+	require.Equal(t, uint32(2), md.NumberOfContinuousStates)
+	require.Equal(t, uint32(1), md.NumberOfEventIndicators)
+	require.InDelta(t, 3.0, *md.DefaultExperiment.StopTime, 1e-9)
+
+	require.Equal(t, "mm", md.UnitDefinitions.BaseUnit[0].DisplayUnitDefinition[0].DisplayUnit)
+	require.Len(t, md.TypeDefinition("Mode").EnumerationType.Item, 2)
+
+	variables := md.Variables()
+	require.Len(t, variables, 5)
+
+	h := md.VariableByName("h")
+	require.Equal(t, "continuous", h.VariabilityOf())
+	require.Equal(t, "internal", h.CausalityOf())
+	require.True(t, *h.Real.Fixed)
+	require.InDelta(t, 1.0, *h.Real.Start, 1e-9)
+
+	require.Nil(t, md.VariableByName("der(h)").Real.Start)
+	require.Equal(t, "parameter", md.VariableByName("e").VariabilityOf())
+	require.Equal(t, "alias", md.VariableByName("height").Alias)
+
+	mode := md.VariableByName("mode")
+	require.Equal(t, "Mode", mode.Enumeration.DeclaredType)
+	require.Equal(t, 2, *mode.Enumeration.Start)
+	require.NotNil(t, mode.DirectDependency)
+
+	require.ElementsMatch(t, []string{"linux64", "win64"}, fmi1.SupportedPlatformsFS(fsys))
+}
+
+func TestReadCoSimulationModelDescription(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml": {Data: []byte(coSimulationDescription)},
+	}
+
+	md, err := fmi1.ReadModelDescriptionFS(fsys)
+
+	require.NoError(t, err)
+	require.Equal(t, fmi1.CoSimulationType, md.Type())
+	require.True(t, md.Capabilities().CanHandleVariableCommunicationStepSize)
+	require.Equal(t, uint32(1), md.Capabilities().MaxOutputDerivativeOrder)
+	require.Equal(t, 1, *md.VariableByName("counter").Integer.Start)
+}
+
+func TestReadModelDescriptionVersion(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml": {Data: []byte(`<fmiModelDescription fmiVersion="2.0" modelName="m" guid="{}"><CoSimulation modelIdentifier="m"/></fmiModelDescription>`)},
+	}
+
+	_, err := fmi1.ReadModelDescriptionFS(fsys)
+	require.ErrorContains(t, err, "unsupported FMI version")
+
+	fsys = fstest.MapFS{
+		"modelDescription.xml": {Data: []byte(`<fmiModelDescription fmiVersion="1.0" modelName="m" modelIdentifier="m" guid="{}"><Implementation/></fmiModelDescription>`)},
+	}
+
+	_, err = fmi1.ReadModelDescriptionFS(fsys)
+	require.ErrorContains(t, err, "CoSimulation_StandAlone")
+}
@@ -0,0 +1,289 @@
+package fmi1
+
+import (
+	"encoding/xml"
+)
+
+// Type returns the interface type of the FMU, which is Co-Simulation if the model description
+// contains an Implementation element and Model Exchange otherwise
+func (md *ModelDescription) Type() Type {
+	if md.Implementation != nil {
+		return CoSimulationType
+	}
+	return ModelExchangeType
+}
+
+// Variables returns the model variables in the order of the model description
+func (md *ModelDescription) Variables() []ScalarVariable {
+	if md.ModelVariables == nil {
+		return nil
+	}
+
+	return md.ModelVariables.ScalarVariable
+}
+
+// VariableByName returns the variable with the given name or nil
+func (md *ModelDescription) VariableByName(name string) *ScalarVariable {
+	variables := md.Variables()
+	for i := range variables {
+		if variables[i].Name == name {
+			return &variables[i]
+		}
+	}
+	return nil
+}
+
+// TypeDefinition returns the type definition with the given name or nil
+func (md *ModelDescription) TypeDefinition(name string) *TypeDefinition {
+	if md.TypeDefinitions == nil {
+		return nil
+	}
+
+	for i := range md.TypeDefinitions.Type {
+		if t := &md.TypeDefinitions.Type[i]; t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Capabilities returns the capabilities of the Co-Simulation slave or nil for a Model Exchange FMU
+func (md *ModelDescription) Capabilities() *Capabilities {
+	switch {
+	case md.Implementation == nil:
+		return nil
+	case md.Implementation.CoSimulationStandAlone != nil:
+		return &md.Implementation.CoSimulationStandAlone.Capabilities
+	case md.Implementation.CoSimulationTool != nil:
+		return &md.Implementation.CoSimulationTool.Capabilities
+	default:
+		return nil
+	}
+}
+
+type ModelDescription struct {
+	XMLName                  xml.Name           `xml:"fmiModelDescription"`
+	FmiVersion               string             `xml:"fmiVersion,attr"`
+	ModelName                string             `xml:"modelName,attr"`
+	ModelIdentifier          string             `xml:"modelIdentifier,attr"`
+	Guid                     string             `xml:"guid,attr"`
+	Description              string             `xml:"description,attr,omitempty"`
+	Author                   string             `xml:"author,attr,omitempty"`
+	Version                  string             `xml:"version,attr,omitempty"`
+	GenerationTool           string             `xml:"generationTool,attr,omitempty"`
+	GenerationDateAndTime    string             `xml:"generationDateAndTime,attr,omitempty"`
+	VariableNamingConvention string             `xml:"variableNamingConvention,attr,omitempty"`
+	NumberOfContinuousStates uint32             `xml:"numberOfContinuousStates,attr"`
+	NumberOfEventIndicators  uint32             `xml:"numberOfEventIndicators,attr"`
+	UnitDefinitions          *UnitDefinitions   `xml:"UnitDefinitions"`
+	TypeDefinitions          *TypeDefinitions   `xml:"TypeDefinitions"`
+	DefaultExperiment        *DefaultExperiment `xml:"DefaultExperiment"`
+	VendorAnnotations        *VendorAnnotations `xml:"VendorAnnotations"`
+	ModelVariables           *ModelVariables    `xml:"ModelVariables"`
+	Implementation           *Implementation    `xml:"Implementation"`
+}
+
+type DisplayUnitDefinition struct {
+	DisplayUnit string  `xml:"displayUnit,attr"`
+	Gain        float64 `xml:"gain,attr,omitempty"`
+	Offset      float64 `xml:"offset,attr,omitempty"`
+}
+
+// BaseUnit is a unit and its display units. Unlike FMI 2.0, FMI 1.0 does not define the unit with respect to the SI units.
+type BaseUnit struct {
+	Unit                  string                  `xml:"unit,attr"`
+	DisplayUnitDefinition []DisplayUnitDefinition `xml:"DisplayUnitDefinition"`
+}
+
+type UnitDefinitions struct {
+	BaseUnit []BaseUnit `xml:"BaseUnit"`
+}
+
+type RealAttributes struct {
+	Quantity         string   `xml:"quantity,attr,omitempty"`
+	Unit             string   `xml:"unit,attr,omitempty"`
+	DisplayUnit      string   `xml:"displayUnit,attr,omitempty"`
+	RelativeQuantity bool     `xml:"relativeQuantity,attr,omitempty"`
+	Min              *float64 `xml:"min,attr,omitempty"`
+	Max              *float64 `xml:"max,attr,omitempty"`
+	Nominal          *float64 `xml:"nominal,attr,omitempty"`
+}
+
+type IntegerAttributes struct {
+	Quantity string `xml:"quantity,attr,omitempty"`
+	Min      *int   `xml:"min,attr,omitempty"`
+	Max      *int   `xml:"max,attr,omitempty"`
+}
+
+type Item struct {
+	Name        string `xml:"name,attr"`
+	Description string `xml:"description,attr,omitempty"`
+}
+
+type RealType struct {
+	RealAttributes
+}
+
+type IntegerType struct {
+	IntegerAttributes
+}
+
+type BooleanType struct{}
+
+type StringType struct{}
+
+// EnumerationType holds the items of an enumeration. The value of an item is its 1-based index.
+type EnumerationType struct {
+	IntegerAttributes
+	Item []Item `xml:"Item"`
+}
+
+// TypeDefinition is a Type element, which contains exactly one of the type elements
+type TypeDefinition struct {
+	Name            string           `xml:"name,attr"`
+	Description     string           `xml:"description,attr,omitempty"`
+	RealType        *RealType        `xml:"RealType"`
+	IntegerType     *IntegerType     `xml:"IntegerType"`
+	BooleanType     *BooleanType     `xml:"BooleanType"`
+	StringType      *StringType      `xml:"StringType"`
+	EnumerationType *EnumerationType `xml:"EnumerationType"`
+}
+
+type TypeDefinitions struct {
+	Type []TypeDefinition `xml:"Type"`
+}
+
+type DefaultExperiment struct {
+	StartTime *float64 `xml:"startTime,attr,omitempty"`
+	StopTime  *float64 `xml:"stopTime,attr,omitempty"`
+	Tolerance *float64 `xml:"tolerance,attr,omitempty"`
+}
+
+type Annotation struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type Tool struct {
+	Name       string       `xml:"name,attr"`
+	Annotation []Annotation `xml:"Annotation"`
+}
+
+type VendorAnnotations struct {
+	Tool []Tool `xml:"Tool"`
+}
+
+// Real is the type element of a Real variable. Fixed is nil if the attribute is not present,
+// which means true if the variable has a start value.
+type Real struct {
+	RealAttributes
+	DeclaredType string   `xml:"declaredType,attr,omitempty"`
+	Start        *float64 `xml:"start,attr,omitempty"`
+	Fixed        *bool    `xml:"fixed,attr,omitempty"`
+}
+
+type Integer struct {
+	IntegerAttributes
+	DeclaredType string `xml:"declaredType,attr,omitempty"`
+	Start        *int   `xml:"start,attr,omitempty"`
+	Fixed        *bool  `xml:"fixed,attr,omitempty"`
+}
+
+type Boolean struct {
+	DeclaredType string `xml:"declaredType,attr,omitempty"`
+	Start        *bool  `xml:"start,attr,omitempty"`
+	Fixed        *bool  `xml:"fixed,attr,omitempty"`
+}
+
+type String struct {
+	DeclaredType string  `xml:"declaredType,attr,omitempty"`
+	Start        *string `xml:"start,attr,omitempty"`
+	Fixed        *bool   `xml:"fixed,attr,omitempty"`
+}
+
+type Enumeration struct {
+	IntegerAttributes
+	DeclaredType string `xml:"declaredType,attr"`
+	Start        *int   `xml:"start,attr,omitempty"`
+	Fixed        *bool  `xml:"fixed,attr,omitempty"`
+}
+
+// DirectDependency lists the inputs that an output depends on directly
+type DirectDependency struct {
+	Name []string `xml:"Name"`
+}
+
+type ScalarVariable struct {
+	Name             string            `xml:"name,attr"`
+	ValueReference   ValueReference    `xml:"valueReference,attr"`
+	Description      string            `xml:"description,attr,omitempty"`
+	Variability      string            `xml:"variability,attr,omitempty"` // "constant", "parameter", "discrete" or "continuous" (default)
+	Causality        string            `xml:"causality,attr,omitempty"`   // "input", "output", "internal" (default) or "none"
+	Alias            string            `xml:"alias,attr,omitempty"`       // "noAlias" (default), "alias" or "negatedAlias"
+	Real             *Real             `xml:"Real"`
+	Integer          *Integer          `xml:"Integer"`
+	Boolean          *Boolean          `xml:"Boolean"`
+	String           *String           `xml:"String"`
+	Enumeration      *Enumeration      `xml:"Enumeration"`
+	DirectDependency *DirectDependency `xml:"DirectDependency"`
+}
+
+// VariabilityOf returns the variability of the variable, which defaults to "continuous"
+func (v *ScalarVariable) VariabilityOf() string {
+	if v.Variability == "" {
+		return "continuous"
+	}
+	return v.Variability
+}
+
+// CausalityOf returns the causality of the variable, which defaults to "internal"
+func (v *ScalarVariable) CausalityOf() string {
+	if v.Causality == "" {
+		return "internal"
+	}
+	return v.Causality
+}
+
+type ModelVariables struct {
+	ScalarVariable []ScalarVariable `xml:"ScalarVariable"`
+}
+
+// Capabilities are the capability flags of a Co-Simulation slave
+type Capabilities struct {
+	CanHandleVariableCommunicationStepSize bool   `xml:"canHandleVariableCommunicationStepSize,attr,omitempty"`
+	CanHandleEvents                        bool   `xml:"canHandleEvents,attr,omitempty"`
+	CanRejectSteps                         bool   `xml:"canRejectSteps,attr,omitempty"`
+	CanInterpolateInputs                   bool   `xml:"canInterpolateInputs,attr,omitempty"`
+	MaxOutputDerivativeOrder               uint32 `xml:"maxOutputDerivativeOrder,attr,omitempty"`
+	CanRunAsynchronuously                  bool   `xml:"canRunAsynchronuously,attr,omitempty"`
+	CanSignalEvents                        bool   `xml:"canSignalEvents,attr,omitempty"`
+	CanBeInstantiatedOnlyOncePerProcess    bool   `xml:"canBeInstantiatedOnlyOncePerProcess,attr,omitempty"`
+	CanNotUseMemoryManagementFunctions     bool   `xml:"canNotUseMemoryManagementFunctions,attr,omitempty"`
+}
+
+type CoSimulationStandAlone struct {
+	Capabilities Capabilities `xml:"Capabilities"`
+}
+
+type File struct {
+	File string `xml:"file,attr"`
+}
+
+// Model is the model of a Co-Simulation slave that is computed by an external tool
+type Model struct {
+	EntryPoint  string `xml:"entryPoint,attr"`
+	ManualStart bool   `xml:"manualStart,attr,omitempty"`
+	Type        string `xml:"type,attr"`
+	File        []File `xml:"File"`
+}
+
+type CoSimulationTool struct {
+	Capabilities Capabilities `xml:"Capabilities"`
+	Model        *Model       `xml:"Model"`
+}
+
+// Implementation is only present in the model description of Co-Simulation slaves
+type Implementation struct {
+	CoSimulationStandAlone *CoSimulationStandAlone `xml:"CoSimulation_StandAlone"`
+	CoSimulationTool       *CoSimulationTool       `xml:"CoSimulation_Tool"`
+}
@@ -0,0 +1,20 @@
+package fmi1
+
+import "go-fmu/pkg/internal/fmi"
+
+type Machine struct {
+	Architecture  string
+	Platform      string
+	LibrarySuffix string
+}
+
+func CurrentMachine() Machine {
+
+	machine := fmi.CurrentMachine()
+
+	return Machine{
+		Architecture:  machine.Architecture,
+		Platform:      machine.Platform(),
+		LibrarySuffix: machine.LibrarySuffix,
+	}
+}
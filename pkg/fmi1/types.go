@@ -0,0 +1,78 @@
+package fmi1
+
+/*
+#include "core.h"
+*/
+import "C"
+
+import "fmt"
+
+type ValueReference uint32 // handle to the value of a variable
+
+type Status int
+
+const (
+	OK      Status = C.fmiOK
+	Warning Status = C.fmiWarning
+	Discard Status = C.fmiDiscard
+	Error   Status = C.fmiError
+	Fatal   Status = C.fmiFatal
+	Pending Status = C.fmiPending // Co-Simulation only
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "Warning"
+	case Discard:
+		return "Discard"
+	case Error:
+		return "Error"
+	case Fatal:
+		return "Fatal"
+	case Pending:
+		return "Pending"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Type is the interface type of an FMU. An FMI 1.0 FMU implements either Model Exchange or Co-Simulation.
+type Type int
+
+const (
+	ModelExchangeType Type = iota
+	CoSimulationType
+)
+
+func (t Type) String() string {
+	switch t {
+	case ModelExchangeType:
+		return "Model Exchange"
+	case CoSimulationType:
+		return "Co-Simulation"
+	default:
+		return "unknown interface type"
+	}
+}
+
+// StatusKind selects the status that is returned by the GetStatus functions of a Co-Simulation slave
+type StatusKind int
+
+const (
+	DoStepStatus       StatusKind = C.fmiDoStepStatus
+	PendingStatus      StatusKind = C.fmiPendingStatus
+	LastSuccessfulTime StatusKind = C.fmiLastSuccessfulTime
+)
+
+// EventInfo is returned by Initialize and EventUpdate of a Model Exchange FMU
+type EventInfo struct {
+	IterationConverged          bool
+	StateValueReferencesChanged bool
+	StateValuesChanged          bool
+	TerminateSimulation         bool
+	UpcomingTimeEvent           bool
+	NextEventTime               float64
+}
@@ -1,9 +1,7 @@
 package fmi2
 
+import "go-fmu/pkg/internal/fmi"
+
 func Transform[To, From any](source []From, f func(int, From) To) []To {
-	vsm := make([]To, 0, len(source))
-	for i, v := range source {
-		vsm = append(vsm, f(i, v))
-	}
-	return vsm
+	return fmi.Transform(source, f)
 }
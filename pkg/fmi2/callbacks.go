@@ -0,0 +1,178 @@
+package fmi2
+
+import (
+	"go-fmu/pkg/internal/fmi"
+	"log/slog"
+	"runtime/cgo"
+	"slices"
+	"sync"
+)
+
+// Logger receives the log messages of an FMU instance
+type Logger = fmi.Logger[Status]
+
+// NewSlogLogger returns a Logger that passes the messages to handler. The level of the records
+// is derived from the status, the instance name, status and category are added as attributes.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return fmi.NewSlogLogger[Status](handler)
+}
+
+// LogCategoryNames returns the names of the log categories that are declared in the model description
+func (md *ModelDescription) LogCategoryNames() []string {
+	names := make([]string, 0)
+	for _, categories := range md.LogCategories {
+		for _, category := range categories.Category {
+			names = append(names, category.Name)
+		}
+	}
+	return names
+}
+
+type InstantiateOption func(*InstantiateOptions)
+
+type InstantiateOptions struct {
+	log          fmi.LogOptions[Status]
+	asynchronous bool
+
+	noLifecycleChecks bool
+}
+
+// WithLogger sets the logger that receives the messages of the instance instead of stdout
+func WithLogger(logger Logger) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Logger = logger
+	}
+}
+
+// WithLogCategories passes only the messages of the given categories to the logger.
+// The categories that an FMU supports are listed by ModelDescription.LogCategoryNames.
+func WithLogCategories(categories ...string) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Categories = categories
+	}
+}
+
+// WithAsynchronousSteps passes the stepFinished callback to the FMU, which allows FMUs with the capability
+// canRunAsynchronuously to return Pending from DoStepAsync and to compute the step in the background
+func WithAsynchronousSteps() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.asynchronous = true
+	}
+}
+
+// WithoutLifecycleChecks disables the tracking of the state of the instance and the checks of the call
+// sequence, e.g. for performance-critical code that is known to call the functions in a legal order
+func WithoutLifecycleChecks() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.noLifecycleChecks = true
+	}
+}
+
+// instanceEnvironment is passed to the callback functions of an instance as componentEnvironment
+type instanceEnvironment struct {
+	logger Logger      // passes only the messages of the categories to log
+	steps  *asyncSteps // the asynchronous steps that wait for stepFinished or nil
+}
+
+// asyncSteps assigns the notifications of the stepFinished callback to the steps that returned Pending in the
+// order in which they were started. The steps are cleared when CancelStep, Reset, SetFMUstate or FreeInstance
+// succeeds, because the FMU is not required to notify a canceled step. As long as a cleared step may still be
+// notified, the receiver of a notification checks with fmi2GetStatus whether its step is still pending.
+type asyncSteps struct {
+	mutex     sync.Mutex
+	waiting   []chan Status // the steps that have not completed, the oldest first
+	abandoned int           // the number of cleared steps that may still be notified
+}
+
+// start registers a new step and returns the channel that receives the notifications, which has room
+// for the late notifications of the abandoned steps
+func (s *asyncSteps) start() chan Status {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	step := make(chan Status, 1+s.abandoned)
+	s.waiting = append(s.waiting, step)
+	return step
+}
+
+// remove unregisters a step that did not return Pending or that has completed
+func (s *asyncSteps) remove(step chan Status) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.waiting = slices.DeleteFunc(s.waiting, func(c chan Status) bool { return c == step })
+}
+
+// finish passes the status to the oldest step, it never blocks the thread of the FMU
+func (s *asyncSteps) finish(status Status) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.waiting) == 0 {
+		// the notification of a cleared step is ignored
+		s.abandoned = max(s.abandoned-1, 0)
+		return
+	}
+
+	select {
+	case s.waiting[0] <- status:
+	default:
+	}
+}
+
+// clear abandons the waiting steps and closes their channels
+func (s *asyncSteps) clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, step := range s.waiting {
+		close(step)
+	}
+
+	s.abandoned += len(s.waiting)
+	s.waiting = nil
+}
+
+// mayBeLate returns whether a notification may belong to a cleared step
+func (s *asyncSteps) mayBeLate() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.abandoned > 0
+}
+
+// late counts a notification that belonged to a cleared step
+func (s *asyncSteps) late() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.abandoned = max(s.abandoned-1, 0)
+}
+
+func newInstanceEnvironment(options *InstantiateOptions) *instanceEnvironment {
+
+	env := &instanceEnvironment{logger: options.log.NewLogger()}
+
+	if options.asynchronous {
+		env.steps = &asyncSteps{}
+	}
+
+	return env
+}
+
+// environmentOf returns the environment of an instance from the handle that was passed to the FMU
+func environmentOf(handle uintptr) *instanceEnvironment {
+	if handle == 0 {
+		return nil
+	}
+
+	env, _ := cgo.Handle(handle).Value().(*instanceEnvironment)
+	return env
+}
+
+// clearSteps abandons the asynchronous steps of an instance that are not notified anymore
+func (c *Component) clearSteps() {
+	if c.handle == 0 {
+		return
+	}
+
+	if env := environmentOf(uintptr(c.handle)); env != nil && env.steps != nil {
+		env.steps.clear()
+	}
+}
@@ -0,0 +1,320 @@
+package fmi2
+
+import (
+	"archive/zip"
+	"embed"
+	"errors"
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+)
+
+// the FMI 2.0 headers that are passed to the compiler in addition to the sources of the FMU
+//
+//go:embed headers/*.h
+var headers embed.FS
+
+type CompileOption func(*CompileOptions)
+
+type CompileOptions struct {
+	compiler string
+	flags    []string
+}
+
+// WithCompiler sets the C compiler instead of $CC or cc
+func WithCompiler(compiler string) CompileOption {
+	return func(o *CompileOptions) {
+		o.compiler = compiler
+	}
+}
+
+// WithCompilerFlags passes additional flags to the compiler, e.g. -O2 or -DNO_FILE
+func WithCompilerFlags(flags ...string) CompileOption {
+	return func(o *CompileOptions) {
+		o.flags = flags
+	}
+}
+
+// WithCompilation compiles the sources of the FMU if it contains no binary for the current platform.
+// The FMU is then always extracted into a temporary directory, the extraction cache and partial
+// extraction are not used.
+func WithCompilation(opts ...CompileOption) LoadOption {
+	return func(o *LoadOptions) {
+		o.compile = true
+		o.compileOptions = opts
+	}
+}
+
+/*
+Compile the sources of an FMU and write the FMU with the binaries for the current platform
+
+Parameters:
+
+	filename  filename of the FMU
+	output    filename of the rebuilt FMU, which may be the same as filename
+	opts      the options of the compiler
+
+Returns:
+
+	an error if the FMU contains no sources or they could not be compiled
+*/
+func Compile(filename string, output string, opts ...CompileOption) error {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	directory, err := os.MkdirTemp("", "go-fmu-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(directory)
+
+	if err := fmi.Unzip(&r.Reader, directory, nil); err != nil {
+		return err
+	}
+
+	if err := CompileSources(directory, opts...); err != nil {
+		return err
+	}
+
+	return writeArchive(directory, output)
+}
+
+/*
+Compile the sources of an extracted FMU into binaries/<platform>/<modelIdentifier>.<suffix>
+
+Parameters:
+
+	directory  the directory of the extracted FMU
+	opts       the options of the compiler
+
+Returns:
+
+	an error if the FMU contains no sources or they could not be compiled
+*/
+func CompileSources(directory string, opts ...CompileOption) error {
+
+	md, err := ReadModelDescriptionFS(os.DirFS(directory), nil)
+	if err != nil {
+		return err
+	}
+
+	compiled := make(map[string]bool)
+
+	for _, fmiType := range []Type{ModelExchangeType, CoSimulationType} {
+
+		identifier, err := md.ModelIdentifier(fmiType)
+		if err != nil || compiled[identifier] || md.SourceFileNames(fmiType) == nil {
+			continue
+		}
+
+		if _, err := compileLibrary(md, directory, fmiType, opts); err != nil {
+			return err
+		}
+
+		compiled[identifier] = true
+	}
+
+	if len(compiled) == 0 {
+		return errors.New("the FMU contains no source files")
+	}
+
+	return nil
+}
+
+// compileLibrary compiles the source files of the interface type and returns the path of the shared library
+func compileLibrary(md *ModelDescription, directory string, fmiType Type, opts []CompileOption) (string, error) {
+
+	options := &CompileOptions{compiler: os.Getenv("CC")}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.compiler == "" {
+		options.compiler = "cc"
+	}
+
+	identifier, err := md.ModelIdentifier(fmiType)
+	if err != nil {
+		return "", err
+	}
+
+	sourceFiles := md.SourceFileNames(fmiType)
+	if sourceFiles == nil {
+		return "", fmt.Errorf("the FMU contains no source files for %s", fmiType)
+	}
+
+	sources := filepath.Join(directory, "sources")
+
+	for _, name := range sourceFiles {
+		if _, err := os.Stat(filepath.Join(sources, filepath.FromSlash(name))); err != nil {
+			return "", fmt.Errorf("the source file %s is missing in the sources directory of the FMU", name)
+		}
+	}
+
+	includes, err := os.MkdirTemp("", "go-fmu-headers-*")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.RemoveAll(includes)
+
+	if err := os.CopyFS(includes, headers); err != nil {
+		return "", err
+	}
+
+	machine := CurrentMachine()
+	library := path.Join("binaries", machine.Platform, identifier+"."+machine.LibrarySuffix)
+	output := filepath.Join(directory, filepath.FromSlash(library))
+
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return "", err
+	}
+
+	// the headers of the FMU take precedence over the standard headers. The shared library must export the
+	// functions without a prefix, so the prefix is defined empty, which also prevents sources that
+	// derive it from MODEL_IDENTIFIER from defining it.
+	args := []string{"-shared", "-fPIC", "-DFMI2_FUNCTION_PREFIX=", "-I" + sources, "-I" + filepath.Join(includes, "headers"), "-o", output}
+	args = append(args, options.flags...)
+	args = append(args, sourceFiles...)
+	args = append(args, "-lm")
+
+	cmd := exec.Command(options.compiler, args...)
+	cmd.Dir = sources
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to compile the sources of %s: %w\n%s", identifier, err, out)
+	}
+
+	return library, nil
+}
+
+// newFromSources extracts the FMU into a temporary directory and compiles the sources of the interface
+// type. noBinary is returned if the FMU contains no sources.
+func newFromSources(r *zip.Reader, fmiType Type, options *LoadOptions, noBinary error) (*Fmu2, error) {
+
+	md, err := ReadModelDescriptionFS(r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.SourceFileNames(fmiType) == nil {
+		return nil, noBinary
+	}
+
+	directory, err := os.MkdirTemp("", "go-fmu-*")
+	if err != nil {
+		return nil, err
+	}
+
+	fmu, err := func() (*Fmu2, error) {
+		if err := fmi.Unzip(r, directory, nil); err != nil {
+			return nil, err
+		}
+
+		library, err := compileLibrary(md, directory, fmiType, options.compileOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		return load(md, directory, library, fmiType)
+	}()
+
+	if err != nil {
+		os.RemoveAll(directory)
+		return nil, err
+	}
+
+	fmu.ownsDirectory = true
+
+	return fmu, nil
+}
+
+// writeArchive writes the files in directory to the zip archive filename. The archive is written to
+// a temporary file that replaces filename when it is complete.
+func writeArchive(directory string, filename string) error {
+
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := f.Chmod(0644); err != nil {
+		return err
+	}
+
+	w := zip.NewWriter(f)
+
+	err = filepath.WalkDir(directory, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == directory {
+			return err
+		}
+
+		rel, err := filepath.Rel(directory, name)
+		if err != nil {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			header.Name += "/"
+			_, err := w.CreateHeader(header)
+			return err
+		}
+
+		header.Method = zip.Deflate
+
+		fw, err := w.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+
+		src, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+
+		defer src.Close()
+
+		_, err = io.Copy(fw, src)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
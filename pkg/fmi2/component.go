@@ -1,13 +1,49 @@
 package fmi2
 
 /*
+#include <stdlib.h>
 #include "headers/fmi2Functions.h"
 */
 import "C"
 
+import (
+	"context"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
 type Component struct {
 	fmu       *Fmu2
 	component C.fmi2Component
+	fmuType   Type
+	mutex     sync.Mutex // guards fatal and state, which are also updated by the goroutine of DoStepAsync
+	fatal     bool       // the instance returned Fatal and must not be called anymore
+	state     State      // the state of the life cycle of the instance
+	handle    cgo.Handle
+	callbacks *C.fmi2CallbackFunctions
+
+	lifecycleChecks bool // track the state and reject calls that are not allowed in it
+}
+
+// isFatal returns whether the instance returned Fatal
+func (c *Component) isFatal() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.fatal
+}
+
+// release frees the callback functions and the environment of the instance
+func (c *Component) release() {
+	if c.callbacks != nil {
+		C.free(unsafe.Pointer(c.callbacks))
+		c.callbacks = nil
+	}
+
+	if c.handle != 0 {
+		c.handle.Delete()
+		c.handle = 0
+	}
 }
 
 /* SetDebugLogging controls the debug logging that is output via the logger callback function by the FMU.
@@ -58,7 +94,7 @@ func (c *Component) Terminate() error {
  * All variables have their default values. Before starting a new run, SetupExperiment and
  * EnterInitializationMode have to be called. */
 func (c *Component) Reset() error {
-	return c.fmu.Terminate(c)
+	return c.fmu.Reset(c)
 }
 
 /* GetReal gets actual values of variables by providing their variable references. */
@@ -176,6 +212,14 @@ func (c *Component) DoStep(currentCommunicationPoint float64, communicationStepS
 	return c.fmu.DoStep(c, currentCommunicationPoint, communicationStepSize, noSetFMUStatePriorToCurrentPoint)
 }
 
+/* DoStepAsync starts the computation of a time step, which the FMU may execute asynchronously if the instance
+ * was created with WithAsynchronousSteps. The returned channel receives the result when the step is completed.
+ * If ctx is canceled before the step is completed, the step is canceled with CancelStep.
+ */
+func (c *Component) DoStepAsync(ctx context.Context, currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) <-chan error {
+	return c.fmu.DoStepAsync(ctx, c, currentCommunicationPoint, communicationStepSize, noSetFMUStatePriorToCurrentPoint)
+}
+
 /* CancelStep can be called if DoStep returned Pending in order to stop the current asynchronous execution.
  * The master calls this function if, for example, the co-simulation run is stopped by the user or one of the slaves.
  * Afterwards only calls to Reset, FreeInstance, or SetFMUstate are valid to exit the step Canceled state.
@@ -3,25 +3,59 @@
 #include <stdarg.h>
 #include <stdint.h>
 #include <stdio.h>
+#include <stdlib.h>
 
 typedef const char cchar_t;
 
-extern void goLogger(void *user_data, fmi2String instanceName, fmi2Status status, fmi2String category, cchar_t *message);
-extern void goStepFinished(void *user_data, fmi2Status status);
+extern void goLogger(uintptr_t environment, uintptr_t caller, fmi2String instanceName, fmi2Status status, fmi2String category, cchar_t *message);
+extern void goStepFinished(uintptr_t environment, fmi2Status status);
 
 void Logger(fmi2ComponentEnvironment componentEnvironment, fmi2String instanceName, fmi2Status status, fmi2String category, fmi2String message, ...) {
 
-	char buffer[1024];
-	va_list ap;
+	va_list ap, aq;
 	va_start(ap, message);
-	vsnprintf(buffer, sizeof(buffer), message, ap);
+
+	// format the message into a buffer of the required size, so long messages are not truncated
+	va_copy(aq, ap);
+	int length = vsnprintf(NULL, 0, message, aq);
+	va_end(aq);
+
+	char *buffer = length < 0 ? NULL : malloc(length + 1);
+	if (buffer) {
+		vsnprintf(buffer, length + 1, message, ap);
+	}
+
 	va_end(ap);
 
-	goLogger(componentEnvironment, instanceName, status, category, buffer);
+	// the library of the caller identifies the FMU if the componentEnvironment is missing
+	goLogger((uintptr_t)componentEnvironment, (uintptr_t)__builtin_return_address(0), instanceName, status, category, buffer ? buffer : message);
+	free(buffer);
 }
 
 void StepFinished(fmi2ComponentEnvironment componentEnvironment, fmi2Status status) {
-	goStepFinished(componentEnvironment, status);
+	goStepFinished((uintptr_t)componentEnvironment, status);
+}
+
+fmi2CallbackFunctions *NewCallbackFunctions(uintptr_t environment, fmi2Boolean asynchronous) {
+
+	fmi2CallbackFunctions *callbacks = malloc(sizeof(fmi2CallbackFunctions));
+	if (callbacks) {
+		callbacks->logger               = Logger;
+		callbacks->allocateMemory       = calloc;
+		callbacks->freeMemory           = free;
+		callbacks->stepFinished         = asynchronous ? StepFinished : NULL;
+		callbacks->componentEnvironment = (fmi2ComponentEnvironment)environment;
+	}
+
+	return callbacks;
+}
+
+// OpenLibrary loads the shared library and returns the message of dlerror if loading fails.
+// dlerror has to be called on the same thread as dlopen, which cannot be guaranteed from Go.
+void *OpenLibrary(const char *filename, const char **error) {
+	void *handle = dlopen(filename, RTLD_LAZY);
+	*error = handle ? NULL : dlerror();
+	return handle;
 }
 
 const char *GetTypesPlatform(void *f) {
@@ -41,15 +75,7 @@ void FreeInstance(void *f, fmi2Component component) {
 }
 
 fmi2Component Instantiate(void *f, fmi2String instanceName, fmi2Type fmuType, fmi2String fmuGUID, fmi2String fmuResourceLocation, const fmi2CallbackFunctions *functions, fmi2Boolean visible, fmi2Boolean loggingOn) {
-
-	fmi2CallbackFunctions callbacks;
-	callbacks.logger               = Logger;
-	callbacks.allocateMemory       = calloc;
-	callbacks.freeMemory           = free;
-	callbacks.stepFinished         = NULL;
-	callbacks.componentEnvironment = NULL;
-
-	return ((fmi2InstantiateTYPE *)f)(instanceName, fmuType, fmuGUID, fmuResourceLocation, &callbacks, visible, loggingOn);
+	return ((fmi2InstantiateTYPE *)f)(instanceName, fmuType, fmuGUID, fmuResourceLocation, functions, visible, loggingOn);
 }
 
 fmi2Status SetupExperiment(void *f, fmi2Component c, fmi2Boolean relativeToleranceDefined, fmi2Real relativeTolerance, fmi2Real tStart, fmi2Boolean tStopDefined, fmi2Real tStop) {
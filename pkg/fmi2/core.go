@@ -8,35 +8,57 @@ import "C"
 
 import (
 	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"go-fmu/pkg/internal/fmi"
 	"io"
+	"io/fs"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
-	"slices"
+	"runtime/cgo"
+	"sort"
 	"strings"
 	"unsafe"
 )
 
 //export goLogger
-func goLogger(env ComponentEnvironment, instanceName C.fmi2String, status C.fmi2Status, category C.fmi2String, message *C.cchar_t) {
+func goLogger(handle C.uintptr_t, caller C.uintptr_t, instanceName C.fmi2String, status C.fmi2Status, category C.fmi2String, message *C.cchar_t) {
 
-	_ = env
-	fmt.Printf("[Name: %s, Status: %d, Category: %s] %s\n", C.GoString(instanceName), int(status), C.GoString(category), C.GoString(message))
+	env := environmentOf(uintptr(handle))
+
+	// the message of an FMU that does not pass the componentEnvironment is attributed by library and instance name
+	if handle == 0 {
+		env, _ = fmi.InstantiatingEnvironment(uintptr(caller), C.GoString(instanceName)).(*instanceEnvironment)
+	}
+
+	if env == nil {
+		fmi.DefaultLogger(C.GoString(instanceName), Status(status), C.GoString(category), C.GoString(message))
+		return
+	}
+
+	env.logger(C.GoString(instanceName), Status(status), C.GoString(category), C.GoString(message))
 }
 
 //export goStepFinished
-func goStepFinished(env ComponentEnvironment, status Status) {
+func goStepFinished(handle C.uintptr_t, status C.fmi2Status) {
 
-	_ = env
-	_ = status
-	fmt.Println("Step Finished!")
+	if env := environmentOf(uintptr(handle)); env != nil && env.steps != nil {
+		env.steps.finish(Status(status))
+	}
 }
 
 type Fmu2 struct {
-	Directory    string
-	moduleHandle unsafe.Pointer
+	Directory     string
+	ownsDirectory bool // the directory is removed by Close
+	moduleHandle  unsafe.Pointer
+
+	modelDescription *ModelDescription
+	variables        map[string]*ScalarVariable // the model variables by name
 
 	// Common Functions
 	getVersionPtr               unsafe.Pointer
@@ -87,13 +109,33 @@ type Fmu2 struct {
 	getIntegerStatusPtr         unsafe.Pointer
 	getBooleanStatusPtr         unsafe.Pointer
 	getStringStatusPtr          unsafe.Pointer
+
+	warningHandler WarningHandler
+	library        *fmi.Library // the loaded library, which attributes the messages without componentEnvironment
+}
+
+// ModelDescription returns the model description of the FMU
+func (f *Fmu2) ModelDescription() *ModelDescription {
+	return f.modelDescription
 }
 
+// Close unloads the shared library and removes the extracted files unless they are shared through the extraction cache
 func (f *Fmu2) Close() error {
+	if f.library != nil {
+		f.library.Close()
+		f.library = nil
+	}
+
 	if f.moduleHandle != nil {
 		C.dlclose(f.moduleHandle)
 		f.moduleHandle = nil
 	}
+
+	if f.ownsDirectory {
+		f.ownsDirectory = false
+		return os.RemoveAll(f.Directory)
+	}
+
 	return nil
 }
 
@@ -110,7 +152,7 @@ func (f *Fmu2) GetVersion() string {
  * as documented in this specification, has fmi2TypesPlatform set to "default" (so this function usually returns "default").
  */
 func (f *Fmu2) GetTypesPlatform() string {
-	return C.GoString(C.GetVersion(f.getTypesPlatformPtr))
+	return C.GoString(C.GetTypesPlatform(f.getTypesPlatformPtr))
 }
 
 /* SetDebugLogging controls the debug logging that is output via the logger callback function by the FMU.
@@ -121,6 +163,10 @@ func (f *Fmu2) GetTypesPlatform() string {
  */
 func (f *Fmu2) SetDebugLogging(c *Component, loggingOn bool, categories []string) error {
 
+	if err := f.available(c, f.setDebugLoggingPtr, "fmi2SetDebugLogging"); err != nil {
+		return err
+	}
+
 	cats := Transform(categories, func(i int, v string) C.fmi2String { return C.CString(v) })
 
 	defer func() {
@@ -129,8 +175,8 @@ func (f *Fmu2) SetDebugLogging(c *Component, loggingOn bool, categories []string
 		}
 	}()
 
-	if status := Status(C.SetDebugLogging(f.setDebugLoggingPtr, c.component, toBool(loggingOn), C.size_t(len(categories)), &cats[0])); status != OK {
-		return fmt.Errorf("error setting debug logging: %v", status)
+	if err := f.check(c, "fmi2SetDebugLogging", C.SetDebugLogging(f.setDebugLoggingPtr, c.component, toBool(loggingOn), C.size_t(len(categories)), &cats[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -140,14 +186,32 @@ func (f *Fmu2) SetDebugLogging(c *Component, loggingOn bool, categories []string
  * If the passed component is nil, the function call is ignored (does not have an effect).
  */
 func (f *Fmu2) FreeInstance(c *Component) {
-	C.FreeInstance(f.freeInstancePtr, c.component)
+	if c == nil || c.component == nil {
+		return
+	}
+
+	// after Fatal no function of the instance may be called, not even FreeInstance
+	if !c.isFatal() {
+		C.FreeInstance(f.freeInstancePtr, c.component)
+	}
+
+	c.clearSteps()
+	c.component = nil
+	c.release()
 }
 
 /* Instantiate returns a new instance of an FMU. If nil is returned, then instantiation failed.
- * In that case, "functions.logger" is called with detailed information about the reason.
+ * In that case, the logger is called with detailed information about the reason.
  * An FMU can be instantiated many times (provided capability flag canBeInstantiatedOnlyOncePerProcess = false).
+ * The messages of the instance are printed to stdout unless a logger is passed with WithLogger.
  */
-func (f *Fmu2) Instantiate(instanceName string, fmuType Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool) *Component {
+func (f *Fmu2) Instantiate(instanceName string, fmuType Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool, opts ...InstantiateOption) *Component {
+
+	options := &InstantiateOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
 
 	cInstanceName := C.CString(instanceName)
 	defer C.free(unsafe.Pointer(cInstanceName))
@@ -159,15 +223,33 @@ func (f *Fmu2) Instantiate(instanceName string, fmuType Type, fmuGuid string, re
 	cVisible := toBool(visible)
 	cLoggingOn := toBool(loggingOn)
 
-	// TODO(eteran): set the logger callback properly
-	// TODO(eteran): set the step finished callback properly
+	// the FMU may keep a pointer to the callback functions, so they live as long as the instance
+	handle := cgo.NewHandle(newInstanceEnvironment(options))
+	callbacks := C.NewCallbackFunctions(C.uintptr_t(handle), toBool(options.asynchronous))
+	if callbacks == nil {
+		handle.Delete()
+		return nil
+	}
+
+	c := &Component{
+		fmu:             f,
+		fmuType:         fmuType,
+		state:           StateInstantiated,
+		handle:          handle,
+		callbacks:       callbacks,
+		lifecycleChecks: !options.noLifecycleChecks,
+	}
 
-	comp := C.Instantiate(f.instantiatePtr, cInstanceName, cType, cGuid, cResourceLocation, nil, cVisible, cLoggingOn)
-	if comp == nil {
+	f.library.Instantiate(instanceName, handle.Value(), func() {
+		c.component = C.Instantiate(f.instantiatePtr, cInstanceName, cType, cGuid, cResourceLocation, callbacks, cVisible, cLoggingOn)
+	})
+
+	if c.component == nil {
+		c.release()
 		return nil
 	}
 
-	return &Component{fmu: f, component: comp}
+	return c
 }
 
 type SetupExperimentOption func(*SetupExperimentOptions)
@@ -193,12 +275,26 @@ func WithStopTime(tStop float64) SetupExperimentOption {
 	}
 }
 
+// RelativeTolerance returns the relative tolerance and whether it is defined, e.g. to forward the options to another process
+func (o *SetupExperimentOptions) RelativeTolerance() (float64, bool) {
+	return o.relativeTolerance, o.relativeToleranceDefined
+}
+
+// StopTime returns the stop time and whether it is defined
+func (o *SetupExperimentOptions) StopTime() (float64, bool) {
+	return o.tStop, o.tStopDefined
+}
+
 /* SetupExperiment informs the FMU to setup the experiment.
  * This function must be called after Instantiate and before EnterInitializationMode is called.
  * Arguments toleranceDefined and tolerance depend on the FMU type.
  */
 func (f *Fmu2) SetupExperiment(c *Component, tStart float64, opts ...SetupExperimentOption) error {
 
+	if err := f.available(c, f.setupExperimentPtr, "fmi2SetupExperiment"); err != nil {
+		return err
+	}
+
 	options := &SetupExperimentOptions{
 		relativeToleranceDefined: false,
 		relativeTolerance:        0.0,
@@ -210,8 +306,8 @@ func (f *Fmu2) SetupExperiment(c *Component, tStart float64, opts ...SetupExperi
 		opt(options)
 	}
 
-	if status := Status(C.SetupExperiment(f.setupExperimentPtr, c.component, toBool(options.relativeToleranceDefined), C.fmi2Real(options.relativeTolerance), C.fmi2Real(tStart), toBool(options.tStopDefined), C.fmi2Real(options.tStop))); status != OK {
-		return fmt.Errorf("error setting up experiment: %v", status)
+	if err := f.check(c, "fmi2SetupExperiment", C.SetupExperiment(f.setupExperimentPtr, c.component, toBool(options.relativeToleranceDefined), C.fmi2Real(options.relativeTolerance), C.fmi2Real(tStart), toBool(options.tStopDefined), C.fmi2Real(options.tStop))); err != nil {
+		return err
 	}
 
 	return nil
@@ -223,8 +319,12 @@ func (f *Fmu2) SetupExperiment(c *Component, tStart float64, opts ...SetupExperi
  * Furthermore, SetupExperiment must be called at least once before calling EnterInitializationMode,
  * in order that startTime is defined. */
 func (f *Fmu2) EnterInitializationMode(c *Component) error {
-	if status := Status(C.EnterInitializationMode(f.setupExperimentPtr, c.component)); status != OK {
-		return fmt.Errorf("error entering initialization mode: %v", status)
+	if err := f.available(c, f.enterInitializationModePtr, "fmi2EnterInitializationMode"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2EnterInitializationMode", C.EnterInitializationMode(f.enterInitializationModePtr, c.component)); err != nil {
+		return err
 	}
 
 	return nil
@@ -235,8 +335,12 @@ func (f *Fmu2) EnterInitializationMode(c *Component) error {
  * that is, all continuous-time and active discrete-time equations are available.
  */
 func (f *Fmu2) ExitInitializationMode(c *Component) error {
-	if status := Status(C.ExitInitializationMode(f.setupExperimentPtr, c.component)); status != OK {
-		return fmt.Errorf("error exiting initialization mode: %v", status)
+	if err := f.available(c, f.exitInitializationModePtr, "fmi2ExitInitializationMode"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2ExitInitializationMode", C.ExitInitializationMode(f.exitInitializationModePtr, c.component)); err != nil {
+		return err
 	}
 
 	return nil
@@ -247,8 +351,12 @@ func (f *Fmu2) ExitInitializationMode(c *Component) error {
  * It is not allowed to call this function after one of the functions returned with a status flag of Error or Fatal.
  */
 func (f *Fmu2) Terminate(c *Component) error {
-	if status := Status(C.Terminate(f.setupExperimentPtr, c.component)); status != OK {
-		return fmt.Errorf("error terminating: %v", status)
+	if err := f.available(c, f.terminatePtr, "fmi2Terminate"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2Terminate", C.Terminate(f.terminatePtr, c.component)); err != nil {
+		return err
 	}
 
 	return nil
@@ -259,21 +367,31 @@ func (f *Fmu2) Terminate(c *Component) error {
  * All variables have their default values. Before starting a new run, SetupExperiment and
  * EnterInitializationMode have to be called. */
 func (f *Fmu2) Reset(c *Component) error {
-	if status := Status(C.Reset(f.setupExperimentPtr, c.component)); status != OK {
-		return fmt.Errorf("error resetting: %v", status)
+	if err := f.available(c, f.resetPtr, "fmi2Reset"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2Reset", C.Reset(f.resetPtr, c.component)); err != nil {
+		return err
 	}
 
+	c.clearSteps()
+
 	return nil
 }
 
 /* GetReal gets actual values of variables by providing their variable references. */
 func (f *Fmu2) GetReal(c *Component, vr []ValueReference) ([]float64, error) {
 
+	if err := f.available(c, f.getRealPtr, "fmi2GetReal"); err != nil {
+		return nil, err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	values := make([]C.fmi2Real, len(vr))
 
-	if status := Status(C.GetReal(f.getRealPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); status != OK {
-		return nil, fmt.Errorf("error getting real values: %v", status)
+	if err := f.check(c, "fmi2GetReal", C.GetReal(f.getRealPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(values, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -283,11 +401,15 @@ func (f *Fmu2) GetReal(c *Component, vr []ValueReference) ([]float64, error) {
 /* GetInteger gets actual values of variables by providing their variable references. */
 func (f *Fmu2) GetInteger(c *Component, vr []ValueReference) ([]int, error) {
 
+	if err := f.available(c, f.getIntegerPtr, "fmi2GetInteger"); err != nil {
+		return nil, err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	values := make([]C.fmi2Integer, len(vr))
 
-	if status := Status(C.GetInteger(f.getIntegerPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); status != OK {
-		return nil, fmt.Errorf("error getting integer values: %v", status)
+	if err := f.check(c, "fmi2GetInteger", C.GetInteger(f.getIntegerPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(values, func(i int, v C.fmi2Integer) int { return int(v) })
@@ -297,11 +419,15 @@ func (f *Fmu2) GetInteger(c *Component, vr []ValueReference) ([]int, error) {
 /* GetBoolean gets actual values of variables by providing their variable references. */
 func (f *Fmu2) GetBoolean(c *Component, vr []ValueReference) ([]bool, error) {
 
+	if err := f.available(c, f.getBooleanPtr, "fmi2GetBoolean"); err != nil {
+		return nil, err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	values := make([]C.fmi2Boolean, len(vr))
 
-	if status := Status(C.GetBoolean(f.getBooleanPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); status != OK {
-		return nil, fmt.Errorf("error getting boolean values: %v", status)
+	if err := f.check(c, "fmi2GetBoolean", C.GetBoolean(f.getBooleanPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(values, func(i int, v C.fmi2Boolean) bool { return v != 0 })
@@ -311,11 +437,15 @@ func (f *Fmu2) GetBoolean(c *Component, vr []ValueReference) ([]bool, error) {
 /* GetString gets actual values of variables by providing their variable references. */
 func (f *Fmu2) GetString(c *Component, vr []ValueReference) ([]string, error) {
 
+	if err := f.available(c, f.getStringPtr, "fmi2GetString"); err != nil {
+		return nil, err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	values := make([]C.fmi2String, len(vr))
 
-	if status := Status(C.GetString(f.getStringPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); status != OK {
-		return nil, fmt.Errorf("error getting string values: %v", status)
+	if err := f.check(c, "fmi2GetString", C.GetString(f.getStringPtr, c.component, &refs[0], C.size_t(len(vr)), &values[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(values, func(i int, v C.fmi2String) string { return C.GoString(v) })
@@ -325,11 +455,15 @@ func (f *Fmu2) GetString(c *Component, vr []ValueReference) ([]string, error) {
 /* SetReal sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables */
 func (f *Fmu2) SetReal(c *Component, vr []ValueReference, value []float64) error {
 
+	if err := f.available(c, f.setRealPtr, "fmi2SetReal"); err != nil {
+		return err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	vals := Transform(value, func(i int, v float64) C.fmi2Real { return C.fmi2Real(v) })
 
-	if status := Status(C.SetReal(f.setRealPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); status != OK {
-		return fmt.Errorf("error setting real values: %v", status)
+	if err := f.check(c, "fmi2SetReal", C.SetReal(f.setRealPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -338,11 +472,15 @@ func (f *Fmu2) SetReal(c *Component, vr []ValueReference, value []float64) error
 /* SetInteger sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables */
 func (f *Fmu2) SetInteger(c *Component, vr []ValueReference, value []int) error {
 
+	if err := f.available(c, f.setIntegerPtr, "fmi2SetInteger"); err != nil {
+		return err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	vals := Transform(value, func(i int, v int) C.fmi2Integer { return C.fmi2Integer(v) })
 
-	if status := Status(C.SetInteger(f.setIntegerPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); status != OK {
-		return fmt.Errorf("error setting integer values: %v", status)
+	if err := f.check(c, "fmi2SetInteger", C.SetInteger(f.setIntegerPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -351,11 +489,15 @@ func (f *Fmu2) SetInteger(c *Component, vr []ValueReference, value []int) error
 /* SetBoolean sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables */
 func (f *Fmu2) SetBoolean(c *Component, vr []ValueReference, value []bool) error {
 
+	if err := f.available(c, f.setBooleanPtr, "fmi2SetBoolean"); err != nil {
+		return err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	vals := Transform(value, func(i int, v bool) C.fmi2Boolean { return toBool(v) })
 
-	if status := Status(C.SetBoolean(f.setBooleanPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); status != OK {
-		return fmt.Errorf("error setting boolean values: %v", status)
+	if err := f.check(c, "fmi2SetBoolean", C.SetBoolean(f.setBooleanPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -364,6 +506,10 @@ func (f *Fmu2) SetBoolean(c *Component, vr []ValueReference, value []bool) error
 /* SetString sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables */
 func (f *Fmu2) SetString(c *Component, vr []ValueReference, value []string) error {
 
+	if err := f.available(c, f.setStringPtr, "fmi2SetString"); err != nil {
+		return err
+	}
+
 	refs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	vals := Transform(value, func(i int, v string) C.fmi2String { return C.CString(v) })
 
@@ -373,8 +519,8 @@ func (f *Fmu2) SetString(c *Component, vr []ValueReference, value []string) erro
 		}
 	}()
 
-	if status := Status(C.SetString(f.setStringPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); status != OK {
-		return fmt.Errorf("error setting string values: %v", status)
+	if err := f.check(c, "fmi2SetString", C.SetString(f.setStringPtr, c.component, &refs[0], C.size_t(len(vr)), &vals[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -387,11 +533,15 @@ func (f *Fmu2) SetString(c *Component, vr []ValueReference, value []string) erro
  */
 func (f *Fmu2) GetFMUstate(c *Component) (FmuState, error) {
 
+	if err := f.available(c, f.getFMUstatePtr, "fmi2GetFMUstate"); err != nil {
+		return FmuState{}, err
+	}
+
 	var state unsafe.Pointer
 	ptr := unsafe.Pointer(&state)
 
-	if status := Status(C.GetFMUstate(f.getFMUstatePtr, c.component, ptr)); status != OK {
-		return FmuState{}, fmt.Errorf("error getting FMU state: %v", status)
+	if err := f.check(c, "fmi2GetFMUstate", C.GetFMUstate(f.getFMUstatePtr, c.component, ptr)); err != nil {
+		return FmuState{}, err
 	}
 
 	return FmuState{state}, nil
@@ -402,12 +552,18 @@ func (f *Fmu2) GetFMUstate(c *Component) (FmuState, error) {
  */
 func (f *Fmu2) SetFMUstate(c *Component, state FmuState) error {
 
+	if err := f.available(c, f.setFMUstatePtr, "fmi2SetFMUstate"); err != nil {
+		return err
+	}
+
 	ptr := state.state
 
-	if status := Status(C.SetFMUstate(f.setFMUstatePtr, c.component, ptr)); status != OK {
-		return fmt.Errorf("error setting FMU state: %v", status)
+	if err := f.check(c, "fmi2SetFMUstate", C.SetFMUstate(f.setFMUstatePtr, c.component, ptr)); err != nil {
+		return err
 	}
 
+	c.clearSteps()
+
 	return nil
 }
 
@@ -417,11 +573,15 @@ func (f *Fmu2) SetFMUstate(c *Component, state FmuState) error {
  * The function returns a null pointer in argument FMUstate. */
 func (f *Fmu2) FreeFMUstate(c *Component, state FmuState) error {
 
+	if err := f.available(c, f.freeFMUstatePtr, "fmi2FreeFMUstate"); err != nil {
+		return err
+	}
+
 	s := state.state
 	ptr := unsafe.Pointer(&s)
 
-	if status := Status(C.FreeFMUstate(f.freeFMUstatePtr, c.component, ptr)); status != OK {
-		return fmt.Errorf("error freeing FMU state: %v", status)
+	if err := f.check(c, "fmi2FreeFMUstate", C.FreeFMUstate(f.freeFMUstatePtr, c.component, ptr)); err != nil {
+		return err
 	}
 
 	return nil
@@ -430,16 +590,24 @@ func (f *Fmu2) FreeFMUstate(c *Component, state FmuState) error {
 /* SerializeFMUstate serializes the data which is referenced by pointer FMUstate and copies this data in to the returned byte slice. */
 func (f *Fmu2) SerializeFMUstate(c *Component, state *FmuState) ([]byte, error) {
 
+	if err := f.available(c, f.serializedFMUstateSizePtr, "fmi2SerializedFMUstateSize"); err != nil {
+		return nil, err
+	}
+
+	if err := f.available(c, f.serializeFMUstatePtr, "fmi2SerializeFMUstate"); err != nil {
+		return nil, err
+	}
+
 	var sz C.size_t
-	if status := Status(C.SerializedFMUstateSize(f.serializedFMUstateSizePtr, c.component, unsafe.Pointer(state.state), &sz)); status != OK {
-		return nil, fmt.Errorf("error getting serialized FMU state size: %v", status)
+	if err := f.check(c, "fmi2SerializedFMUstateSize", C.SerializedFMUstateSize(f.serializedFMUstateSizePtr, c.component, unsafe.Pointer(state.state), &sz)); err != nil {
+		return nil, err
 	}
 
 	array := make([]byte, int(sz))
 	ptr := (unsafe.Pointer(&array[0]))
 
-	if status := Status(C.SerializeFMUstate(f.serializeFMUstatePtr, c.component, unsafe.Pointer(state.state), ptr, sz)); status != OK {
-		return nil, fmt.Errorf("error serializing FMU state: %v", status)
+	if err := f.check(c, "fmi2SerializeFMUstate", C.SerializeFMUstate(f.serializeFMUstatePtr, c.component, unsafe.Pointer(state.state), ptr, sz)); err != nil {
+		return nil, err
 	}
 
 	return array, nil
@@ -448,9 +616,13 @@ func (f *Fmu2) SerializeFMUstate(c *Component, state *FmuState) ([]byte, error)
 /* DeSerializeFMUstate deserializes the byte slice, constructs a copy of the FMU state and returns FMUstate, the pointer to this copy. [The simulation is restarted at this state, when calling fmi2SetFMUState with FMUstate.] */
 func (f *Fmu2) DeSerializeFMUstate(c *Component, state *FmuState, serializedState []byte) error {
 
+	if err := f.available(c, f.deSerializeFMUstatePtr, "fmi2DeSerializeFMUstate"); err != nil {
+		return err
+	}
+
 	ptr := (unsafe.Pointer(&serializedState[0]))
-	if status := Status(C.DeSerializeFMUstate(f.deSerializeFMUstatePtr, c.component, ptr, C.size_t(len(serializedState)), unsafe.Pointer(state.state))); status != OK {
-		return fmt.Errorf("error deserializing FMU state: %v", status)
+	if err := f.check(c, "fmi2DeSerializeFMUstate", C.DeSerializeFMUstate(f.deSerializeFMUstatePtr, c.component, ptr, C.size_t(len(serializedState)), unsafe.Pointer(state.state))); err != nil {
+		return err
 	}
 
 	return nil
@@ -465,10 +637,18 @@ func (f *Fmu2) DeSerializeFMUstate(c *Component, state *FmuState, serializedStat
  */
 func (f *Fmu2) GetEventIndicators(c *Component, ni int) ([]float64, error) {
 
+	if err := f.available(c, f.getEventIndicatorsPtr, "fmi2GetEventIndicators"); err != nil {
+		return nil, err
+	}
+
+	if ni == 0 {
+		return []float64{}, nil
+	}
+
 	indicators := make([]C.fmi2Real, ni)
 
-	if status := Status(C.GetEventIndicators(f.getEventIndicatorsPtr, c.component, &indicators[0], C.size_t(ni))); status != OK {
-		return nil, fmt.Errorf("error getting event indicators: %v", status)
+	if err := f.check(c, "fmi2GetEventIndicators", C.GetEventIndicators(f.getEventIndicatorsPtr, c.component, &indicators[0], C.size_t(ni))); err != nil {
+		return nil, err
 	}
 
 	result := Transform(indicators, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -480,10 +660,19 @@ func (f *Fmu2) GetEventIndicators(c *Component, ni int) ([]float64, error) {
  * The derivatives are returned as a slice with "nx" elements.
  */
 func (f *Fmu2) GetDerivatives(c *Component, nx int) ([]float64, error) {
+
+	if err := f.available(c, f.getDerivativesPtr, "fmi2GetDerivatives"); err != nil {
+		return nil, err
+	}
+
+	if nx == 0 {
+		return []float64{}, nil
+	}
+
 	derivatives := make([]C.fmi2Real, nx)
 
-	if status := Status(C.GetDerivatives(f.getDerivativesPtr, c.component, &derivatives[0], C.size_t(nx))); status != OK {
-		return nil, fmt.Errorf("error getting derivatives: %v", status)
+	if err := f.check(c, "fmi2GetDerivatives", C.GetDerivatives(f.getDerivativesPtr, c.component, &derivatives[0], C.size_t(nx))); err != nil {
+		return nil, err
 	}
 
 	result := Transform(derivatives, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -497,8 +686,12 @@ func (f *Fmu2) GetDerivatives(c *Component, nx int) ([]float64, error) {
  * but the previously computed values can be reused).
  */
 func (f *Fmu2) SetTime(c *Component, time float64) error {
-	if status := Status(C.SetTime(f.setTimePtr, c.component, C.fmi2Real(time))); status != OK {
-		return fmt.Errorf("error setting time: %v", status)
+	if err := f.available(c, f.setTimePtr, "fmi2SetTime"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2SetTime", C.SetTime(f.setTimePtr, c.component, C.fmi2Real(time))); err != nil {
+		return err
 	}
 
 	return nil
@@ -510,8 +703,12 @@ func (f *Fmu2) SetTime(c *Component, time float64) error {
  * into Continuous-Time Mode.
  */
 func (f *Fmu2) EnterContinuousTimeMode(c *Component) error {
-	if status := Status(C.EnterContinuousTimeMode(f.enterContinuousTimeModePtr, c.component)); status != OK {
-		return fmt.Errorf("error entering continuous time mode: %v", status)
+	if err := f.available(c, f.enterContinuousTimeModePtr, "fmi2EnterContinuousTimeMode"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2EnterContinuousTimeMode", C.EnterContinuousTimeMode(f.enterContinuousTimeModePtr, c.component)); err != nil {
+		return err
 	}
 
 	return nil
@@ -520,26 +717,103 @@ func (f *Fmu2) EnterContinuousTimeMode(c *Component) error {
 /* DoStep causes the computation of a time step to be started. */
 func (f *Fmu2) DoStep(c *Component, currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) error {
 
-	if f.doStepPtr == nil {
-		return fmt.Errorf("doStep function not available")
+	if err := f.available(c, f.doStepPtr, "fmi2DoStep"); err != nil {
+		return err
 	}
 
-	if status := Status(C.DoStep(f.doStepPtr, c.component, C.fmi2Real(currentCommunicationPoint), C.fmi2Real(communicationStepSize), toBool(noSetFMUStatePriorToCurrentPoint))); status != OK {
-		return fmt.Errorf("error doing step: %v", status)
+	if err := f.check(c, "fmi2DoStep", C.DoStep(f.doStepPtr, c.component, C.fmi2Real(currentCommunicationPoint), C.fmi2Real(communicationStepSize), toBool(noSetFMUStatePriorToCurrentPoint))); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+/* DoStepAsync starts the computation of a time step, which the FMU may execute asynchronously if the instance
+ * was created with WithAsynchronousSteps. The returned channel receives the result when the step is completed.
+ * While the step is Pending, its progress can be queried with GetStatus(PendingStatus) and GetStringStatus(PendingStatus).
+ * If ctx is canceled before the step is completed, the step is canceled with CancelStep and the channel receives ctx.Err().
+ */
+func (f *Fmu2) DoStepAsync(ctx context.Context, c *Component, currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) <-chan error {
+
+	result := make(chan error, 1)
+
+	if err := f.available(c, f.doStepPtr, "fmi2DoStep"); err != nil {
+		result <- err
+		return result
+	}
+
+	env := environmentOf(uintptr(c.handle))
+	if env == nil || env.steps == nil {
+		result <- errors.New("the instance was not created with WithAsynchronousSteps")
+		return result
+	}
+
+	// the step is registered before the call, because the FMU may call stepFinished before DoStep returns
+	step := env.steps.start()
+
+	status := C.DoStep(f.doStepPtr, c.component, C.fmi2Real(currentCommunicationPoint), C.fmi2Real(communicationStepSize), toBool(noSetFMUStatePriorToCurrentPoint))
+	if Status(status) != Pending {
+		env.steps.remove(step)
+		result <- f.check(c, "fmi2DoStep", status)
+		return result
+	}
+
+	c.transition("fmi2DoStep", Pending)
+
+	go func() {
+		for {
+			select {
+			case status, ok := <-step:
+				if !ok {
+					result <- errors.New("the step was abandoned by CancelStep, Reset, SetFMUstate or FreeInstance")
+					return
+				}
+
+				// a canceled step may be notified while this step is still pending
+				if env.steps.mayBeLate() && f.stepPending(c) {
+					env.steps.late()
+					continue
+				}
+
+				env.steps.remove(step)
+				result <- f.check(c, "fmi2DoStep", C.fmi2Status(status))
+				return
+			case <-ctx.Done():
+				// CancelStep clears the steps, so a late notification of the canceled step is ignored
+				if err := f.CancelStep(c); err != nil {
+					result <- err
+					return
+				}
+				result <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return result
+}
+
+// stepPending returns whether the FMU reports that the current step is still pending
+func (f *Fmu2) stepPending(c *Component) bool {
+	status, err := f.GetStatus(c, DoStepStatus)
+	return err == nil && status == Pending
+}
+
 /* CancelStep can be called if DoStep returned Pending in order to stop the current asynchronous execution.
  * The master calls this function if, for example, the co-simulation run is stopped by the user or one of the slaves.
  * Afterwards only calls to Reset, FreeInstance, or SetFMUstate are valid to exit the step Canceled state.
  */
 func (f *Fmu2) CancelStep(c *Component) error {
-	if status := Status(C.CancelStep(f.cancelStepPtr, c.component)); status != OK {
-		return fmt.Errorf("error canceling step: %v", status)
+	if err := f.available(c, f.cancelStepPtr, "fmi2CancelStep"); err != nil {
+		return err
 	}
 
+	if err := f.check(c, "fmi2CancelStep", C.CancelStep(f.cancelStepPtr, c.component)); err != nil {
+		return err
+	}
+
+	c.clearSteps()
+
 	return nil
 }
 
@@ -550,10 +824,14 @@ func (f *Fmu2) CancelStep(c *Component) error {
  */
 func (f *Fmu2) GetStatus(c *Component, s StatusKind) (Status, error) {
 
+	if err := f.available(c, f.getStatusPtr, "fmi2GetStatus"); err != nil {
+		return Error, err
+	}
+
 	var value C.fmi2Status
 
-	if status := Status(C.CancelStep(f.cancelStepPtr, c.component)); status != OK {
-		return Error, fmt.Errorf("error getting status: %v", status)
+	if err := f.check(c, "fmi2GetStatus", C.GetStatus(f.getStatusPtr, c.component, C.fmi2StatusKind(s), &value)); err != nil {
+		return Error, err
 	}
 
 	return Status(value), nil
@@ -566,10 +844,14 @@ func (f *Fmu2) GetStatus(c *Component, s StatusKind) (Status, error) {
  */
 func (f *Fmu2) GetRealStatus(c *Component, s StatusKind) (float64, error) {
 
+	if err := f.available(c, f.getRealStatusPtr, "fmi2GetRealStatus"); err != nil {
+		return 0, err
+	}
+
 	var value C.fmi2Real
 
-	if status := Status(C.GetRealStatus(f.getRealStatusPtr, c.component, C.fmi2StatusKind(s), &value)); status != OK {
-		return 0, fmt.Errorf("error getting real status: %v", status)
+	if err := f.check(c, "fmi2GetRealStatus", C.GetRealStatus(f.getRealStatusPtr, c.component, C.fmi2StatusKind(s), &value)); err != nil {
+		return 0, err
 	}
 
 	return float64(value), nil
@@ -582,10 +864,14 @@ func (f *Fmu2) GetRealStatus(c *Component, s StatusKind) (float64, error) {
  * If a status is required which cannot be retrieved by the slave it returns Discard.
  */
 func (f *Fmu2) GetIntegerStatus(c *Component, s StatusKind) (int, error) {
+	if err := f.available(c, f.getIntegerStatusPtr, "fmi2GetIntegerStatus"); err != nil {
+		return 0, err
+	}
+
 	var value C.fmi2Integer
 
-	if status := Status(C.GetIntegerStatus(f.getIntegerStatusPtr, c.component, C.fmi2StatusKind(s), &value)); status != OK {
-		return 0, fmt.Errorf("error getting integer status: %v", status)
+	if err := f.check(c, "fmi2GetIntegerStatus", C.GetIntegerStatus(f.getIntegerStatusPtr, c.component, C.fmi2StatusKind(s), &value)); err != nil {
+		return 0, err
 	}
 
 	return int(value), nil
@@ -597,10 +883,14 @@ func (f *Fmu2) GetIntegerStatus(c *Component, s StatusKind) (int, error) {
  * If a status is required which cannot be retrieved by the slave it returns Discard.
  */
 func (f *Fmu2) GetBooleanStatus(c *Component, s StatusKind) (bool, error) {
+	if err := f.available(c, f.getBooleanStatusPtr, "fmi2GetBooleanStatus"); err != nil {
+		return false, err
+	}
+
 	var value C.fmi2Boolean
 
-	if status := Status(C.GetBooleanStatus(f.getBooleanStatusPtr, c.component, C.fmi2StatusKind(s), &value)); status != OK {
-		return false, fmt.Errorf("error getting boolean status: %v", status)
+	if err := f.check(c, "fmi2GetBooleanStatus", C.GetBooleanStatus(f.getBooleanStatusPtr, c.component, C.fmi2StatusKind(s), &value)); err != nil {
+		return false, err
 	}
 
 	return value != 0, nil
@@ -612,23 +902,30 @@ func (f *Fmu2) GetBooleanStatus(c *Component, s StatusKind) (bool, error) {
  * If a status is required which cannot be retrieved by the slave it returns Discard.
  */
 func (f *Fmu2) GetStringStatus(c *Component, s StatusKind) (string, error) {
+	if err := f.available(c, f.getStringStatusPtr, "fmi2GetStringStatus"); err != nil {
+		return "", err
+	}
+
 	var value C.fmi2String
 
-	if status := Status(C.GetStringStatus(f.getStringStatusPtr, c.component, C.fmi2StatusKind(s), &value)); status != OK {
-		return "", fmt.Errorf("error getting string status: %v", status)
+	if err := f.check(c, "fmi2GetStringStatus", C.GetStringStatus(f.getStringStatusPtr, c.component, C.fmi2StatusKind(s), &value)); err != nil {
+		return "", err
 	}
 
-	str := C.GoString(value)
-	C.free(unsafe.Pointer(value))
-	return str, nil
+	// the string is owned by the FMU
+	return C.GoString(value), nil
 }
 
 /* EnterEventMode causes the model to enter Event Mode from the Continuous-Time Mode and
  * discrete-time equations may become active (and relations are not "frozen").
  */
 func (f *Fmu2) EnterEventMode(c *Component) error {
-	if status := Status(C.EnterEventMode(f.enterEventModePtr, c.component)); status != OK {
-		return fmt.Errorf("error entering event mode: %v", status)
+	if err := f.available(c, f.enterEventModePtr, "fmi2EnterEventMode"); err != nil {
+		return err
+	}
+
+	if err := f.check(c, "fmi2EnterEventMode", C.EnterEventMode(f.enterEventModePtr, c.component)); err != nil {
+		return err
 	}
 
 	return nil
@@ -636,12 +933,16 @@ func (f *Fmu2) EnterEventMode(c *Component) error {
 
 func (f *Fmu2) GetRealOutputDerivatives(c *Component, vr []ValueReference, order []int) ([]float64, error) {
 
+	if err := f.available(c, f.getRealOutputDerivativesPtr, "fmi2GetRealOutputDerivatives"); err != nil {
+		return nil, err
+	}
+
 	vrs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	orders := Transform(order, func(i int, v int) C.fmi2Integer { return C.fmi2Integer(v) })
 	values := make([]C.fmi2Real, len(vr))
 
-	if status := Status(C.GetRealOutputDerivatives(f.getRealOutputDerivativesPtr, c.component, &vrs[0], C.size_t(len(vr)), &orders[0], &values[0])); status != OK {
-		return nil, fmt.Errorf("error getting real output derivatives: %v", status)
+	if err := f.check(c, "fmi2GetRealOutputDerivatives", C.GetRealOutputDerivatives(f.getRealOutputDerivativesPtr, c.component, &vrs[0], C.size_t(len(vr)), &orders[0], &values[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(values, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -650,12 +951,16 @@ func (f *Fmu2) GetRealOutputDerivatives(c *Component, vr []ValueReference, order
 
 func (f *Fmu2) SetRealInputDerivatives(c *Component, vr []ValueReference, order []int, value []float64) error {
 
+	if err := f.available(c, f.setRealInputDerivativesPtr, "fmi2SetRealInputDerivatives"); err != nil {
+		return err
+	}
+
 	vrs := Transform(vr, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	orders := Transform(order, func(i int, v int) C.fmi2Integer { return C.fmi2Integer(v) })
 	values := Transform(value, func(i int, v float64) C.fmi2Real { return C.fmi2Real(v) })
 
-	if status := Status(C.SetRealInputDerivatives(f.setRealInputDerivativesPtr, c.component, &vrs[0], C.size_t(len(vr)), &orders[0], &values[0])); status != OK {
-		return fmt.Errorf("error setting real input derivatives: %v", status)
+	if err := f.check(c, "fmi2SetRealInputDerivatives", C.SetRealInputDerivatives(f.setRealInputDerivativesPtr, c.component, &vrs[0], C.size_t(len(vr)), &orders[0], &values[0])); err != nil {
+		return err
 	}
 
 	return nil
@@ -663,10 +968,14 @@ func (f *Fmu2) SetRealInputDerivatives(c *Component, vr []ValueReference, order
 
 func (f *Fmu2) NewDiscreteStates(c *Component) (*EventInfo, error) {
 
+	if err := f.available(c, f.newDiscreteStatesPtr, "fmi2NewDiscreteStates"); err != nil {
+		return nil, err
+	}
+
 	var eventInfo C.fmi2EventInfo
 
-	if status := Status(C.NewDiscreteStates(f.newDiscreteStatesPtr, c.component, &eventInfo)); status != OK {
-		return nil, fmt.Errorf("error getting new discrete states: %v", status)
+	if err := f.check(c, "fmi2NewDiscreteStates", C.NewDiscreteStates(f.newDiscreteStatesPtr, c.component, &eventInfo)); err != nil {
+		return nil, err
 	}
 
 	result := &EventInfo{
@@ -682,10 +991,19 @@ func (f *Fmu2) NewDiscreteStates(c *Component) (*EventInfo, error) {
 }
 
 func (f *Fmu2) GetContinuousStates(c *Component, nx int) ([]float64, error) {
+
+	if err := f.available(c, f.getContinuousStatesPtr, "fmi2GetContinuousStates"); err != nil {
+		return nil, err
+	}
+
+	if nx == 0 {
+		return []float64{}, nil
+	}
+
 	states := make([]C.fmi2Real, nx)
 
-	if status := Status(C.GetContinuousStates(f.getContinuousStatesPtr, c.component, &states[0], C.size_t(nx))); status != OK {
-		return nil, fmt.Errorf("error getting continuous states: %v", status)
+	if err := f.check(c, "fmi2GetContinuousStates", C.GetContinuousStates(f.getContinuousStatesPtr, c.component, &states[0], C.size_t(nx))); err != nil {
+		return nil, err
 	}
 
 	result := Transform(states, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -694,20 +1012,37 @@ func (f *Fmu2) GetContinuousStates(c *Component, nx int) ([]float64, error) {
 
 func (f *Fmu2) SetContinuousStates(c *Component, x []float64) error {
 
+	if err := f.available(c, f.setContinuousStatesPtr, "fmi2SetContinuousStates"); err != nil {
+		return err
+	}
+
+	if len(x) == 0 {
+		return nil
+	}
+
 	states := Transform(x, func(i int, v float64) C.fmi2Real { return C.fmi2Real(v) })
 
-	if status := Status(C.SetContinuousStates(f.setContinuousStatesPtr, c.component, &states[0], C.size_t(len(x)))); status != OK {
-		return fmt.Errorf("error setting continuous states: %v", status)
+	if err := f.check(c, "fmi2SetContinuousStates", C.SetContinuousStates(f.setContinuousStatesPtr, c.component, &states[0], C.size_t(len(x)))); err != nil {
+		return err
 	}
 
 	return nil
 }
 
 func (f *Fmu2) GetNominalsOfContinuousStates(c *Component, nx int) ([]float64, error) {
+
+	if err := f.available(c, f.getNominalsOfContinuousStatesPtr, "fmi2GetNominalsOfContinuousStates"); err != nil {
+		return nil, err
+	}
+
+	if nx == 0 {
+		return []float64{}, nil
+	}
+
 	nominals := make([]C.fmi2Real, nx)
 
-	if status := Status(C.GetNominalsOfContinuousStates(f.getNominalsOfContinuousStatesPtr, c.component, &nominals[0], C.size_t(nx))); status != OK {
-		return nil, fmt.Errorf("error getting nominals of continuous states: %v", status)
+	if err := f.check(c, "fmi2GetNominalsOfContinuousStates", C.GetNominalsOfContinuousStates(f.getNominalsOfContinuousStatesPtr, c.component, &nominals[0], C.size_t(nx))); err != nil {
+		return nil, err
 	}
 
 	result := Transform(nominals, func(i int, v C.fmi2Real) float64 { return float64(v) })
@@ -716,11 +1051,15 @@ func (f *Fmu2) GetNominalsOfContinuousStates(c *Component, nx int) ([]float64, e
 
 func (f *Fmu2) CompletedIntegratorStep(c *Component, noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error) {
 
+	if err := f.available(c, f.completedIntegratorStepPtr, "fmi2CompletedIntegratorStep"); err != nil {
+		return false, false, err
+	}
+
 	var enterEventMode C.fmi2Boolean
 	var terminateSimulation C.fmi2Boolean
 
-	if status := Status(C.CompletedIntegratorStep(f.completedIntegratorStepPtr, c.component, toBool(noSetFMUStatePriorToCurrentPoint), &enterEventMode, &terminateSimulation)); status != OK {
-		return false, false, fmt.Errorf("error completing integrator step: %v", status)
+	if err := f.check(c, "fmi2CompletedIntegratorStep", C.CompletedIntegratorStep(f.completedIntegratorStepPtr, c.component, toBool(noSetFMUStatePriorToCurrentPoint), &enterEventMode, &terminateSimulation)); err != nil {
+		return false, false, err
 	}
 
 	return enterEventMode != 0, terminateSimulation != 0, nil
@@ -728,21 +1067,69 @@ func (f *Fmu2) CompletedIntegratorStep(c *Component, noSetFMUStatePriorToCurrent
 
 func (f *Fmu2) GetDirectionalDerivative(c *Component, zRef []ValueReference, vRef []ValueReference, dv []float64) ([]float64, error) {
 
+	if err := f.available(c, f.getDirectionalDerivativePtr, "fmi2GetDirectionalDerivative"); err != nil {
+		return nil, err
+	}
+
 	zRefs := Transform(zRef, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	vRefs := Transform(vRef, func(i int, v ValueReference) C.fmi2ValueReference { return C.fmi2ValueReference(v) })
 	dvs := Transform(dv, func(i int, v float64) C.fmi2Real { return C.fmi2Real(v) })
 	dz := make([]C.fmi2Real, len(zRef))
 
-	if status := Status(C.GetDirectionalDerivative(f.getDirectionalDerivativePtr, c.component, &zRefs[0], C.size_t(len(zRef)), &vRefs[0], C.size_t(len(vRef)), &dvs[0], &dz[0])); status != OK {
-		return nil, fmt.Errorf("error getting directional derivative: %v", status)
+	if err := f.check(c, "fmi2GetDirectionalDerivative", C.GetDirectionalDerivative(f.getDirectionalDerivativePtr, c.component, &zRefs[0], C.size_t(len(zRef)), &vRefs[0], C.size_t(len(vRef)), &dvs[0], &dz[0])); err != nil {
+		return nil, err
 	}
 
 	result := Transform(dz, func(i int, v C.fmi2Real) float64 { return float64(v) })
 	return result, nil
 }
 
-func (f *Fmu2) ResourceLocation() string {
-	return filepath.Join(f.Directory, "resources")
+type ResourceLocationOption func(*ResourceLocationOptions)
+
+type ResourceLocationOptions struct {
+	legacy bool
+}
+
+// WithLegacyFileURI creates URIs of the form file:/path, which some older FMUs expect, instead of file:///path
+func WithLegacyFileURI() ResourceLocationOption {
+	return func(o *ResourceLocationOptions) {
+		o.legacy = true
+	}
+}
+
+// ResourceLocation returns the URI of the resources directory of the extracted FMU that is passed to Instantiate
+func (f *Fmu2) ResourceLocation(opts ...ResourceLocationOption) string {
+	return FileURI(filepath.Join(f.Directory, "resources"), opts...)
+}
+
+// FileURI converts an absolute path to a percent-encoded file URI as required by the FMI
+// specification, e.g. /tmp/my fmu/resources becomes file:///tmp/my%20fmu/resources
+// and C:\temp\resources becomes file:///C:/temp/resources
+func FileURI(path string, opts ...ResourceLocationOption) string {
+
+	options := &ResourceLocationOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	uri := &url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+
+	switch {
+	case strings.HasPrefix(uri.Path, "//"):
+		// UNC path \\server\share
+		host, share, _ := strings.Cut(uri.Path[2:], "/")
+		uri.Host, uri.Path = host, "/"+share
+	case !strings.HasPrefix(uri.Path, "/"):
+		// path with a drive letter
+		uri.Path = "/" + uri.Path
+	}
+
+	if options.legacy && uri.Host == "" {
+		return "file:" + uri.EscapedPath()
+	}
+
+	return uri.String()
 }
 
 func resolveFunction(handle unsafe.Pointer, name string) unsafe.Pointer {
@@ -753,32 +1140,153 @@ func resolveFunction(handle unsafe.Pointer, name string) unsafe.Pointer {
 
 }
 
-func New(filename string) (*Fmu2, error) {
+// libraryCandidates returns the paths of the shared libraries in the FMU that may implement the interface type.
+// The library is named after the model identifier of the interface type. FMUs that were exported with the
+// model name as the name of the library are supported as a fallback.
+func libraryCandidates(md *ModelDescription, fmiType Type, machine Machine) ([]string, error) {
+
+	identifier, err := md.ModelIdentifier(fmiType)
+	if err != nil {
+		return nil, err
+	}
+
+	identifiers := []string{identifier}
+	if md.ModelName != identifier && md.ModelName != "" {
+		identifiers = append(identifiers, md.ModelName)
+	}
+
+	candidates := make([]string, len(identifiers))
+	for i, id := range identifiers {
+		candidates[i] = path.Join("binaries", machine.Platform, id+"."+machine.LibrarySuffix)
+	}
+
+	return candidates, nil
+}
+
+// findLibrary returns the first of the candidates that exists in the FMU
+func findLibrary(fsys fs.FS, candidates []string) (string, error) {
+
+	for _, candidate := range candidates {
+		if _, err := fs.Stat(fsys, candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("the FMU contains no shared library for %s (tried %s, the FMU contains binaries for the platforms: %s)",
+		CurrentMachine().Platform, strings.Join(candidates, ", "), strings.Join(SupportedPlatformsFS(fsys), ", "))
+}
+
+// selectLibrary reads the model description and returns the path of the shared library that implements fmiType
+func selectLibrary(fsys fs.FS, fmiType Type) (*ModelDescription, string, error) {
+
+	md, err := ReadModelDescriptionFS(fsys, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	candidates, err := libraryCandidates(md, fmiType, CurrentMachine())
+	if err != nil {
+		return nil, "", err
+	}
+
+	library, err := findLibrary(fsys, candidates)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return md, library, nil
+}
+
+// New loads the shared library of the FMU that implements the interface type fmiType.
+// By default the FMU is extracted into a temporary directory that is removed by Close.
+func New(filename string, fmiType Type, opts ...LoadOption) (*Fmu2, error) {
 
-	platforms := SupportedPlatforms(filename)
-	machine := CurrentMachine()
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return newFromArchive(&r.Reader, func() (string, error) { return fmi.FileHash(filename) }, fmiType, opts)
+}
 
-	if !slices.Contains(platforms, machine.Platform) {
-		return nil, fmt.Errorf("unsupported machine: %s", machine)
+// NewFromReader loads the FMU from the zip archive in r, e.g. an FMU that is stored in a database or an object store
+func NewFromReader(r io.ReaderAt, size int64, fmiType Type, opts ...LoadOption) (*Fmu2, error) {
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
 	}
 
-	md, err := ReadModelDescription(filename, nil)
+	return newFromArchive(zr, func() (string, error) { return fmi.ReaderHash(io.NewSectionReader(r, 0, size)) }, fmiType, opts)
+}
+
+// NewFromBytes loads the FMU from the zip archive in data
+func NewFromBytes(data []byte, fmiType Type, opts ...LoadOption) (*Fmu2, error) {
+	return NewFromReader(bytes.NewReader(data), int64(len(data)), fmiType, opts...)
+}
+
+// NewFromDirectory loads an FMU that has already been extracted into directory.
+// The directory is not removed by Close.
+func NewFromDirectory(directory string, fmiType Type) (*Fmu2, error) {
+
+	md, library, err := selectLibrary(os.DirFS(directory), fmiType)
 	if err != nil {
 		return nil, err
 	}
 
-	library := path.Join(machine.Platform, md.ModelName+"."+machine.LibrarySuffix)
+	return load(md, directory, library, fmiType)
+}
+
+// newFromArchive extracts the FMU and loads the shared library. The hash of the archive
+// is only computed if the extraction cache is used.
+func newFromArchive(r *zip.Reader, hash func() (string, error), fmiType Type, opts []LoadOption) (*Fmu2, error) {
+
+	options := &LoadOptions{}
 
-	directory, err := Extract(filename)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	md, library, err := selectLibrary(r, fmiType)
 	if err != nil {
+		if options.compile {
+			return newFromSources(r, fmiType, options, err)
+		}
 		return nil, err
 	}
 
-	modulePath := path.Join(directory, "binaries", library)
+	directory, owned, err := extract(r, hash, options)
+	if err != nil {
+		return nil, err
+	}
+
+	fmu, err := load(md, directory, library, fmiType)
+	if err != nil {
+		if owned {
+			os.RemoveAll(directory)
+		}
+		return nil, err
+	}
+
+	fmu.ownsDirectory = owned
+
+	return fmu, nil
+}
+
+// load loads the shared library of the FMU that has been extracted into directory
+func load(md *ModelDescription, directory string, library string, fmiType Type) (*Fmu2, error) {
+
+	modulePath := filepath.Join(directory, filepath.FromSlash(library))
 	moduleString := C.CString(modulePath)
 	defer C.free(unsafe.Pointer(moduleString))
 
-	handle := C.dlopen(moduleString, C.RTLD_LAZY)
+	var loadError *C.char
+	handle := C.OpenLibrary(moduleString, &loadError)
+	if handle == nil {
+		return nil, fmt.Errorf("error loading %s: %s", library, C.GoString(loadError))
+	}
 
 	// Common Functions
 	getTypesPlatformPtr := resolveFunction(handle, "fmi2GetTypesPlatform")
@@ -884,91 +1392,139 @@ func New(filename string) (*Fmu2, error) {
 		getStringStatusPtr:          getStringStatusPtr,
 	}
 
+	fmu.modelDescription = md
+	fmu.variables = md.variablesByName()
+
+	if err := fmu.checkFunctions(md, fmiType); err != nil {
+		fmu.Close()
+		return nil, err
+	}
+
+	fmu.library = fmi.OpenLibrary(uintptr(fmu.instantiatePtr))
+
 	runtime.SetFinalizer(fmu, (*Fmu2).Close)
 
 	return fmu, nil
 }
 
-func toBool(x bool) C.fmi2Boolean {
-	if x {
-		return 1
+// commonFunctions returns the functions that every FMU must export. The functions to get, set and
+// serialize the FMU state and fmi2GetDirectionalDerivative depend on capability flags and are optional.
+func (f *Fmu2) commonFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi2GetTypesPlatform":        f.getTypesPlatformPtr,
+		"fmi2GetVersion":              f.getVersionPtr,
+		"fmi2SetDebugLogging":         f.setDebugLoggingPtr,
+		"fmi2Instantiate":             f.instantiatePtr,
+		"fmi2FreeInstance":            f.freeInstancePtr,
+		"fmi2SetupExperiment":         f.setupExperimentPtr,
+		"fmi2EnterInitializationMode": f.enterInitializationModePtr,
+		"fmi2ExitInitializationMode":  f.exitInitializationModePtr,
+		"fmi2Terminate":               f.terminatePtr,
+		"fmi2Reset":                   f.resetPtr,
+		"fmi2GetReal":                 f.getRealPtr,
+		"fmi2GetInteger":              f.getIntegerPtr,
+		"fmi2GetBoolean":              f.getBooleanPtr,
+		"fmi2GetString":               f.getStringPtr,
+		"fmi2SetReal":                 f.setRealPtr,
+		"fmi2SetInteger":              f.setIntegerPtr,
+		"fmi2SetBoolean":              f.setBooleanPtr,
+		"fmi2SetString":               f.setStringPtr,
 	}
-	return 0
 }
 
-func Unzip(src string, dest string) error {
-	r, err := zip.OpenReader(src)
-	if err != nil {
-		return err
+// modelExchangeFunctions returns the functions that an FMU for Model Exchange must export
+func (f *Fmu2) modelExchangeFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi2EnterEventMode":                f.enterEventModePtr,
+		"fmi2NewDiscreteStates":             f.newDiscreteStatesPtr,
+		"fmi2EnterContinuousTimeMode":       f.enterContinuousTimeModePtr,
+		"fmi2CompletedIntegratorStep":       f.completedIntegratorStepPtr,
+		"fmi2SetTime":                       f.setTimePtr,
+		"fmi2SetContinuousStates":           f.setContinuousStatesPtr,
+		"fmi2GetDerivatives":                f.getDerivativesPtr,
+		"fmi2GetEventIndicators":            f.getEventIndicatorsPtr,
+		"fmi2GetContinuousStates":           f.getContinuousStatesPtr,
+		"fmi2GetNominalsOfContinuousStates": f.getNominalsOfContinuousStatesPtr,
 	}
-	defer func() {
-		if err := r.Close(); err != nil {
-			panic(err)
-		}
-	}()
+}
 
-	os.MkdirAll(dest, 0755)
+// coSimulationFunctions returns the functions that an FMU for Co-Simulation must export
+func (f *Fmu2) coSimulationFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi2SetRealInputDerivatives":  f.setRealInputDerivativesPtr,
+		"fmi2GetRealOutputDerivatives": f.getRealOutputDerivativesPtr,
+		"fmi2DoStep":                   f.doStepPtr,
+		"fmi2CancelStep":               f.cancelStepPtr,
+		"fmi2GetStatus":                f.getStatusPtr,
+		"fmi2GetRealStatus":            f.getRealStatusPtr,
+		"fmi2GetIntegerStatus":         f.getIntegerStatusPtr,
+		"fmi2GetBooleanStatus":         f.getBooleanStatusPtr,
+		"fmi2GetStringStatus":          f.getStringStatusPtr,
+	}
+}
 
-	// Closure to address file descriptors issue with all the deferred .Close() methods
-	extractAndWriteFile := func(f *zip.File) error {
-		rc, err := f.Open()
-		if err != nil {
-			return err
-		}
-		defer func() {
-			if err := rc.Close(); err != nil {
-				panic(err)
-			}
-		}()
+// checkFunctions returns an error if the library does not export all common functions and the functions
+// of the interface types it implements. Besides fmiType the library implements every interface type
+// that the model description declares with the same model identifier.
+func (f *Fmu2) checkFunctions(md *ModelDescription, fmiType Type) error {
 
-		path := filepath.Join(dest, f.Name)
+	identifier, err := md.ModelIdentifier(fmiType)
+	if err != nil {
+		return err
+	}
 
-		// Check for ZipSlip (Directory traversal)
-		if !strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return fmt.Errorf("illegal file path: %s", path)
-		}
+	implements := func(t Type) bool {
+		id, err := md.ModelIdentifier(t)
+		return err == nil && id == identifier
+	}
 
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(path, f.Mode())
-		} else {
-			os.MkdirAll(filepath.Dir(path), f.Mode())
-			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-			if err != nil {
-				return err
-			}
-			defer func() {
-				if err := f.Close(); err != nil {
-					panic(err)
-				}
-			}()
+	var missing []string
 
-			_, err = io.Copy(f, rc)
-			if err != nil {
-				return err
+	collect := func(functions map[string]unsafe.Pointer) {
+		for name, ptr := range functions {
+			if ptr == nil {
+				missing = append(missing, name)
 			}
 		}
-		return nil
 	}
 
-	for _, f := range r.File {
-		err := extractAndWriteFile(f)
-		if err != nil {
-			return err
-		}
+	collect(f.commonFunctions())
+
+	if implements(ModelExchangeType) {
+		collect(f.modelExchangeFunctions())
+	}
+
+	if implements(CoSimulationType) {
+		collect(f.coSimulationFunctions())
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the shared library of %s does not export the mandatory functions: %s", identifier, strings.Join(missing, ", "))
 	}
 
 	return nil
 }
 
-func Extract(filename string) (string, error) {
-	dir, err := os.MkdirTemp("", "go-fmu-*")
-	if err != nil {
-		return "", err
+func toBool(x bool) C.fmi2Boolean {
+	if x {
+		return 1
 	}
+	return 0
+}
 
-	if err := Unzip(filename, dir); err != nil {
-		return "", err
+func Unzip(src string, dest string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
 	}
 
-	return dir, nil
+	defer r.Close()
+
+	return fmi.Unzip(&r.Reader, dest, nil)
+}
+
+// Extract extracts the FMU into a new temporary directory
+func Extract(filename string) (string, error) {
+	return fmi.ExtractFile(filename)
 }
@@ -7,11 +7,14 @@
 #include <stdarg.h>
 #include <stdint.h>
 #include <stdio.h>
+#include <stdlib.h>
 
 typedef const char cchar_t;
 
 extern void Logger(fmi2ComponentEnvironment componentEnvironment, fmi2String instanceName, fmi2Status status, fmi2String category, fmi2String message, ...);
 extern void StepFinished(fmi2ComponentEnvironment componentEnvironment, fmi2Status status);
+extern fmi2CallbackFunctions *NewCallbackFunctions(uintptr_t environment, fmi2Boolean asynchronous);
+extern void *OpenLibrary(const char *filename, const char **error);
 extern const char *GetTypesPlatform(void *f);
 extern const char *GetVersion(void *f);
 extern fmi2Status SetDebugLogging(void *f, fmi2Component component, fmi2Boolean loggingOn, size_t nCategories, const fmi2String categories[]);
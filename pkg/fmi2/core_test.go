@@ -0,0 +1,766 @@
+package fmi2_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// repackage copies the FMU to a temporary file. The files of the FMU are renamed by rename
+// (an empty name drops the file) and the model description is modified by edit.
+func repackage(t *testing.T, filename string, rename func(name string) string, edit func(modelDescription string) string) string {
+
+	r, err := zip.OpenReader(filename)
+	require.NoError(t, err)
+	defer r.Close()
+
+	output := filepath.Join(t.TempDir(), filepath.Base(filename))
+	f, err := os.Create(output)
+	require.NoError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	defer w.Close()
+
+	for _, file := range r.File {
+		name := rename(file.Name)
+		if name == "" {
+			continue
+		}
+
+		rc, err := file.Open()
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+
+		if name == "modelDescription.xml" {
+			data = []byte(edit(string(data)))
+		}
+
+		fw, err := w.Create(name)
+		require.NoError(t, err)
+
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+	}
+
+	return output
+}
+
+func TestNewModelIdentifier(t *testing.T) {
+
+	// an FMU with a separate binary for every interface type that is not named after the model
+	filename := repackage(t, "../../examples/Drivetrain.fmu",
+		func(name string) string {
+			if strings.HasPrefix(name, "binaries/") {
+				return strings.Replace(name, "/Drivetrain.", "/Drivetrain_me.", 1)
+			}
+			return name
+		},
+		func(modelDescription string) string {
+			modelDescription = strings.Replace(modelDescription, `modelIdentifier="Drivetrain"`, `modelIdentifier="Drivetrain_me"`, 1)
+			return strings.Replace(modelDescription, `modelIdentifier="Drivetrain"`, `modelIdentifier="Drivetrain_cs"`, 1)
+		})
+
+	md, err := fmi2.ReadModelDescription(filename, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Drivetrain_me", md.ModelExchange.ModelIdentifier)
+	require.Equal(t, "Drivetrain_cs", md.CoSimulation.ModelIdentifier)
+
+	fmu, err := fmi2.New(filename, fmi2.ModelExchangeType)
+	require.NoError(t, err)
+	require.Equal(t, "2.0", fmu.GetVersion())
+	require.NoError(t, fmu.Close())
+
+	// the Co-Simulation binary is missing
+	_, err = fmi2.New(filename, fmi2.CoSimulationType)
+	require.ErrorContains(t, err, "Drivetrain_cs.")
+	require.ErrorContains(t, err, "win64")
+
+	_, err = fmi2.New("../../examples/Rectifier.fmu", fmi2.ModelExchangeType)
+	require.ErrorContains(t, err, "does not support Model Exchange")
+}
+
+func TestNewMissingFunctions(t *testing.T) {
+
+	// a library that cannot be loaded
+	filename := repackage(t, "../../examples/Controller.fmu",
+		func(name string) string {
+			switch name {
+			case "binaries/linux64/Controller.so":
+				return ""
+			case "binaries/win64/Controller.dll":
+				return "binaries/linux64/Controller.so"
+			}
+			return name
+		},
+		func(modelDescription string) string { return modelDescription })
+
+	_, err := fmi2.New(filename, fmi2.CoSimulationType)
+	require.ErrorContains(t, err, "error loading binaries/linux64/Controller.so")
+
+	// the model description declares Model Exchange, but the library only implements Co-Simulation
+	filename = repackage(t, "../../examples/Rectifier.fmu",
+		func(name string) string { return name },
+		func(modelDescription string) string {
+			return strings.Replace(modelDescription, "<CoSimulation", `<ModelExchange modelIdentifier="Rectifier"/><CoSimulation`, 1)
+		})
+
+	_, err = fmi2.New(filename, fmi2.CoSimulationType)
+	require.ErrorContains(t, err, "does not export the mandatory functions")
+	require.ErrorContains(t, err, "fmi2SetTime")
+	require.NotContains(t, err.Error(), "fmi2DoStep")
+
+	// the functions of the other interface type are not available
+	fmu, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	err = fmu.DoStep(nil, 0, 1, true)
+	require.ErrorIs(t, err, fmi2.ErrFunctionNotAvailable)
+
+	var notAvailable *fmi2.FunctionNotAvailableError
+	require.ErrorAs(t, err, &notAvailable)
+	require.Equal(t, "fmi2DoStep", notAvailable.Function)
+}
+
+func TestStatusError(t *testing.T) {
+
+	instantiate := func(filename string) (*fmi2.Fmu2, *fmi2.Component) {
+		md, err := fmi2.ReadModelDescription(filename, nil)
+		require.NoError(t, err)
+
+		fmu, err := fmi2.New(filename, fmi2.CoSimulationType)
+		require.NoError(t, err)
+
+		c := fmu.Instantiate("instance", fmi2.CoSimulationType, md.Guid, "", false, false, fmi2.WithoutLifecycleChecks())
+		require.NotNil(t, c)
+
+		return fmu, c
+	}
+
+	// the FMU warns that the values are not available before initialization
+	fmu, c := instantiate("../../examples/Controller.fmu")
+
+	var warnings []string
+	fmu.SetWarningHandler(func(c *fmi2.Component, function string) { warnings = append(warnings, function) })
+
+	require.NoError(t, c.DoStep(0, 1, false))
+	require.Equal(t, []string{"fmi2DoStep"}, warnings)
+
+	c.FreeInstance()
+	require.NoError(t, fmu.Close())
+
+	// an unknown value reference
+	fmu, c = instantiate("../../examples/Rectifier.fmu")
+	defer fmu.Close()
+	defer c.FreeInstance()
+
+	err := c.SetReal([]fmi2.ValueReference{123456789}, []float64{1})
+
+	var statusError *fmi2.StatusError
+	require.ErrorAs(t, err, &statusError)
+	require.Equal(t, "fmi2SetReal", statusError.Function)
+	require.Equal(t, fmi2.Error, statusError.Status)
+	require.EqualError(t, err, "fmi2SetReal returned Error")
+	require.NotErrorIs(t, err, fmi2.ErrFatal)
+	require.False(t, fmi2.IsDiscard(err))
+
+	require.True(t, fmi2.IsDiscard(&fmi2.StatusError{Function: "fmi2DoStep", Status: fmi2.Discard}))
+	require.ErrorIs(t, &fmi2.StatusError{Function: "fmi2DoStep", Status: fmi2.Fatal}, fmi2.ErrFatal)
+}
+
+func TestLifecycle(t *testing.T) {
+
+	md, err := fmi2.ReadModelDescription("../../examples/Controller.fmu", nil)
+	require.NoError(t, err)
+
+	fmu, err := fmi2.New("../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	c := fmu.Instantiate("instance", fmi2.CoSimulationType, md.Guid, "", false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.Equal(t, fmi2.StateInstantiated, c.State())
+
+	// steps are only allowed after the initialization
+	err = c.DoStep(0, 1, false)
+	require.ErrorIs(t, err, fmi2.ErrIllegalCall)
+
+	var illegalCall *fmi2.IllegalCallError
+	require.ErrorAs(t, err, &illegalCall)
+	require.Equal(t, "fmi2DoStep", illegalCall.Function)
+	require.Equal(t, fmi2.StateInstantiated, illegalCall.State)
+	require.Equal(t, fmi2.StateStepComplete, illegalCall.Allowed)
+
+	// Model Exchange functions are not allowed for Co-Simulation
+	_, err = c.GetDerivatives(1)
+	require.ErrorIs(t, err, fmi2.ErrIllegalCall)
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.Equal(t, fmi2.StateInitializationMode, c.State())
+	require.NoError(t, c.ExitInitializationMode())
+	require.Equal(t, fmi2.StateStepComplete, c.State())
+	require.NoError(t, c.DoStep(0, 1, false))
+	require.NoError(t, c.Terminate())
+	require.Equal(t, fmi2.StateTerminated, c.State())
+
+	require.ErrorIs(t, c.DoStep(1, 1, false), fmi2.ErrIllegalCall)
+	require.ErrorIs(t, c.Terminate(), fmi2.ErrIllegalCall)
+
+	_, err = c.GetReal([]fmi2.ValueReference{0})
+	require.NoError(t, err)
+
+	require.NoError(t, c.Reset())
+	require.Equal(t, fmi2.StateInstantiated, c.State())
+
+	require.Equal(t, "StepComplete|StepFailed", (fmi2.StateStepComplete | fmi2.StateStepFailed).String())
+}
+
+func TestVariableAccess(t *testing.T) {
+
+	md, err := fmi2.ReadModelDescription("../../examples/Controller.fmu", nil)
+	require.NoError(t, err)
+
+	fmu, err := fmi2.New("../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	c := fmu.Instantiate("instance", fmi2.CoSimulationType, md.Guid, "", false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	// integers are converted to Real
+	require.NoError(t, c.Set(map[string]any{"PI.k": 2, "PI.yMax": 5}))
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.ExitInitializationMode())
+
+	require.NoError(t, fmi2.SetValue(c, "u_s", 0.5))
+
+	values, err := c.GetValues("PI.k", "u_s", "PI.yMax")
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"PI.k": 2.0, "u_s": 0.5, "PI.yMax": 5.0}, values)
+
+	value, err := c.Get("PI.k")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, value)
+
+	k, err := fmi2.GetValue[float64](c, "PI.k")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, k)
+
+	_, err = fmi2.GetValue[bool](c, "PI.k")
+	require.EqualError(t, err, "the variable PI.k of type Real cannot be read as bool")
+
+	_, err = c.Get("unknown")
+	require.EqualError(t, err, "unknown variable unknown")
+
+	require.EqualError(t, c.Set(map[string]any{"u_s": "high"}), "the value of u_s must be a number but is string")
+}
+
+func TestLogger(t *testing.T) {
+
+	md, err := fmi2.ReadModelDescription("../../examples/Controller.fmu", nil)
+	require.NoError(t, err)
+
+	fmu, err := fmi2.New("../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	type message struct {
+		instance string
+		status   fmi2.Status
+		text     string
+	}
+
+	var first, second []message
+
+	c1 := fmu.Instantiate("first", fmi2.CoSimulationType, md.Guid, "", false, false,
+		fmi2.WithLogger(func(instanceName string, status fmi2.Status, category string, text string) {
+			first = append(first, message{instanceName, status, text})
+		}),
+		fmi2.WithoutLifecycleChecks())
+	require.NotNil(t, c1)
+	defer c1.FreeInstance()
+
+	c2 := fmu.Instantiate("second", fmi2.CoSimulationType, md.Guid, "", false, false,
+		fmi2.WithLogger(func(instanceName string, status fmi2.Status, category string, text string) {
+			second = append(second, message{instanceName, status, text})
+		}),
+		fmi2.WithoutLifecycleChecks())
+	require.NotNil(t, c2)
+	defer c2.FreeInstance()
+
+	// the FMU warns that the instance is not initialized
+	require.NoError(t, c2.DoStep(0, 1, false))
+	require.NoError(t, c1.DoStep(0, 1, false))
+	require.NoError(t, c2.DoStep(0, 1, false))
+
+	require.Len(t, first, 1)
+	require.Equal(t, "first", first[0].instance)
+	require.Equal(t, fmi2.Warning, first[0].status)
+	require.Contains(t, first[0].text, "Initialization must be finished")
+
+	require.Len(t, second, 2)
+	require.Equal(t, "second", second[1].instance)
+
+	// messages of other categories are dropped
+	var filtered []string
+
+	c3 := fmu.Instantiate("third", fmi2.CoSimulationType, md.Guid, "", false, false,
+		fmi2.WithLogger(func(instanceName string, status fmi2.Status, category string, text string) {
+			filtered = append(filtered, text)
+		}),
+		fmi2.WithLogCategories("logStatusError"),
+		fmi2.WithoutLifecycleChecks())
+	require.NotNil(t, c3)
+	defer c3.FreeInstance()
+
+	require.NoError(t, c3.DoStep(0, 1, false))
+	require.Empty(t, filtered)
+
+	// long messages are not truncated
+	rectifier, err := fmi2.New("../../examples/Rectifier.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer rectifier.Close()
+
+	var messages []string
+	guid := strings.Repeat("x", 2000)
+
+	c4 := rectifier.Instantiate("rectifier", fmi2.CoSimulationType, guid, "", false, false,
+		fmi2.WithLogger(func(instanceName string, status fmi2.Status, category string, text string) {
+			messages = append(messages, text)
+		}))
+	require.Nil(t, c4)
+	require.Len(t, messages, 1)
+	require.Contains(t, messages[0], guid)
+}
+
+func TestLoggerWithoutEnvironment(t *testing.T) {
+
+	// the Async FMU logs its creation and steps without componentEnvironment if loggingOn is true
+	var fmus []*fmi2.Fmu2
+
+	for range 2 {
+		fmu, err := fmi2.New("../../examples/Async.fmu", fmi2.CoSimulationType)
+		require.NoError(t, err)
+		defer fmu.Close()
+		fmus = append(fmus, fmu)
+	}
+
+	guid := fmus[0].ModelDescription().Guid
+
+	var (
+		mutex    sync.Mutex
+		messages = make(map[string][]string) // the messages by the name of the logger's instance
+	)
+
+	instantiate := func(fmu *fmi2.Fmu2, name string) *fmi2.Component {
+		return fmu.Instantiate(name, fmi2.CoSimulationType, guid, "", false, true,
+			fmi2.WithLogger(func(instanceName string, status fmi2.Status, category string, text string) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				messages[name] = append(messages[name], text)
+			}))
+	}
+
+	running := instantiate(fmus[0], "running")
+	require.NotNil(t, running)
+	defer running.FreeInstance()
+
+	require.NoError(t, running.SetupExperiment(0))
+	require.NoError(t, running.EnterInitializationMode())
+	require.NoError(t, running.Set(map[string]any{"duration": 0.0}))
+	require.NoError(t, running.ExitInitializationMode())
+
+	// the instances of both FMUs are created while the running instance logs its steps
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := range 500 {
+			if err := running.DoStep(float64(i)*0.1, 0.1, true); err != nil {
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for i, fmu := range fmus {
+		for j := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := 0; ; k++ {
+					select {
+					case <-done:
+						return
+					default:
+						if c := instantiate(fmu, fmt.Sprintf("instance%d_%d_%d", i, j, k)); c != nil {
+							c.FreeInstance()
+						}
+					}
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	// every instance receives only the message of its creation, the messages of the steps cannot be attributed
+	require.Greater(t, len(messages), 1)
+
+	for name, texts := range messages {
+		require.Equal(t, []string{"instantiating " + name}, texts)
+	}
+}
+
+func TestDoStepAsync(t *testing.T) {
+
+	md, err := fmi2.ReadModelDescription("../../examples/Controller.fmu", nil)
+	require.NoError(t, err)
+
+	fmu, err := fmi2.New("../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	// asynchronous steps must be enabled
+	c := fmu.Instantiate("synchronous", fmi2.CoSimulationType, md.Guid, "", false, false)
+	require.NotNil(t, c)
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.ExitInitializationMode())
+
+	require.ErrorContains(t, <-c.DoStepAsync(context.Background(), 0, 0.1, false), "WithAsynchronousSteps")
+	c.FreeInstance()
+
+	c = fmu.Instantiate("asynchronous", fmi2.CoSimulationType, md.Guid, "", false, false, fmi2.WithAsynchronousSteps())
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.ExitInitializationMode())
+
+	// the FMU cannot run asynchronously and completes the steps immediately
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := range 10 {
+		require.NoError(t, <-c.DoStepAsync(ctx, float64(i)*0.1, 0.1, false))
+	}
+
+	time, err := c.GetRealStatus(fmi2.LastSuccessfulTime)
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, time, 1e-9)
+}
+
+func TestDoStepAsyncPending(t *testing.T) {
+
+	fmu, err := fmi2.New("../../examples/Async.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	c := fmu.Instantiate("async", fmi2.CoSimulationType, fmu.ModelDescription().Guid, "", false, false, fmi2.WithAsynchronousSteps())
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	initialize := func() {
+		require.NoError(t, c.SetupExperiment(0))
+		require.NoError(t, c.EnterInitializationMode())
+		require.NoError(t, c.ExitInitializationMode())
+	}
+
+	initialize()
+
+	// the state and status are polled while the goroutine of the step waits for the FMU
+	done := c.DoStepAsync(context.Background(), 0, 0.1, false)
+
+	polls := 0
+
+poll:
+	for {
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+			break poll
+		default:
+			state := c.State()
+			status, err := c.GetStatus(fmi2.DoStepStatus)
+			if state == fmi2.StateStepInProgress && err == nil && status == fmi2.Pending {
+				polls++
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	require.Positive(t, polls)
+	require.Equal(t, fmi2.StateStepComplete, c.State())
+
+	// the step is canceled, but the FMU notifies its end while the next step is pending
+	require.NoError(t, c.Set(map[string]any{"duration": 0.1}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done = c.DoStepAsync(ctx, 0.1, 0.1, false)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, fmi2.StateStepCanceled, c.State())
+
+	require.NoError(t, c.Reset())
+	initialize()
+	require.NoError(t, c.Set(map[string]any{"duration": 0.3}))
+
+	start := time.Now()
+	require.NoError(t, <-c.DoStepAsync(context.Background(), 0, 0.1, false))
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+
+	steps, err := c.Get("steps")
+	require.NoError(t, err)
+	require.Equal(t, 1, steps)
+}
+
+func TestDoStepAsyncCanceledWithoutNotification(t *testing.T) {
+
+	fmu, err := fmi2.New("../../examples/Async.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	c := fmu.Instantiate("async", fmi2.CoSimulationType, fmu.ModelDescription().Guid, "", false, false, fmi2.WithAsynchronousSteps())
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	initialize := func() {
+		require.NoError(t, c.SetupExperiment(0))
+		require.NoError(t, c.EnterInitializationMode())
+		require.NoError(t, c.ExitInitializationMode())
+	}
+
+	initialize()
+
+	// the FMU never calls stepFinished for the canceled step
+	require.NoError(t, c.Set(map[string]any{"duration": 0.1, "notifyCanceled": false}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := c.DoStepAsync(ctx, 0, 0.1, false)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	require.NoError(t, c.Reset())
+	initialize()
+
+	// the notification of the next step is not passed to the canceled step
+	select {
+	case err := <-c.DoStepAsync(context.Background(), 0, 0.1, false):
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the step did not complete")
+	}
+
+	steps, err := c.Get("steps")
+	require.NoError(t, err)
+	require.Equal(t, 1, steps)
+}
+
+func TestResourceLocation(t *testing.T) {
+
+	require.Equal(t, "file:///tmp/fmu/resources", fmi2.FileURI("/tmp/fmu/resources"))
+	require.Equal(t, "file:///tmp/my%20fmu/%C3%BC%23/resources", fmi2.FileURI("/tmp/my fmu/ü#/resources"))
+	require.Equal(t, "file:/tmp/my%20fmu/resources", fmi2.FileURI("/tmp/my fmu/resources", fmi2.WithLegacyFileURI()))
+
+	fmu, err := fmi2.New("../../examples/Rectifier.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	location := fmu.ResourceLocation()
+	require.True(t, strings.HasPrefix(location, "file:///"), location)
+	require.True(t, strings.HasSuffix(location, "/resources"), location)
+
+	// the FMU is simulated with an overridden resource location
+	stopTime := 0.01
+
+	_, err = fmi2.SimulateFmu("../../examples/Rectifier.fmu", fmi2.SimulationOptions{
+		ResourceLocation: fmi2.FileURI(t.TempDir()),
+		StopTime:         &stopTime,
+		Initialize:       true,
+		Terminate:        true,
+	})
+	require.NoError(t, err)
+}
+
+func TestExtraction(t *testing.T) {
+
+	// the temporary directory is removed by Close
+	fmu, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType)
+	require.NoError(t, err)
+	require.DirExists(t, fmu.Directory)
+	require.FileExists(t, filepath.Join(fmu.Directory, "modelDescription.xml"))
+
+	require.NoError(t, fmu.Close())
+	require.NoDirExists(t, fmu.Directory)
+
+	// only the binaries of the current platform and the resources are extracted
+	fmu, err = fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithPartialExtraction())
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(fmu.Directory, "binaries"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, fmi2.CurrentMachine().Platform, entries[0].Name())
+	require.NoFileExists(t, filepath.Join(fmu.Directory, "modelDescription.xml"))
+	require.NoError(t, fmu.Close())
+
+	// the cached files are shared and not removed
+	cache := t.TempDir()
+
+	fmu1, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache))
+	require.NoError(t, err)
+
+	fmu2, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache))
+	require.NoError(t, err)
+
+	require.Equal(t, fmu1.Directory, fmu2.Directory)
+	require.Equal(t, cache, filepath.Dir(fmu1.Directory))
+
+	require.NoError(t, fmu1.Close())
+	require.NoError(t, fmu2.Close())
+	require.DirExists(t, fmu1.Directory)
+
+	// a partial extraction is cached separately
+	fmu3, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache), fmi2.WithPartialExtraction())
+	require.NoError(t, err)
+	require.NotEqual(t, fmu1.Directory, fmu3.Directory)
+	require.NoError(t, fmu3.Close())
+
+	// the cache can be used concurrently
+	cache = t.TempDir()
+
+	var wg sync.WaitGroup
+	fmus := make([]*fmi2.Fmu2, 8)
+	errs := make([]error, len(fmus))
+
+	for i := range fmus {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fmus[i], errs[i] = fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache))
+		}()
+	}
+
+	wg.Wait()
+
+	for i := range fmus {
+		require.NoError(t, errs[i])
+		require.Equal(t, fmus[0].Directory, fmus[i].Directory)
+		require.NoError(t, fmus[i].Close())
+	}
+}
+
+func TestNewFromMemoryAndDirectory(t *testing.T) {
+
+	data, err := os.ReadFile("../../examples/Bounce.fmu")
+	require.NoError(t, err)
+
+	fmu, err := fmi2.NewFromBytes(data, fmi2.ModelExchangeType)
+	require.NoError(t, err)
+	require.Equal(t, "2.0", fmu.GetVersion())
+	require.NoError(t, fmu.Close())
+	require.NoDirExists(t, fmu.Directory)
+
+	// the hash of the archive is used as the key of the cache
+	cache := t.TempDir()
+
+	fmu1, err := fmi2.NewFromReader(bytes.NewReader(data), int64(len(data)), fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache))
+	require.NoError(t, err)
+	require.NoError(t, fmu1.Close())
+
+	fmu2, err := fmi2.New("../../examples/Bounce.fmu", fmi2.ModelExchangeType, fmi2.WithExtractionCache(cache))
+	require.NoError(t, err)
+	require.NoError(t, fmu2.Close())
+	require.Equal(t, fmu1.Directory, fmu2.Directory)
+
+	// the extracted FMU is used as is
+	directory := t.TempDir()
+	require.NoError(t, fmi2.Unzip("../../examples/Bounce.fmu", directory))
+
+	md, err := fmi2.ReadModelDescriptionFS(os.DirFS(directory), nil)
+	require.NoError(t, err)
+	require.Equal(t, "Bounce", md.ModelName)
+	require.Contains(t, fmi2.SupportedPlatformsFS(os.DirFS(directory)), "linux64")
+
+	fmu, err = fmi2.NewFromDirectory(directory, fmi2.ModelExchangeType)
+	require.NoError(t, err)
+	require.Equal(t, directory, fmu.Directory)
+	require.NoError(t, fmu.Close())
+	require.DirExists(t, directory)
+
+	_, err = fmi2.NewFromBytes([]byte("not an FMU"), fmi2.ModelExchangeType)
+	require.Error(t, err)
+}
+
+func TestCompile(t *testing.T) {
+
+	// a source FMU without binaries
+	filename := repackage(t, "../../examples/Controller.fmu",
+		func(name string) string {
+			if strings.HasPrefix(name, "binaries/") {
+				return ""
+			}
+			return name
+		},
+		func(modelDescription string) string { return modelDescription })
+
+	require.Empty(t, fmi2.SupportedPlatforms(filename))
+
+	_, err := fmi2.New(filename, fmi2.CoSimulationType)
+	require.ErrorContains(t, err, "no shared library")
+
+	// the sources are compiled when the FMU is loaded
+	fmu, err := fmi2.New(filename, fmi2.CoSimulationType, fmi2.WithCompilation())
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(fmu.Directory, "binaries", fmi2.CurrentMachine().Platform, "Controller.so"))
+	require.Equal(t, "2.0", fmu.GetVersion())
+	require.NoError(t, fmu.Close())
+	require.NoDirExists(t, fmu.Directory)
+
+	// the rebuilt FMU contains the binary
+	output := filepath.Join(t.TempDir(), "Controller.fmu")
+	require.NoError(t, fmi2.Compile(filename, output))
+	require.Equal(t, []string{fmi2.CurrentMachine().Platform}, fmi2.SupportedPlatforms(output))
+
+	stopTime := 0.1
+
+	_, err = fmi2.SimulateFmu(output, fmi2.SimulationOptions{
+		StopTime:    &stopTime,
+		StartValues: map[string]any{"u_s": 0.0},
+		Initialize:  true,
+		Terminate:   true,
+	})
+	require.NoError(t, err)
+
+	// the source files that are listed in the model description must exist
+	err = fmi2.Compile("../../examples/Rectifier.fmu", filepath.Join(t.TempDir(), "Rectifier.fmu"))
+	require.ErrorContains(t, err, "Rectifier.c is missing")
+
+	err = fmi2.Compile(filename, output, fmi2.WithCompilerFlags("-DFMI_2", "-invalid-flag"))
+	require.ErrorContains(t, err, "failed to compile")
+}
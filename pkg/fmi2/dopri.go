@@ -0,0 +1,174 @@
+package fmi2
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Butcher tableau of the Dormand-Prince method
+var (
+	dopriC = [7]float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1}
+	dopriA = [7][6]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	}
+	// difference between the fifth and the embedded fourth order solution
+	dopriE = [7]float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+)
+
+// DormandPrinceSolver is the explicit Runge-Kutta method of order 5(4) by Dormand and Prince
+// with adaptive step size control. Zero crossings of the event indicators are located within the step.
+type DormandPrinceSolver struct {
+	system            OdeSystem
+	h                 float64
+	maxStepSize       float64
+	relativeTolerance float64
+	absoluteTolerance float64
+	z                 []float64
+}
+
+func NewDormandPrinceSolver(system OdeSystem, t float64, options *SolverOptions) (*DormandPrinceSolver, error) {
+
+	s := &DormandPrinceSolver{
+		system:            system,
+		maxStepSize:       options.maxStepSize,
+		relativeTolerance: options.relativeTolerance,
+		absoluteTolerance: options.absoluteTolerance,
+		h:                 math.Min(options.stepSize, options.maxStepSize),
+	}
+
+	if err := s.Reset(t); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *DormandPrinceSolver) Reset(t float64) error {
+	z, err := s.system.GetEventIndicators()
+	if err != nil {
+		return err
+	}
+
+	s.z = z
+	return nil
+}
+
+func (s *DormandPrinceSolver) Step(t float64, tNext float64) (float64, bool, error) {
+
+	x, err := s.system.GetContinuousStates()
+	if err != nil {
+		return t, false, err
+	}
+
+	dx, err := evaluate(s.system, t, x)
+	if err != nil {
+		return t, false, err
+	}
+
+	for t < tNext && !Float64IsClose(t, tNext, WithEpsilon(1e-12)) {
+
+		tStep := nextStepTime(t, s.h, tNext)
+		h := tStep - t
+
+		xNext, k, err := s.stages(t, h, x, dx)
+		if err != nil {
+			return t, false, err
+		}
+
+		errorNorm := 0.0
+		for n := range x {
+			e := 0.0
+			for j := range k {
+				e += h * dopriE[j] * k[j][n]
+			}
+
+			scale := s.absoluteTolerance + s.relativeTolerance*math.Max(math.Abs(x[n]), math.Abs(xNext[n]))
+			errorNorm += (e / scale) * (e / scale)
+		}
+
+		if len(x) > 0 {
+			errorNorm = math.Sqrt(errorNorm / float64(len(x)))
+		}
+
+		factor := 5.0
+		if errorNorm > 0 {
+			factor = math.Max(0.2, math.Min(5.0, 0.9*math.Pow(errorNorm, -1.0/5)))
+		}
+
+		hNew := math.Min(h*factor, s.maxStepSize)
+
+		if errorNorm > 1.0 {
+			// reject the step and retry with a smaller step size
+			if hNew < minStepSize(t) {
+				return t, false, fmt.Errorf("step size too small at t = %g", t)
+			}
+
+			s.h = hNew
+			continue
+		}
+
+		// don't let a step that was shortened to reach tNext reduce the step size
+		if tStep != tNext || hNew > s.h {
+			s.h = hNew
+		}
+
+		// the system is at the new solution after the last stage
+		z, err := s.system.GetEventIndicators()
+		if err != nil {
+			return t, false, err
+		}
+
+		if zeroCrossing(s.z, z) {
+			tEvent, err := locateEvent(s.system, t, x, dx, s.z, tStep, xNext, k[6])
+			if err != nil {
+				return t, false, err
+			}
+
+			return tEvent, true, s.Reset(tEvent)
+		}
+
+		t = tStep
+		x = xNext
+		dx = k[6]
+		s.z = z
+	}
+
+	// leave the system at the accepted solution
+	if _, err := evaluate(s.system, t, x); err != nil {
+		return t, false, err
+	}
+
+	return t, false, nil
+}
+
+// stages computes the stages of a step of size h from (t, x), where dx are the derivatives at (t, x).
+// The last stage is evaluated at the new solution, so it can be reused as the first stage of the next step (FSAL).
+func (s *DormandPrinceSolver) stages(t float64, h float64, x []float64, dx []float64) ([]float64, [7][]float64, error) {
+
+	var k [7][]float64
+	var xi []float64
+
+	k[0] = dx
+	for i := 1; i < 7; i++ {
+		xi = slices.Clone(x)
+		for j := 0; j < i; j++ {
+			for n := range xi {
+				xi[n] += h * dopriA[i][j] * k[j][n]
+			}
+		}
+
+		var err error
+		if k[i], err = evaluate(s.system, t+dopriC[i]*h, xi); err != nil {
+			return nil, k, err
+		}
+	}
+
+	return xi, k, nil
+}
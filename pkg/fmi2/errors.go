@@ -0,0 +1,82 @@
+package fmi2
+
+/*
+#include "headers/fmi2Functions.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"unsafe"
+)
+
+// ErrFunctionNotAvailable is matched by errors.Is for every FunctionNotAvailableError
+var ErrFunctionNotAvailable = fmi.ErrFunctionNotAvailable
+
+// FunctionNotAvailableError is returned when the FMU does not export the FMI function that is called
+type FunctionNotAvailableError = fmi.FunctionNotAvailableError
+
+// ErrFatal is matched by errors.Is for the errors of all calls to an instance after it returned Fatal
+var ErrFatal = fmi.ErrFatal
+
+// StatusError is returned when an FMI function returns a status other than OK or Warning
+type StatusError = fmi.StatusError[Status]
+
+// IsDiscard returns whether err is a StatusError with the status Discard
+func IsDiscard(err error) bool {
+	return fmi.HasStatus(err, Discard)
+}
+
+// WarningHandler is called when an FMI function returns Warning
+type WarningHandler = fmi.WarningHandler[*Component]
+
+// SetWarningHandler sets the function that is called when an FMI function returns Warning.
+// Warnings are treated as success, the details are reported by the FMU via the logger.
+func (f *Fmu2) SetWarningHandler(handler WarningHandler) {
+	f.warningHandler = handler
+}
+
+// available returns an error if the FMU does not export the function, the instance returned Fatal before
+// or the function must not be called in the state of the instance
+func (f *Fmu2) available(c *Component, ptr unsafe.Pointer, function string) error {
+	if ptr == nil {
+		return &FunctionNotAvailableError{Function: function}
+	}
+
+	if c == nil {
+		return nil
+	}
+
+	if c.isFatal() {
+		return fmt.Errorf("cannot call %s: %w", function, ErrFatal)
+	}
+
+	return c.checkCall(function)
+}
+
+// check converts the status that the function returned to an error and updates the state of the instance.
+// Warning is treated as success and Fatal prevents any further calls to the instance.
+func (f *Fmu2) check(c *Component, function string, status C.fmi2Status) error {
+	if c != nil {
+		c.transition(function, Status(status))
+	}
+
+	switch Status(status) {
+	case OK:
+		return nil
+	case Warning:
+		if f.warningHandler != nil {
+			f.warningHandler(c, function)
+		}
+		return nil
+	case Fatal:
+		if c != nil {
+			c.mutex.Lock()
+			c.fatal = true
+			c.mutex.Unlock()
+		}
+	}
+
+	return &StatusError{Function: function, Status: Status(status)}
+}
@@ -0,0 +1,65 @@
+package fmi2
+
+import (
+	"archive/zip"
+	"go-fmu/pkg/internal/fmi"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type LoadOption func(*LoadOptions)
+
+type LoadOptions struct {
+	cacheDirectory string
+	partial        bool
+	compile        bool // compile the sources if the FMU contains no binary for the current platform
+	compileOptions []CompileOption
+}
+
+// WithExtractionCache extracts the FMU into a sub-directory of directory that is named after the SHA-256 hash
+// of the FMU. The extracted files are shared by all instances of the same FMU, also across processes,
+// and are not removed by Close.
+func WithExtractionCache(directory string) LoadOption {
+	return func(o *LoadOptions) {
+		o.cacheDirectory = directory
+	}
+}
+
+// WithPartialExtraction extracts only the binaries for the current platform and the resources of the FMU
+func WithPartialExtraction() LoadOption {
+	return func(o *LoadOptions) {
+		o.partial = true
+	}
+}
+
+// DefaultCacheDirectory returns the directory of the extraction cache in the user's cache directory
+func DefaultCacheDirectory() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "go-fmu"), nil
+}
+
+// extract extracts the FMU into a new temporary directory or into the cache.
+// It returns whether the directory is owned by the caller and has to be removed.
+func extract(r *zip.Reader, hash func() (string, error), options *LoadOptions) (string, bool, error) {
+
+	extractOptions := fmi.ExtractOptions{CacheDirectory: options.cacheDirectory, Hash: hash}
+
+	if options.partial {
+		platform := CurrentMachine().Platform
+		binaries := "binaries/" + platform + "/"
+
+		extractOptions.Filter = func(name string) bool {
+			return strings.HasPrefix(name, binaries) || strings.HasPrefix(name, "resources/")
+		}
+
+		// the partially extracted FMU must not be used for a full extraction and vice versa
+		extractOptions.Suffix = "-" + platform
+	}
+
+	return fmi.Extract(r, extractOptions)
+}
@@ -0,0 +1,530 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi1"
+	"slices"
+)
+
+// modelDescriptionFromFmi1 converts an FMI 1.0 model description to the FMI 2.0 structure, so FMI 1.0 FMUs
+// can be inspected and simulated with the same functions. The FmiVersion remains "1.0".
+func modelDescriptionFromFmi1(md1 *fmi1.ModelDescription) *ModelDescription {
+
+	md := &ModelDescription{
+		FmiVersion:               md1.FmiVersion,
+		ModelName:                md1.ModelName,
+		Guid:                     md1.Guid,
+		Description:              md1.Description,
+		Author:                   md1.Author,
+		Version:                  md1.Version,
+		GenerationTool:           md1.GenerationTool,
+		GenerationDateAndTime:    md1.GenerationDateAndTime,
+		VariableNamingConvention: md1.VariableNamingConvention,
+		NumberOfEventIndicators:  md1.NumberOfEventIndicators,
+		numberOfContinuousStates: int(md1.NumberOfContinuousStates),
+	}
+
+	if capabilities := md1.Capabilities(); capabilities != nil {
+		md.CoSimulation = &CoSimulation{
+			ModelIdentifier:                        md1.ModelIdentifier,
+			NeedsExecutionTool:                     md1.Implementation.CoSimulationTool != nil,
+			CanHandleVariableCommunicationStepSize: capabilities.CanHandleVariableCommunicationStepSize,
+			CanInterpolateInputs:                   capabilities.CanInterpolateInputs,
+			MaxOutputDerivativeOrder:               capabilities.MaxOutputDerivativeOrder,
+			CanRunAsynchronuously:                  capabilities.CanRunAsynchronuously,
+			CanBeInstantiatedOnlyOncePerProcess:    capabilities.CanBeInstantiatedOnlyOncePerProcess,
+			CanNotUseMemoryManagementFunctions:     capabilities.CanNotUseMemoryManagementFunctions,
+		}
+	} else {
+		md.ModelExchange = &ModelExchange{ModelIdentifier: md1.ModelIdentifier}
+	}
+
+	if experiment := md1.DefaultExperiment; experiment != nil {
+		md.DefaultExperiment = &DefaultExperiment{
+			StartTime: experiment.StartTime,
+			StopTime:  experiment.StopTime,
+			Tolerance: experiment.Tolerance,
+		}
+	}
+
+	units := make([]Unit, 0)
+	declared := make(map[string]bool)
+
+	// FMI 1.0 does not require the units to be declared, so the units that are only used are added as well
+	addUnit := func(name string) {
+		if name != "" && !declared[name] {
+			units = append(units, Unit{Name: name})
+			declared[name] = true
+		}
+	}
+
+	if md1.UnitDefinitions != nil {
+		for _, baseUnit := range md1.UnitDefinitions.BaseUnit {
+			unit := Unit{Name: baseUnit.Unit}
+			for _, displayUnit := range baseUnit.DisplayUnitDefinition {
+				unit.DisplayUnit = append(unit.DisplayUnit, DisplayUnit{Name: displayUnit.DisplayUnit, Factor: displayUnit.Gain, Offset: displayUnit.Offset})
+			}
+			units = append(units, unit)
+			declared[unit.Name] = true
+		}
+	}
+
+	if md1.TypeDefinitions != nil {
+		simpleTypes := make([]SimpleType, 0, len(md1.TypeDefinitions.Type))
+
+		for _, t := range md1.TypeDefinitions.Type {
+			simpleType := SimpleType{Name: t.Name, Description: t.Description}
+
+			switch {
+			case t.RealType != nil:
+				simpleType.Real = &RealType{RealAttributes: realAttributesFromFmi1(t.RealType.RealAttributes)}
+				addUnit(t.RealType.Unit)
+			case t.IntegerType != nil:
+				simpleType.Integer = &IntegerType{IntegerAttributes: integerAttributesFromFmi1(t.IntegerType.IntegerAttributes)}
+			case t.BooleanType != nil:
+				simpleType.Boolean = &Boolean{}
+			case t.StringType != nil:
+				simpleType.String = &String{}
+			case t.EnumerationType != nil:
+				simpleType.Enumeration = &EnumerationType{Quantity: t.EnumerationType.Quantity}
+				// the value of an item is its 1-based index
+				for i, item := range t.EnumerationType.Item {
+					simpleType.Enumeration.Item = append(simpleType.Enumeration.Item, Item{Name: item.Name, Value: i + 1, Description: item.Description})
+				}
+			}
+
+			simpleTypes = append(simpleTypes, simpleType)
+		}
+
+		md.TypeDefinitions = []TypeDefinitions{{SimpleType: simpleTypes}}
+	}
+
+	variables := make([]ScalarVariable, 0, len(md1.Variables()))
+
+	for _, v1 := range md1.Variables() {
+
+		// FMI 2.0 has no negated aliases, so the values of these variables cannot be accessed correctly
+		if v1.Alias == "negatedAlias" {
+			md.negatedAliases = append(md.negatedAliases, v1.Name)
+			continue
+		}
+
+		v := ScalarVariable{
+			Name:           v1.Name,
+			ValueReference: ValueReference(v1.ValueReference),
+			Description:    v1.Description,
+		}
+
+		var fixed *bool
+
+		switch {
+		case v1.Real != nil:
+			v.Real = &Real{RealAttributes: realAttributesFromFmi1(v1.Real.RealAttributes), DeclaredType: v1.Real.DeclaredType, Start: v1.Real.Start}
+			fixed = v1.Real.Fixed
+			addUnit(v1.Real.Unit)
+		case v1.Integer != nil:
+			v.Integer = &Integer{IntegerAttributes: integerAttributesFromFmi1(v1.Integer.IntegerAttributes), DeclaredType: v1.Integer.DeclaredType, Start: v1.Integer.Start}
+			fixed = v1.Integer.Fixed
+		case v1.Boolean != nil:
+			v.Boolean = &Boolean{DeclaredType: v1.Boolean.DeclaredType, Start: v1.Boolean.Start}
+			fixed = v1.Boolean.Fixed
+		case v1.String != nil:
+			v.String = &String{DeclaredType: v1.String.DeclaredType, Start: v1.String.Start}
+			fixed = v1.String.Fixed
+		case v1.Enumeration != nil:
+			v.Enumeration = &Enumeration{
+				DeclaredType: v1.Enumeration.DeclaredType,
+				Quantity:     v1.Enumeration.Quantity,
+				Min:          v1.Enumeration.Min,
+				Max:          v1.Enumeration.Max,
+				Start:        v1.Enumeration.Start,
+			}
+			fixed = v1.Enumeration.Fixed
+		}
+
+		// only one variable of an alias set may define a start value
+		if v1.Alias == "alias" {
+			clearStart(&v)
+		}
+
+		switch v1.CausalityOf() {
+		case "input", "output":
+			v.Causality = v1.CausalityOf()
+		default:
+			v.Causality = "local"
+		}
+
+		v.Variability = v1.VariabilityOf()
+
+		if v.Variability == "parameter" {
+			v.Variability = "fixed"
+
+			if v.Causality == "output" || !hasStart(&v) {
+				v.Causality = "calculatedParameter"
+			} else {
+				v.Causality = "parameter"
+			}
+		}
+
+		// a start value that is not fixed is a guess value, FMI 1.0 only allows to set the start values of
+		// parameters, inputs and guess values before fmiInitialize
+		switch {
+		case !hasStart(&v) || v.Causality == "input" || v.Causality == "parameter" || v.Variability == "constant":
+			// the default of the causality and variability
+		case v.Causality == "calculatedParameter" || fixed != nil && !*fixed:
+			v.Initial = "approx"
+		default:
+			v.Initial = "exact"
+			v.fixedStart = true
+		}
+
+		variables = append(variables, v)
+	}
+
+	md.ModelVariables = &ModelVariables{ScalarVariable: variables}
+
+	if len(units) > 0 {
+		md.UnitDefinitions = []UnitDefinitions{{Unit: units}}
+	}
+
+	return md
+}
+
+func realAttributesFromFmi1(a fmi1.RealAttributes) RealAttributes {
+	return RealAttributes{
+		Quantity:         a.Quantity,
+		Unit:             a.Unit,
+		DisplayUnit:      a.DisplayUnit,
+		RelativeQuantity: a.RelativeQuantity,
+		Min:              a.Min,
+		Max:              a.Max,
+		Nominal:          a.Nominal,
+	}
+}
+
+func integerAttributesFromFmi1(a fmi1.IntegerAttributes) IntegerAttributes {
+	return IntegerAttributes{Quantity: a.Quantity, Min: a.Min, Max: a.Max}
+}
+
+// clearStart removes the start value of the variable
+func clearStart(v *ScalarVariable) {
+	switch {
+	case v.Real != nil:
+		v.Real.Start = nil
+	case v.Integer != nil:
+		v.Integer.Start = nil
+	case v.Boolean != nil:
+		v.Boolean.Start = nil
+	case v.String != nil:
+		v.String.Start = nil
+	case v.Enumeration != nil:
+		v.Enumeration.Start = nil
+	}
+}
+
+// unknownVariableError returns the error for a name that is not a variable of the model description,
+// which explains that the negated aliases of FMI 1.0 models are not supported
+func (md *ModelDescription) unknownVariableError(format string, name string) error {
+	if slices.Contains(md.negatedAliases, name) {
+		return fmt.Errorf("%s is a negated alias, negated aliases are not supported", name)
+	}
+	return fmt.Errorf(format, name)
+}
+
+// fmi1Instance adapts an FMI 1.0 instance to the Instance interface, so FMI 1.0 FMUs are simulated by
+// SimulateME and SimulateCS. FMI 1.0 has no Initialization Mode: all values are set before
+// ExitInitializationMode calls fmiInitialize or fmiInitializeSlave.
+type fmi1Instance struct {
+	instance          *fmi1.Instance
+	relativeTolerance *float64 // the tolerance of fmiInitialize, SimulateME does not pass it to SetupExperiment
+	startTime         float64
+	experiment        SetupExperimentOptions
+	eventInfo         *EventInfo // the result of fmiInitialize that is returned by the first call of NewDiscreteStates
+}
+
+// NewFmi1Instance adapts an FMI 1.0 instance to the Instance interface. The relative tolerance of
+// fmiInitialize is set with SetupExperiment.
+func NewFmi1Instance(instance *fmi1.Instance) Instance {
+	return &fmi1Instance{instance: instance}
+}
+
+func fmi1References(vr []ValueReference) []fmi1.ValueReference {
+	return Transform(vr, func(i int, v ValueReference) fmi1.ValueReference { return fmi1.ValueReference(v) })
+}
+
+// fmi1Error converts the status errors of FMI 1.0 functions, so IsDiscard works for both versions
+func fmi1Error(err error) error {
+	var statusError *fmi1.StatusError
+	if errors.As(err, &statusError) {
+		return &StatusError{Function: statusError.Function, Status: Status(statusError.Status)}
+	}
+	return err
+}
+
+// eventInfoFromFmi1 converts the event info of fmiInitialize and fmiEventUpdate
+func eventInfoFromFmi1(eventInfo *fmi1.EventInfo) *EventInfo {
+	return &EventInfo{
+		NewDiscreteStatesNeeded:         !eventInfo.IterationConverged,
+		TerminateSimulation:             eventInfo.TerminateSimulation,
+		ValuesOfContinuousStatesChanged: eventInfo.StateValuesChanged,
+		NextEventTimeDefined:            eventInfo.UpcomingTimeEvent,
+		NextEventTime:                   eventInfo.NextEventTime,
+	}
+}
+
+func (c *fmi1Instance) SetupExperiment(tStart float64, opts ...SetupExperimentOption) error {
+
+	c.startTime = tStart
+
+	for _, opt := range opts {
+		opt(&c.experiment)
+	}
+
+	// the model is initialized at the time that is set before fmiInitialize
+	if c.instance.Type() == fmi1.ModelExchangeType {
+		return c.instance.SetTime(tStart)
+	}
+
+	return nil
+}
+
+func (c *fmi1Instance) EnterInitializationMode() error {
+	return nil
+}
+
+func (c *fmi1Instance) ExitInitializationMode() error {
+
+	if c.instance.Type() == fmi1.ModelExchangeType {
+
+		relativeTolerance, toleranceControlled := c.experiment.RelativeTolerance()
+		if c.relativeTolerance != nil {
+			relativeTolerance, toleranceControlled = *c.relativeTolerance, true
+		}
+
+		// fmiInitialize performs the event iteration at the start time
+		eventInfo, err := c.instance.Initialize(toleranceControlled, relativeTolerance)
+		if err != nil {
+			return err
+		}
+
+		c.eventInfo = eventInfoFromFmi1(eventInfo)
+
+		return nil
+	}
+
+	var options []fmi1.InitializeSlaveOption
+	if stopTime, ok := c.experiment.StopTime(); ok {
+		options = append(options, fmi1.WithStopTime(stopTime))
+	}
+
+	return c.instance.InitializeSlave(c.startTime, options...)
+}
+
+func (c *fmi1Instance) Terminate() error {
+	return c.instance.Terminate()
+}
+
+func (c *fmi1Instance) FreeInstance() {
+	c.instance.FreeInstance()
+}
+
+func (c *fmi1Instance) GetReal(vr []ValueReference) ([]float64, error) {
+	return c.instance.GetReal(fmi1References(vr))
+}
+
+func (c *fmi1Instance) GetInteger(vr []ValueReference) ([]int, error) {
+	return c.instance.GetInteger(fmi1References(vr))
+}
+
+func (c *fmi1Instance) GetBoolean(vr []ValueReference) ([]bool, error) {
+	return c.instance.GetBoolean(fmi1References(vr))
+}
+
+func (c *fmi1Instance) GetString(vr []ValueReference) ([]string, error) {
+	return c.instance.GetString(fmi1References(vr))
+}
+
+func (c *fmi1Instance) SetReal(vr []ValueReference, value []float64) error {
+	return c.instance.SetReal(fmi1References(vr), value)
+}
+
+func (c *fmi1Instance) SetInteger(vr []ValueReference, value []int) error {
+	return c.instance.SetInteger(fmi1References(vr), value)
+}
+
+func (c *fmi1Instance) SetBoolean(vr []ValueReference, value []bool) error {
+	return c.instance.SetBoolean(fmi1References(vr), value)
+}
+
+func (c *fmi1Instance) SetString(vr []ValueReference, value []string) error {
+	return c.instance.SetString(fmi1References(vr), value)
+}
+
+// Model Exchange
+
+func (c *fmi1Instance) SetTime(time float64) error {
+	return c.instance.SetTime(time)
+}
+
+// EnterEventMode does nothing, FMI 1.0 has no Event Mode
+func (c *fmi1Instance) EnterEventMode() error {
+	return nil
+}
+
+// EnterContinuousTimeMode does nothing, FMI 1.0 has no Continuous-Time Mode
+func (c *fmi1Instance) EnterContinuousTimeMode() error {
+	return nil
+}
+
+// NewDiscreteStates returns the result of fmiInitialize after the initialization and calls fmiEventUpdate otherwise
+func (c *fmi1Instance) NewDiscreteStates() (*EventInfo, error) {
+
+	if eventInfo := c.eventInfo; eventInfo != nil {
+		c.eventInfo = nil
+		return eventInfo, nil
+	}
+
+	eventInfo, err := c.instance.EventUpdate(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventInfoFromFmi1(eventInfo), nil
+}
+
+func (c *fmi1Instance) GetContinuousStates(nx int) ([]float64, error) {
+	return c.instance.GetContinuousStates(nx)
+}
+
+func (c *fmi1Instance) SetContinuousStates(x []float64) error {
+	return c.instance.SetContinuousStates(x)
+}
+
+func (c *fmi1Instance) GetDerivatives(nx int) ([]float64, error) {
+	return c.instance.GetDerivatives(nx)
+}
+
+func (c *fmi1Instance) GetEventIndicators(ni int) ([]float64, error) {
+	return c.instance.GetEventIndicators(ni)
+}
+
+// CompletedIntegratorStep returns whether fmiEventUpdate must be called, FMI 1.0 models cannot request to terminate
+func (c *fmi1Instance) CompletedIntegratorStep(noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error) {
+	callEventUpdate, err := c.instance.CompletedIntegratorStep()
+	return callEventUpdate, false, err
+}
+
+func (c *fmi1Instance) GetDirectionalDerivative(zRef []ValueReference, vRef []ValueReference, dv []float64) ([]float64, error) {
+	return nil, &FunctionNotAvailableError{Function: "fmiGetDirectionalDerivative"}
+}
+
+// Co-Simulation
+
+func (c *fmi1Instance) DoStep(currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) error {
+	return fmi1Error(c.instance.DoStep(currentCommunicationPoint, communicationStepSize, true))
+}
+
+func (c *fmi1Instance) GetRealStatus(s StatusKind) (float64, error) {
+	kind, err := fmi1StatusKind(s)
+	if err != nil {
+		return 0, err
+	}
+	return c.instance.GetRealStatus(kind)
+}
+
+// GetBooleanStatus reports a terminated simulation for Terminated, because FMI 1.0 has no such status and
+// a slave that discarded a step cannot continue, so the simulation ends at the last successful time
+func (c *fmi1Instance) GetBooleanStatus(s StatusKind) (bool, error) {
+	if s == Terminated {
+		return true, nil
+	}
+
+	kind, err := fmi1StatusKind(s)
+	if err != nil {
+		return false, err
+	}
+	return c.instance.GetBooleanStatus(kind)
+}
+
+func (c *fmi1Instance) SetRealInputDerivatives(vr []ValueReference, order []int, value []float64) error {
+	return c.instance.SetRealInputDerivatives(fmi1References(vr), order, value)
+}
+
+// fmi1StatusKind converts the status kinds that FMI 1.0 defines
+func fmi1StatusKind(s StatusKind) (fmi1.StatusKind, error) {
+	switch s {
+	case DoStepStatus:
+		return fmi1.DoStepStatus, nil
+	case PendingStatus:
+		return fmi1.PendingStatus, nil
+	case LastSuccessfulTime:
+		return fmi1.LastSuccessfulTime, nil
+	default:
+		return 0, fmt.Errorf("the status kind %d is not defined by FMI 1.0", s)
+	}
+}
+
+// simulateFmi1 simulates an FMI 1.0 FMU with the options that SimulateFmu has completed.
+// The extraction cache and partial extraction are not supported for FMI 1.0.
+func simulateFmi1(filename string, options SimulationOptions) (*Result, error) {
+
+	if options.FmuInstance != nil {
+		return nil, errors.New("FmuInstance cannot be used with FMI 1.0 FMUs")
+	}
+
+	fmu, err := fmi1.New(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fmu.Close()
+
+	var instantiateOptions []fmi1.InstantiateOption
+
+	if options.Logger != nil {
+		instantiateOptions = append(instantiateOptions, fmi1.WithLogger(func(instanceName string, status fmi1.Status, category string, message string) {
+			options.Logger(instanceName, Status(status), category, message)
+		}))
+	}
+
+	if options.LogCategories != nil {
+		instantiateOptions = append(instantiateOptions, fmi1.WithLogCategories(options.LogCategories...))
+	}
+
+	md := options.ModelDescription
+
+	var instance *fmi1.Instance
+
+	if options.FmiType == "ModelExchange" {
+
+		if fmu.Type() != fmi1.ModelExchangeType {
+			return nil, errors.New("the FMU does not support Model Exchange")
+		}
+
+		instance = fmu.InstantiateModel(md.ModelName, md.Guid, options.DebugLogging, instantiateOptions...)
+
+	} else {
+
+		if fmu.Type() != fmi1.CoSimulationType {
+			return nil, errors.New("the FMU does not support Co-Simulation")
+		}
+
+		var fmuLocation string
+		if options.LegacyResourceLocation {
+			fmuLocation = FileURI(fmu.Directory, WithLegacyFileURI())
+		} else {
+			fmuLocation = FileURI(fmu.Directory)
+		}
+
+		instance = fmu.InstantiateSlave(md.ModelName, md.Guid, fmuLocation, 0, options.Visible, false, options.DebugLogging, instantiateOptions...)
+	}
+
+	if instance == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+
+	defer instance.FreeInstance()
+
+	return simulateInstance(&fmi1Instance{instance: instance, relativeTolerance: options.RelativeTolerance}, options)
+}
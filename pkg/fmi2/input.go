@@ -0,0 +1,244 @@
+package fmi2
+
+import (
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"math"
+	"sort"
+)
+
+// Signal is a sampled input signal. Time must be non-decreasing.
+// Two samples with the same time mark a discontinuity: the first value holds before and the second value after the event.
+// Integer and Boolean (non-zero is true) values are given as float64.
+type Signal struct {
+	Time   []float64
+	Values []float64
+}
+
+// value returns the value of the signal at time t. Continuous signals are interpolated linearly,
+// discrete signals are held constant between the samples (zero-order hold).
+// At a discontinuity afterEvent selects the value after the event.
+func (s *Signal) value(t float64, continuous bool, afterEvent bool) float64 {
+
+	n := len(s.Time)
+
+	// the index of the first sample after t, or at or after t if the value before the event is requested
+	i := sort.Search(n, func(i int) bool {
+		if afterEvent {
+			return s.Time[i] > t
+		}
+		return s.Time[i] >= t
+	})
+
+	if i == 0 {
+		return s.Values[0]
+	}
+
+	if i == n {
+		return s.Values[n-1]
+	}
+
+	if !continuous {
+		return s.Values[i-1]
+	}
+
+	if s.Time[i] == t {
+		return s.Values[i]
+	}
+
+	t0, t1 := s.Time[i-1], s.Time[i]
+	v0, v1 := s.Values[i-1], s.Values[i]
+
+	return v0 + (v1-v0)*(t-t0)/(t1-t0)
+}
+
+// derivative returns the slope of the linear interpolation of the signal after time t
+func (s *Signal) derivative(t float64) float64 {
+
+	n := len(s.Time)
+
+	i := sort.Search(n, func(i int) bool { return s.Time[i] > t })
+
+	if i == 0 || i == n || s.Time[i] == s.Time[i-1] {
+		return 0
+	}
+
+	return (s.Values[i] - s.Values[i-1]) / (s.Time[i] - s.Time[i-1])
+}
+
+// nextEvent returns the time of the first discontinuity of the signal after time t or +Inf if there is none.
+// Continuous signals are discontinuous at repeated samples, discrete signals wherever the value changes.
+func (s *Signal) nextEvent(t float64, continuous bool) float64 {
+
+	i := sort.Search(len(s.Time), func(i int) bool { return s.Time[i] > t })
+
+	for i = max(i, 1); i < len(s.Time); i++ {
+
+		if Float64IsClose(s.Time[i], t, WithEpsilon(1e-10)) {
+			continue
+		}
+
+		if continuous && s.Time[i] == s.Time[i-1] && s.Values[i] != s.Values[i-1] {
+			return s.Time[i]
+		}
+
+		if !continuous && s.Values[i] != s.Values[i-1] {
+			return s.Time[i]
+		}
+	}
+
+	return math.Inf(1)
+}
+
+// inputVariable is a variable of the FMU that is set from a signal
+type inputVariable struct {
+	name   string
+	vr     ValueReference
+	typ    string
+	signal *Signal
+}
+
+// Input sets the values of the input variables of an FMU instance from sampled signals
+type Input struct {
+	fmu                 variableAccess
+	setInputDerivatives bool
+	continuous          []inputVariable // continuous Real inputs (linear interpolation)
+	discrete            []inputVariable // discrete Real, Integer, Enumeration and Boolean inputs (zero-order hold)
+}
+
+// NewInput creates an Input for the signals, which map the names of input variables to their values.
+// If setInputDerivatives is true, the first derivatives of the continuous inputs are set as well (Co-Simulation only).
+func NewInput(fmu Instance, modelDescription *ModelDescription, signals map[string]Signal, setInputDerivatives bool) (*Input, error) {
+	return newInput(fmu, modelDescription, signals, setInputDerivatives)
+}
+
+// newInput creates an Input that sets the values through access
+func newInput(fmu variableAccess, modelDescription *ModelDescription, signals map[string]Signal, setInputDerivatives bool) (*Input, error) {
+
+	input := &Input{fmu: fmu, setInputDerivatives: setInputDerivatives}
+
+	if len(signals) == 0 {
+		return input, nil
+	}
+
+	byName := modelDescription.variablesByName()
+
+	// iterate over the names in a fixed order, so the inputs are always set in the same order
+	for _, name := range fmi.SortedNames(signals) {
+
+		signal := signals[name]
+
+		v, ok := byName[name]
+		if !ok {
+			return nil, modelDescription.unknownVariableError("unknown input variable: %s", name)
+		}
+
+		if v.Causality != "input" {
+			return nil, fmt.Errorf("variable %s is not an input", name)
+		}
+
+		if len(signal.Time) == 0 || len(signal.Time) != len(signal.Values) {
+			return nil, fmt.Errorf("the signal of %s must have the same number of time points and values (at least one)", name)
+		}
+
+		for i := 1; i < len(signal.Time); i++ {
+			if signal.Time[i] < signal.Time[i-1] {
+				return nil, fmt.Errorf("the time of the signal of %s is not non-decreasing", name)
+			}
+		}
+
+		variable := inputVariable{
+			name:   name,
+			vr:     v.ValueReference,
+			typ:    variableType(v),
+			signal: &signal,
+		}
+
+		switch variable.typ {
+		case "Real":
+			if v.Variability == "" || v.Variability == "continuous" {
+				input.continuous = append(input.continuous, variable)
+			} else {
+				input.discrete = append(input.discrete, variable)
+			}
+		case "Integer", "Enumeration", "Boolean":
+			input.discrete = append(input.discrete, variable)
+		default:
+			return nil, fmt.Errorf("input variable %s has the unsupported type %s", name, variable.typ)
+		}
+	}
+
+	return input, nil
+}
+
+// Apply sets the continuous and/or discrete inputs to their values at time t.
+// At a discontinuity afterEvent selects the values after the event.
+func (in *Input) Apply(t float64, continuous bool, discrete bool, afterEvent bool) error {
+
+	if continuous && len(in.continuous) > 0 {
+		vrs := make([]ValueReference, len(in.continuous))
+		values := make([]float64, len(in.continuous))
+
+		for i, v := range in.continuous {
+			vrs[i] = v.vr
+			values[i] = v.signal.value(t, true, afterEvent)
+		}
+
+		if err := in.fmu.SetReal(vrs, values); err != nil {
+			return err
+		}
+
+		if in.setInputDerivatives {
+			orders := make([]int, len(in.continuous))
+			derivatives := make([]float64, len(in.continuous))
+
+			for i, v := range in.continuous {
+				orders[i] = 1
+				derivatives[i] = v.signal.derivative(t)
+			}
+
+			if err := in.fmu.SetRealInputDerivatives(vrs, orders, derivatives); err != nil {
+				return err
+			}
+		}
+	}
+
+	if discrete {
+		for _, v := range in.discrete {
+
+			value := v.signal.value(t, false, afterEvent)
+
+			var err error
+			switch v.typ {
+			case "Real":
+				err = in.fmu.SetReal([]ValueReference{v.vr}, []float64{value})
+			case "Integer", "Enumeration":
+				err = in.fmu.SetInteger([]ValueReference{v.vr}, []int{int(math.Round(value))})
+			case "Boolean":
+				err = in.fmu.SetBoolean([]ValueReference{v.vr}, []bool{value != 0})
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NextEvent returns the time of the next discontinuity of the inputs after time t or +Inf if there is none
+func (in *Input) NextEvent(t float64) float64 {
+
+	next := math.Inf(1)
+
+	for _, v := range in.continuous {
+		next = math.Min(next, v.signal.nextEvent(t, true))
+	}
+
+	for _, v := range in.discrete {
+		next = math.Min(next, v.signal.nextEvent(t, false))
+	}
+
+	return next
+}
@@ -0,0 +1,203 @@
+package fmi2
+
+import "math"
+
+// EventHandler handles the events of a Model Exchange instance that is advanced by an Integrator
+type EventHandler interface {
+	// CompletedIntegratorStep is called after every accepted integrator step and returns whether an event
+	// must be handled and whether the FMU requested to terminate the simulation
+	CompletedIntegratorStep() (bool, bool, error)
+
+	// HandleEvent performs the event iteration at time t and returns to Continuous-Time Mode. It returns the
+	// time of the next time event, which is +Inf if there is none, and whether the FMU requested to terminate.
+	HandleEvent(t float64) (float64, bool, error)
+}
+
+// Integrator advances a Model Exchange instance in Continuous-Time Mode with a solver and handles the
+// time, state and step events. It is used by SimulateME and by the instances of the fmu package.
+type Integrator struct {
+	system        OdeSystem
+	events        EventHandler
+	solver        Solver
+	nextEventTime float64
+	terminated    bool
+}
+
+// NewIntegrator creates an Integrator for an instance that is in Continuous-Time Mode. The solver must
+// integrate the system and nextEventTime is the time of the next time event or +Inf.
+func NewIntegrator(system OdeSystem, events EventHandler, solver Solver, nextEventTime float64) *Integrator {
+	return &Integrator{system: system, events: events, solver: solver, nextEventTime: nextEventTime}
+}
+
+// Terminated returns whether the FMU requested to terminate the simulation
+func (i *Integrator) Terminated() bool {
+	return i.terminated
+}
+
+// Step integrates from t towards tEnd and stops at the first time, state or step event, which is handled
+// before Step returns. If eventAtEnd is true an event is also handled when tEnd is reached, e.g. for a
+// change of the inputs. Step returns the time that was reached, which is t after the FMU requested to terminate.
+func (i *Integrator) Step(t float64, tEnd float64, eventAtEnd bool) (float64, error) {
+
+	if i.terminated {
+		return t, nil
+	}
+
+	tNext := math.Min(tEnd, i.nextEventTime)
+
+	var (
+		stateEvent bool
+		err        error
+	)
+
+	if tNext > t {
+		t, stateEvent, err = i.solver.Step(t, tNext)
+		if err != nil {
+			return t, err
+		}
+	} else {
+		t = tNext
+	}
+
+	// a state event may stop the solver before the time event or the end is reached
+	timeEvent := t >= i.nextEventTime || Float64IsClose(t, i.nextEventTime)
+	endEvent := eventAtEnd && Float64IsClose(t, tEnd)
+
+	if err := i.system.SetTime(t); err != nil {
+		return t, err
+	}
+
+	stepEvent, terminate, err := i.events.CompletedIntegratorStep()
+	if err != nil {
+		return t, err
+	}
+
+	if !terminate && (timeEvent || stateEvent || stepEvent || endEvent) {
+
+		i.nextEventTime, terminate, err = i.events.HandleEvent(t)
+		if err != nil {
+			return t, err
+		}
+
+		// the event may have changed the states and the event indicators
+		if !terminate {
+			if err := i.solver.Reset(t); err != nil {
+				return t, err
+			}
+		}
+	}
+
+	i.terminated = terminate
+
+	return t, nil
+}
+
+// NewSystem adapts a Model Exchange instance to the OdeSystem interface. The system provides the
+// Jacobian if the FMU provides directional derivatives.
+func NewSystem(fmu Instance, md *ModelDescription) (OdeSystem, error) {
+	return newSystem(fmu, md, nil)
+}
+
+// newSystem adapts the instance to the OdeSystem interface and applies the continuous inputs whenever the time is set
+func newSystem(fmu Instance, md *ModelDescription, input *Input) (OdeSystem, error) {
+
+	system := &componentSystem{
+		component: fmu,
+		input:     input,
+		nx:        md.NumberOfContinuousStates(),
+		nz:        int(md.NumberOfEventIndicators),
+	}
+
+	if md.ModelExchange == nil || !md.ModelExchange.ProvidesDirectionalDerivative {
+		return system, nil
+	}
+
+	states, derivatives, err := md.continuousStateReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	return &directionalDerivativeSystem{componentSystem: system, states: states, derivatives: derivatives}, nil
+}
+
+// InstanceEvents is the EventHandler of a Model Exchange Instance
+type InstanceEvents struct {
+	fmu                           Instance
+	completedIntegratorStepNeeded bool
+	input                         *Input    // the inputs that are applied in Event Mode (optional)
+	recorder                      *recorder // records the values before and after the events (optional)
+}
+
+// NewInstanceEvents creates the EventHandler of a Model Exchange instance
+func NewInstanceEvents(fmu Instance, md *ModelDescription) *InstanceEvents {
+	return &InstanceEvents{
+		fmu:                           fmu,
+		completedIntegratorStepNeeded: md.ModelExchange == nil || !md.ModelExchange.CompletedIntegratorStepNotNeeded,
+	}
+}
+
+func (e *InstanceEvents) CompletedIntegratorStep() (bool, bool, error) {
+	if !e.completedIntegratorStepNeeded {
+		return false, false, nil
+	}
+	return e.fmu.CompletedIntegratorStep(true)
+}
+
+func (e *InstanceEvents) HandleEvent(t float64) (float64, bool, error) {
+
+	// values before the event
+	if e.recorder != nil {
+		if err := e.recorder.sample(t, true); err != nil {
+			return 0, false, err
+		}
+	}
+
+	if err := e.fmu.EnterEventMode(); err != nil {
+		return 0, false, err
+	}
+
+	if e.input != nil {
+		if err := e.input.Apply(t, true, true, true); err != nil {
+			return 0, false, err
+		}
+	}
+
+	nextEventTime, terminate, err := e.UpdateDiscreteStates()
+	if err != nil || terminate {
+		return 0, terminate, err
+	}
+
+	// values after the event
+	if e.recorder != nil {
+		if err := e.recorder.sample(t, true); err != nil {
+			return 0, false, err
+		}
+	}
+
+	return nextEventTime, false, nil
+}
+
+// UpdateDiscreteStates performs the event iteration in Event Mode, e.g. after the initialization, and
+// enters Continuous-Time Mode. It returns the time of the next time event, which is +Inf if there is
+// none, and whether the FMU requested to terminate.
+func (e *InstanceEvents) UpdateDiscreteStates() (float64, bool, error) {
+
+	eventInfo, err := updateDiscreteStates(e.fmu)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if eventInfo.TerminateSimulation {
+		return 0, true, nil
+	}
+
+	if err := e.fmu.EnterContinuousTimeMode(); err != nil {
+		return 0, false, err
+	}
+
+	if eventInfo.NextEventTimeDefined {
+		return eventInfo.NextEventTime, false, nil
+	}
+
+	return math.Inf(1), false, nil
+}
@@ -0,0 +1,243 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// State is a state of the life cycle of an FMU instance (FMI 2.0 specification, sections 3.2.3 and 4.2.4).
+// The states are bits, so sets of states can be combined with |.
+type State uint
+
+const (
+	StateInstantiated State = 1 << iota
+	StateInitializationMode
+	StateEventMode          // Model Exchange only
+	StateContinuousTimeMode // Model Exchange only
+	StateStepComplete       // Co-Simulation only
+	StateStepInProgress     // Co-Simulation only
+	StateStepFailed         // Co-Simulation only
+	StateStepCanceled       // Co-Simulation only
+	StateTerminated
+	StateError
+	StateFatal
+)
+
+var stateNames = []string{
+	"Instantiated",
+	"InitializationMode",
+	"EventMode",
+	"ContinuousTimeMode",
+	"StepComplete",
+	"StepInProgress",
+	"StepFailed",
+	"StepCanceled",
+	"Terminated",
+	"Error",
+	"Fatal",
+}
+
+func (s State) String() string {
+	names := make([]string, 0, 1)
+	for i, name := range stateNames {
+		if s&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return fmt.Sprintf("State(%d)", uint(s))
+	}
+
+	return strings.Join(names, "|")
+}
+
+const (
+	// the states in which the values can be read (for debugging only in some of them)
+	meReadable = StateInitializationMode | StateEventMode | StateContinuousTimeMode | StateTerminated | StateError
+	csReadable = StateInitializationMode | StateStepComplete | StateStepFailed | StateStepCanceled | StateTerminated | StateError
+
+	// the states in which the instance can be reset, freed and its state can be saved and restored
+	meAlive = StateInstantiated | meReadable
+	csAlive = StateInstantiated | csReadable
+)
+
+// modelExchangeStates are the states in which the functions may be called for Model Exchange
+var modelExchangeStates = map[string]State{
+	"fmi2SetDebugLogging":               meAlive,
+	"fmi2SetupExperiment":               StateInstantiated,
+	"fmi2EnterInitializationMode":       StateInstantiated,
+	"fmi2ExitInitializationMode":        StateInitializationMode,
+	"fmi2Terminate":                     StateEventMode | StateContinuousTimeMode,
+	"fmi2Reset":                         meAlive,
+	"fmi2GetReal":                       meReadable,
+	"fmi2GetInteger":                    meReadable,
+	"fmi2GetBoolean":                    meReadable,
+	"fmi2GetString":                     meReadable,
+	"fmi2SetReal":                       StateInstantiated | StateInitializationMode | StateEventMode | StateContinuousTimeMode,
+	"fmi2SetInteger":                    StateInstantiated | StateInitializationMode | StateEventMode,
+	"fmi2SetBoolean":                    StateInstantiated | StateInitializationMode | StateEventMode,
+	"fmi2SetString":                     StateInstantiated | StateInitializationMode | StateEventMode,
+	"fmi2GetFMUstate":                   meAlive,
+	"fmi2SetFMUstate":                   meAlive,
+	"fmi2FreeFMUstate":                  meAlive,
+	"fmi2SerializedFMUstateSize":        meAlive,
+	"fmi2SerializeFMUstate":             meAlive,
+	"fmi2DeSerializeFMUstate":           meAlive,
+	"fmi2GetDirectionalDerivative":      meReadable,
+	"fmi2EnterEventMode":                StateEventMode | StateContinuousTimeMode,
+	"fmi2NewDiscreteStates":             StateEventMode,
+	"fmi2EnterContinuousTimeMode":       StateEventMode,
+	"fmi2CompletedIntegratorStep":       StateContinuousTimeMode,
+	"fmi2SetTime":                       StateEventMode | StateContinuousTimeMode,
+	"fmi2SetContinuousStates":           StateContinuousTimeMode,
+	"fmi2GetEventIndicators":            meReadable,
+	"fmi2GetContinuousStates":           meReadable,
+	"fmi2GetDerivatives":                meReadable,
+	"fmi2GetNominalsOfContinuousStates": meReadable,
+}
+
+// coSimulationStates are the states in which the functions may be called for Co-Simulation
+var coSimulationStates = map[string]State{
+	"fmi2SetDebugLogging":          csAlive | StateStepInProgress,
+	"fmi2SetupExperiment":          StateInstantiated,
+	"fmi2EnterInitializationMode":  StateInstantiated,
+	"fmi2ExitInitializationMode":   StateInitializationMode,
+	"fmi2Terminate":                StateStepComplete | StateStepFailed,
+	"fmi2Reset":                    csAlive,
+	"fmi2GetReal":                  csReadable,
+	"fmi2GetInteger":               csReadable,
+	"fmi2GetBoolean":               csReadable,
+	"fmi2GetString":                csReadable,
+	"fmi2SetReal":                  StateInstantiated | StateInitializationMode | StateStepComplete,
+	"fmi2SetInteger":               StateInstantiated | StateInitializationMode | StateStepComplete,
+	"fmi2SetBoolean":               StateInstantiated | StateInitializationMode | StateStepComplete,
+	"fmi2SetString":                StateInstantiated | StateInitializationMode | StateStepComplete,
+	"fmi2GetFMUstate":              csAlive,
+	"fmi2SetFMUstate":              csAlive,
+	"fmi2FreeFMUstate":             csAlive,
+	"fmi2SerializedFMUstateSize":   csAlive,
+	"fmi2SerializeFMUstate":        csAlive,
+	"fmi2DeSerializeFMUstate":      csAlive,
+	"fmi2GetDirectionalDerivative": csReadable,
+	"fmi2SetRealInputDerivatives":  StateInstantiated | StateInitializationMode | StateStepComplete,
+	"fmi2GetRealOutputDerivatives": StateStepComplete | StateStepFailed | StateStepCanceled | StateTerminated | StateError,
+	"fmi2DoStep":                   StateStepComplete,
+	"fmi2CancelStep":               StateStepInProgress,
+	"fmi2GetStatus":                StateStepComplete | StateStepInProgress | StateStepFailed | StateTerminated,
+	"fmi2GetRealStatus":            StateStepComplete | StateStepInProgress | StateStepFailed | StateTerminated,
+	"fmi2GetIntegerStatus":         StateStepComplete | StateStepInProgress | StateStepFailed | StateTerminated,
+	"fmi2GetBooleanStatus":         StateStepComplete | StateStepInProgress | StateStepFailed | StateTerminated,
+	"fmi2GetStringStatus":          StateStepComplete | StateStepInProgress | StateStepFailed | StateTerminated,
+}
+
+// ErrIllegalCall is matched by errors.Is for every IllegalCallError
+var ErrIllegalCall = errors.New("illegal call sequence")
+
+// IllegalCallError is returned when a function is called in a state of the instance in which it is not allowed
+type IllegalCallError struct {
+	Function string // the name of the FMI function, e.g. "fmi2DoStep"
+	Type     Type   // the interface type of the instance
+	State    State  // the state of the instance
+	Allowed  State  // the states in which the function may be called
+}
+
+func (e *IllegalCallError) Error() string {
+	if e.Allowed == 0 {
+		return fmt.Sprintf("%s must not be called for %s", e.Function, e.Type)
+	}
+	return fmt.Sprintf("%s must not be called in state %v (%s, allowed in %v)", e.Function, e.State, e.Type, e.Allowed)
+}
+
+func (e *IllegalCallError) Is(target error) bool {
+	return target == ErrIllegalCall
+}
+
+// State returns the state of the life cycle of the instance. The state is not tracked if the instance
+// was created with WithoutLifecycleChecks.
+func (c *Component) State() State {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.state
+}
+
+// checkCall returns an IllegalCallError if the function must not be called in the current state of the instance
+func (c *Component) checkCall(function string) error {
+
+	if !c.lifecycleChecks {
+		return nil
+	}
+
+	states := modelExchangeStates
+	if c.fmuType == CoSimulationType {
+		states = coSimulationStates
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if allowed := states[function]; c.state&allowed == 0 {
+		return &IllegalCallError{Function: function, Type: c.fmuType, State: c.state, Allowed: allowed}
+	}
+
+	return nil
+}
+
+// transition updates the state of the instance after the function returned status
+func (c *Component) transition(function string, status Status) {
+
+	if !c.lifecycleChecks {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch status {
+	case Fatal:
+		c.state = StateFatal
+		return
+	case Error:
+		c.state = StateError
+		return
+	case Discard:
+		if function == "fmi2DoStep" {
+			c.state = StateStepFailed
+		}
+		return
+	case Pending:
+		if function == "fmi2DoStep" {
+			c.state = StateStepInProgress
+		}
+		return
+	}
+
+	switch function {
+	case "fmi2EnterInitializationMode":
+		c.state = StateInitializationMode
+	case "fmi2ExitInitializationMode":
+		if c.fmuType == CoSimulationType {
+			c.state = StateStepComplete
+		} else {
+			c.state = StateEventMode
+		}
+	case "fmi2Terminate":
+		c.state = StateTerminated
+	case "fmi2Reset":
+		c.state = StateInstantiated
+	case "fmi2EnterEventMode":
+		c.state = StateEventMode
+	case "fmi2EnterContinuousTimeMode":
+		c.state = StateContinuousTimeMode
+	case "fmi2DoStep":
+		c.state = StateStepComplete
+	case "fmi2CancelStep":
+		c.state = StateStepCanceled
+	case "fmi2SetFMUstate", "fmi2DeSerializeFMUstate":
+		// restoring a state recovers from a failed or canceled step
+		if c.state&(StateStepFailed|StateStepCanceled) != 0 {
+			c.state = StateStepComplete
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package fmi2
 
-import "math"
+import (
+	"errors"
+	"math"
+)
 
 type Float64IsCloseOption func(*Float64IsCloseOptions)
 
@@ -37,3 +40,63 @@ func Float64IsClose(a float64, b float64, opts ...Float64IsCloseOption) bool {
 
 	return (d / math.Abs(b)) < options.epsilon
 }
+
+// machineEpsilon is the difference between 1.0 and the next representable float64
+const machineEpsilon = 2.220446049250313e-16
+
+// luDecompose computes the LU decomposition with partial pivoting of the square matrix a in place
+// and returns the row permutation
+func luDecompose(a [][]float64) ([]int, error) {
+
+	n := len(a)
+	pivots := make([]int, n)
+
+	for k := 0; k < n; k++ {
+
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[p][k]) {
+				p = i
+			}
+		}
+
+		if a[p][k] == 0 {
+			return nil, errors.New("matrix is singular")
+		}
+
+		pivots[k] = p
+		a[k], a[p] = a[p], a[k]
+
+		for i := k + 1; i < n; i++ {
+			a[i][k] /= a[k][k]
+			for j := k + 1; j < n; j++ {
+				a[i][j] -= a[i][k] * a[k][j]
+			}
+		}
+	}
+
+	return pivots, nil
+}
+
+// luSolve solves a x = b in place for the LU decomposition of a returned by luDecompose
+func luSolve(lu [][]float64, pivots []int, b []float64) {
+
+	n := len(lu)
+
+	for k := 0; k < n; k++ {
+		b[k], b[pivots[k]] = b[pivots[k]], b[k]
+	}
+
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			b[i] -= lu[i][j] * b[j]
+		}
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			b[i] -= lu[i][j] * b[j]
+		}
+		b[i] /= lu[i][i]
+	}
+}
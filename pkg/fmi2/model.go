@@ -5,6 +5,8 @@ import (
 	"bufio"
 	"encoding/xml"
 	"fmt"
+	"go-fmu/pkg/fmi1"
+	"io/fs"
 	"path"
 	"slices"
 	"strings"
@@ -33,6 +35,30 @@ Returns:
 */
 func ReadModelDescription(filename string, options *ValidationOptions) (*ModelDescription, error) {
 
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return ReadModelDescriptionFS(r, options)
+}
+
+/*
+Read the model description from the files of an FMU
+
+Parameters:
+
+	fsys     the files of the FMU, e.g. a *zip.Reader for an FMU in memory or os.DirFS for an extracted FMU
+	options  an instance of ValidationOptions or nil (see ReadModelDescription)
+
+Returns:
+
+	a ModelDescription object
+*/
+func ReadModelDescriptionFS(fsys fs.FS, options *ValidationOptions) (*ModelDescription, error) {
+
 	if options == nil {
 		options = &ValidationOptions{
 			Validate:               true,
@@ -41,12 +67,7 @@ func ReadModelDescription(filename string, options *ValidationOptions) (*ModelDe
 		}
 	}
 
-	r, err := zip.OpenReader(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	f, err := r.Open("modelDescription.xml")
+	f, err := fsys.Open("modelDescription.xml")
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +91,18 @@ func ReadModelDescription(filename string, options *ValidationOptions) (*ModelDe
 		return nil, fmt.Errorf("unsupported FMI version: %s", md.FmiVersion)
 	}
 
-	if options.Validate {
+	// FMI 1.0 has a different schema, which is read by the fmi1 package and converted
+	if is_fmi1 {
+		md1, err := fmi1.ReadModelDescriptionFS(fsys)
+		if err != nil {
+			return nil, err
+		}
+
+		md = *modelDescriptionFromFmi1(md1)
+	}
+
+	// the rules of the FMI 2.0 schema do not apply to converted FMI 1.0 model descriptions
+	if options.Validate && !is_fmi1 {
 		if err := md.Validate(); err != nil {
 			return nil, err
 		}
@@ -88,9 +120,8 @@ func ReadModelDescription(filename string, options *ValidationOptions) (*ModelDe
 		}
 	}
 
-	// TODO(eteran): normalize other FMI versions ?
+	// TODO(eteran): normalize FMI 3.0 ?
 	// FMI3: GUID -> instantiationToken
-	// FMI2: len(ModelStructure/Derivatives/Unknown) -> numberOfContinuousStates
 
 	return &md, nil
 }
@@ -108,33 +139,47 @@ Returns:
 */
 func SupportedPlatforms(filename string) []string {
 
-	platforms := make([]string, 0)
-
 	r, err := zip.OpenReader(filename)
 	if err != nil {
-		return platforms
+		return make([]string, 0)
 	}
 
-	defer func() {
-		if err := r.Close(); err != nil {
-			panic(err)
-		}
-	}()
+	defer r.Close()
+
+	return SupportedPlatformsFS(r)
+}
+
+/*
+Determine the supported platforms from the files of an FMU
+
+Parameters:
 
-	for _, f := range r.File {
+	fsys the files of the FMU, e.g. a *zip.Reader for an FMU in memory or os.DirFS for an extracted FMU
 
-		dir := path.Dir(f.Name)
+Returns:
+
+	a slice of strings representing the supported platforms, or an empty slice on error
+*/
+func SupportedPlatformsFS(fsys fs.FS) []string {
+
+	platforms := make([]string, 0)
+
+	fs.WalkDir(fsys, "binaries", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		dir := path.Dir(name)
 		base := path.Base(dir)
 
 		switch {
-		case strings.HasSuffix(f.Name, ".dylib") && strings.HasPrefix(dir, "binaries/"):
-			platforms = append(platforms, base)
-		case strings.HasSuffix(f.Name, ".so") && strings.HasPrefix(dir, "binaries/"):
-			platforms = append(platforms, base)
-		case strings.HasSuffix(f.Name, ".dll") && strings.HasPrefix(dir, "binaries/"):
+		case dir == "binaries":
+		case strings.HasSuffix(name, ".dylib"), strings.HasSuffix(name, ".so"), strings.HasSuffix(name, ".dll"):
 			platforms = append(platforms, base)
 		}
-	}
+
+		return nil
+	})
 
 	return platforms
 }
@@ -177,7 +222,7 @@ func FmuInfo(filename string, causalities []string) (string, error) {
 	sb.WriteString(fmt.Sprintf("  Model Name         %s\n", md.ModelName))
 	sb.WriteString(fmt.Sprintf("  Description        %s\n", md.Description))
 	sb.WriteString(fmt.Sprintf("  Platforms          %s\n", strings.Join(platforms, ", ")))
-	sb.WriteString(fmt.Sprintf("  Continuous States  %d\n", -1)) // ?
+	sb.WriteString(fmt.Sprintf("  Continuous States  %d\n", md.NumberOfContinuousStates()))
 	sb.WriteString(fmt.Sprintf("  Event Indicators   %d\n", md.NumberOfEventIndicators))
 	sb.WriteString(fmt.Sprintf("  Variables          %d\n", len(md.ModelVariables.ScalarVariable)))
 	sb.WriteString(fmt.Sprintf("  Generation Tool    %s\n", md.GenerationTool))
@@ -231,16 +276,24 @@ func FmuInfo(filename string, causalities []string) (string, error) {
 
 		switch {
 		case v.Real != nil:
-			startValue = fmt.Sprint(v.Real.Start)
+			if v.Real.Start != nil {
+				startValue = fmt.Sprint(*v.Real.Start)
+			}
 			units = v.Real.Unit
 		case v.Boolean != nil:
-			startValue = fmt.Sprint(v.Boolean.Start)
+			if v.Boolean.Start != nil {
+				startValue = fmt.Sprint(*v.Boolean.Start)
+			}
 			units = v.Boolean.DeclaredType
 		case v.Integer != nil:
-			startValue = fmt.Sprint(v.Integer.Start)
+			if v.Integer.Start != nil {
+				startValue = fmt.Sprint(*v.Integer.Start)
+			}
 			units = v.Integer.DeclaredType
 		case v.String != nil:
-			startValue = v.String.Start
+			if v.String.Start != nil {
+				startValue = *v.String.Start
+			}
 			units = v.String.DeclaredType
 		}
 
@@ -1,8 +1,10 @@
 package fmi2_test
 
 import (
+	"encoding/xml"
 	"go-fmu/pkg/fmi2"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/require"
 )
@@ -60,3 +62,205 @@ func TestReadModelBall(t *testing.T) {
 	require.NotNil(t, md.ModelVariables.ScalarVariable)
 	require.Len(t, md.ModelVariables.ScalarVariable, 8)
 }
+
+func TestReadModelStructureDrivetrain(t *testing.T) {
+
+	const filename = "../../examples/Drivetrain.fmu"
+
+	md, err := fmi2.ReadModelDescription(filename, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, md.ModelStructure)
+	require.Equal(t, 4, md.NumberOfContinuousStates())
+
+	require.Len(t, md.ModelStructure.Outputs, 1)
+	require.Equal(t, uint32(15), md.ModelStructure.Outputs[0].Index)
+
+	derivative := md.ModelStructure.Derivatives[1]
+	require.Equal(t, uint32(5), derivative.Index)
+	require.Equal(t, &fmi2.IndexList{9, 11, 16}, derivative.Dependencies)
+	require.Equal(t, fmi2.StringList{"fixed", "fixed", "fixed"}, derivative.DependenciesKind)
+
+	// an empty list means the unknown depends on no knowns
+	require.Len(t, md.ModelStructure.InitialUnknowns, 6)
+	require.NotNil(t, md.ModelStructure.InitialUnknowns[1].Dependencies)
+	require.Empty(t, *md.ModelStructure.InitialUnknowns[1].Dependencies)
+}
+
+func TestMarshalDependencies(t *testing.T) {
+
+	for _, dependencies := range []string{``, ` dependencies=""`, ` dependencies="1 2"`} {
+
+		input := `<Unknown index="3"` + dependencies + `></Unknown>`
+
+		var u fmi2.Unknown
+		require.NoError(t, xml.Unmarshal([]byte(input), &u))
+
+		output, err := xml.Marshal(&u)
+		require.NoError(t, err)
+
+		// a missing and an empty list are written as they were read
+		require.Equal(t, input, string(output))
+	}
+}
+
+func TestValidateModelDescription(t *testing.T) {
+
+	const modelDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="2.0" modelName="Invalid" guid="{1}">
+  <ModelExchange modelIdentifier="Invalid"/>
+  <UnitDefinitions>
+    <Unit name="m"><DisplayUnit name="mm" factor="1000"/></Unit>
+  </UnitDefinitions>
+  <TypeDefinitions>
+    <SimpleType name="Length"><Real unit="m" min="0"/></SimpleType>
+  </TypeDefinitions>
+  <ModelVariables>
+    <ScalarVariable name="x" valueReference="1" initial="exact"><Real declaredType="Length" start="-1" displayUnit="mm"/></ScalarVariable>
+    <ScalarVariable name="der(x)" valueReference="2"><Real derivative="4"/></ScalarVariable>
+    <ScalarVariable name="p" valueReference="3" causality="parameter" variability="continuous"><Real start="1"/></ScalarVariable>
+    <ScalarVariable name="u" valueReference="4" causality="input"><Integer/></ScalarVariable>
+    <ScalarVariable name="y" valueReference="5" causality="output" initial="exact"><Real unit="s" start="1"/></ScalarVariable>
+    <ScalarVariable name="x" valueReference="6" variability="discrete"><Enumeration declaredType="Unknown"/></ScalarVariable>
+  </ModelVariables>
+  <ModelStructure>
+    <Outputs><Unknown index="7"/><Unknown index="5" dependencies="9" dependenciesKind="fixed"/></Outputs>
+  </ModelStructure>
+</fmiModelDescription>`
+
+	var md fmi2.ModelDescription
+	require.NoError(t, xml.Unmarshal([]byte(modelDescription), &md))
+
+	err := md.Validate()
+	require.Error(t, err)
+
+	message := err.Error()
+	require.Contains(t, message, "variable x: start (-1) is less than min (0)")
+	require.Contains(t, message, "variable der(x): derivative index 4 refers to u, which is not a Real variable")
+	require.Contains(t, message, `variable p has the illegal combination of causality "parameter" and variability "continuous"`)
+	require.Contains(t, message, "variable u of type Integer cannot have variability")
+	require.Contains(t, message, "input u must have a start value")
+	require.Contains(t, message, `variable y: unit "s" is not defined`)
+	require.Contains(t, message, "variable x is defined more than once")
+	require.Contains(t, message, `variable x: declaredType "Unknown" is not defined`)
+	require.Contains(t, message, "ModelStructure/Outputs: index 7 is out of range")
+	require.Contains(t, message, "ModelStructure/Outputs: dependency 9 of variable y is out of range")
+	require.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 10)
+}
+
+func TestEnumerationItems(t *testing.T) {
+
+	const modelDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="2.0" modelName="Enumeration" guid="{1}">
+  <TypeDefinitions>
+    <SimpleType name="Mode">
+      <Enumeration>
+        <Item name="Off" value="1"/>
+        <Item name="On" value="2" description="switched on"/>
+      </Enumeration>
+    </SimpleType>
+  </TypeDefinitions>
+</fmiModelDescription>`
+
+	var md fmi2.ModelDescription
+	require.NoError(t, xml.Unmarshal([]byte(modelDescription), &md))
+
+	mode := md.SimpleType("Mode")
+	require.NotNil(t, mode)
+	require.Len(t, mode.Enumeration.Item, 2)
+	require.Equal(t, 2, mode.Enumeration.ItemByName("On").Value)
+	require.Equal(t, "Off", mode.Enumeration.ItemByValue(1).Name)
+	require.Nil(t, mode.Enumeration.ItemByName("Unknown"))
+	require.Nil(t, md.SimpleType("Unknown"))
+}
+
+func TestReadModelDescriptionFmi1(t *testing.T) {
+
+	const modelDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="1.0" modelName="bouncingBall" modelIdentifier="bouncingBall" guid="{1}" numberOfContinuousStates="2" numberOfEventIndicators="1">
+  <UnitDefinitions>
+    <BaseUnit unit="m">
+      <DisplayUnitDefinition displayUnit="mm" gain="1000"/>
+    </BaseUnit>
+  </UnitDefinitions>
+  <TypeDefinitions>
+    <Type name="Mode">
+      <EnumerationType>
+        <Item name="off"/>
+        <Item name="on"/>
+      </EnumerationType>
+    </Type>
+  </TypeDefinitions>
+  <ModelVariables>
+    <ScalarVariable name="h" valueReference="0">
+      <Real unit="m" start="1"/>
+    </ScalarVariable>
+    <ScalarVariable name="v" valueReference="1">
+      <Real unit="m/s" start="0" fixed="false"/>
+    </ScalarVariable>
+    <ScalarVariable name="e" valueReference="2" variability="parameter">
+      <Real start="0.7"/>
+    </ScalarVariable>
+    <ScalarVariable name="height" valueReference="0" alias="alias" causality="output">
+      <Real unit="m" start="1"/>
+    </ScalarVariable>
+    <ScalarVariable name="depth" valueReference="0" alias="negatedAlias">
+      <Real unit="m"/>
+    </ScalarVariable>
+    <ScalarVariable name="mode" valueReference="3" variability="discrete" causality="input">
+      <Enumeration declaredType="Mode" start="2"/>
+    </ScalarVariable>
+  </ModelVariables>
+  <Implementation>
+    <CoSimulation_StandAlone>
+      <Capabilities canHandleVariableCommunicationStepSize="true"/>
+    </CoSimulation_StandAlone>
+  </Implementation>
+</fmiModelDescription>`
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml": {Data: []byte(modelDescription)},
+	}
+
+	md, err := fmi2.ReadModelDescriptionFS(fsys, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "1.0", md.FmiVersion)
+	require.Nil(t, md.ModelExchange)
+	require.Equal(t, "bouncingBall", md.CoSimulation.ModelIdentifier)
+	require.True(t, md.CoSimulation.CanHandleVariableCommunicationStepSize)
+	require.Equal(t, 2, md.NumberOfContinuousStates())
+	require.Equal(t, uint32(1), md.NumberOfEventIndicators)
+
+	// the used unit m/s is added to the declared units
+	units := md.UnitDefinitions[0].Unit
+	require.Len(t, units, 2)
+	require.Equal(t, "mm", units[0].DisplayUnit[0].Name)
+	require.InDelta(t, 1000.0, units[0].DisplayUnit[0].Factor, 1e-9)
+	require.Equal(t, "m/s", units[1].Name)
+
+	require.Equal(t, 2, md.SimpleType("Mode").Enumeration.ItemByName("on").Value)
+
+	variables := md.ModelVariables.ScalarVariable
+	require.Len(t, variables, 5)
+
+	require.Equal(t, "local", variables[0].Causality)
+	require.Equal(t, "exact", variables[0].Initial)
+	require.Equal(t, "approx", variables[1].Initial)
+	require.Equal(t, "parameter", variables[2].Causality)
+	require.Equal(t, "fixed", variables[2].Variability)
+
+	// aliases have no start value and negated aliases are removed
+	require.Equal(t, "height", variables[3].Name)
+	require.Equal(t, "output", variables[3].Causality)
+	require.Nil(t, variables[3].Real.Start)
+	require.Equal(t, "mode", variables[4].Name)
+	require.Equal(t, "input", variables[4].Causality)
+
+	require.NoError(t, md.Validate())
+
+	// only the start values of parameters, inputs and guess values can be set
+	require.NoError(t, md.ValidateStartValues(map[string]any{"v": 1.0, "e": 0.5, "mode": 1}))
+	require.ErrorContains(t, md.ValidateStartValues(map[string]any{"h": 2.0}), "the start value of h cannot be set because it is fixed")
+	require.ErrorContains(t, md.ValidateStartValues(map[string]any{"depth": 1.0}), "depth is a negated alias, negated aliases are not supported")
+}
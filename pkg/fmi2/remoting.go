@@ -0,0 +1,196 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Instance is the interface of an FMU instance that is used by SimulateME and SimulateCS. It is implemented
+// by *Component and by the instances of a RemotingBackend.
+type Instance interface {
+	SetupExperiment(tStart float64, opts ...SetupExperimentOption) error
+	EnterInitializationMode() error
+	ExitInitializationMode() error
+	Terminate() error
+	FreeInstance()
+
+	GetReal(vr []ValueReference) ([]float64, error)
+	GetInteger(vr []ValueReference) ([]int, error)
+	GetBoolean(vr []ValueReference) ([]bool, error)
+	GetString(vr []ValueReference) ([]string, error)
+	SetReal(vr []ValueReference, value []float64) error
+	SetInteger(vr []ValueReference, value []int) error
+	SetBoolean(vr []ValueReference, value []bool) error
+	SetString(vr []ValueReference, value []string) error
+
+	// Model Exchange
+	SetTime(time float64) error
+	EnterEventMode() error
+	EnterContinuousTimeMode() error
+	NewDiscreteStates() (*EventInfo, error)
+	GetContinuousStates(nx int) ([]float64, error)
+	SetContinuousStates(x []float64) error
+	GetDerivatives(nx int) ([]float64, error)
+	GetEventIndicators(ni int) ([]float64, error)
+	CompletedIntegratorStep(noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error)
+	GetDirectionalDerivative(zRef []ValueReference, vRef []ValueReference, dv []float64) ([]float64, error)
+
+	// Co-Simulation
+	DoStep(currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) error
+	GetRealStatus(s StatusKind) (float64, error)
+	GetBooleanStatus(s StatusKind) (bool, error)
+	SetRealInputDerivatives(vr []ValueReference, order []int, value []float64) error
+}
+
+// RemotingBackend loads the binaries of FMUs that the current process cannot load, e.g. the linux32
+// binaries in a 32-bit helper process that is controlled over a socket
+type RemotingBackend interface {
+	// Platforms returns the platforms of the binaries that the backend can load, e.g. linux32
+	Platforms() []string
+
+	// Load loads the binary of the FMU for platform. The messages of the instances are passed to logger
+	// or printed to stdout if logger is nil.
+	Load(filename string, platform string, fmiType Type, logger Logger) (RemoteFmu, error)
+}
+
+// RemoteFmu is an FMU that has been loaded by a RemotingBackend
+type RemoteFmu interface {
+	// Instantiate creates an instance like Fmu2.Instantiate. If resourceLocation is empty the resources of
+	// the FMU that was extracted by the backend are used.
+	Instantiate(instanceName string, fmuType Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool) (Instance, error)
+
+	// Close unloads the FMU
+	Close() error
+}
+
+var (
+	remotingMutex    sync.Mutex
+	remotingBackends = make(map[string]RemotingBackend) // the registered backends by platform
+)
+
+// RegisterRemotingBackend makes the backend available for the platforms it supports, so SimulateFmu can
+// simulate FMUs with binaries for these platforms (see SimulationOptions.RemotePlatform).
+// A backend that is registered later replaces the previous backend of a platform. The returned function
+// unregisters the backend and restores the previous backends, e.g. t.Cleanup(fmi2.RegisterRemotingBackend(backend)).
+func RegisterRemotingBackend(backend RemotingBackend) func() {
+	remotingMutex.Lock()
+	defer remotingMutex.Unlock()
+
+	platforms := backend.Platforms()
+	previous := make(map[string]RemotingBackend, len(platforms))
+
+	for _, platform := range platforms {
+		previous[platform] = remotingBackends[platform]
+		remotingBackends[platform] = backend
+	}
+
+	return func() {
+		remotingMutex.Lock()
+		defer remotingMutex.Unlock()
+		for platform, p := range previous {
+			// a backend that has been registered in the meantime is not replaced
+			if remotingBackends[platform] != backend {
+				continue
+			}
+			if p == nil {
+				delete(remotingBackends, platform)
+			} else {
+				remotingBackends[platform] = p
+			}
+		}
+	}
+}
+
+// RemotingBackendFor returns the backend that has been registered for the platform or nil
+func RemotingBackendFor(platform string) RemotingBackend {
+	remotingMutex.Lock()
+	defer remotingMutex.Unlock()
+	return remotingBackends[platform]
+}
+
+// RemotePlatforms returns the platforms for which a remoting backend has been registered
+func RemotePlatforms() []string {
+	remotingMutex.Lock()
+	defer remotingMutex.Unlock()
+
+	platforms := make([]string, 0, len(remotingBackends))
+	for platform := range remotingBackends {
+		platforms = append(platforms, platform)
+	}
+
+	sort.Strings(platforms)
+
+	return platforms
+}
+
+/*
+Determine whether an FMU can be simulated on the current platform
+
+Parameters:
+
+	platforms       the platforms of the binaries in the FMU, see SupportedPlatforms
+	remotePlatform  "" to load the binary of the current platform, "auto" to use a registered remoting backend
+	                if the FMU has no binary for the current platform, or the platform of the backend to use
+
+Returns:
+
+	whether the FMU can be simulated and the platform of the remoting backend to use ("" for no remoting)
+*/
+func CanSimulate(platforms []string, remotePlatform string) (bool, string) {
+
+	current := CurrentMachine().Platform
+
+	switch remotePlatform {
+	case "":
+		return slices.Contains(platforms, current), ""
+	case "auto":
+		if slices.Contains(platforms, current) {
+			return true, ""
+		}
+
+		// the FMU lists its platforms in a stable order, so the selection is deterministic
+		for _, platform := range platforms {
+			if RemotingBackendFor(platform) != nil {
+				return true, platform
+			}
+		}
+
+		return false, ""
+	default:
+		if slices.Contains(platforms, remotePlatform) && RemotingBackendFor(remotePlatform) != nil {
+			return true, remotePlatform
+		}
+
+		return false, ""
+	}
+}
+
+// unsupportedPlatformError describes why an FMU with binaries for platforms cannot be simulated
+func unsupportedPlatformError(platforms []string, remotePlatform string) error {
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "the FMU cannot be simulated on the current platform (%s)", CurrentMachine().Platform)
+
+	if remotePlatform != "" && remotePlatform != "auto" {
+		fmt.Fprintf(&sb, " with the remote platform %s", remotePlatform)
+	}
+
+	if len(platforms) == 0 {
+		sb.WriteString(", the FMU contains no binaries")
+	} else {
+		fmt.Fprintf(&sb, ", the FMU contains binaries for the platforms: %s", strings.Join(platforms, ", "))
+	}
+
+	if remotePlatforms := RemotePlatforms(); len(remotePlatforms) == 0 {
+		sb.WriteString(", no remoting backend has been registered")
+	} else {
+		fmt.Fprintf(&sb, ", remoting backends have been registered for: %s", strings.Join(remotePlatforms, ", "))
+	}
+
+	return errors.New(sb.String())
+}
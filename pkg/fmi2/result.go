@@ -0,0 +1,233 @@
+package fmi2
+
+import (
+	"fmt"
+	"math"
+)
+
+// Column holds the recorded values of a single variable.
+// Depending on Type, the values are stored in Real, Integer (also used for Enumeration), Boolean or String.
+type Column struct {
+	Name     string
+	Type     string // "Real", "Integer", "Boolean", "String" or "Enumeration"
+	Variable *ScalarVariable
+	Real     []float64
+	Integer  []int
+	Boolean  []bool
+	String   []string
+}
+
+// Len returns the number of recorded values
+func (c *Column) Len() int {
+	switch c.Type {
+	case "Real":
+		return len(c.Real)
+	case "Integer", "Enumeration":
+		return len(c.Integer)
+	case "Boolean":
+		return len(c.Boolean)
+	default:
+		return len(c.String)
+	}
+}
+
+// Value returns the i-th recorded value
+func (c *Column) Value(i int) any {
+	switch c.Type {
+	case "Real":
+		return c.Real[i]
+	case "Integer", "Enumeration":
+		return c.Integer[i]
+	case "Boolean":
+		return c.Boolean[i]
+	default:
+		return c.String[i]
+	}
+}
+
+// Float64 returns the i-th recorded value as a float64. Booleans are converted to 0 or 1 and strings to NaN.
+func (c *Column) Float64(i int) float64 {
+	switch c.Type {
+	case "Real":
+		return c.Real[i]
+	case "Integer", "Enumeration":
+		return float64(c.Integer[i])
+	case "Boolean":
+		if c.Boolean[i] {
+			return 1
+		}
+		return 0
+	default:
+		return math.NaN()
+	}
+}
+
+// Result is the time series of the recorded variables of a simulation
+type Result struct {
+	Time    []float64
+	Columns []*Column
+}
+
+// Column returns the column of the variable with the given name or nil if the variable was not recorded
+func (r *Result) Column(name string) *Column {
+	for _, c := range r.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// variableType returns the name of the type element of the variable
+func variableType(v *ScalarVariable) string {
+	switch {
+	case v.Real != nil:
+		return "Real"
+	case v.Integer != nil:
+		return "Integer"
+	case v.Boolean != nil:
+		return "Boolean"
+	case v.String != nil:
+		return "String"
+	case v.Enumeration != nil:
+		return "Enumeration"
+	default:
+		return ""
+	}
+}
+
+// recorder samples the values of variables of an FMU instance into a Result
+type recorder struct {
+	fmu            variableAccess
+	result         *Result
+	lastSampleTime float64
+
+	// the columns and value references per type, so every type is read with a single call
+	realColumns    []*Column
+	realRefs       []ValueReference
+	integerColumns []*Column
+	integerRefs    []ValueReference
+	booleanColumns []*Column
+	booleanRefs    []ValueReference
+	stringColumns  []*Column
+	stringRefs     []ValueReference
+}
+
+// newRecorder creates a recorder for the variables with the given names.
+// If names is nil, all variables with causality "output" are recorded.
+func newRecorder(fmu variableAccess, modelDescription *ModelDescription, names []string) (*recorder, error) {
+
+	r := &recorder{
+		fmu:            fmu,
+		result:         &Result{Time: make([]float64, 0), Columns: make([]*Column, 0)},
+		lastSampleTime: math.Inf(-1),
+	}
+
+	variables := make([]*ScalarVariable, 0)
+
+	if modelDescription.ModelVariables != nil {
+		if names == nil {
+			for i := range modelDescription.ModelVariables.ScalarVariable {
+				v := &modelDescription.ModelVariables.ScalarVariable[i]
+				if v.Causality == "output" {
+					variables = append(variables, v)
+				}
+			}
+		} else {
+			byName := modelDescription.variablesByName()
+
+			for _, name := range names {
+				v, ok := byName[name]
+				if !ok {
+					return nil, modelDescription.unknownVariableError("unknown output variable: %s", name)
+				}
+				variables = append(variables, v)
+			}
+		}
+	}
+
+	for _, v := range variables {
+
+		column := &Column{Name: v.Name, Type: variableType(v), Variable: v}
+		vr := v.ValueReference
+
+		switch column.Type {
+		case "Real":
+			column.Real = make([]float64, 0)
+			r.realColumns = append(r.realColumns, column)
+			r.realRefs = append(r.realRefs, vr)
+		case "Integer", "Enumeration":
+			column.Integer = make([]int, 0)
+			r.integerColumns = append(r.integerColumns, column)
+			r.integerRefs = append(r.integerRefs, vr)
+		case "Boolean":
+			column.Boolean = make([]bool, 0)
+			r.booleanColumns = append(r.booleanColumns, column)
+			r.booleanRefs = append(r.booleanRefs, vr)
+		case "String":
+			column.String = make([]string, 0)
+			r.stringColumns = append(r.stringColumns, column)
+			r.stringRefs = append(r.stringRefs, vr)
+		default:
+			return nil, fmt.Errorf("variable %s has no type", v.Name)
+		}
+
+		r.result.Columns = append(r.result.Columns, column)
+	}
+
+	return r, nil
+}
+
+// sample records the values at time t, unless a sample has already been recorded at t.
+// If force is true, the values are recorded in any case (e.g. before and after an event).
+func (r *recorder) sample(t float64, force bool) error {
+
+	if !force && t <= r.lastSampleTime {
+		return nil
+	}
+
+	if len(r.realRefs) > 0 {
+		values, err := r.fmu.GetReal(r.realRefs)
+		if err != nil {
+			return err
+		}
+		for i, c := range r.realColumns {
+			c.Real = append(c.Real, values[i])
+		}
+	}
+
+	if len(r.integerRefs) > 0 {
+		values, err := r.fmu.GetInteger(r.integerRefs)
+		if err != nil {
+			return err
+		}
+		for i, c := range r.integerColumns {
+			c.Integer = append(c.Integer, values[i])
+		}
+	}
+
+	if len(r.booleanRefs) > 0 {
+		values, err := r.fmu.GetBoolean(r.booleanRefs)
+		if err != nil {
+			return err
+		}
+		for i, c := range r.booleanColumns {
+			c.Boolean = append(c.Boolean, values[i])
+		}
+	}
+
+	if len(r.stringRefs) > 0 {
+		values, err := r.fmu.GetString(r.stringRefs)
+		if err != nil {
+			return err
+		}
+		for i, c := range r.stringColumns {
+			c.String = append(c.String, values[i])
+		}
+	}
+
+	r.result.Time = append(r.result.Time, t)
+	r.lastSampleTime = t
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package results
+
+import (
+	"encoding/csv"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes the result as comma separated values. The first row holds the variable names,
+// starting with "time". Booleans are written as 0 and 1.
+func WriteCSV(w io.Writer, result *fmi2.Result) error {
+
+	cw := csv.NewWriter(w)
+
+	record := make([]string, len(result.Columns)+1)
+
+	record[0] = "time"
+	for j, c := range result.Columns {
+		record[j+1] = c.Name
+	}
+
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for i, t := range result.Time {
+
+		record[0] = strconv.FormatFloat(t, 'g', -1, 64)
+
+		for j, c := range result.Columns {
+			switch c.Type {
+			case "Real":
+				record[j+1] = strconv.FormatFloat(c.Real[i], 'g', -1, 64)
+			case "Integer", "Enumeration":
+				record[j+1] = strconv.Itoa(c.Integer[i])
+			case "Boolean":
+				record[j+1] = strconv.FormatFloat(c.Float64(i), 'g', -1, 64)
+			default:
+				record[j+1] = c.String[i]
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
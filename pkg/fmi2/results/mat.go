@@ -0,0 +1,159 @@
+package results
+
+import (
+	"encoding/binary"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"strings"
+)
+
+// types of MAT v4 matrices (little endian, full)
+const (
+	matDouble = 0
+	matInt32  = 20
+	matText   = 51
+)
+
+// WriteMAT writes the result as a MATLAB v4 file with the layout of a Dymola result file (dsres.mat),
+// so it can be read by the tools that read Modelica results.
+//
+// The file contains the matrices
+//
+//	Aclass      the file class "Atrajectory", version "1.1" and the storage "binTrans"
+//	name        the names of the variables (one per column)
+//	description the descriptions of the variables (one per column)
+//	dataInfo    the location of the variables (one column per variable: matrix, column, 0, -1)
+//	data_1      the start and stop time
+//	data_2      the time series (one column per sample, the first row is the time)
+//
+// String variables cannot be stored in the data matrices and are omitted.
+func WriteMAT(w io.Writer, result *fmi2.Result) error {
+
+	columns := make([]*fmi2.Column, 0, len(result.Columns))
+	for _, c := range result.Columns {
+		if c.Type != "String" {
+			columns = append(columns, c)
+		}
+	}
+
+	names := []string{"time"}
+	descriptions := []string{"Time in [s]"}
+	dataInfo := []int32{0, 1, 0, -1}
+
+	for j, c := range columns {
+		names = append(names, c.Name)
+
+		description := ""
+		if c.Variable != nil {
+			description = c.Variable.Description
+		}
+		descriptions = append(descriptions, description)
+
+		dataInfo = append(dataInfo, 2, int32(j+2), 0, -1)
+	}
+
+	// Aclass is stored row-wise, all other matrices are transposed (binTrans)
+	if err := writeMatText(w, "Aclass", []string{"Atrajectory", "1.1", "", "binTrans"}, false); err != nil {
+		return err
+	}
+
+	if err := writeMatText(w, "name", names, true); err != nil {
+		return err
+	}
+
+	if err := writeMatText(w, "description", descriptions, true); err != nil {
+		return err
+	}
+
+	if err := writeMatHeader(w, "dataInfo", matInt32, 4, len(names)); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, dataInfo); err != nil {
+		return err
+	}
+
+	startTime, stopTime := 0.0, 0.0
+	if len(result.Time) > 0 {
+		startTime, stopTime = result.Time[0], result.Time[len(result.Time)-1]
+	}
+
+	if err := writeMatHeader(w, "data_1", matDouble, 1, 2); err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, []float64{startTime, stopTime}); err != nil {
+		return err
+	}
+
+	if err := writeMatHeader(w, "data_2", matDouble, len(names), len(result.Time)); err != nil {
+		return err
+	}
+
+	row := make([]float64, len(names))
+	for i, t := range result.Time {
+		row[0] = t
+		for j, c := range columns {
+			row[j+1] = c.Float64(i)
+		}
+
+		if err := binary.Write(w, binary.LittleEndian, row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeMatHeader writes the header of a MAT v4 matrix
+func writeMatHeader(w io.Writer, name string, matrixType int32, rows int, columns int) error {
+
+	header := []int32{matrixType, int32(rows), int32(columns), 0, int32(len(name) + 1)}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, name+"\x00")
+	return err
+}
+
+// writeMatText writes strings as a text matrix padded with blanks.
+// If transposed is true, every string is stored in a column, otherwise in a row.
+func writeMatText(w io.Writer, name string, values []string, transposed bool) error {
+
+	length := 1
+	for _, v := range values {
+		length = max(length, len(v))
+	}
+
+	padded := make([]string, len(values))
+	for i, v := range values {
+		padded[i] = v + strings.Repeat(" ", length-len(v))
+	}
+
+	data := make([]byte, 0, length*len(values))
+
+	if transposed {
+		if err := writeMatHeader(w, name, matText, length, len(values)); err != nil {
+			return err
+		}
+
+		for _, v := range padded {
+			data = append(data, v...)
+		}
+	} else {
+		if err := writeMatHeader(w, name, matText, len(values), length); err != nil {
+			return err
+		}
+
+		// MAT files store matrices column by column
+		for k := 0; k < length; k++ {
+			for _, v := range padded {
+				data = append(data, v[k])
+			}
+		}
+	}
+
+	_, err := w.Write(data)
+	return err
+}
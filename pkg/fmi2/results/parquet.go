@@ -0,0 +1,302 @@
+package results
+
+import (
+	"bytes"
+	"encoding/binary"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"math"
+)
+
+// physical types, encodings and other enumerations of the Parquet format
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetUTF8     = 0
+
+	parquetPlain        = 0
+	parquetRLE          = 3
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// a column chunk that has been written to the file
+type parquetChunk struct {
+	name       string
+	typ        int32
+	dataOffset int64
+	size       int64
+}
+
+// WriteParquet writes the result as an Apache Parquet file with a single row group.
+// Every variable is stored in a required column: Real as DOUBLE, Integer and Enumeration as INT32,
+// Boolean as BOOLEAN and String as UTF-8 BYTE_ARRAY. The values are PLAIN encoded and uncompressed.
+func WriteParquet(w io.Writer, result *fmi2.Result) error {
+
+	cw := &countingWriter{w: w}
+
+	if _, err := io.WriteString(cw, "PAR1"); err != nil {
+		return err
+	}
+
+	numRows := len(result.Time)
+	chunks := make([]parquetChunk, 0, len(result.Columns)+1)
+
+	writeChunk := func(name string, typ int32, values []byte) error {
+		chunk := parquetChunk{name: name, typ: typ, dataOffset: cw.n}
+
+		header := &thriftWriter{}
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(values)))
+		header.i32(3, int32(len(values)))
+		header.structBegin(5)
+		header.i32(1, int32(numRows))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.structEnd()
+		header.stop()
+
+		if _, err := cw.Write(header.buf.Bytes()); err != nil {
+			return err
+		}
+
+		if _, err := cw.Write(values); err != nil {
+			return err
+		}
+
+		chunk.size = cw.n - chunk.dataOffset
+		chunks = append(chunks, chunk)
+		return nil
+	}
+
+	if err := writeChunk("time", parquetDouble, encodeDoubles(result.Time)); err != nil {
+		return err
+	}
+
+	for _, c := range result.Columns {
+
+		var err error
+
+		switch c.Type {
+		case "Real":
+			err = writeChunk(c.Name, parquetDouble, encodeDoubles(c.Real))
+		case "Integer", "Enumeration":
+			values := make([]byte, 0, 4*len(c.Integer))
+			for _, v := range c.Integer {
+				values = binary.LittleEndian.AppendUint32(values, uint32(int32(v)))
+			}
+			err = writeChunk(c.Name, parquetInt32, values)
+		case "Boolean":
+			// booleans are bit-packed, least significant bit first
+			values := make([]byte, (len(c.Boolean)+7)/8)
+			for i, v := range c.Boolean {
+				if v {
+					values[i/8] |= 1 << (i % 8)
+				}
+			}
+			err = writeChunk(c.Name, parquetBoolean, values)
+		default:
+			values := make([]byte, 0)
+			for _, v := range c.String {
+				values = binary.LittleEndian.AppendUint32(values, uint32(len(v)))
+				values = append(values, v...)
+			}
+			err = writeChunk(c.Name, parquetByteArray, values)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	footer := &thriftWriter{}
+
+	// FileMetaData
+	footer.i32(1, 1)
+
+	footer.listBegin(2, thriftStruct, len(chunks)+1)
+	footer.elementBegin()
+	footer.binary(4, "schema")
+	footer.i32(5, int32(len(chunks)))
+	footer.elementEnd()
+	for _, chunk := range chunks {
+		footer.elementBegin()
+		footer.i32(1, chunk.typ)
+		footer.i32(3, parquetRequired)
+		footer.binary(4, chunk.name)
+		if chunk.typ == parquetByteArray {
+			footer.i32(6, parquetUTF8)
+		}
+		footer.elementEnd()
+	}
+
+	footer.i64(3, int64(numRows))
+
+	totalSize := int64(0)
+	for _, chunk := range chunks {
+		totalSize += chunk.size
+	}
+
+	footer.listBegin(4, thriftStruct, 1)
+	footer.elementBegin()
+	footer.listBegin(1, thriftStruct, len(chunks))
+	for _, chunk := range chunks {
+		// ColumnChunk
+		footer.elementBegin()
+		footer.i64(2, chunk.dataOffset)
+		footer.structBegin(3)
+		// ColumnMetaData
+		footer.i32(1, chunk.typ)
+		footer.listBegin(2, thriftI32, 2)
+		footer.element32(parquetPlain)
+		footer.element32(parquetRLE)
+		footer.listBegin(3, thriftBinary, 1)
+		footer.elementBinary(chunk.name)
+		footer.i32(4, parquetUncompressed)
+		footer.i64(5, int64(numRows))
+		footer.i64(6, chunk.size)
+		footer.i64(7, chunk.size)
+		footer.i64(9, chunk.dataOffset)
+		footer.structEnd()
+		footer.elementEnd()
+	}
+	footer.i64(2, totalSize)
+	footer.i64(3, int64(numRows))
+	footer.elementEnd()
+
+	footer.binary(6, "go-fmu")
+	footer.stop()
+
+	if _, err := cw.Write(footer.buf.Bytes()); err != nil {
+		return err
+	}
+
+	if err := binary.Write(cw, binary.LittleEndian, uint32(footer.buf.Len())); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(cw, "PAR1")
+	return err
+}
+
+// encodeDoubles returns the PLAIN encoding of the values
+func encodeDoubles(values []float64) []byte {
+	data := make([]byte, 0, 8*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+	return data
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// types of the Thrift compact protocol
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs with the Thrift compact protocol, which is used for the metadata of Parquet files
+type thriftWriter struct {
+	buf       bytes.Buffer
+	lastField int16
+	stack     []int16
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	t.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (t *thriftWriter) zigzag(v int64) {
+	t.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.lastField; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.zigzag(int64(id))
+	}
+	t.lastField = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.zigzag(v)
+}
+
+func (t *thriftWriter) binary(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.elementBinary(v)
+}
+
+// structBegin starts a struct field, the fields that follow belong to the struct until structEnd
+func (t *thriftWriter) structBegin(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.elementBegin()
+}
+
+func (t *thriftWriter) structEnd() {
+	t.elementEnd()
+}
+
+// listBegin starts a list field with n elements that must be written next
+func (t *thriftWriter) listBegin(id int16, elementType byte, n int) {
+	t.fieldHeader(id, thriftList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elementType)
+	} else {
+		t.buf.WriteByte(0xf0 | elementType)
+		t.varint(uint64(n))
+	}
+}
+
+// elementBegin starts a struct that is an element of a list
+func (t *thriftWriter) elementBegin() {
+	t.stack = append(t.stack, t.lastField)
+	t.lastField = 0
+}
+
+func (t *thriftWriter) elementEnd() {
+	t.stop()
+	t.lastField = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftWriter) element32(v int32) {
+	t.zigzag(int64(v))
+}
+
+func (t *thriftWriter) elementBinary(v string) {
+	t.varint(uint64(len(v)))
+	t.buf.WriteString(v)
+}
+
+// stop ends the fields of a struct
+func (t *thriftWriter) stop() {
+	t.buf.WriteByte(0)
+}
@@ -0,0 +1,90 @@
+// Package results writes the results of a simulation to files
+package results
+
+import (
+	"bufio"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is the file format of a result file
+type Format int
+
+const (
+	CSV     Format = iota // comma separated values with a header row
+	MAT                   // MATLAB v4 file with the layout of a Dymola result (dsres.mat)
+	Parquet               // Apache Parquet
+)
+
+func (f Format) String() string {
+	switch f {
+	case CSV:
+		return "CSV"
+	case MAT:
+		return "MAT"
+	case Parquet:
+		return "Parquet"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// FormatFromFilename returns the format for the extension of filename (".csv", ".mat" or ".parquet")
+func FormatFromFilename(filename string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return CSV, nil
+	case ".mat":
+		return MAT, nil
+	case ".parquet":
+		return Parquet, nil
+	default:
+		return 0, fmt.Errorf("unsupported result file extension: %q", filepath.Ext(filename))
+	}
+}
+
+// Write writes the result to w in the given format
+func Write(w io.Writer, format Format, result *fmi2.Result) error {
+	switch format {
+	case CSV:
+		return WriteCSV(w, result)
+	case MAT:
+		return WriteMAT(w, result)
+	case Parquet:
+		return WriteParquet(w, result)
+	default:
+		return fmt.Errorf("unsupported result format: %v", format)
+	}
+}
+
+// WriteFile writes the result to a file. The format is determined by the extension of filename.
+func WriteFile(filename string, result *fmi2.Result) error {
+
+	format, err := FormatFromFilename(filename)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+
+	if err := Write(w, format, result); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
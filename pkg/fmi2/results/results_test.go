@@ -0,0 +1,95 @@
+package results_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmi2/results"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testResult() *fmi2.Result {
+	return &fmi2.Result{
+		Time: []float64{0, 0.5, 1},
+		Columns: []*fmi2.Column{
+			{Name: "x", Type: "Real", Real: []float64{1, 1.5, 2}, Variable: &fmi2.ScalarVariable{Description: "position"}},
+			{Name: "n", Type: "Integer", Integer: []int{0, -1, 2}},
+			{Name: "b", Type: "Boolean", Boolean: []bool{true, false, true}},
+			{Name: "s", Type: "String", String: []string{"a", "b,c", "d"}},
+		},
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+
+	format, err := results.FormatFromFilename("dsres.MAT")
+	require.NoError(t, err)
+	require.Equal(t, results.MAT, format)
+
+	_, err = results.FormatFromFilename("result.txt")
+	require.Error(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteCSV(&buf, testResult()))
+	require.Equal(t, "time,x,n,b,s\n0,1,0,1,a\n0.5,1.5,-1,0,\"b,c\"\n1,2,2,1,d\n", buf.String())
+}
+
+func TestWriteMAT(t *testing.T) {
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteMAT(&buf, testResult()))
+
+	// read the matrices of the MAT v4 file
+	data := buf.Bytes()
+	matrices := make(map[string][]byte)
+	sizes := make(map[string][2]int32)
+
+	for len(data) > 0 {
+		var header [5]int32
+		require.NoError(t, binary.Read(bytes.NewReader(data), binary.LittleEndian, &header))
+		data = data[20:]
+
+		name := string(data[:header[4]-1])
+		data = data[header[4]:]
+
+		size := int(header[1] * header[2])
+		switch header[0] {
+		case 0:
+			size *= 8
+		case 20:
+			size *= 4
+		}
+
+		matrices[name] = data[:size]
+		sizes[name] = [2]int32{header[1], header[2]}
+		data = data[size:]
+	}
+
+	require.Equal(t, [2]int32{4, 11}, sizes["Aclass"])
+	require.Equal(t, "time", string(matrices["name"][:4]))
+	require.Equal(t, "x   n   b   ", string(matrices["name"][4:]))
+	require.Equal(t, [2]int32{4, 3}, sizes["data_2"])
+
+	var data2 [12]float64
+	require.NoError(t, binary.Read(bytes.NewReader(matrices["data_2"]), binary.LittleEndian, &data2))
+	require.Equal(t, [12]float64{0, 1, 0, 1, 0.5, 1.5, -1, 0, 1, 2, 2, 1}, data2)
+}
+
+func TestWriteParquet(t *testing.T) {
+
+	var buf bytes.Buffer
+	require.NoError(t, results.WriteParquet(&buf, testResult()))
+
+	data := buf.Bytes()
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	footerLength := binary.LittleEndian.Uint32(data[len(data)-8:])
+	require.Less(t, int(footerLength), len(data)-12)
+	require.Contains(t, string(data[len(data)-8-int(footerLength):]), "go-fmu")
+}
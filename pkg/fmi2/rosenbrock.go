@@ -0,0 +1,223 @@
+package fmi2
+
+import (
+	"fmt"
+	"math"
+)
+
+// RosenbrockSolver is the linearly implicit Rosenbrock method of order 2(3) by Shampine and Reichelt
+// (as in MATLAB's ode23s) with adaptive step size control for stiff models. The Jacobian is provided by the
+// system if it implements JacobianProvider and approximated with finite differences otherwise.
+// Zero crossings of the event indicators are located within the step.
+type RosenbrockSolver struct {
+	system            OdeSystem
+	h                 float64
+	maxStepSize       float64
+	relativeTolerance float64
+	absoluteTolerance float64
+	z                 []float64
+}
+
+func NewRosenbrockSolver(system OdeSystem, t float64, options *SolverOptions) (*RosenbrockSolver, error) {
+
+	s := &RosenbrockSolver{
+		system:            system,
+		maxStepSize:       options.maxStepSize,
+		relativeTolerance: options.relativeTolerance,
+		absoluteTolerance: options.absoluteTolerance,
+		h:                 math.Min(options.stepSize, options.maxStepSize),
+	}
+
+	if err := s.Reset(t); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *RosenbrockSolver) Reset(t float64) error {
+	z, err := s.system.GetEventIndicators()
+	if err != nil {
+		return err
+	}
+
+	s.z = z
+	return nil
+}
+
+// jacobian returns the Jacobian of the derivatives with respect to the states and
+// the partial derivatives of the derivatives with respect to time at (t, x)
+func (s *RosenbrockSolver) jacobian(t float64, x []float64, dx []float64) ([][]float64, []float64, error) {
+
+	var jacobian [][]float64
+	var err error
+
+	if provider, ok := s.system.(JacobianProvider); ok {
+		if _, err := evaluate(s.system, t, x); err != nil {
+			return nil, nil, err
+		}
+		jacobian, err = provider.GetJacobian()
+	} else {
+		jacobian, err = finiteDifferenceJacobian(s.system, t, x, dx)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delta := math.Sqrt(machineEpsilon) * math.Max(math.Abs(t), 1.0)
+	dxPerturbed, err := evaluate(s.system, t+delta, x)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dt := make([]float64, len(x))
+	for i := range dt {
+		dt[i] = (dxPerturbed[i] - dx[i]) / delta
+	}
+
+	return jacobian, dt, nil
+}
+
+func (s *RosenbrockSolver) Step(t float64, tNext float64) (float64, bool, error) {
+
+	d := 1 / (2 + math.Sqrt2)
+	e32 := 6 + math.Sqrt2
+
+	x, err := s.system.GetContinuousStates()
+	if err != nil {
+		return t, false, err
+	}
+
+	f0, err := evaluate(s.system, t, x)
+	if err != nil {
+		return t, false, err
+	}
+
+	jacobian, dt, err := s.jacobian(t, x, f0)
+	if err != nil {
+		return t, false, err
+	}
+
+	n := len(x)
+
+	for t < tNext && !Float64IsClose(t, tNext, WithEpsilon(1e-12)) {
+
+		tStep := nextStepTime(t, s.h, tNext)
+		h := tStep - t
+
+		// W = I - h d J
+		w := make([][]float64, n)
+		for i := range w {
+			w[i] = make([]float64, n)
+			for j := range w[i] {
+				w[i][j] = -h * d * jacobian[i][j]
+			}
+			w[i][i] += 1
+		}
+
+		pivots, err := luDecompose(w)
+		if err != nil {
+			return t, false, fmt.Errorf("iteration matrix at t = %g: %w", t, err)
+		}
+
+		k1 := make([]float64, n)
+		for i := range k1 {
+			k1[i] = f0[i] + h*d*dt[i]
+		}
+		luSolve(w, pivots, k1)
+
+		f1, err := evaluate(s.system, t+h/2, axpy(h/2, k1, x))
+		if err != nil {
+			return t, false, err
+		}
+
+		k2 := make([]float64, n)
+		for i := range k2 {
+			k2[i] = f1[i] - k1[i]
+		}
+		luSolve(w, pivots, k2)
+		for i := range k2 {
+			k2[i] += k1[i]
+		}
+
+		xNext := axpy(h, k2, x)
+
+		f2, err := evaluate(s.system, tStep, xNext)
+		if err != nil {
+			return t, false, err
+		}
+
+		k3 := make([]float64, n)
+		for i := range k3 {
+			k3[i] = f2[i] - e32*(k2[i]-f1[i]) - 2*(k1[i]-f0[i]) + h*d*dt[i]
+		}
+		luSolve(w, pivots, k3)
+
+		errorNorm := 0.0
+		for i := range x {
+			e := h / 6 * (k1[i] - 2*k2[i] + k3[i])
+			scale := s.absoluteTolerance + s.relativeTolerance*math.Max(math.Abs(x[i]), math.Abs(xNext[i]))
+			errorNorm += (e / scale) * (e / scale)
+		}
+
+		if n > 0 {
+			errorNorm = math.Sqrt(errorNorm / float64(n))
+		}
+
+		factor := 5.0
+		if errorNorm > 0 {
+			factor = math.Max(0.2, math.Min(5.0, 0.8*math.Pow(errorNorm, -1.0/3)))
+		}
+
+		hNew := math.Min(h*factor, s.maxStepSize)
+
+		if errorNorm > 1.0 {
+			// reject the step and retry with a smaller step size
+			if hNew < minStepSize(t) {
+				return t, false, fmt.Errorf("step size too small at t = %g", t)
+			}
+
+			s.h = hNew
+			continue
+		}
+
+		// don't let a step that was shortened to reach tNext reduce the step size
+		if tStep != tNext || hNew > s.h {
+			s.h = hNew
+		}
+
+		// the system is at the new solution after the last evaluation
+		z, err := s.system.GetEventIndicators()
+		if err != nil {
+			return t, false, err
+		}
+
+		if zeroCrossing(s.z, z) {
+			tEvent, err := locateEvent(s.system, t, x, f0, s.z, tStep, xNext, f2)
+			if err != nil {
+				return t, false, err
+			}
+
+			return tEvent, true, s.Reset(tEvent)
+		}
+
+		t = tStep
+		x = xNext
+		f0 = f2
+		s.z = z
+
+		if t < tNext {
+			if jacobian, dt, err = s.jacobian(t, x, f0); err != nil {
+				return t, false, err
+			}
+		}
+	}
+
+	// leave the system at the accepted solution
+	if _, err := evaluate(s.system, t, x); err != nil {
+		return t, false, err
+	}
+
+	return t, false, nil
+}
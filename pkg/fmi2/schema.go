@@ -1,6 +1,11 @@
 package fmi2
 
-import "fmt"
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 func (md *ModelDescription) SimulationType() (Type, error) {
 	switch {
@@ -13,21 +18,91 @@ func (md *ModelDescription) SimulationType() (Type, error) {
 	}
 }
 
-func (md *ModelDescription) Validate() error {
-	// TODO(eteran): validate against XSD
-	return nil
+// ModelIdentifier returns the model identifier of the interface type, which is the name of its shared library
+func (md *ModelDescription) ModelIdentifier(fmiType Type) (string, error) {
+	switch {
+	case fmiType == ModelExchangeType && md.ModelExchange != nil:
+		return md.ModelExchange.ModelIdentifier, nil
+	case fmiType == CoSimulationType && md.CoSimulation != nil:
+		return md.CoSimulation.ModelIdentifier, nil
+	default:
+		return "", fmt.Errorf("the FMU does not support %s", fmiType)
+	}
 }
 
-func (md *ModelDescription) ValidateVariableNames() error {
-	// TODO(eteran): validate variable names
-	return nil
+// SourceFileNames returns the names of the source files of the interface type, which are relative to the
+// sources directory of the FMU, or nil if the FMU does not contain sources for the interface type
+func (md *ModelDescription) SourceFileNames(fmiType Type) []string {
+
+	var sourceFiles *SourceFiles
+
+	switch {
+	case fmiType == ModelExchangeType && md.ModelExchange != nil:
+		sourceFiles = md.ModelExchange.SourceFiles
+	case fmiType == CoSimulationType && md.CoSimulation != nil:
+		sourceFiles = md.CoSimulation.SourceFiles
+	}
+
+	if sourceFiles == nil || len(sourceFiles.File) == 0 {
+		return nil
+	}
+
+	return Transform(sourceFiles.File, func(i int, f File) string { return f.Name })
 }
 
-func (md *ModelDescription) ValidateStructure() error {
-	// TODO(eteran): validate model structure
+// SimpleType returns the type definition with the given name or nil
+func (md *ModelDescription) SimpleType(name string) *SimpleType {
+	for i := range md.TypeDefinitions {
+		for j := range md.TypeDefinitions[i].SimpleType {
+			if t := &md.TypeDefinitions[i].SimpleType[j]; t.Name == name {
+				return t
+			}
+		}
+	}
 	return nil
 }
 
+// NumberOfContinuousStates returns the number of continuous states, which is defined by the
+// number of ModelStructure/Derivatives/Unknown elements (FMI 1.0: the numberOfContinuousStates attribute)
+func (md *ModelDescription) NumberOfContinuousStates() int {
+	if md.ModelStructure == nil {
+		return md.numberOfContinuousStates
+	}
+
+	return len(md.ModelStructure.Derivatives)
+}
+
+// continuousStateReferences returns the value references of the continuous states and their derivatives
+// in the order of the ModelStructure/Derivatives/Unknown elements
+func (md *ModelDescription) continuousStateReferences() ([]ValueReference, []ValueReference, error) {
+
+	states := make([]ValueReference, 0)
+	derivatives := make([]ValueReference, 0)
+
+	if md.ModelStructure == nil || md.ModelVariables == nil {
+		return states, derivatives, nil
+	}
+
+	variables := md.ModelVariables.ScalarVariable
+
+	for _, u := range md.ModelStructure.Derivatives {
+		if u.Index < 1 || int(u.Index) > len(variables) {
+			return nil, nil, fmt.Errorf("derivative index out of range: %d", u.Index)
+		}
+
+		derivative := variables[u.Index-1]
+		if derivative.Real == nil || derivative.Real.Derivative < 1 || int(derivative.Real.Derivative) > len(variables) {
+			return nil, nil, fmt.Errorf("variable %s is not the derivative of a continuous state", derivative.Name)
+		}
+
+		state := variables[derivative.Real.Derivative-1]
+		states = append(states, state.ValueReference)
+		derivatives = append(derivatives, derivative.ValueReference)
+	}
+
+	return states, derivatives, nil
+}
+
 type ModelDescription struct {
 	FmiVersion               string             `xml:"fmiVersion,attr"`
 	ModelName                string             `xml:"modelName,attr"`
@@ -49,7 +124,11 @@ type ModelDescription struct {
 	DefaultExperiment        *DefaultExperiment `xml:"DefaultExperiment"`
 	VendorAnnotations        []Annotation       `xml:"VendorAnnotations"`
 	ModelVariables           *ModelVariables    `xml:"ModelVariables"`
+	ModelStructure           *ModelStructure    `xml:"ModelStructure"`
 	FmiModelDescription      string             `xml:"fmiModelDescription"`
+
+	numberOfContinuousStates int      // the number of states of an FMI 1.0 model, which has no ModelStructure
+	negatedAliases           []string // the names of the negated aliases of an FMI 1.0 model, which are not supported
 }
 
 type ModelExchange struct {
@@ -115,99 +194,137 @@ type ModelVariables struct {
 	ScalarVariable []ScalarVariable `xml:"ScalarVariable"`
 }
 
-type Unknown struct {
-	Index            uint32 `xml:"index,attr"`
-	Dependencies     uint32 `xml:"dependencies,attr,omitempty"`
-	DependenciesKind string `xml:"dependenciesKind,attr,omitempty"`
+// IndexList is a space separated list of 1-based indices into ModelVariables
+type IndexList []uint32
+
+func (l *IndexList) UnmarshalXMLAttr(attr xml.Attr) error {
+	fields := strings.Fields(attr.Value)
+
+	list := make(IndexList, len(fields))
+	for i, field := range fields {
+		index, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid index %q in attribute %s", field, attr.Name.Local)
+		}
+		list[i] = uint32(index)
+	}
+
+	*l = list
+	return nil
 }
 
-type InitialUnknowns struct {
-	Unknown []Unknown `xml:"Unknown"`
+func (l IndexList) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	fields := make([]string, len(l))
+	for i, index := range l {
+		fields[i] = strconv.FormatUint(uint64(index), 10)
+	}
+
+	return xml.Attr{Name: name, Value: strings.Join(fields, " ")}, nil
 }
 
-type ModelStructure struct {
-	Outputs         []VariableDependency `xml:"Outputs"`
-	Derivatives     []VariableDependency `xml:"Derivatives"`
-	InitialUnknowns []InitialUnknowns    `xml:"InitialUnknowns"`
+// StringList is a space separated list of strings
+type StringList []string
+
+func (l *StringList) UnmarshalXMLAttr(attr xml.Attr) error {
+	*l = strings.Fields(attr.Value)
+	return nil
 }
 
-type VariableDependency struct {
-	Unknown []Unknown `xml:"Unknown"`
+func (l StringList) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: strings.Join(l, " ")}, nil
+}
+
+// Unknown is an unknown of the model structure and the knowns it depends on.
+// Dependencies is nil if the attribute is not present, which means the unknown depends on all knowns,
+// and points to an empty list if the unknown depends on no knowns (dependencies="").
+// DependenciesKind holds the kind ("dependent", "constant", "fixed", "tunable" or "discrete") of every dependency.
+type Unknown struct {
+	Index            uint32     `xml:"index,attr"`
+	Dependencies     *IndexList `xml:"dependencies,attr,omitempty"`
+	DependenciesKind StringList `xml:"dependenciesKind,attr,omitempty"`
+}
+
+type ModelStructure struct {
+	Outputs         []Unknown `xml:"Outputs>Unknown"`
+	Derivatives     []Unknown `xml:"Derivatives>Unknown"`
+	InitialUnknowns []Unknown `xml:"InitialUnknowns>Unknown"`
 }
 
 type Real struct {
 	RealAttributes
-	DeclaredType string  `xml:"declaredType,attr,omitempty"`
-	Start        float64 `xml:"start,attr,omitempty"`
-	Derivative   uint32  `xml:"derivative,attr,omitempty"`
-	Reinit       bool    `xml:"reinit,attr,omitempty"`
+	DeclaredType string   `xml:"declaredType,attr,omitempty"`
+	Start        *float64 `xml:"start,attr,omitempty"`
+	Derivative   uint32   `xml:"derivative,attr,omitempty"`
+	Reinit       bool     `xml:"reinit,attr,omitempty"`
 }
 
 type Integer struct {
 	IntegerAttributes
 	DeclaredType string `xml:"declaredType,attr,omitempty"`
-	Start        int    `xml:"start,attr,omitempty"`
+	Start        *int   `xml:"start,attr,omitempty"`
 }
 
 type Boolean struct {
 	DeclaredType string `xml:"declaredType,attr,omitempty"`
-	Start        bool   `xml:"start,attr,omitempty"`
+	Start        *bool  `xml:"start,attr,omitempty"`
 }
 
 type String struct {
-	DeclaredType string `xml:"declaredType,attr,omitempty"`
-	Start        string `xml:"start,attr,omitempty"`
+	DeclaredType string  `xml:"declaredType,attr,omitempty"`
+	Start        *string `xml:"start,attr,omitempty"`
 }
 
 type Enumeration struct {
 	DeclaredType string `xml:"declaredType,attr"`
 	Quantity     string `xml:"quantity,attr,omitempty"`
-	Min          int    `xml:"min,attr,omitempty"`
-	Max          int    `xml:"max,attr,omitempty"`
-	Start        int    `xml:"start,attr,omitempty"`
+	Min          *int   `xml:"min,attr,omitempty"`
+	Max          *int   `xml:"max,attr,omitempty"`
+	Start        *int   `xml:"start,attr,omitempty"`
 }
 
 type RealType struct {
-	FmiRealAttributes *RealAttributes
+	RealAttributes
 }
 
 type IntegerType struct {
-	FmiIntegerAttributes *IntegerAttributes
+	IntegerAttributes
 }
 
 // RealAttributes is Set to true, e.g., for crank angle. If true and variable is a state, relative tolerance should be zero on this variable.
 type RealAttributes struct {
-	Quantity         string  `xml:"quantity,attr,omitempty"`
-	Unit             string  `xml:"unit,attr,omitempty"`
-	DisplayUnit      string  `xml:"displayUnit,attr,omitempty"`
-	RelativeQuantity bool    `xml:"relativeQuantity,attr,omitempty"`
-	Min              float64 `xml:"min,attr,omitempty"`
-	Max              float64 `xml:"max,attr,omitempty"`
-	Nominal          float64 `xml:"nominal,attr,omitempty"`
-	Unbounded        bool    `xml:"unbounded,attr,omitempty"`
+	Quantity         string   `xml:"quantity,attr,omitempty"`
+	Unit             string   `xml:"unit,attr,omitempty"`
+	DisplayUnit      string   `xml:"displayUnit,attr,omitempty"`
+	RelativeQuantity bool     `xml:"relativeQuantity,attr,omitempty"`
+	Min              *float64 `xml:"min,attr,omitempty"`
+	Max              *float64 `xml:"max,attr,omitempty"`
+	Nominal          *float64 `xml:"nominal,attr,omitempty"`
+	Unbounded        bool     `xml:"unbounded,attr,omitempty"`
 }
 
 // IntegerAttributes is max >= min required
 type IntegerAttributes struct {
 	Quantity string `xml:"quantity,attr,omitempty"`
-	Min      int    `xml:"min,attr,omitempty"`
-	Max      int    `xml:"max,attr,omitempty"`
+	Min      *int   `xml:"min,attr,omitempty"`
+	Max      *int   `xml:"max,attr,omitempty"`
 }
 
 type ScalarVariable struct {
-	Name                               string       `xml:"name,attr"`
-	ValueReference                     uint32       `xml:"valueReference,attr"`
-	Description                        string       `xml:"description,attr,omitempty"`
-	Causality                          string       `xml:"causality,attr,omitempty"`
-	Variability                        string       `xml:"variability,attr,omitempty"`
-	Initial                            string       `xml:"initial,attr,omitempty"`
-	CanHandleMultipleSetPerTimeInstant bool         `xml:"canHandleMultipleSetPerTimeInstant,attr,omitempty"`
-	Real                               *Real        `xml:"Real"`
-	Integer                            *Integer     `xml:"Integer"`
-	Boolean                            *Boolean     `xml:"Boolean"`
-	String                             *String      `xml:"String"`
-	Enumeration                        *Enumeration `xml:"Enumeration"`
-	Annotations                        []Annotation `xml:"Annotations"`
+	Name                               string         `xml:"name,attr"`
+	ValueReference                     ValueReference `xml:"valueReference,attr"`
+	Description                        string         `xml:"description,attr,omitempty"`
+	Causality                          string         `xml:"causality,attr,omitempty"`
+	Variability                        string         `xml:"variability,attr,omitempty"`
+	Initial                            string         `xml:"initial,attr,omitempty"`
+	CanHandleMultipleSetPerTimeInstant bool           `xml:"canHandleMultipleSetPerTimeInstant,attr,omitempty"`
+	Real                               *Real          `xml:"Real"`
+	Integer                            *Integer       `xml:"Integer"`
+	Boolean                            *Boolean       `xml:"Boolean"`
+	String                             *String        `xml:"String"`
+	Enumeration                        *Enumeration   `xml:"Enumeration"`
+	Annotations                        []Annotation   `xml:"Annotations"`
+
+	fixedStart bool // the start value of an FMI 1.0 variable that is fixed and cannot be set
 }
 
 type Item struct {
@@ -218,7 +335,27 @@ type Item struct {
 
 type EnumerationType struct {
 	Quantity string `xml:"quantity,attr,omitempty"`
-	Item     *Item  `xml:"Item"`
+	Item     []Item `xml:"Item"`
+}
+
+// ItemByName returns the item with the given name or nil
+func (e *EnumerationType) ItemByName(name string) *Item {
+	for i := range e.Item {
+		if e.Item[i].Name == name {
+			return &e.Item[i]
+		}
+	}
+	return nil
+}
+
+// ItemByValue returns the item with the given value or nil
+func (e *EnumerationType) ItemByValue(value int) *Item {
+	for i := range e.Item {
+		if e.Item[i].Value == value {
+			return &e.Item[i]
+		}
+	}
+	return nil
 }
 
 // SimpleType is Type attributes of a scalar variable
@@ -253,9 +390,9 @@ type DisplayUnit struct {
 
 // Unit is Unit definition (with respect to SI base units) and default display units
 type Unit struct {
-	Name        string       `xml:"name,attr"`
-	BaseUnit    *BaseUnit    `xml:"BaseUnit"`
-	DisplayUnit *DisplayUnit `xml:"DisplayUnit"`
+	Name        string        `xml:"name,attr"`
+	BaseUnit    *BaseUnit     `xml:"BaseUnit"`
+	DisplayUnit []DisplayUnit `xml:"DisplayUnit"`
 }
 
 type Tool struct {
@@ -2,7 +2,10 @@ package fmi2
 
 import (
 	"errors"
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
 	"math"
+	"slices"
 	"time"
 )
 
@@ -31,13 +34,17 @@ func AutoInterval(t float64) float64 {
 	return h
 }
 
+// StepFinishedFunc is called with the current simulation time after every step.
+// Returning false stops the simulation.
+type StepFinishedFunc func(time float64) bool
+
 type SimulationOptions struct {
 	Validate                bool              // validate the FMU and start values
 	StartTime               *float64          // simulation start time (nil: use default experiment or 0 if not defined)
 	StopTime                *float64          // simulation stop time (nil: use default experiment or start_time + 1 if not defined)
-	Solver                  string            // solver to use for model exchange ('Euler' or 'CVode')
-	StepSize                *float64          // step size for the 'Euler' solver
-	RelativeTolerance       *float64          // relative tolerance for the 'CVode' solver and FMI 2.0 co-simulation FMUs
+	Solver                  string            // solver to use for model exchange ('Euler', 'RK4', 'RK45' or 'Rosenbrock', "": 'Euler')
+	StepSize                *float64          // step size for the fixed step solvers and initial step size for the variable step solvers
+	RelativeTolerance       *float64          // relative tolerance for the variable step solvers and FMI 2.0 co-simulation FMUs
 	OutputInterval          *float64          // interval for sampling the output
 	RecordEvents            bool              // record outputs at events (model exchange only)
 	FmiType                 string            // FMI type for the simulation ("": determine from FMU)
@@ -48,29 +55,38 @@ type SimulationOptions struct {
 	SetInputDerivatives     bool              // set the input derivatives (FMI 2.0 Co-Simulation only)
 	Visible                 bool              // interactive mode (True) or batch mode (False)
 	ModelDescription        *ModelDescription // the previously loaded model description (experimental)
-	RemotePlatform          string            // platform to use for remoting server ('auto': determine automatically if current platform is not supported, "": no remoting; experimental)
-	EarlyReturnAllowed      bool              // allow early return in FMI 3.0 Co-Simulation
-	UseEventMode            bool              // use event mode in FMI 3.0 Co-Simulation if the FMU supports it
+	RemotePlatform          string            // platform of the remoting backend to use, see RegisterRemotingBackend ('auto': if the current platform is not supported, "": no remoting; experimental)
+	EarlyReturnAllowed      bool              // ignored, early return of FMI 3.0 Co-Simulation is only available through fmi3.WithEarlyReturn
+	UseEventMode            bool              // ignored, Event Mode of FMI 3.0 Co-Simulation is only available through fmi3.WithEventMode
 	Initialize              bool              // initialize the FMU
 	Terminate               bool              // terminate the FMU
 	SetStopTime             bool              // communicate the stop time to the FMU instance
 	FmuInstance             *Component        // the previously instantiated FMU (experimental)
+	StepFinished            StepFinishedFunc  // callback to interact with the simulation (experimental)
+	Output                  []string          // names of the variables to record (nil: record the variables with causality "output")
+	Input                   map[string]Signal // input signals by variable name (nil: no inputs)
+	Logger                  Logger            // callback function passed to the FMU (nil: print the messages to stdout)
+	LogCategories           []string          // log categories of the messages passed to the logger (nil: all categories)
+	ResourceLocation        string            // URI of the resources passed to the FMU, see FileURI ("": the resources of the extracted FMU)
+	LegacyResourceLocation  bool              // pass the resources of the extracted FMU as file:/path instead of file:///path
+	ExtractionCache         string            // directory of the cache for the extracted FMUs, see DefaultCacheDirectory ("": extract into a temporary directory)
+	PartialExtraction       bool              // extract only the binaries for the current platform and the resources
+	CompileSources          bool              // compile the sources if the FMU contains no binary for the current platform, see Compile
 
 	// TODO(eteran):
 	/*
-		input                  a structured numpy array that contains the input (see :class:`Input`)
-		output                 list of variables to record (None: record outputs)
 		fmi_call_logger        callback function to log FMI calls
-		logger                 callback function passed to the FMU (experimental)
-		step_finished          callback to interact with the simulation (experimental)
 		fmu_state              the FMU state or serialized FMU state to initialize the FMU
 	*/
 }
 
-func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *float64, stopTime *float64, relativeTolerance *float64, start_values map[string]any, apply_default_start_values bool /*input_signals int, output int,*/, outputInterval *float64, timeout *float64 /*step_finished int,*/, setInputDerivatives bool, use_event_mode bool, early_return_allowed bool, validate bool, initialize bool, terminate bool, set_stop_time bool) error {
+// SimulateCS simulates a Co-Simulation instance from startTime to stopTime and records the outputs. The model
+// description and the start values are validated by SimulateFmu. Event Mode and early return are FMI 3.0 options
+// that only apply through the fmi3 package, see fmi3.WithEventMode and fmi3.WithEarlyReturn.
+func SimulateCS(model_description *ModelDescription, fmu Instance, startTime *float64, stopTime *float64, relativeTolerance *float64, start_values map[string]any, apply_default_start_values bool, inputSignals map[string]Signal, output []string, outputInterval *float64, timeout *float64, stepFinished StepFinishedFunc, setInputDerivatives bool, initialize bool, terminate bool, set_stop_time bool) (*Result, error) {
 
 	if setInputDerivatives && !model_description.CoSimulation.CanInterpolateInputs {
-		return errors.New("parameter set_input_derivatives is True but the FMU cannot interpolate inputs")
+		return nil, errors.New("parameter set_input_derivatives is True but the FMU cannot interpolate inputs")
 	}
 
 	if outputInterval == nil {
@@ -82,9 +98,16 @@ func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *
 
 	canHandleVariableStepSize := model_description.CoSimulation.CanHandleVariableCommunicationStepSize
 
-	//input = Input(fmu=fmu, modelDescription=model_description, signals=input_signals, set_input_derivatives=set_input_derivatives)
+	if apply_default_start_values {
+		start_values = defaultStartValues(model_description, start_values)
+	}
+
+	input, err := NewInput(fmu, model_description, inputSignals, setInputDerivatives)
+	if err != nil {
+		return nil, err
+	}
 
-	currentTime := startTime
+	currentTime := *startTime
 
 	if initialize {
 
@@ -96,13 +119,38 @@ func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *
 			options = append(options, WithRelativeTolerance(*relativeTolerance))
 		}
 
-		fmu.SetupExperiment(*startTime, options...)
-		//start_values = apply_start_values(fmu, model_description, start_values, settable=settable_in_instantiated)
-		fmu.EnterInitializationMode()
-		//start_values = apply_start_values(fmu, model_description, start_values, settable=settable_in_initialization_mode)
-		//input.apply(current_time)
-		fmu.ExitInitializationMode()
+		if err := fmu.SetupExperiment(*startTime, options...); err != nil {
+			return nil, err
+		}
+
+		if err := applyStartValues(fmu, model_description, start_values, settableInInstantiated); err != nil {
+			return nil, err
+		}
+
+		if err := fmu.EnterInitializationMode(); err != nil {
+			return nil, err
+		}
+
+		if err := applyStartValues(fmu, model_description, start_values, settableInInitializationMode); err != nil {
+			return nil, err
+		}
 
+		if err := input.Apply(currentTime, true, true, true); err != nil {
+			return nil, err
+		}
+
+		if err := fmu.ExitInitializationMode(); err != nil {
+			return nil, err
+		}
+	}
+
+	recorder, err := newRecorder(fmu, model_description, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recorder.sample(currentTime, false); err != nil {
+		return nil, err
 	}
 
 	stepCount := 0.0
@@ -112,7 +160,7 @@ func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *
 			break
 		}
 
-		if *currentTime >= *stopTime {
+		if currentTime >= *stopTime {
 			break
 		}
 
@@ -120,12 +168,13 @@ func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *
 
 		nextCommunicationPoint := nextRegularPoint
 
-		/*
-			nextInputEventTime = input.nextEvent(current_time)
-			if canHandleVariableStepSize && nextCommunicationPoint > nextInputEventTime && !FloatIsClose(nextCommunicationPoint, nextInputEventTime) {
-				nextCommunicationPoint = nextInputEventTime
-			}
-		*/
+		nextInputEventTime := input.NextEvent(currentTime)
+		if Float64IsClose(nextCommunicationPoint, nextInputEventTime) {
+			// don't step over the event by a rounding error
+			nextCommunicationPoint = nextInputEventTime
+		} else if canHandleVariableStepSize && nextCommunicationPoint > nextInputEventTime {
+			nextCommunicationPoint = nextInputEventTime
+		}
 
 		if nextCommunicationPoint > *stopTime && !Float64IsClose(nextCommunicationPoint, *stopTime) {
 			if canHandleVariableStepSize {
@@ -135,79 +184,265 @@ func SimulateCS(model_description *ModelDescription, fmu *Component, startTime *
 			}
 		}
 
-		//inputEvent = FloatIsClose(nextCommunicationPoint, nextInputEventTime)
+		stepSize := nextCommunicationPoint - currentTime
 
-		stepSize := nextCommunicationPoint - *currentTime
+		// the inputs are held constant (or extrapolated with their derivatives) during the step
+		if err := input.Apply(currentTime, true, true, true); err != nil {
+			return nil, err
+		}
 
-		// input.apply(time, continuous=True, discrete=use_event_mode, after_event=use_event_mode)
+		if err := fmu.DoStep(currentTime, stepSize, false); err != nil {
 
-		if err := fmu.DoStep(*currentTime, stepSize, false); err != nil {
+			// the FMU refused to complete the step
+			if !IsDiscard(err) {
+				return nil, err
+			}
 
-			if true { //exception.status == fmi2Discard:
+			terminateSimulation, statusErr := fmu.GetBooleanStatus(Terminated)
+			if statusErr != nil {
+				return nil, statusErr
+			}
 
-				terminateSimulation, err := fmu.GetBooleanStatus(Terminated)
+			if terminateSimulation {
+				cTime, err := fmu.GetRealStatus(LastSuccessfulTime)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
-				if terminateSimulation {
-					cTime, err := fmu.GetRealStatus(LastSuccessfulTime)
-					if err != nil {
-						return err
-					}
-
-					*currentTime = cTime
-					//recorder.sample(time, force=True)
-					break
+				currentTime = cTime
+				if err := recorder.sample(currentTime, true); err != nil {
+					return nil, err
 				}
-			} else {
-				return err
+				break
 			}
+
+			// the FMU did not reach the communication point, so no sample is recorded
+			return nil, fmt.Errorf("the FMU discarded the step from t=%g to t=%g: %w", currentTime, nextCommunicationPoint, err)
 		}
 
-		*currentTime = nextCommunicationPoint
+		currentTime = nextCommunicationPoint
+
+		if err := recorder.sample(currentTime, false); err != nil {
+			return nil, err
+		}
 
-		if Float64IsClose(*currentTime, nextRegularPoint) {
+		if Float64IsClose(currentTime, nextRegularPoint) {
 			stepCount += 1.0
 		}
 
-		/*
-			if step_finished != nil && ! step_finished(time, recorder) {
+		if stepFinished != nil && !stepFinished(currentTime) {
+			break
+		}
+
+	}
+
+	if terminate {
+		if err := fmu.Terminate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return recorder.result, nil
+}
+
+// SimulateME simulates a Model Exchange instance with the solver from startTime to stopTime and records the
+// outputs. The model description and the start values are validated by SimulateFmu.
+func SimulateME(model_description *ModelDescription, fmu Instance, startTime *float64, stopTime *float64, solverName string, stepSize *float64, relativeTolerance *float64, start_values map[string]any, apply_default_start_values bool, inputSignals map[string]Signal, output []string, outputInterval *float64, recordEvents bool, timeout *float64, stepFinished StepFinishedFunc, terminate bool, set_stop_time bool) (*Result, error) {
+
+	if model_description.ModelExchange == nil {
+		return nil, errors.New("the FMU does not support Model Exchange")
+	}
+
+	if outputInterval == nil {
+		interval := AutoInterval(*stopTime - *startTime)
+		outputInterval = &interval
+	}
+
+	if stepSize == nil {
+		stepSize = outputInterval
+	}
+
+	simStart := time.Now()
+
+	if apply_default_start_values {
+		start_values = defaultStartValues(model_description, start_values)
+	}
+
+	input, err := NewInput(fmu, model_description, inputSignals, false)
+	if err != nil {
+		return nil, err
+	}
+
+	currentTime := *startTime
+
+	// the relative tolerance is used by the solver, tolerance control is not defined for Model Exchange FMUs
+	options := []SetupExperimentOption{}
+	if set_stop_time {
+		options = append(options, WithStopTime(*stopTime))
+	}
+
+	if err := fmu.SetupExperiment(currentTime, options...); err != nil {
+		return nil, err
+	}
+
+	if err := applyStartValues(fmu, model_description, start_values, settableInInstantiated); err != nil {
+		return nil, err
+	}
+
+	if err := fmu.EnterInitializationMode(); err != nil {
+		return nil, err
+	}
+
+	if err := applyStartValues(fmu, model_description, start_values, settableInInitializationMode); err != nil {
+		return nil, err
+	}
+
+	if err := input.Apply(currentTime, true, true, true); err != nil {
+		return nil, err
+	}
+
+	if err := fmu.ExitInitializationMode(); err != nil {
+		return nil, err
+	}
+
+	events := NewInstanceEvents(fmu, model_description)
+	events.input = input
+
+	// the FMU is in Event Mode after initialization
+	nextEventTime, terminated, err := events.UpdateDiscreteStates()
+	if err != nil {
+		return nil, err
+	}
+
+	recorder, err := newRecorder(fmu, model_description, output)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recorder.sample(currentTime, false); err != nil {
+		return nil, err
+	}
+
+	if recordEvents {
+		events.recorder = recorder
+	}
+
+	if !terminated {
+
+		system, err := newSystem(fmu, model_description, input)
+		if err != nil {
+			return nil, err
+		}
+
+		solverOptions := []SolverOption{
+			WithStepSize(*stepSize),
+			WithMaxStepSize((*stopTime - *startTime) / 50),
+		}
+		if relativeTolerance != nil && *relativeTolerance > 0 {
+			solverOptions = append(solverOptions, WithTolerance(*relativeTolerance, *relativeTolerance))
+		}
+
+		solver, err := NewSolver(solverName, system, currentTime, solverOptions...)
+		if err != nil {
+			return nil, err
+		}
+
+		integrator := NewIntegrator(system, events, solver, nextEventTime)
+
+		stepCount := 0.0
+
+		for currentTime < *stopTime && !Float64IsClose(currentTime, *stopTime) {
+
+			if timeout != nil && time.Since(simStart).Seconds() > *timeout {
 				break
 			}
-		*/
 
+			nextRegularPoint := math.Min((*startTime)+(stepCount+1.0)*(*outputInterval), *stopTime)
+
+			tNext := nextRegularPoint
+
+			nextInputEventTime := input.NextEvent(currentTime)
+			inputEvent := nextInputEventTime <= tNext || Float64IsClose(nextInputEventTime, tNext)
+			if inputEvent {
+				tNext = nextInputEventTime
+			}
+
+			currentTime, err = integrator.Step(currentTime, tNext, inputEvent)
+			if err != nil {
+				return nil, err
+			}
+
+			if integrator.Terminated() {
+				break
+			}
+
+			if Float64IsClose(currentTime, nextRegularPoint) {
+				stepCount += 1.0
+
+				if err := recorder.sample(currentTime, false); err != nil {
+					return nil, err
+				}
+			}
+
+			if stepFinished != nil && !stepFinished(currentTime) {
+				break
+			}
+		}
 	}
 
 	if terminate {
-		fmu.Terminate()
+		if err := fmu.Terminate(); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	return recorder.result, nil
 }
 
-func SimulateFmu(filename string, options SimulationOptions) error {
+// updateDiscreteStates performs the event iteration of a Model Exchange FMU in Event Mode
+func updateDiscreteStates(fmu Instance) (*EventInfo, error) {
 
-	/*
-		if fmu_instance is None and platform not in platforms and remote_platform is None:
-		raise Exception(f"The current platform ({platform}) is not supported by the FMU.")
-	*/
+	for {
+		eventInfo, err := fmu.NewDiscreteStates()
+		if err != nil {
+			return nil, err
+		}
 
-	/*
-		can_sim, remote_platform = can_simulate(platforms, remote_platform)
-		if not can_sim:
-		raise Exception(f"The FMU cannot be simulated on the current platform ({platform}).")
-	*/
+		if !eventInfo.NewDiscreteStatesNeeded || eventInfo.TerminateSimulation {
+			return eventInfo, nil
+		}
+	}
+}
+
+func SimulateFmu(filename string, options SimulationOptions) (*Result, error) {
 
 	if options.ModelDescription == nil {
 		md, err := ReadModelDescription(filename, &ValidationOptions{Validate: options.Validate})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		options.ModelDescription = md
 	}
 
+	if options.Validate {
+		if err := options.ModelDescription.ValidateStartValues(options.StartValues); err != nil {
+			return nil, err
+		}
+
+		// FMI 1.0 does not declare the log categories
+		categories := options.ModelDescription.LogCategoryNames()
+		for _, category := range options.LogCategories {
+			if options.ModelDescription.FmiVersion == "1.0" {
+				break
+			}
+
+			if !slices.Contains(categories, category) {
+				return nil, fmt.Errorf("the log category %s is not defined in the model description", category)
+			}
+		}
+	}
+
 	if options.FmiType == "" {
 		if options.FmuInstance != nil {
 			// options.FmiType = options.FmuInstance.FmiType
@@ -223,17 +458,17 @@ func SimulateFmu(filename string, options SimulationOptions) error {
 	}
 
 	if options.FmiType != "ModelExchange" && options.FmiType != "CoSimulation" {
-		return errors.New("FmiType must be one of 'ModelExchange' or 'CoSimulation'")
+		return nil, errors.New("FmiType must be one of 'ModelExchange' or 'CoSimulation'")
 	}
 
 	if !options.Initialize {
 		if options.FmiType != "CoSimulation" {
-			return errors.New("if initialize is False, the interface type must be 'CoSimulation'")
+			return nil, errors.New("if initialize is False, the interface type must be 'CoSimulation'")
 		}
 
 		// TODO(eteran): support FmuState
 		if options.FmuInstance == nil {
-			return errors.New("if initialize is False, FmuInstance or FmuState must be provided")
+			return nil, errors.New("if initialize is False, FmuInstance or FmuState must be provided")
 		}
 	}
 
@@ -283,54 +518,157 @@ func SimulateFmu(filename string, options SimulationOptions) error {
 		}
 	}
 
-	fmu, err := New(filename)
+	// without remoting New reports the missing binary of the current platform
+	remotePlatform := ""
+	if options.RemotePlatform != "" && options.FmuInstance == nil {
+		platforms := SupportedPlatforms(filename)
+
+		canSimulate, platform := CanSimulate(platforms, options.RemotePlatform)
+		if !canSimulate && !options.CompileSources {
+			return nil, unsupportedPlatformError(platforms, options.RemotePlatform)
+		}
+
+		remotePlatform = platform
+	}
+
+	if options.ModelDescription.FmiVersion == "1.0" {
+		if remotePlatform != "" {
+			return nil, errors.New("remoting is not supported for FMI 1.0 FMUs")
+		}
+		return simulateFmi1(filename, options)
+	}
+
+	fmiType := CoSimulationType
+	if options.FmiType == "ModelExchange" {
+		fmiType = ModelExchangeType
+	}
+
+	if remotePlatform != "" {
+		return simulateRemote(filename, remotePlatform, fmiType, options)
+	}
+
+	var loadOptions []LoadOption
+
+	if options.ExtractionCache != "" {
+		loadOptions = append(loadOptions, WithExtractionCache(options.ExtractionCache))
+	}
+
+	if options.PartialExtraction {
+		loadOptions = append(loadOptions, WithPartialExtraction())
+	}
+
+	if options.CompileSources {
+		loadOptions = append(loadOptions, WithCompilation())
+	}
+
+	fmu, err := New(filename, fmiType, loadOptions...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	defer fmu.Close()
 
-	switch options.FmiType {
-	case "ModelExchange":
-		//result = simulateME(model_description, fmu, start_time, stop_time, solver, step_size, relative_tolerance, start_values, apply_default_start_values, input, output, output_interval, record_events, timeout, step_finished, validate, set_stop_time)
-	case "CoSimulation":
-
-		md, err := ReadModelDescription(filename, &ValidationOptions{Validate: options.Validate})
-		if err != nil {
-			return err
+	resourceLocation := options.ResourceLocation
+	if resourceLocation == "" {
+		if options.LegacyResourceLocation {
+			resourceLocation = fmu.ResourceLocation(WithLegacyFileURI())
+		} else {
+			resourceLocation = fmu.ResourceLocation()
 		}
+	}
 
-		comp := fmu.Instantiate(
-			md.ModelName,
-			CoSimulationType,
-			md.Guid,
-			fmu.ResourceLocation(),
-			options.Visible,
-			options.DebugLogging)
+	comp := fmu.Instantiate(
+		options.ModelDescription.ModelName,
+		fmiType,
+		options.ModelDescription.Guid,
+		resourceLocation,
+		options.Visible,
+		options.DebugLogging,
+		WithLogger(options.Logger),
+		WithLogCategories(options.LogCategories...))
+
+	if comp == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+
+	defer comp.FreeInstance()
 
-		result := SimulateCS(
+	return simulateInstance(comp, options)
+}
+
+// simulateInstance simulates the instance with the options that SimulateFmu has completed
+func simulateInstance(comp Instance, options SimulationOptions) (*Result, error) {
+
+	if options.FmiType == "ModelExchange" {
+		return SimulateME(
 			options.ModelDescription,
 			comp,
 			options.StartTime,
 			options.StopTime,
+			options.Solver,
+			options.StepSize,
 			options.RelativeTolerance,
 			options.StartValues,
 			options.ApplyDefaultStartValues,
-			//input,
-			//output,
+			options.Input,
+			options.Output,
 			options.OutputInterval,
+			options.RecordEvents,
 			options.Timeout,
-			//step_finished,
-			options.SetInputDerivatives,
-			options.UseEventMode,
-			options.EarlyReturnAllowed,
-			options.Validate,
-			options.Initialize,
+			options.StepFinished,
 			options.Terminate,
 			options.SetStopTime)
+	}
+
+	return SimulateCS(
+		options.ModelDescription,
+		comp,
+		options.StartTime,
+		options.StopTime,
+		options.RelativeTolerance,
+		options.StartValues,
+		options.ApplyDefaultStartValues,
+		options.Input,
+		options.Output,
+		options.OutputInterval,
+		options.Timeout,
+		options.StepFinished,
+		options.SetInputDerivatives,
+		options.Initialize,
+		options.Terminate,
+		options.SetStopTime)
+}
 
-		_ = result
+// simulateRemote simulates the binary of the FMU for platform with the registered remoting backend
+func simulateRemote(filename string, platform string, fmiType Type, options SimulationOptions) (*Result, error) {
+
+	backend := RemotingBackendFor(platform)
+	if backend == nil {
+		return nil, fmt.Errorf("no remoting backend has been registered for the platform %s", platform)
+	}
+
+	// the remoting backends do not filter the messages themselves
+	logger := fmi.LogOptions[Status]{Logger: options.Logger, Categories: options.LogCategories}.NewLogger()
+
+	fmu, err := backend.Load(filename, platform, fmiType, logger)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	defer fmu.Close()
+
+	comp, err := fmu.Instantiate(
+		options.ModelDescription.ModelName,
+		fmiType,
+		options.ModelDescription.Guid,
+		options.ResourceLocation,
+		options.Visible,
+		options.DebugLogging)
+	if err != nil {
+		return nil, err
+	}
+
+	defer comp.FreeInstance()
+
+	return simulateInstance(comp, options)
 }
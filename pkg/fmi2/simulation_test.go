@@ -0,0 +1,432 @@
+package fmi2_test
+
+import (
+	"errors"
+	"go-fmu/pkg/fmi2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateTicker(t *testing.T) {
+
+	const filename = "../../examples/Ticker.fmu"
+	const delta = 1e-4
+
+	stopTime := 3.0
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:     &stopTime,
+		Solver:       "Euler",
+		RecordEvents: true,
+		Initialize:   true,
+		Terminate:    true,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Len(t, result.Columns, 1)
+
+	ticks := result.Column("ticks")
+	require.NotNil(t, ticks)
+	require.Equal(t, "Real", ticks.Type)
+
+	// the start value, one sample per output interval and every tick is recorded before and after the event
+	require.Len(t, result.Time, 1+600+3)
+	require.Equal(t, len(result.Time), ticks.Len())
+	require.InDelta(t, 0.0, result.Time[0], delta)
+	require.InDelta(t, 3.0, result.Time[len(result.Time)-1], delta)
+	require.InDelta(t, 3.0, ticks.Real[len(ticks.Real)-1], delta)
+}
+
+func TestSimulateRectifier(t *testing.T) {
+
+	const filename = "../../examples/Rectifier.fmu"
+
+	stopTime := 0.01
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:   &stopTime,
+		Output:     []string{"outputs", "Rectifier1.Capacitor2.v"},
+		Initialize: true,
+		Terminate:  true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Columns, 2)
+	require.Equal(t, "outputs", result.Columns[0].Name)
+	require.Equal(t, len(result.Time), result.Columns[0].Len())
+	require.Equal(t, len(result.Time), result.Columns[1].Len())
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:   &stopTime,
+		Output:     []string{"unknown"},
+		Initialize: true,
+	})
+
+	require.Error(t, err)
+}
+
+func TestSimulateInputCS(t *testing.T) {
+
+	const filename = "../../examples/Controller.fmu"
+	const delta = 1e-4
+
+	stopTime := 1.0
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:            &stopTime,
+		FmiType:             "CoSimulation",
+		Output:              []string{"u_s", "y"},
+		SetInputDerivatives: true,
+		Initialize:          true,
+		Terminate:           true,
+		Input: map[string]fmi2.Signal{
+			"u_s": {Time: []float64{0, 0.3511, 0.3511, 1}, Values: []float64{0, 0, 1, 2}},
+		},
+	})
+
+	require.NoError(t, err)
+
+	u := result.Column("u_s")
+	require.NotNil(t, u)
+
+	// the communication step is shortened to the step of the input
+	i := slices.Index(result.Time, 0.3511)
+	require.GreaterOrEqual(t, i, 0)
+	require.InDelta(t, 0.0, u.Real[i], delta)
+	require.InDelta(t, 2.0, u.Real[len(u.Real)-1], delta)
+}
+
+// discardingInstance discards the steps that end after a point in time without terminating the simulation
+type discardingInstance struct {
+	fmi2.Instance
+	discardAfter float64
+}
+
+func (c *discardingInstance) DoStep(currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) error {
+	if currentCommunicationPoint+communicationStepSize > c.discardAfter {
+		return &fmi2.StatusError{Function: "fmi2DoStep", Status: fmi2.Discard}
+	}
+	return c.Instance.DoStep(currentCommunicationPoint, communicationStepSize, noSetFMUStatePriorToCurrentPoint)
+}
+
+func (c *discardingInstance) GetBooleanStatus(s fmi2.StatusKind) (bool, error) {
+	return false, nil
+}
+
+func TestSimulateDiscardCS(t *testing.T) {
+
+	fmu, err := fmi2.New("../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	md := fmu.ModelDescription()
+
+	c := fmu.Instantiate("controller", fmi2.CoSimulationType, md.Guid, fmu.ResourceLocation(), false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	startTime, stopTime, outputInterval := 0.0, 1.0, 0.1
+
+	_, err = fmi2.SimulateCS(md, &discardingInstance{Instance: c, discardAfter: 0.5}, &startTime, &stopTime, nil, nil, false, nil, nil, &outputInterval, nil, nil, false, true, true, false)
+
+	// the refused step is reported instead of recording a time the FMU did not reach
+	require.True(t, fmi2.IsDiscard(err))
+	require.ErrorContains(t, err, "the FMU discarded the step from t=0.5 to t=0.6")
+}
+
+func TestSimulateInputME(t *testing.T) {
+
+	const filename = "../../examples/Bounce.fmu"
+	const delta = 1e-4
+
+	stopTime := 1.0
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:     &stopTime,
+		Solver:       "RK45",
+		RecordEvents: true,
+		Initialize:   true,
+		Terminate:    true,
+		Input: map[string]fmi2.Signal{
+			"h": {Time: []float64{0, 1}, Values: []float64{1, -1}},
+		},
+	})
+
+	require.NoError(t, err)
+
+	reset := result.Column("reset")
+	require.NotNil(t, reset)
+
+	// the interpolated input crosses zero at t = 0.5
+	i := slices.Index(reset.Boolean, true)
+	require.GreaterOrEqual(t, i, 0)
+	require.InDelta(t, 0.5, result.Time[i], delta)
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:   &stopTime,
+		Initialize: true,
+		Input: map[string]fmi2.Signal{
+			"reset": {Time: []float64{0}, Values: []float64{1}},
+		},
+	})
+
+	require.Error(t, err)
+}
+
+func TestSimulateStartValues(t *testing.T) {
+
+	const filename = "../../examples/Ball.fmu"
+	const delta = 1e-4
+
+	stopTime := 0.1
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:    &stopTime,
+		StartValues: map[string]any{"h0": 2, "v": 1.0},
+		Initialize:  true,
+		Terminate:   true,
+	})
+
+	require.NoError(t, err)
+
+	h := result.Column("h")
+	require.NotNil(t, h)
+	require.InDelta(t, 2.0, h.Real[0], delta)
+	require.Greater(t, h.Real[1], h.Real[0])
+
+	// the initial value of h is calculated from h0
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:    &stopTime,
+		StartValues: map[string]any{"h": 2.0},
+		Initialize:  true,
+	})
+
+	require.ErrorContains(t, err, "calculated")
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:    &stopTime,
+		StartValues: map[string]any{"h0": true},
+		Initialize:  true,
+	})
+
+	require.ErrorContains(t, err, "must be a number")
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StopTime:    &stopTime,
+		StartValues: map[string]any{"unknown": 1.0},
+		Validate:    true,
+		Initialize:  true,
+	})
+
+	require.ErrorContains(t, err, "unknown")
+}
+
+func TestSimulateFmi1ME(t *testing.T) {
+
+	const filename = "../../examples/BouncingBall.fmu"
+	const delta = 1e-3
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		Solver:                  "RK45",
+		StartValues:             map[string]any{"e": 0.5},
+		ApplyDefaultStartValues: true,
+		Initialize:              true,
+		Terminate:               true,
+	})
+
+	require.NoError(t, err)
+	require.InDelta(t, 3.0, result.Time[len(result.Time)-1], delta)
+
+	// the ball bounces off the ground with half of its speed until it comes to rest
+	h, bounces := result.Column("h"), result.Column("bounces")
+	require.InDelta(t, 1.0, h.Real[0], delta)
+	require.InDelta(t, 0.0, h.Real[len(h.Real)-1], delta)
+	require.Equal(t, 6, bounces.Integer[len(bounces.Integer)-1])
+
+	for _, value := range h.Real {
+		require.GreaterOrEqual(t, value, -delta)
+	}
+
+	// the guess value v can be set, the fixed start value of h cannot be set before fmiInitialize
+	result, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StartValues: map[string]any{"v": 2.0},
+		Output:      []string{"v"},
+		Initialize:  true,
+	})
+
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, result.Column("v").Real[0], delta)
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StartValues: map[string]any{"h": 2.0},
+		Initialize:  true,
+	})
+
+	require.ErrorContains(t, err, "the start value of h cannot be set because it is fixed")
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		Output:     []string{"depth"},
+		Initialize: true,
+	})
+
+	require.ErrorContains(t, err, "depth is a negated alias, negated aliases are not supported")
+}
+
+func TestSimulateFmi1CS(t *testing.T) {
+
+	const filename = "../../examples/Integrator.fmu"
+	const delta = 1e-6
+
+	outputInterval := 0.1
+
+	result, err := fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StartValues:         map[string]any{"k": 2.0},
+		OutputInterval:      &outputInterval,
+		SetInputDerivatives: true,
+		Initialize:          true,
+		Terminate:           true,
+		Input: map[string]fmi2.Signal{
+			"u": {Time: []float64{0, 1}, Values: []float64{0, 1}},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Time, 11)
+
+	// the ramp is integrated exactly with the derivatives of the input: y = k * t^2 / 2
+	y := result.Column("y")
+	require.InDelta(t, 0.25, y.Real[5], delta)
+	require.InDelta(t, 1.0, y.Real[10], delta)
+	require.Equal(t, 10, result.Column("steps").Integer[10])
+
+	// FMI 1.0 has no status to distinguish a terminated simulation, so a discarded step ends the simulation
+	result, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		StartValues:    map[string]any{"discardTime": 0.5},
+		OutputInterval: &outputInterval,
+		Initialize:     true,
+	})
+
+	require.NoError(t, err)
+	require.InDelta(t, 0.5, result.Time[len(result.Time)-1], delta)
+	require.InDelta(t, 0.5, result.Column("y").Real[len(result.Time)-1], delta)
+
+	_, err = fmi2.SimulateFmu(filename, fmi2.SimulationOptions{
+		Output:     []string{"minusY"},
+		Initialize: true,
+	})
+
+	require.ErrorContains(t, err, "minusY is a negated alias, negated aliases are not supported")
+}
+
+// fakeRemoting simulates a 32-bit helper by loading the linux64 binary of the original FMU in the current process
+type fakeRemoting struct {
+	original          string
+	loads             int
+	resourceLocations []string // the resource locations passed to Instantiate
+}
+
+func (r *fakeRemoting) Platforms() []string {
+	return []string{"linux32"}
+}
+
+func (r *fakeRemoting) Load(filename string, platform string, fmiType fmi2.Type, logger fmi2.Logger) (fmi2.RemoteFmu, error) {
+	r.loads++
+	fmu, err := fmi2.New(r.original, fmiType)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRemoteFmu{backend: r, fmu: fmu}, nil
+}
+
+type fakeRemoteFmu struct {
+	backend *fakeRemoting
+	fmu     *fmi2.Fmu2
+}
+
+func (f *fakeRemoteFmu) Instantiate(instanceName string, fmuType fmi2.Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool) (fmi2.Instance, error) {
+	f.backend.resourceLocations = append(f.backend.resourceLocations, resourceLocation)
+	if resourceLocation == "" {
+		resourceLocation = f.fmu.ResourceLocation()
+	}
+	c := f.fmu.Instantiate(instanceName, fmuType, fmuGuid, resourceLocation, visible, loggingOn)
+	if c == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+	return c, nil
+}
+
+func (f *fakeRemoteFmu) Close() error {
+	return f.fmu.Close()
+}
+
+func TestRemotePlatform(t *testing.T) {
+
+	const original = "../../examples/Ticker.fmu"
+
+	// an FMU that only contains binaries for linux32
+	filename := repackage(t, original,
+		func(name string) string {
+			if strings.HasPrefix(name, "binaries/") && !strings.HasPrefix(name, "binaries/linux64/") {
+				return ""
+			}
+			return strings.Replace(name, "binaries/linux64/", "binaries/linux32/", 1)
+		},
+		func(md string) string { return md })
+
+	require.Equal(t, []string{"linux32"}, fmi2.SupportedPlatforms(filename))
+
+	canSimulate, remotePlatform := fmi2.CanSimulate([]string{"linux64", "linux32"}, "auto")
+	require.True(t, canSimulate)
+	require.Empty(t, remotePlatform)
+
+	options := fmi2.SimulationOptions{Initialize: true, Terminate: true}
+
+	_, err := fmi2.SimulateFmu(filename, options)
+	require.ErrorContains(t, err, "linux32")
+
+	options.RemotePlatform = "auto"
+	_, err = fmi2.SimulateFmu(filename, options)
+	require.EqualError(t, err, "the FMU cannot be simulated on the current platform (linux64), the FMU contains binaries for the platforms: linux32, no remoting backend has been registered")
+
+	backend := &fakeRemoting{original: original}
+	t.Cleanup(fmi2.RegisterRemotingBackend(backend))
+
+	canSimulate, remotePlatform = fmi2.CanSimulate([]string{"linux32"}, "auto")
+	require.True(t, canSimulate)
+	require.Equal(t, "linux32", remotePlatform)
+
+	canSimulate, _ = fmi2.CanSimulate([]string{"linux32"}, "win32")
+	require.False(t, canSimulate)
+
+	result, err := fmi2.SimulateFmu(filename, options)
+	require.NoError(t, err)
+	require.Equal(t, 1, backend.loads)
+	require.InDelta(t, 1.0, result.Column("ticks").Real[len(result.Time)-1], 1e-4)
+
+	options.RemotePlatform = "linux32"
+	options.ResourceLocation = "file:///tmp/resources/"
+	_, err = fmi2.SimulateFmu(filename, options)
+	require.NoError(t, err)
+	require.Equal(t, 2, backend.loads)
+
+	// the backend uses its own resources unless a resource location is passed
+	require.Equal(t, []string{"", "file:///tmp/resources/"}, backend.resourceLocations)
+}
+
+func TestRegisterRemotingBackend(t *testing.T) {
+
+	first := &fakeRemoting{}
+	unregisterFirst := fmi2.RegisterRemotingBackend(first)
+	unregisterSecond := fmi2.RegisterRemotingBackend(&fakeRemoting{})
+
+	unregisterSecond()
+	require.Same(t, first, fmi2.RemotingBackendFor("linux32"))
+
+	unregisterFirst()
+	require.Nil(t, fmi2.RemotingBackendFor("linux32"))
+	require.Empty(t, fmi2.RemotePlatforms())
+}
@@ -0,0 +1,501 @@
+package fmi2
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// OdeSystem is the continuous-time part of a model as seen by a Solver
+type OdeSystem interface {
+	NumberOfStates() int
+	NumberOfEventIndicators() int
+	SetTime(t float64) error
+	GetContinuousStates() ([]float64, error)
+	SetContinuousStates(x []float64) error
+	GetDerivatives() ([]float64, error)
+	GetEventIndicators() ([]float64, error)
+}
+
+// Solver integrates the continuous states of an OdeSystem between events
+type Solver interface {
+	// Step integrates from t towards tNext. It returns the time that was reached and
+	// whether a state event (a zero crossing of an event indicator) was detected.
+	// On a state event the returned time is the time of the event.
+	Step(t float64, tNext float64) (float64, bool, error)
+
+	// Reset re-initializes the solver at time t, after the continuous states have been changed by an event
+	Reset(t float64) error
+}
+
+// JacobianProvider is implemented by an OdeSystem that can compute the Jacobian
+// J[i][j] = d(der(x[i]))/d(x[j]) of the derivatives with respect to the states itself.
+// Solvers that need a Jacobian use finite differences for other systems.
+type JacobianProvider interface {
+	GetJacobian() ([][]float64, error)
+}
+
+// componentSystem adapts a Model Exchange Component to the OdeSystem interface
+type componentSystem struct {
+	component Instance
+	input     *Input // the continuous inputs are applied whenever the time is set (optional)
+	nx        int
+	nz        int
+}
+
+func (s *componentSystem) NumberOfStates() int {
+	return s.nx
+}
+
+func (s *componentSystem) NumberOfEventIndicators() int {
+	return s.nz
+}
+
+func (s *componentSystem) SetTime(t float64) error {
+	if err := s.component.SetTime(t); err != nil {
+		return err
+	}
+
+	if s.input != nil {
+		return s.input.Apply(t, true, false, false)
+	}
+
+	return nil
+}
+
+func (s *componentSystem) GetContinuousStates() ([]float64, error) {
+	return s.component.GetContinuousStates(s.nx)
+}
+
+func (s *componentSystem) SetContinuousStates(x []float64) error {
+	return s.component.SetContinuousStates(x)
+}
+
+func (s *componentSystem) GetDerivatives() ([]float64, error) {
+	return s.component.GetDerivatives(s.nx)
+}
+
+func (s *componentSystem) GetEventIndicators() ([]float64, error) {
+	return s.component.GetEventIndicators(s.nz)
+}
+
+// directionalDerivativeSystem is a componentSystem of an FMU that provides directional derivatives
+type directionalDerivativeSystem struct {
+	*componentSystem
+	states      []ValueReference
+	derivatives []ValueReference
+}
+
+func (s *directionalDerivativeSystem) GetJacobian() ([][]float64, error) {
+
+	jacobian := make([][]float64, s.nx)
+	for i := range jacobian {
+		jacobian[i] = make([]float64, s.nx)
+	}
+
+	// column j is the directional derivative in the direction of the unit vector of state j
+	for j, vr := range s.states {
+		column, err := s.component.GetDirectionalDerivative(s.derivatives, []ValueReference{vr}, []float64{1.0})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range column {
+			jacobian[i][j] = column[i]
+		}
+	}
+
+	return jacobian, nil
+}
+
+type SolverOption func(*SolverOptions)
+
+type SolverOptions struct {
+	stepSize          float64
+	maxStepSize       float64
+	relativeTolerance float64
+	absoluteTolerance float64
+}
+
+// WithStepSize sets the step size of the fixed step solvers and the initial step size of the variable step solvers
+func WithStepSize(stepSize float64) SolverOption {
+	return func(o *SolverOptions) {
+		o.stepSize = stepSize
+	}
+}
+
+// WithMaxStepSize limits the step size of the variable step solvers
+func WithMaxStepSize(maxStepSize float64) SolverOption {
+	return func(o *SolverOptions) {
+		o.maxStepSize = maxStepSize
+	}
+}
+
+// WithTolerance sets the relative and absolute tolerance of the variable step solvers
+func WithTolerance(relativeTolerance float64, absoluteTolerance float64) SolverOption {
+	return func(o *SolverOptions) {
+		o.relativeTolerance = relativeTolerance
+		o.absoluteTolerance = absoluteTolerance
+	}
+}
+
+// NewSolver creates the solver with the given name for system, starting at time t.
+// The supported solvers are "Euler" (the default for an empty name), "RK4", "RK45" and "Rosenbrock".
+func NewSolver(name string, system OdeSystem, t float64, opts ...SolverOption) (Solver, error) {
+
+	options := &SolverOptions{
+		stepSize:          1e-3,
+		maxStepSize:       math.Inf(1),
+		relativeTolerance: 1e-5,
+		absoluteTolerance: 1e-5,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.stepSize <= 0 || options.maxStepSize <= 0 {
+		return nil, fmt.Errorf("step size must be positive: %g", options.stepSize)
+	}
+
+	if options.relativeTolerance <= 0 || options.absoluteTolerance <= 0 {
+		return nil, fmt.Errorf("tolerance must be positive: %g", options.relativeTolerance)
+	}
+
+	switch name {
+	case "", "Euler":
+		return NewEulerSolver(system, t, options.stepSize)
+	case "RK4":
+		return NewRK4Solver(system, t, options.stepSize)
+	case "RK45":
+		return NewDormandPrinceSolver(system, t, options)
+	case "Rosenbrock":
+		return NewRosenbrockSolver(system, t, options)
+	default:
+		return nil, fmt.Errorf("unsupported solver: %s", name)
+	}
+}
+
+// zeroCrossing reports if any event indicator changed its domain from z > 0 to z <= 0 or vice versa
+func zeroCrossing(previous []float64, current []float64) bool {
+	for i := range previous {
+		if (previous[i] > 0) != (current[i] > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate sets the time and the states of system and returns the derivatives
+func evaluate(system OdeSystem, t float64, x []float64) ([]float64, error) {
+
+	if err := system.SetTime(t); err != nil {
+		return nil, err
+	}
+
+	if err := system.SetContinuousStates(x); err != nil {
+		return nil, err
+	}
+
+	return system.GetDerivatives()
+}
+
+// finiteDifferenceJacobian approximates the Jacobian of system at (t, x) with forward differences, where dx are the derivatives at (t, x)
+func finiteDifferenceJacobian(system OdeSystem, t float64, x []float64, dx []float64) ([][]float64, error) {
+
+	jacobian := make([][]float64, len(x))
+	for i := range jacobian {
+		jacobian[i] = make([]float64, len(x))
+	}
+
+	perturbed := slices.Clone(x)
+
+	for j := range x {
+		delta := math.Sqrt(machineEpsilon) * math.Max(math.Abs(x[j]), 1.0)
+		perturbed[j] = x[j] + delta
+
+		dxPerturbed, err := evaluate(system, t, perturbed)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range x {
+			jacobian[i][j] = (dxPerturbed[i] - dx[i]) / delta
+		}
+
+		perturbed[j] = x[j]
+	}
+
+	return jacobian, nil
+}
+
+// hermite interpolates the states at time t0 + theta * h from the states and derivatives at the start and the end of a step
+func hermite(theta float64, h float64, x0 []float64, dx0 []float64, x1 []float64, dx1 []float64) []float64 {
+
+	h00 := (1 + 2*theta) * (1 - theta) * (1 - theta)
+	h10 := theta * (1 - theta) * (1 - theta)
+	h01 := theta * theta * (3 - 2*theta)
+	h11 := theta * theta * (theta - 1)
+
+	x := make([]float64, len(x0))
+	for i := range x {
+		x[i] = h00*x0[i] + h10*h*dx0[i] + h01*x1[i] + h11*h*dx1[i]
+	}
+
+	return x
+}
+
+// locateEvent finds the time of a zero crossing of the event indicators within the step from (t0, x0)
+// to (t1, x1) by bisection of the interpolated states, z0 are the event indicators at t0.
+// The system is left at the first point found after the zero crossing, which is also the returned time.
+func locateEvent(system OdeSystem, t0 float64, x0 []float64, dx0 []float64, z0 []float64, t1 float64, x1 []float64, dx1 []float64) (float64, error) {
+
+	h := t1 - t0
+	lower := 0.0
+	upper := 1.0
+	xUpper := x1
+
+	for (upper-lower)*math.Abs(h) > 1e-12*math.Max(1.0, math.Abs(t1)) {
+
+		theta := (lower + upper) / 2
+		x := hermite(theta, h, x0, dx0, x1, dx1)
+
+		if err := system.SetTime(t0 + theta*h); err != nil {
+			return 0, err
+		}
+
+		if err := system.SetContinuousStates(x); err != nil {
+			return 0, err
+		}
+
+		z, err := system.GetEventIndicators()
+		if err != nil {
+			return 0, err
+		}
+
+		if zeroCrossing(z0, z) {
+			upper = theta
+			xUpper = x
+		} else {
+			lower = theta
+		}
+	}
+
+	t := t0 + upper*h
+
+	if err := system.SetTime(t); err != nil {
+		return 0, err
+	}
+
+	if err := system.SetContinuousStates(xUpper); err != nil {
+		return 0, err
+	}
+
+	return t, nil
+}
+
+// nextStepTime returns the end of a step of size h from t, which is tNext if the step would exceed it
+func nextStepTime(t float64, h float64, tNext float64) float64 {
+	tStep := t + h
+	if tStep > tNext || Float64IsClose(tStep, tNext, WithEpsilon(1e-9)) {
+		return tNext
+	}
+	return tStep
+}
+
+// minStepSize is the smallest step size the variable step solvers use at time t
+func minStepSize(t float64) float64 {
+	return 16 * machineEpsilon * math.Max(1.0, math.Abs(t))
+}
+
+// EulerSolver is the explicit (forward) Euler method with a fixed step size
+type EulerSolver struct {
+	system   OdeSystem
+	stepSize float64
+	z        []float64
+}
+
+func NewEulerSolver(system OdeSystem, t float64, stepSize float64) (*EulerSolver, error) {
+
+	if stepSize <= 0 {
+		return nil, fmt.Errorf("step size must be positive: %g", stepSize)
+	}
+
+	s := &EulerSolver{
+		system:   system,
+		stepSize: stepSize,
+	}
+
+	if err := s.Reset(t); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *EulerSolver) Reset(t float64) error {
+	z, err := s.system.GetEventIndicators()
+	if err != nil {
+		return err
+	}
+
+	s.z = z
+	return nil
+}
+
+func (s *EulerSolver) Step(t float64, tNext float64) (float64, bool, error) {
+
+	for t < tNext && !Float64IsClose(t, tNext, WithEpsilon(1e-12)) {
+
+		// take the remainder as the last step if the step size doesn't divide the interval
+		tStep := nextStepTime(t, s.stepSize, tNext)
+		h := tStep - t
+
+		dx, err := s.system.GetDerivatives()
+		if err != nil {
+			return t, false, err
+		}
+
+		x, err := s.system.GetContinuousStates()
+		if err != nil {
+			return t, false, err
+		}
+
+		for i := range x {
+			x[i] += h * dx[i]
+		}
+
+		t = tStep
+
+		if err := s.system.SetTime(t); err != nil {
+			return t, false, err
+		}
+
+		if err := s.system.SetContinuousStates(x); err != nil {
+			return t, false, err
+		}
+
+		z, err := s.system.GetEventIndicators()
+		if err != nil {
+			return t, false, err
+		}
+
+		stateEvent := zeroCrossing(s.z, z)
+		s.z = z
+
+		if stateEvent {
+			return t, true, nil
+		}
+	}
+
+	return t, false, nil
+}
+
+// RK4Solver is the classical fourth order Runge-Kutta method with a fixed step size.
+// Zero crossings of the event indicators are located within the step.
+type RK4Solver struct {
+	system   OdeSystem
+	stepSize float64
+	z        []float64
+}
+
+func NewRK4Solver(system OdeSystem, t float64, stepSize float64) (*RK4Solver, error) {
+
+	if stepSize <= 0 {
+		return nil, fmt.Errorf("step size must be positive: %g", stepSize)
+	}
+
+	s := &RK4Solver{
+		system:   system,
+		stepSize: stepSize,
+	}
+
+	if err := s.Reset(t); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *RK4Solver) Reset(t float64) error {
+	z, err := s.system.GetEventIndicators()
+	if err != nil {
+		return err
+	}
+
+	s.z = z
+	return nil
+}
+
+func (s *RK4Solver) Step(t float64, tNext float64) (float64, bool, error) {
+
+	x, err := s.system.GetContinuousStates()
+	if err != nil {
+		return t, false, err
+	}
+
+	for t < tNext && !Float64IsClose(t, tNext, WithEpsilon(1e-12)) {
+
+		tStep := nextStepTime(t, s.stepSize, tNext)
+		h := tStep - t
+
+		k1, err := evaluate(s.system, t, x)
+		if err != nil {
+			return t, false, err
+		}
+
+		k2, err := evaluate(s.system, t+h/2, axpy(h/2, k1, x))
+		if err != nil {
+			return t, false, err
+		}
+
+		k3, err := evaluate(s.system, t+h/2, axpy(h/2, k2, x))
+		if err != nil {
+			return t, false, err
+		}
+
+		k4, err := evaluate(s.system, tStep, axpy(h, k3, x))
+		if err != nil {
+			return t, false, err
+		}
+
+		xNext := make([]float64, len(x))
+		for i := range x {
+			xNext[i] = x[i] + h/6*(k1[i]+2*k2[i]+2*k3[i]+k4[i])
+		}
+
+		dxNext, err := evaluate(s.system, tStep, xNext)
+		if err != nil {
+			return t, false, err
+		}
+
+		z, err := s.system.GetEventIndicators()
+		if err != nil {
+			return t, false, err
+		}
+
+		if zeroCrossing(s.z, z) {
+			tEvent, err := locateEvent(s.system, t, x, k1, s.z, tStep, xNext, dxNext)
+			if err != nil {
+				return t, false, err
+			}
+
+			return tEvent, true, s.Reset(tEvent)
+		}
+
+		t = tStep
+		x = xNext
+		s.z = z
+	}
+
+	return t, false, nil
+}
+
+// axpy returns a * x + y
+func axpy(a float64, x []float64, y []float64) []float64 {
+	result := make([]float64, len(y))
+	for i := range y {
+		result[i] = a*x[i] + y[i]
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+package fmi2_test
+
+import (
+	"go-fmu/pkg/fmi2"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// odeSystem implements fmi2.OdeSystem for der(x) = f(t, x) with event indicators z(t, x)
+type odeSystem struct {
+	t           float64
+	x           []float64
+	f           func(t float64, x []float64) []float64
+	z           func(t float64, x []float64) []float64
+	evaluations int
+}
+
+func (s *odeSystem) NumberOfStates() int          { return len(s.x) }
+func (s *odeSystem) NumberOfEventIndicators() int { return len(s.z(s.t, s.x)) }
+func (s *odeSystem) SetTime(t float64) error      { s.t = t; return nil }
+
+func (s *odeSystem) GetContinuousStates() ([]float64, error) {
+	return append([]float64(nil), s.x...), nil
+}
+
+func (s *odeSystem) SetContinuousStates(x []float64) error {
+	copy(s.x, x)
+	return nil
+}
+
+func (s *odeSystem) GetDerivatives() ([]float64, error) {
+	s.evaluations++
+	return s.f(s.t, s.x), nil
+}
+
+func (s *odeSystem) GetEventIndicators() ([]float64, error) {
+	return s.z(s.t, s.x), nil
+}
+
+func newDecaySystem() *odeSystem {
+	return &odeSystem{
+		x: []float64{1.0},
+		f: func(t float64, x []float64) []float64 { return []float64{-x[0]} },
+		z: func(t float64, x []float64) []float64 { return []float64{x[0] - 0.5} },
+	}
+}
+
+func TestSolvers(t *testing.T) {
+
+	tests := []struct {
+		solver string
+		delta  float64
+	}{
+		{"Euler", 1e-3},
+		{"RK4", 1e-10},
+		{"RK45", 1e-5},
+		{"Rosenbrock", 1e-4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.solver, func(t *testing.T) {
+			system := newDecaySystem()
+			system.z = func(t float64, x []float64) []float64 { return []float64{} }
+
+			solver, err := fmi2.NewSolver(tt.solver, system, 0, fmi2.WithStepSize(1e-3), fmi2.WithTolerance(1e-6, 1e-8))
+			require.NoError(t, err)
+
+			time, stateEvent, err := solver.Step(0, 1)
+			require.NoError(t, err)
+			require.False(t, stateEvent)
+			require.Equal(t, 1.0, time)
+			require.InDelta(t, math.Exp(-1), system.x[0], tt.delta)
+		})
+	}
+}
+
+func TestSolverStateEvent(t *testing.T) {
+
+	for _, name := range []string{"RK4", "RK45", "Rosenbrock"} {
+		t.Run(name, func(t *testing.T) {
+			system := newDecaySystem()
+
+			solver, err := fmi2.NewSolver(name, system, 0, fmi2.WithStepSize(0.1), fmi2.WithTolerance(1e-6, 1e-8))
+			require.NoError(t, err)
+
+			// x = exp(-t) crosses 0.5 at t = ln(2)
+			time, stateEvent, err := solver.Step(0, 1)
+			require.NoError(t, err)
+			require.True(t, stateEvent)
+			require.InDelta(t, math.Ln2, time, 1e-4)
+			require.LessOrEqual(t, system.x[0], 0.5)
+		})
+	}
+}
+
+func TestRosenbrockStiff(t *testing.T) {
+
+	// der(x) = -1000 * (x - cos(t)) follows cos(t) closely after a short transient
+	newSystem := func() *odeSystem {
+		return &odeSystem{
+			x: []float64{0.0},
+			f: func(t float64, x []float64) []float64 { return []float64{-1000 * (x[0] - math.Cos(t))} },
+			z: func(t float64, x []float64) []float64 { return []float64{} },
+		}
+	}
+
+	explicit := newSystem()
+	solver, err := fmi2.NewSolver("RK45", explicit, 0, fmi2.WithStepSize(1e-3))
+	require.NoError(t, err)
+	_, _, err = solver.Step(0, 10)
+	require.NoError(t, err)
+
+	implicit := newSystem()
+	solver, err = fmi2.NewSolver("Rosenbrock", implicit, 0, fmi2.WithStepSize(1e-3))
+	require.NoError(t, err)
+	_, _, err = solver.Step(0, 10)
+	require.NoError(t, err)
+
+	require.InDelta(t, math.Cos(10), implicit.x[0], 1e-3)
+	require.Less(t, implicit.evaluations, explicit.evaluations)
+}
@@ -0,0 +1,176 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+)
+
+// causalityOf returns the causality of the variable, which defaults to "local"
+func causalityOf(v *ScalarVariable) string {
+	if v.Causality == "" {
+		return "local"
+	}
+	return v.Causality
+}
+
+// variabilityOf returns the variability of the variable, which defaults to "continuous"
+func variabilityOf(v *ScalarVariable) string {
+	if v.Variability == "" {
+		return "continuous"
+	}
+	return v.Variability
+}
+
+// initialOf returns the initial attribute of the variable or its default for the combination
+// of causality and variability (FMI 2.0 specification, section 2.2.7). Inputs and the independent
+// variable have no initial attribute.
+func initialOf(v *ScalarVariable) string {
+	if v.Initial != "" {
+		return v.Initial
+	}
+
+	switch causalityOf(v) {
+	case "parameter":
+		return "exact"
+	case "calculatedParameter":
+		return "calculated"
+	case "input", "independent":
+		return ""
+	default:
+		if variabilityOf(v) == "constant" {
+			return "exact"
+		}
+		return "calculated"
+	}
+}
+
+// settableInInstantiated returns whether the variable can be set after the FMU has been instantiated
+func settableInInstantiated(v *ScalarVariable) bool {
+	initial := initialOf(v)
+	return variabilityOf(v) != "constant" && (initial == "exact" || initial == "approx")
+}
+
+// settableInInitializationMode returns whether the variable can be set in Initialization Mode
+func settableInInitializationMode(v *ScalarVariable) bool {
+	return causalityOf(v) == "input" || (variabilityOf(v) != "constant" && initialOf(v) == "exact")
+}
+
+// checkStartValue returns a descriptive error if no start value can be set for the variable
+func checkStartValue(v *ScalarVariable) error {
+	switch {
+	case variabilityOf(v) == "constant":
+		return fmt.Errorf("the start value of %s cannot be set because the variable is constant", v.Name)
+	case causalityOf(v) == "independent":
+		return fmt.Errorf("the start value of %s cannot be set because it is the independent variable", v.Name)
+	case initialOf(v) == "calculated":
+		return fmt.Errorf("the start value of %s cannot be set because its initial value is calculated", v.Name)
+	case v.fixedStart:
+		return fmt.Errorf("the start value of %s cannot be set because it is fixed", v.Name)
+	case variableType(v) == "":
+		return fmt.Errorf("the start value of %s cannot be set because the variable has no type", v.Name)
+	}
+	return nil
+}
+
+// variablesByName returns a map of the variables of the model description by name
+func (md *ModelDescription) variablesByName() map[string]*ScalarVariable {
+	variables := make(map[string]*ScalarVariable)
+	if md.ModelVariables != nil {
+		for i := range md.ModelVariables.ScalarVariable {
+			v := &md.ModelVariables.ScalarVariable[i]
+			variables[v.Name] = v
+		}
+	}
+	return variables
+}
+
+// ValidateStartValues checks that all start values refer to variables of the model that can be set
+func (md *ModelDescription) ValidateStartValues(startValues map[string]any) error {
+
+	variables := md.variablesByName()
+
+	var errs []error
+	for _, name := range fmi.SortedNames(startValues) {
+		v, ok := variables[name]
+		if !ok {
+			errs = append(errs, md.unknownVariableError("unknown variable in start values: %s", name))
+			continue
+		}
+
+		if err := checkStartValue(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// defaultStartValues adds the start values from the model description of the variables that can be set
+// to a copy of startValues
+func defaultStartValues(md *ModelDescription, startValues map[string]any) map[string]any {
+
+	values := make(map[string]any)
+
+	if md.ModelVariables != nil {
+		for i := range md.ModelVariables.ScalarVariable {
+			v := &md.ModelVariables.ScalarVariable[i]
+
+			if !settableInInitializationMode(v) || v.fixedStart {
+				continue
+			}
+
+			switch {
+			case v.Real != nil && v.Real.Start != nil:
+				values[v.Name] = *v.Real.Start
+			case v.Integer != nil && v.Integer.Start != nil:
+				values[v.Name] = *v.Integer.Start
+			case v.Boolean != nil && v.Boolean.Start != nil:
+				values[v.Name] = *v.Boolean.Start
+			case v.String != nil && v.String.Start != nil:
+				values[v.Name] = *v.String.Start
+			case v.Enumeration != nil && v.Enumeration.Start != nil:
+				values[v.Name] = *v.Enumeration.Start
+			}
+		}
+	}
+
+	for name, value := range startValues {
+		values[name] = value
+	}
+
+	return values
+}
+
+// applyStartValues sets the start values of the variables for which settable returns true
+func applyStartValues(fmu variableAccess, md *ModelDescription, startValues map[string]any, settable func(*ScalarVariable) bool) error {
+
+	variables := md.variablesByName()
+
+	for _, name := range fmi.SortedNames(startValues) {
+
+		v, ok := variables[name]
+		if !ok {
+			return md.unknownVariableError("unknown variable in start values: %s", name)
+		}
+
+		if err := checkStartValue(v); err != nil {
+			return err
+		}
+
+		if !settable(v) {
+			continue
+		}
+
+		if err := setStartValue(fmu, md, v, startValues[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setStartValue converts value to the type of the variable and sets it
+func setStartValue(fmu variableAccess, md *ModelDescription, v *ScalarVariable, value any) error {
+	return setVariables(fmu, md, []*ScalarVariable{v}, []any{value}, "the start value")
+}
@@ -0,0 +1,281 @@
+package fmi2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NamePart is a component of a structured name, e.g. "b[1,2]" in "a.b[1,2].c"
+type NamePart struct {
+	Name    string // the identifier, quoted names ('my var') include the quotes
+	Indices []int  // the array indices or nil
+}
+
+func (p NamePart) String() string {
+	if len(p.Indices) == 0 {
+		return p.Name
+	}
+
+	indices := make([]string, len(p.Indices))
+	for i, index := range p.Indices {
+		indices[i] = strconv.Itoa(index)
+	}
+
+	return p.Name + "[" + strings.Join(indices, ",") + "]"
+}
+
+// StructuredName is the syntax tree of a variable name with variableNamingConvention="structured"
+// (FMI 2.0 specification, section 2.2.9)
+type StructuredName struct {
+	Parts           []NamePart // the components of the identifier
+	DerivativeOrder int        // the order of the derivative for der(x) and der(x, n) or 0
+}
+
+// String returns the name in its canonical form
+func (n *StructuredName) String() string {
+
+	parts := make([]string, len(n.Parts))
+	for i, p := range n.Parts {
+		parts[i] = p.String()
+	}
+
+	identifier := strings.Join(parts, ".")
+
+	switch n.DerivativeOrder {
+	case 0:
+		return identifier
+	case 1:
+		return "der(" + identifier + ")"
+	default:
+		return fmt.Sprintf("der(%s,%d)", identifier, n.DerivativeOrder)
+	}
+}
+
+// NameSyntaxError describes the position in the name where parsing failed
+type NameSyntaxError struct {
+	Name     string
+	Position int
+	Message  string
+}
+
+func (e *NameSyntaxError) Error() string {
+	return fmt.Sprintf("invalid structured name %q at position %d: %s", e.Name, e.Position, e.Message)
+}
+
+// ParseStructuredName parses a variable name according to the grammar
+//
+//	name            = identifier | "der(" identifier ["," unsignedInteger ] ")"
+//	identifier      = B-name [ arrayIndices ] {"." B-name [ arrayIndices ] }
+//	arrayIndices    = "[" unsignedInteger {"," unsignedInteger} "]"
+//	B-name          = nondigit { digit | nondigit } | Q-name
+//	Q-name          = "'" ( Q-char | escape ) { Q-char | escape } "'"
+//
+// Spaces are allowed after the commas.
+func ParseStructuredName(name string) (*StructuredName, error) {
+
+	p := &nameParser{name: name}
+
+	result := &StructuredName{}
+
+	if strings.HasPrefix(name, "der(") {
+		p.pos = len("der(")
+		result.DerivativeOrder = 1
+	}
+
+	parts, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	result.Parts = parts
+
+	if result.DerivativeOrder > 0 {
+		if p.accept(',') {
+			p.skipSpaces()
+
+			order, err := p.unsignedInteger()
+			if err != nil {
+				return nil, err
+			}
+
+			if order < 1 {
+				return nil, p.errorf("the order of the derivative must be at least 1")
+			}
+
+			result.DerivativeOrder = order
+		}
+
+		if !p.accept(')') {
+			return nil, p.errorf("expected ')'")
+		}
+	}
+
+	if p.pos < len(p.name) {
+		return nil, p.errorf("unexpected character %q", p.name[p.pos])
+	}
+
+	return result, nil
+}
+
+// nameParser is a recursive descent parser for structured names
+type nameParser struct {
+	name string
+	pos  int
+}
+
+func (p *nameParser) errorf(format string, args ...any) error {
+	return &NameSyntaxError{Name: p.name, Position: p.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *nameParser) peek() byte {
+	if p.pos < len(p.name) {
+		return p.name[p.pos]
+	}
+	return 0
+}
+
+func (p *nameParser) accept(c byte) bool {
+	if p.peek() == c && p.pos < len(p.name) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *nameParser) skipSpaces() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNondigit(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isQChar returns whether c may appear unescaped in a quoted name
+func isQChar(c byte) bool {
+	return isDigit(c) || isNondigit(c) || strings.IndexByte("!#$%&()*+,-./:;<>=?@[]^{}|~ ", c) >= 0
+}
+
+func (p *nameParser) identifier() ([]NamePart, error) {
+
+	parts := make([]NamePart, 0, 1)
+
+	for {
+		name, err := p.bName()
+		if err != nil {
+			return nil, err
+		}
+
+		part := NamePart{Name: name}
+
+		if p.peek() == '[' {
+			if part.Indices, err = p.arrayIndices(); err != nil {
+				return nil, err
+			}
+		}
+
+		parts = append(parts, part)
+
+		if !p.accept('.') {
+			return parts, nil
+		}
+	}
+}
+
+func (p *nameParser) bName() (string, error) {
+
+	start := p.pos
+
+	switch c := p.peek(); {
+	case c == '\'':
+		return p.qName()
+	case isNondigit(c):
+		for isDigit(p.peek()) || isNondigit(p.peek()) {
+			p.pos++
+		}
+		return p.name[start:p.pos], nil
+	case p.pos >= len(p.name):
+		return "", p.errorf("unexpected end of name")
+	default:
+		return "", p.errorf("unexpected character %q", c)
+	}
+}
+
+func (p *nameParser) qName() (string, error) {
+
+	start := p.pos
+	p.pos++ // opening quote
+
+	for {
+		switch c := p.peek(); {
+		case p.pos >= len(p.name):
+			return "", p.errorf("unterminated quoted name")
+		case c == '\'':
+			if p.pos == start+1 {
+				return "", p.errorf("empty quoted name")
+			}
+			p.pos++
+			return p.name[start:p.pos], nil
+		case c == '\\':
+			p.pos++
+			if strings.IndexByte(`'"?\abfnrtv`, p.peek()) < 0 || p.pos >= len(p.name) {
+				return "", p.errorf("invalid escape sequence")
+			}
+			p.pos++
+		case isQChar(c):
+			p.pos++
+		default:
+			return "", p.errorf("character %q is not allowed in a quoted name", c)
+		}
+	}
+}
+
+func (p *nameParser) arrayIndices() ([]int, error) {
+
+	p.pos++ // '['
+
+	indices := make([]int, 0, 1)
+
+	for {
+		p.skipSpaces()
+
+		index, err := p.unsignedInteger()
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+
+		if p.accept(']') {
+			return indices, nil
+		}
+
+		if !p.accept(',') {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (p *nameParser) unsignedInteger() (int, error) {
+
+	start := p.pos
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return 0, p.errorf("expected an unsigned integer")
+	}
+
+	value, err := strconv.Atoi(p.name[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid unsigned integer")
+	}
+
+	return value, nil
+}
@@ -0,0 +1,72 @@
+package fmi2_test
+
+import (
+	"go-fmu/pkg/fmi2"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructuredName(t *testing.T) {
+
+	valid := map[string]*fmi2.StructuredName{
+		"x":                {Parts: []fmi2.NamePart{{Name: "x"}}},
+		"a.b[1,2].c":       {Parts: []fmi2.NamePart{{Name: "a"}, {Name: "b", Indices: []int{1, 2}}, {Name: "c"}}},
+		"der(x)":           {Parts: []fmi2.NamePart{{Name: "x"}}, DerivativeOrder: 1},
+		"der(a.x[3], 2)":   {Parts: []fmi2.NamePart{{Name: "a"}, {Name: "x", Indices: []int{3}}}, DerivativeOrder: 2},
+		"'my var'.y":       {Parts: []fmi2.NamePart{{Name: "'my var'"}, {Name: "y"}}},
+		`'a\'b'`:           {Parts: []fmi2.NamePart{{Name: `'a\'b'`}}},
+		"_x1[1, 2]":        {Parts: []fmi2.NamePart{{Name: "_x1", Indices: []int{1, 2}}}},
+		"'der(x)'.'[1]'.z": {Parts: []fmi2.NamePart{{Name: "'der(x)'"}, {Name: "'[1]'"}, {Name: "z"}}},
+	}
+
+	for name, expected := range valid {
+		parsed, err := fmi2.ParseStructuredName(name)
+		require.NoError(t, err, name)
+		require.Equal(t, expected, parsed, name)
+	}
+
+	parsed, err := fmi2.ParseStructuredName("der(a.x[1, 2], 2)")
+	require.NoError(t, err)
+	require.Equal(t, "der(a.x[1,2],2)", parsed.String())
+
+	invalid := []string{"", "1x", "a..b", "a.", "x[]", "x[1", "x[-1]", "der(x", "der(x, 0)", "der(x))", "'unterminated", "''", `'\x'`, "a b", "a.b.der(x)"}
+
+	for _, name := range invalid {
+		_, err := fmi2.ParseStructuredName(name)
+
+		var syntaxError *fmi2.NameSyntaxError
+		require.ErrorAs(t, err, &syntaxError, name)
+	}
+}
+
+func TestVariableTreeDrivetrain(t *testing.T) {
+
+	const filename = "../../examples/Drivetrain.fmu"
+
+	md, err := fmi2.ReadModelDescription(filename, &fmi2.ValidationOptions{Validate: true, ValidateVariableNames: true})
+	require.NoError(t, err)
+
+	root, err := md.VariableTree()
+	require.NoError(t, err)
+
+	inertia := root.Child("inertia1")
+	require.NotNil(t, inertia)
+	require.Nil(t, inertia.Variable)
+
+	derivative := inertia.Child("der(phi)")
+	require.NotNil(t, derivative)
+	require.Equal(t, "inertia1.der(phi)", derivative.Path)
+	require.Equal(t, "der(inertia1.phi)", derivative.Variable.Name)
+
+	require.Equal(t, derivative, root.Find("der(inertia1.phi)"))
+
+	count := 0
+	root.Walk(func(node *fmi2.VariableNode) bool {
+		if node.Variable != nil {
+			count++
+		}
+		return true
+	})
+	require.Equal(t, len(md.ModelVariables.ScalarVariable), count)
+}
@@ -1,9 +1,6 @@
 package fmi2
 
-import (
-	"runtime"
-	"strings"
-)
+import "go-fmu/pkg/internal/fmi"
 
 type Machine struct {
 	Architecture  string
@@ -13,42 +10,11 @@ type Machine struct {
 
 func CurrentMachine() Machine {
 
-	platform := strings.ToLower(runtime.GOOS)
-	machine := strings.ToLower(runtime.GOARCH)
-
-	intSize := 32 << (^uint(0) >> 63) // 32 or 64
-
-	architecture := ""
-
-	suffix := func() string {
-		switch platform {
-		case "windows":
-			return "dll"
-		case "darwin":
-			return "dylib"
-		default:
-			return "so"
-		}
-	}()
-
-	switch machine {
-	case "aarch64", "arm64":
-		platform += "64"
-		architecture = "aarch64"
-	case "amd64", "i386", "i686", "x86", "x86_64", "x86pc":
-		switch intSize {
-		case 32:
-			platform += "32"
-			architecture = "x86"
-		case 64:
-			platform += "64"
-			architecture = "x86_64"
-		}
-	}
+	machine := fmi.CurrentMachine()
 
 	return Machine{
-		Architecture:  architecture,
-		Platform:      platform,
-		LibrarySuffix: suffix,
+		Architecture:  machine.Architecture,
+		Platform:      machine.Platform(),
+		LibrarySuffix: machine.LibrarySuffix,
 	}
 }
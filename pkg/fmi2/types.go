@@ -5,7 +5,10 @@ package fmi2
 */
 import "C"
 
-import "unsafe"
+import (
+	"fmt"
+	"unsafe"
+)
 
 // Pointer to internal FMU state
 type FmuState struct {
@@ -22,27 +25,57 @@ type Status int
 
 const (
 	OK      Status = C.fmi2OK
-	Warning        = C.fmi2Warning
-	Discard        = C.fmi2Discard
-	Error          = C.fmi2Error
-	Fatal          = C.fmi2Fatal
-	Pending        = C.fmi2Pending
+	Warning Status = C.fmi2Warning
+	Discard Status = C.fmi2Discard
+	Error   Status = C.fmi2Error
+	Fatal   Status = C.fmi2Fatal
+	Pending Status = C.fmi2Pending
 )
 
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "Warning"
+	case Discard:
+		return "Discard"
+	case Error:
+		return "Error"
+	case Fatal:
+		return "Fatal"
+	case Pending:
+		return "Pending"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
 type Type int
 
 const (
 	ModelExchangeType Type = C.fmi2ModelExchange
-	CoSimulationType       = C.fmi2CoSimulation
+	CoSimulationType  Type = C.fmi2CoSimulation
 )
 
+func (t Type) String() string {
+	switch t {
+	case ModelExchangeType:
+		return "Model Exchange"
+	case CoSimulationType:
+		return "Co-Simulation"
+	default:
+		return "unknown interface type"
+	}
+}
+
 type StatusKind int
 
 const (
 	DoStepStatus       StatusKind = C.fmi2DoStepStatus
-	PendingStatus                 = C.fmi2PendingStatus
-	LastSuccessfulTime            = C.fmi2LastSuccessfulTime
-	Terminated                    = C.fmi2Terminated
+	PendingStatus      StatusKind = C.fmi2PendingStatus
+	LastSuccessfulTime StatusKind = C.fmi2LastSuccessfulTime
+	Terminated         StatusKind = C.fmi2Terminated
 )
 
 type EventInfo struct {
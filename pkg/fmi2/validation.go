@@ -0,0 +1,465 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// legal variabilities per causality (FMI 2.0 specification, section 2.2.7)
+var legalVariabilities = map[string][]string{
+	"parameter":           {"fixed", "tunable"},
+	"calculatedParameter": {"fixed", "tunable"},
+	"input":               {"discrete", "continuous"},
+	"output":              {"constant", "discrete", "continuous"},
+	"local":               {"constant", "fixed", "tunable", "discrete", "continuous"},
+	"independent":         {"continuous"},
+}
+
+// legalInitials returns the legal values of the initial attribute for the causality and variability
+// of the variable (FMI 2.0 specification, section 2.2.7). Inputs and the independent variable must not
+// define initial.
+func legalInitials(causality string, variability string) []string {
+	switch causality {
+	case "parameter":
+		return []string{"exact"}
+	case "calculatedParameter":
+		return []string{"calculated", "approx"}
+	case "input", "independent":
+		return nil
+	}
+
+	switch variability {
+	case "constant":
+		return []string{"exact"}
+	case "fixed", "tunable":
+		return []string{"calculated", "approx"}
+	default:
+		return []string{"calculated", "exact", "approx"}
+	}
+}
+
+// hasStart returns whether the variable defines a start value
+func hasStart(v *ScalarVariable) bool {
+	switch {
+	case v.Real != nil:
+		return v.Real.Start != nil
+	case v.Integer != nil:
+		return v.Integer.Start != nil
+	case v.Boolean != nil:
+		return v.Boolean.Start != nil
+	case v.String != nil:
+		return v.String.Start != nil
+	case v.Enumeration != nil:
+		return v.Enumeration.Start != nil
+	default:
+		return false
+	}
+}
+
+// checkRange returns an error if the value is outside [min, max]. Missing bounds are nil.
+func checkRange[T int | float64](element string, value *T, min *T, max *T) error {
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("%s: min (%v) is greater than max (%v)", element, *min, *max)
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	if min != nil && *value < *min {
+		return fmt.Errorf("%s: start (%v) is less than min (%v)", element, *value, *min)
+	}
+
+	if max != nil && *value > *max {
+		return fmt.Errorf("%s: start (%v) is greater than max (%v)", element, *value, *max)
+	}
+
+	return nil
+}
+
+// Validate checks the model description against the rules of the FMI 2.0 schema and the
+// constraints of the specification that can be checked without the model structure.
+// All problems are reported in a single error that wraps one error per problem.
+func (md *ModelDescription) Validate() error {
+
+	var errs []error
+
+	if md.ModelName == "" {
+		errs = append(errs, errors.New("attribute modelName is missing"))
+	}
+
+	if md.Guid == "" {
+		errs = append(errs, errors.New("attribute guid is missing"))
+	}
+
+	if md.ModelExchange == nil && md.CoSimulation == nil {
+		errs = append(errs, errors.New("the model description must contain ModelExchange or CoSimulation"))
+	}
+
+	if md.ModelExchange != nil && md.ModelExchange.ModelIdentifier == "" {
+		errs = append(errs, errors.New("attribute modelIdentifier of ModelExchange is missing"))
+	}
+
+	if md.CoSimulation != nil && md.CoSimulation.ModelIdentifier == "" {
+		errs = append(errs, errors.New("attribute modelIdentifier of CoSimulation is missing"))
+	}
+
+	units := make(map[string]*Unit)
+	for i := range md.UnitDefinitions {
+		for j := range md.UnitDefinitions[i].Unit {
+			unit := &md.UnitDefinitions[i].Unit[j]
+			if _, ok := units[unit.Name]; ok {
+				errs = append(errs, fmt.Errorf("unit %s is defined more than once", unit.Name))
+			}
+			units[unit.Name] = unit
+		}
+	}
+
+	types := make(map[string]*SimpleType)
+	for i := range md.TypeDefinitions {
+		for j := range md.TypeDefinitions[i].SimpleType {
+			simpleType := &md.TypeDefinitions[i].SimpleType[j]
+			if _, ok := types[simpleType.Name]; ok {
+				errs = append(errs, fmt.Errorf("type %s is defined more than once", simpleType.Name))
+			}
+			types[simpleType.Name] = simpleType
+
+			if simpleType.Real != nil {
+				errs = append(errs, checkUnit(units, "type "+simpleType.Name, &simpleType.Real.RealAttributes)...)
+
+				if err := checkRange("type "+simpleType.Name, nil, simpleType.Real.Min, simpleType.Real.Max); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if simpleType.Integer != nil {
+				if err := checkRange("type "+simpleType.Name, nil, simpleType.Integer.Min, simpleType.Integer.Max); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	if md.ModelVariables == nil {
+		return errors.Join(errs...)
+	}
+
+	variables := md.ModelVariables.ScalarVariable
+
+	names := make(map[string]bool)
+
+	// variables that share a value reference and type are aliases
+	type alias struct {
+		typ string
+		vr  ValueReference
+	}
+	aliases := make(map[alias][]*ScalarVariable)
+
+	for i := range variables {
+		v := &variables[i]
+
+		if v.Name == "" {
+			errs = append(errs, fmt.Errorf("variable %d has no name", i+1))
+		} else if names[v.Name] {
+			errs = append(errs, fmt.Errorf("variable %s is defined more than once", v.Name))
+		}
+		names[v.Name] = true
+
+		if _, ok := types[v.Name]; ok {
+			errs = append(errs, fmt.Errorf("variable %s has the same name as a type", v.Name))
+		}
+
+		errs = append(errs, md.validateVariable(v, types, units)...)
+
+		typ := variableType(v)
+		aliases[alias{typ, v.ValueReference}] = append(aliases[alias{typ, v.ValueReference}], v)
+	}
+
+	for key, set := range aliases {
+		if len(set) < 2 {
+			continue
+		}
+
+		// only one variable of an alias set may define a start value, unless all variables are constant
+		withStart := make([]string, 0)
+		for _, v := range set {
+			if variabilityOf(v) != "constant" && hasStart(v) {
+				withStart = append(withStart, v.Name)
+			}
+		}
+
+		if len(withStart) > 1 {
+			slices.Sort(withStart)
+			errs = append(errs, fmt.Errorf("the variables %v share the %s value reference %d, but more than one defines a start value", withStart, key.typ, key.vr))
+		}
+	}
+
+	if md.ModelStructure != nil {
+		errs = append(errs, md.validateStructureIndices()...)
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateVariable checks the attributes of a single variable
+func (md *ModelDescription) validateVariable(v *ScalarVariable, types map[string]*SimpleType, units map[string]*Unit) []error {
+
+	var errs []error
+
+	typ := variableType(v)
+
+	count := 0
+	for _, defined := range []bool{v.Real != nil, v.Integer != nil, v.Boolean != nil, v.String != nil, v.Enumeration != nil} {
+		if defined {
+			count++
+		}
+	}
+
+	if count != 1 {
+		return append(errs, fmt.Errorf("variable %s must have exactly one type element", v.Name))
+	}
+
+	causality := causalityOf(v)
+	variability := variabilityOf(v)
+
+	if legal, ok := legalVariabilities[causality]; !ok {
+		errs = append(errs, fmt.Errorf("variable %s has the illegal causality %q", v.Name, v.Causality))
+	} else if !slices.Contains(legal, variability) {
+		errs = append(errs, fmt.Errorf("variable %s has the illegal combination of causality %q and variability %q", v.Name, causality, variability))
+	}
+
+	if variability == "continuous" && typ != "Real" {
+		errs = append(errs, fmt.Errorf("variable %s of type %s cannot have variability \"continuous\"", v.Name, typ))
+	}
+
+	if v.Initial != "" {
+		if legal := legalInitials(causality, variability); !slices.Contains(legal, v.Initial) {
+			errs = append(errs, fmt.Errorf("variable %s with causality %q and variability %q cannot have initial %q", v.Name, causality, variability, v.Initial))
+		}
+	}
+
+	initial := initialOf(v)
+	switch {
+	case causality == "input" && !hasStart(v):
+		errs = append(errs, fmt.Errorf("input %s must have a start value", v.Name))
+	case (initial == "exact" || initial == "approx") && !hasStart(v):
+		errs = append(errs, fmt.Errorf("variable %s with initial %q must have a start value", v.Name, initial))
+	case (initial == "calculated" || causality == "independent") && hasStart(v):
+		errs = append(errs, fmt.Errorf("variable %s with initial %q must not have a start value", v.Name, initial))
+	}
+
+	// the type definition referenced by declaredType
+	var declaredType *SimpleType
+
+	declaredTypeName := ""
+	switch {
+	case v.Real != nil:
+		declaredTypeName = v.Real.DeclaredType
+	case v.Integer != nil:
+		declaredTypeName = v.Integer.DeclaredType
+	case v.Boolean != nil:
+		declaredTypeName = v.Boolean.DeclaredType
+	case v.String != nil:
+		declaredTypeName = v.String.DeclaredType
+	case v.Enumeration != nil:
+		declaredTypeName = v.Enumeration.DeclaredType
+		if declaredTypeName == "" {
+			errs = append(errs, fmt.Errorf("enumeration %s must have a declaredType", v.Name))
+		}
+	}
+
+	if declaredTypeName != "" {
+		declaredType = types[declaredTypeName]
+
+		if declaredType == nil {
+			errs = append(errs, fmt.Errorf("variable %s: declaredType %q is not defined", v.Name, declaredTypeName))
+		} else if (v.Real != nil && declaredType.Real == nil) ||
+			(v.Integer != nil && declaredType.Integer == nil) ||
+			(v.Boolean != nil && declaredType.Boolean == nil) ||
+			(v.String != nil && declaredType.String == nil) ||
+			(v.Enumeration != nil && declaredType.Enumeration == nil) {
+			errs = append(errs, fmt.Errorf("variable %s: declaredType %q is not of type %s", v.Name, declaredTypeName, typ))
+			declaredType = nil
+		}
+	}
+
+	switch {
+	case v.Real != nil:
+		// the attributes of the variable override the ones of the declared type
+		attributes := v.Real.RealAttributes
+		if declaredType != nil {
+			attributes.Min = cmpOr(attributes.Min, declaredType.Real.Min)
+			attributes.Max = cmpOr(attributes.Max, declaredType.Real.Max)
+			if attributes.Unit == "" {
+				attributes.Unit = declaredType.Real.Unit
+			}
+		}
+
+		errs = append(errs, checkUnit(units, "variable "+v.Name, &attributes)...)
+
+		min, max := attributes.Min, attributes.Max
+
+		if err := checkRange("variable "+v.Name, v.Real.Start, min, max); err != nil {
+			errs = append(errs, err)
+		}
+
+		if v.Real.Derivative != 0 {
+			variables := md.ModelVariables.ScalarVariable
+			if int(v.Real.Derivative) > len(variables) {
+				errs = append(errs, fmt.Errorf("variable %s: derivative index %d is out of range", v.Name, v.Real.Derivative))
+			} else if state := &variables[v.Real.Derivative-1]; state.Real == nil {
+				errs = append(errs, fmt.Errorf("variable %s: derivative index %d refers to %s, which is not a Real variable", v.Name, v.Real.Derivative, state.Name))
+			}
+		}
+
+	case v.Integer != nil:
+		min, max := v.Integer.Min, v.Integer.Max
+		if declaredType != nil {
+			min = cmpOr(min, declaredType.Integer.Min)
+			max = cmpOr(max, declaredType.Integer.Max)
+		}
+
+		if err := checkRange("variable "+v.Name, v.Integer.Start, min, max); err != nil {
+			errs = append(errs, err)
+		}
+
+	case v.Enumeration != nil:
+		if err := checkRange("variable "+v.Name, v.Enumeration.Start, v.Enumeration.Min, v.Enumeration.Max); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// checkUnit checks that the unit and the display unit of the attributes are defined in the UnitDefinitions
+func checkUnit(units map[string]*Unit, element string, attributes *RealAttributes) []error {
+
+	var errs []error
+
+	if attributes.Unit == "" {
+		if attributes.DisplayUnit != "" {
+			errs = append(errs, fmt.Errorf("%s: displayUnit %q requires a unit", element, attributes.DisplayUnit))
+		}
+		return errs
+	}
+
+	unit, ok := units[attributes.Unit]
+	if !ok {
+		return append(errs, fmt.Errorf("%s: unit %q is not defined", element, attributes.Unit))
+	}
+
+	if attributes.DisplayUnit != "" && !slices.ContainsFunc(unit.DisplayUnit, func(d DisplayUnit) bool { return d.Name == attributes.DisplayUnit }) {
+		errs = append(errs, fmt.Errorf("%s: displayUnit %q is not defined for unit %q", element, attributes.DisplayUnit, attributes.Unit))
+	}
+
+	return errs
+}
+
+// cmpOr returns a if it is not nil and b otherwise
+func cmpOr[T any](a *T, b *T) *T {
+	if a != nil {
+		return a
+	}
+	return b
+}
+
+// validateStructureIndices checks that the indices in the ModelStructure refer to variables
+func (md *ModelDescription) validateStructureIndices() []error {
+
+	var errs []error
+
+	n := 0
+	if md.ModelVariables != nil {
+		n = len(md.ModelVariables.ScalarVariable)
+	}
+
+	legalKinds := []string{"dependent", "constant", "fixed", "tunable", "discrete"}
+
+	check := func(element string, unknowns []Unknown) {
+		for _, u := range unknowns {
+			if u.Index < 1 || int(u.Index) > n {
+				errs = append(errs, fmt.Errorf("ModelStructure/%s: index %d is out of range", element, u.Index))
+				continue
+			}
+
+			name := md.ModelVariables.ScalarVariable[u.Index-1].Name
+
+			var dependencies IndexList
+			if u.Dependencies != nil {
+				dependencies = *u.Dependencies
+			}
+
+			for _, d := range dependencies {
+				if d < 1 || int(d) > n {
+					errs = append(errs, fmt.Errorf("ModelStructure/%s: dependency %d of variable %s is out of range", element, d, name))
+				}
+			}
+
+			if u.DependenciesKind != nil && len(u.DependenciesKind) != len(dependencies) {
+				errs = append(errs, fmt.Errorf("ModelStructure/%s: variable %s has %d dependencies but %d dependenciesKind", element, name, len(dependencies), len(u.DependenciesKind)))
+			}
+
+			for _, kind := range u.DependenciesKind {
+				if !slices.Contains(legalKinds, kind) {
+					errs = append(errs, fmt.Errorf("ModelStructure/%s: variable %s has the illegal dependenciesKind %q", element, name, kind))
+				}
+			}
+		}
+	}
+
+	check("Outputs", md.ModelStructure.Outputs)
+	check("Derivatives", md.ModelStructure.Derivatives)
+	check("InitialUnknowns", md.ModelStructure.InitialUnknowns)
+
+	return errs
+}
+
+// ValidateStructure checks that the ModelStructure is consistent with the model variables:
+// Outputs lists exactly the outputs, Derivatives refers to derivatives of continuous states
+// and InitialUnknowns lists no inputs or parameters.
+func (md *ModelDescription) ValidateStructure() error {
+
+	if md.ModelVariables == nil || md.ModelStructure == nil {
+		return nil
+	}
+
+	errs := md.validateStructureIndices()
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	variables := md.ModelVariables.ScalarVariable
+
+	outputs := make(map[uint32]bool)
+	for _, u := range md.ModelStructure.Outputs {
+		v := &variables[u.Index-1]
+		if v.Causality != "output" {
+			errs = append(errs, fmt.Errorf("ModelStructure/Outputs: variable %s is not an output", v.Name))
+		}
+		outputs[u.Index] = true
+	}
+
+	for i := range variables {
+		if variables[i].Causality == "output" && !outputs[uint32(i+1)] {
+			errs = append(errs, fmt.Errorf("output %s is missing in ModelStructure/Outputs", variables[i].Name))
+		}
+	}
+
+	for _, u := range md.ModelStructure.Derivatives {
+		v := &variables[u.Index-1]
+		if v.Real == nil || v.Real.Derivative == 0 {
+			errs = append(errs, fmt.Errorf("ModelStructure/Derivatives: variable %s is not a derivative", v.Name))
+		}
+	}
+
+	for _, u := range md.ModelStructure.InitialUnknowns {
+		v := &variables[u.Index-1]
+		if causality := causalityOf(v); causality == "input" || causality == "parameter" || causality == "independent" {
+			errs = append(errs, fmt.Errorf("ModelStructure/InitialUnknowns: variable %s with causality %q is not an unknown", v.Name, causality))
+		}
+	}
+
+	return errors.Join(errs...)
+}
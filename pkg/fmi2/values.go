@@ -0,0 +1,288 @@
+package fmi2
+
+import (
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"math"
+)
+
+// variable returns the model variable with the given name
+func (c *Component) variable(name string) (*ScalarVariable, error) {
+	v, ok := c.fmu.variables[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %s", name)
+	}
+
+	if variableType(v) == "" {
+		return nil, fmt.Errorf("the variable %s has no type", name)
+	}
+
+	return v, nil
+}
+
+// enumerationType returns the type definition of an Enumeration variable or nil
+func (md *ModelDescription) enumerationType(v *ScalarVariable) *EnumerationType {
+	if v.Enumeration == nil {
+		return nil
+	}
+
+	if t := md.SimpleType(v.Enumeration.DeclaredType); t != nil {
+		return t.Enumeration
+	}
+
+	return nil
+}
+
+// convertValue converts value to the Go type that is used to set the variable, which is float64 for Real,
+// int for Integer and Enumeration, bool for Boolean and string for String variables. Enumeration
+// variables can also be set to the name of an item. what describes the value in error messages.
+func convertValue(md *ModelDescription, v *ScalarVariable, value any, what string) (any, error) {
+
+	switch variableType(v) {
+	case "Real":
+		f, ok := fmi.ToFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("%s of %s must be a number but is %T", what, v.Name, value)
+		}
+		return f, nil
+	case "Integer", "Enumeration":
+		if name, ok := value.(string); ok && v.Enumeration != nil {
+			var item *Item
+			if t := md.enumerationType(v); t != nil {
+				item = t.ItemByName(name)
+			}
+			if item == nil {
+				return nil, fmt.Errorf("%s of %s must be an item of %s but is %q", what, v.Name, v.Enumeration.DeclaredType, name)
+			}
+			return item.Value, nil
+		}
+
+		f, ok := fmi.ToFloat64(value)
+		if !ok || f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+			return nil, fmt.Errorf("%s of %s must be an integer but is %v (%T)", what, v.Name, value, value)
+		}
+		return int(f), nil
+	case "Boolean":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s of %s must be a bool but is %T", what, v.Name, value)
+		}
+		return b, nil
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s of %s must be a string but is %T", what, v.Name, value)
+		}
+		return s, nil
+	}
+}
+
+// Get returns the value of the variable with the given name as float64 (Real), int (Integer and Enumeration),
+// bool (Boolean) or string (String)
+func (c *Component) Get(name string) (any, error) {
+	values, err := c.GetValues(name)
+	if err != nil {
+		return nil, err
+	}
+	return values[name], nil
+}
+
+// GetValues returns the values of the variables with the given names by name. The values are retrieved
+// with one call of GetReal, GetInteger, GetBoolean and GetString for all variables of the respective type.
+func (c *Component) GetValues(names ...string) (map[string]any, error) {
+
+	var (
+		realNames, integerNames, booleanNames, stringNames []string
+		realVRs, integerVRs, booleanVRs, stringVRs         []ValueReference
+	)
+
+	for _, name := range names {
+		v, err := c.variable(name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch variableType(v) {
+		case "Real":
+			realNames, realVRs = append(realNames, name), append(realVRs, v.ValueReference)
+		case "Integer", "Enumeration":
+			integerNames, integerVRs = append(integerNames, name), append(integerVRs, v.ValueReference)
+		case "Boolean":
+			booleanNames, booleanVRs = append(booleanNames, name), append(booleanVRs, v.ValueReference)
+		default:
+			stringNames, stringVRs = append(stringNames, name), append(stringVRs, v.ValueReference)
+		}
+	}
+
+	values := make(map[string]any, len(names))
+
+	if len(realVRs) > 0 {
+		reals, err := c.GetReal(realVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, realNames, reals)
+	}
+
+	if len(integerVRs) > 0 {
+		integers, err := c.GetInteger(integerVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, integerNames, integers)
+	}
+
+	if len(booleanVRs) > 0 {
+		booleans, err := c.GetBoolean(booleanVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, booleanNames, booleans)
+	}
+
+	if len(stringVRs) > 0 {
+		texts, err := c.GetString(stringVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, stringNames, texts)
+	}
+
+	return values, nil
+}
+
+// storeValues adds the values to the map by the names of their variables
+func storeValues[T any](values map[string]any, names []string, result []T) {
+	for i, name := range names {
+		values[name] = result[i]
+	}
+}
+
+// Set sets the values of the variables by name. The values are converted to the types of the variables,
+// so numbers of any Go type can be used for Real and Integer variables and Enumeration variables can be
+// set to the name of an item. The variables are set with one call of SetReal, SetInteger, SetBoolean
+// and SetString for all variables of the respective type.
+func (c *Component) Set(values map[string]any) error {
+
+	names := fmi.SortedNames(values)
+
+	variables := make([]*ScalarVariable, len(names))
+	for i, name := range names {
+		v, err := c.variable(name)
+		if err != nil {
+			return err
+		}
+		variables[i] = v
+	}
+
+	return setVariables(c, c.fmu.modelDescription, variables, Transform(names, func(i int, name string) any { return values[name] }), "the value")
+}
+
+// variableAccess gets and sets the values of the variables of an instance. It is implemented by
+// Component and by the adapter of FMI 1.0 instances, so the inputs, start values and outputs are
+// handled the same way for both versions.
+type variableAccess interface {
+	GetReal(vr []ValueReference) ([]float64, error)
+	GetInteger(vr []ValueReference) ([]int, error)
+	GetBoolean(vr []ValueReference) ([]bool, error)
+	GetString(vr []ValueReference) ([]string, error)
+	SetReal(vr []ValueReference, value []float64) error
+	SetInteger(vr []ValueReference, value []int) error
+	SetBoolean(vr []ValueReference, value []bool) error
+	SetString(vr []ValueReference, value []string) error
+	SetRealInputDerivatives(vr []ValueReference, order []int, value []float64) error
+}
+
+// setVariables converts the values to the types of the variables and sets them with one call per type
+func setVariables(access variableAccess, md *ModelDescription, variables []*ScalarVariable, values []any, what string) error {
+
+	var (
+		realVRs, integerVRs, booleanVRs, stringVRs []ValueReference
+		reals                                      []float64
+		integers                                   []int
+		booleans                                   []bool
+		texts                                      []string
+	)
+
+	for i, v := range variables {
+		value, err := convertValue(md, v, values[i], what)
+		if err != nil {
+			return err
+		}
+
+		switch value := value.(type) {
+		case float64:
+			realVRs, reals = append(realVRs, v.ValueReference), append(reals, value)
+		case int:
+			integerVRs, integers = append(integerVRs, v.ValueReference), append(integers, value)
+		case bool:
+			booleanVRs, booleans = append(booleanVRs, v.ValueReference), append(booleans, value)
+		case string:
+			stringVRs, texts = append(stringVRs, v.ValueReference), append(texts, value)
+		}
+	}
+
+	if len(realVRs) > 0 {
+		if err := access.SetReal(realVRs, reals); err != nil {
+			return err
+		}
+	}
+
+	if len(integerVRs) > 0 {
+		if err := access.SetInteger(integerVRs, integers); err != nil {
+			return err
+		}
+	}
+
+	if len(booleanVRs) > 0 {
+		if err := access.SetBoolean(booleanVRs, booleans); err != nil {
+			return err
+		}
+	}
+
+	if len(stringVRs) > 0 {
+		if err := access.SetString(stringVRs, texts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetValue returns the value of the variable with the given name as T. The value of an Enumeration
+// variable can be read as int or, with T = string, as the name of its item.
+func GetValue[T float64 | int | bool | string](c *Component, name string) (T, error) {
+
+	var zero T
+
+	value, err := c.Get(name)
+	if err != nil {
+		return zero, err
+	}
+
+	v := c.fmu.variables[name]
+
+	if _, ok := any(zero).(string); ok && v.Enumeration != nil {
+		var item *Item
+		if t := c.fmu.modelDescription.enumerationType(v); t != nil {
+			item = t.ItemByValue(value.(int))
+		}
+		if item == nil {
+			return zero, fmt.Errorf("the value %d of %s is not an item of %s", value, name, v.Enumeration.DeclaredType)
+		}
+		return any(item.Name).(T), nil
+	}
+
+	result, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("the variable %s of type %s cannot be read as %T", name, variableType(v), zero)
+	}
+
+	return result, nil
+}
+
+// SetValue sets the value of the variable with the given name. Enumeration variables can be set to the
+// name of an item.
+func SetValue[T float64 | int | bool | string](c *Component, name string, value T) error {
+	return c.Set(map[string]any{name: value})
+}
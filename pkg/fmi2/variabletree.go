@@ -0,0 +1,161 @@
+package fmi2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// VariableNode is a node of the hierarchical view of the model variables.
+// Inner nodes represent the components of structured names ("a" and "b" in "a.b.c") and the
+// elements of arrays ("[1]" in "x[1]"), leaves hold a variable. A node can hold a variable and children.
+type VariableNode struct {
+	Name     string          // the name of the node, e.g. "c", "[1,2]" or "der(x)"
+	Path     string          // the names of the nodes from the root to this node
+	Variable *ScalarVariable // the variable of the node or nil
+	Children []*VariableNode // the child nodes in the order of the model variables
+
+	children map[string]*VariableNode
+}
+
+// Child returns the child node with the given name or nil
+func (n *VariableNode) Child(name string) *VariableNode {
+	return n.children[name]
+}
+
+// Find returns the node of the variable with the given name or nil
+func (n *VariableNode) Find(name string) *VariableNode {
+	if n.Variable != nil && n.Variable.Name == name {
+		return n
+	}
+
+	for _, child := range n.Children {
+		if node := child.Find(name); node != nil {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// Walk calls f for the node and all its descendants in depth-first order until f returns false
+func (n *VariableNode) Walk(f func(node *VariableNode) bool) bool {
+	if !f(n) {
+		return false
+	}
+
+	for _, child := range n.Children {
+		if !child.Walk(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// child returns the child node with the given name and creates it if it does not exist
+func (n *VariableNode) child(name string) *VariableNode {
+	if node, ok := n.children[name]; ok {
+		return node
+	}
+
+	path := name
+	if n.Path != "" {
+		if strings.HasPrefix(name, "[") {
+			path = n.Path + name
+		} else {
+			path = n.Path + "." + name
+		}
+	}
+
+	node := &VariableNode{Name: name, Path: path, children: make(map[string]*VariableNode)}
+
+	n.Children = append(n.Children, node)
+	n.children[name] = node
+
+	return node
+}
+
+// VariableTree returns the model variables as a tree. If variableNamingConvention is "structured",
+// the names are split into their components and derivatives der(a.x) are placed next to their
+// state as "der(x)". Otherwise all variables are children of the root node.
+func (md *ModelDescription) VariableTree() (*VariableNode, error) {
+
+	root := &VariableNode{children: make(map[string]*VariableNode)}
+
+	if md.ModelVariables == nil {
+		return root, nil
+	}
+
+	structured := md.VariableNamingConvention == "structured"
+
+	for i := range md.ModelVariables.ScalarVariable {
+		v := &md.ModelVariables.ScalarVariable[i]
+
+		if !structured {
+			root.child(v.Name).Variable = v
+			continue
+		}
+
+		name, err := ParseStructuredName(v.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		node := root
+		last := len(name.Parts) - 1
+
+		for _, part := range name.Parts[:last] {
+			node = node.child(part.Name)
+			if part.Indices != nil {
+				node = node.child(NamePart{Indices: part.Indices}.String())
+			}
+		}
+
+		leaf := name.Parts[last]
+
+		switch {
+		case name.DerivativeOrder > 0:
+			derivative := &StructuredName{Parts: []NamePart{leaf}, DerivativeOrder: name.DerivativeOrder}
+			node = node.child(derivative.String())
+		case leaf.Indices != nil:
+			node = node.child(leaf.Name).child(NamePart{Indices: leaf.Indices}.String())
+		default:
+			node = node.child(leaf.Name)
+		}
+
+		node.Variable = v
+	}
+
+	return root, nil
+}
+
+// ValidateVariableNames checks that all variable names are non-empty and, if variableNamingConvention
+// is "structured", that they follow the grammar of structured names.
+func (md *ModelDescription) ValidateVariableNames() error {
+
+	if md.VariableNamingConvention != "" && md.VariableNamingConvention != "flat" && md.VariableNamingConvention != "structured" {
+		return fmt.Errorf("illegal variableNamingConvention %q", md.VariableNamingConvention)
+	}
+
+	if md.ModelVariables == nil {
+		return nil
+	}
+
+	var errs []error
+
+	for i, v := range md.ModelVariables.ScalarVariable {
+		if v.Name == "" {
+			errs = append(errs, fmt.Errorf("variable %d has no name", i+1))
+			continue
+		}
+
+		if md.VariableNamingConvention == "structured" {
+			if _, err := ParseStructuredName(v.Name); err != nil {
+				errs = append(errs, fmt.Errorf("variable %s: %w", v.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
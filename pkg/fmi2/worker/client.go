@@ -0,0 +1,802 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/internal/fmi"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ErrCrashed is returned by the calls that fail because the worker process terminated, e.g. due to a
+// segmentation fault in the FMU. The worker is restarted by the next call of Worker.New or Fmu.Instantiate,
+// but the instances of the crashed process cannot be used anymore.
+var ErrCrashed = errors.New("the worker process crashed")
+
+// ErrTimeout is returned by a call that did not return within the timeout. The worker process is killed.
+var ErrTimeout = errors.New("the call to the worker process timed out")
+
+// ErrClosed is returned by the calls after Worker.Close
+var ErrClosed = errors.New("the worker has been closed")
+
+type Option func(*Options)
+
+type Options struct {
+	command     []string
+	timeout     time.Duration
+	memoryLimit uint64
+	maxRestarts int
+	logger      fmi2.Logger
+}
+
+// WithCommand sets the command that starts the worker process instead of "go-fmu worker". The
+// command must call Main.
+func WithCommand(name string, args ...string) Option {
+	return func(o *Options) {
+		o.command = append([]string{name}, args...)
+	}
+}
+
+// WithTimeout sets the time after which a call is aborted and the worker process is killed
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMemoryLimit limits the resident set size of the worker process to the given number of bytes. The
+// memory usage is polled, and the worker is killed when it exceeds the limit, so the calls return ErrCrashed.
+// The limit requires the /proc file system of Linux.
+func WithMemoryLimit(bytes uint64) Option {
+	return func(o *Options) {
+		o.memoryLimit = bytes
+	}
+}
+
+// WithMaxRestarts sets the number of times the worker process is restarted after a crash or timeout.
+// By default the worker is restarted without limit.
+func WithMaxRestarts(n int) Option {
+	return func(o *Options) {
+		o.maxRestarts = n
+	}
+}
+
+// WithLogger sets the logger that receives the messages of the instances in the worker instead of stdout
+func WithLogger(logger fmi2.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// Worker starts and restarts the worker process that hosts the FMUs. The FMUs and instances of
+// different workers run in different processes and can be used in parallel.
+type Worker struct {
+	options  *Options
+	mutex    sync.Mutex
+	process  *process
+	restarts int
+	closed   bool
+}
+
+// process is a running worker process
+type process struct {
+	cmd         *exec.Cmd
+	client      *rpc.Client
+	exited      chan struct{} // closed when the process has exited
+	err         error         // the result of Wait after exited has been closed
+	dead        atomic.Bool   // the process crashed, timed out or was closed
+	memoryLimit uint64        // the limit of the resident set size in bytes or 0 for no limit
+	exceeded    atomic.Bool   // the process was killed because it exceeded the memory limit
+}
+
+// the interval in which the resident set size of a worker process with a memory limit is polled
+const memoryPollInterval = 10 * time.Millisecond
+
+/* Start starts a worker process.
+ *
+ * Parameters:
+ *   - opts: the options of the worker, e.g. WithTimeout or WithMemoryLimit
+ *
+ * Returns:
+ *   - *Worker: the worker
+ *   - error: an error if the worker process could not be started
+ */
+func Start(opts ...Option) (*Worker, error) {
+
+	options := &Options{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.command == nil {
+		path, err := exec.LookPath("go-fmu")
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the worker command: %w", err)
+		}
+		options.command = []string{path, "worker"}
+	}
+
+	if options.logger == nil {
+		options.logger = fmi.DefaultLogger[fmi2.Status]
+	}
+
+	w := &Worker{options: options}
+
+	if _, err := w.current(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Pid returns the process ID of the current worker process or 0 if it is not running
+func (w *Worker) Pid() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.process == nil || w.process.dead.Load() {
+		return 0
+	}
+	return w.process.cmd.Process.Pid
+}
+
+// Restarts returns the number of times the worker process has been restarted
+func (w *Worker) Restarts() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.restarts
+}
+
+// Close terminates the worker process, which frees the remaining instances and closes the FMUs
+func (w *Worker) Close() error {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	w.closed = true
+
+	if p := w.process; p != nil {
+		p.dead.Store(true)
+		p.client.Close()
+		select {
+		case <-p.exited:
+		case <-time.After(5 * time.Second):
+			p.cmd.Process.Kill()
+			<-p.exited
+		}
+	}
+
+	return nil
+}
+
+// current returns the running worker process and starts a new one if it crashed
+func (w *Worker) current() (*process, error) {
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil, ErrClosed
+	}
+
+	if w.process != nil && !w.process.dead.Load() {
+		return w.process, nil
+	}
+
+	if w.process != nil {
+		if w.options.maxRestarts > 0 && w.restarts >= w.options.maxRestarts {
+			return nil, fmt.Errorf("%w and has been restarted %d times", ErrCrashed, w.restarts)
+		}
+		w.restarts++
+	}
+
+	p, err := startProcess(w.options.command)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.options.memoryLimit > 0 {
+		if _, err := residentSetSize(p.cmd.Process.Pid); err != nil {
+			p.kill()
+			return nil, fmt.Errorf("failed to read the memory usage of the worker process: %w", err)
+		}
+		p.memoryLimit = w.options.memoryLimit
+		go p.watchMemory()
+	}
+
+	w.process = p
+
+	return p, nil
+}
+
+// startProcess starts the worker command and passes one end of a Unix socket pair as file descriptor 3
+func startProcess(command []string) (*process, error) {
+
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the socket of the worker: %w", err)
+	}
+
+	syscall.CloseOnExec(fds[0])
+
+	parent := os.NewFile(uintptr(fds[0]), "client")
+	child := os.NewFile(uintptr(fds[1]), "worker")
+	defer child.Close()
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.ExtraFiles = []*os.File{child}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		parent.Close()
+		return nil, fmt.Errorf("failed to start the worker process: %w", err)
+	}
+
+	conn, err := net.FileConn(parent)
+	parent.Close()
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+
+	p := &process{
+		cmd:    cmd,
+		client: rpc.NewClient(conn),
+		exited: make(chan struct{}),
+	}
+
+	go func() {
+		p.err = cmd.Wait()
+		p.client.Close()
+		close(p.exited)
+	}()
+
+	return p, nil
+}
+
+// kill kills the process and waits until it has exited
+func (p *process) kill() {
+	p.dead.Store(true)
+	p.cmd.Process.Kill()
+	<-p.exited
+}
+
+// watchMemory polls the resident set size of the process and kills it when it exceeds the memory limit
+func (p *process) watchMemory() {
+
+	ticker := time.NewTicker(memoryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.exited:
+			return
+		case <-ticker.C:
+			if rss, err := residentSetSize(p.cmd.Process.Pid); err == nil && rss > p.memoryLimit {
+				p.exceeded.Store(true)
+				p.dead.Store(true)
+				p.cmd.Process.Kill()
+				return
+			}
+		}
+	}
+}
+
+// residentSetSize returns the resident set size of a process in bytes from /proc/<pid>/status
+func residentSetSize(pid int) (uint64, error) {
+
+	status, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(status), "\n") {
+		if value, ok := strings.CutPrefix(line, "VmRSS:"); ok {
+			kilobytes, err := strconv.ParseUint(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "kB")), 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid VmRSS in /proc/%d/status: %w", pid, err)
+			}
+			return kilobytes * 1024, nil
+		}
+	}
+
+	return 0, fmt.Errorf("/proc/%d/status contains no VmRSS", pid)
+}
+
+// crashed marks the process as dead and returns an ErrCrashed that contains the exit status
+func (p *process) crashed(function string) error {
+
+	p.dead.Store(true)
+
+	select {
+	case <-p.exited:
+	case <-time.After(time.Second):
+		// the connection is broken but the process is still running
+		p.kill()
+	}
+
+	if p.exceeded.Load() {
+		return fmt.Errorf("%s: %w (the memory usage exceeded the limit of %d bytes)", function, ErrCrashed, p.memoryLimit)
+	}
+
+	return fmt.Errorf("%s: %w (%v)", function, ErrCrashed, p.err)
+}
+
+// call calls a method of the worker service in process p and waits for the result, the exit of
+// the process or the timeout. The messages in the response are passed to the logger.
+func (w *Worker) call(p *process, function string, method string, args any, reply *Response) error {
+
+	if p.dead.Load() {
+		return fmt.Errorf("%s: %w", function, ErrCrashed)
+	}
+
+	call := p.client.Go("Worker."+method, args, reply, make(chan *rpc.Call, 1))
+
+	var timeout <-chan time.Time
+	if w.options.timeout > 0 {
+		timer := time.NewTimer(w.options.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-call.Done:
+		if call.Error != nil {
+			if _, ok := call.Error.(rpc.ServerError); ok {
+				return call.Error
+			}
+			return p.crashed(function)
+		}
+	case <-p.exited:
+		return p.crashed(function)
+	case <-timeout:
+		p.kill()
+		return fmt.Errorf("%s: %w after %v", function, ErrTimeout, w.options.timeout)
+	}
+
+	for _, m := range reply.Messages {
+		w.options.logger(m.InstanceName, m.Status, m.Category, m.Message)
+	}
+
+	return nil
+}
+
+// Fmu is an FMU that is loaded in the worker process. It is loaded again when the worker process
+// has been restarted.
+type Fmu struct {
+	worker           *Worker
+	filename         string
+	fmiType          fmi2.Type
+	modelDescription *fmi2.ModelDescription
+
+	mutex            sync.Mutex
+	process          *process // the process in which the FMU has been loaded
+	id               int
+	resourceLocation string
+}
+
+/* New loads an FMU in the worker process.
+ *
+ * Parameters:
+ *   - filename: the path to the FMU, which must be accessible by the worker process
+ *   - fmiType: the interface type to load
+ *
+ * Returns:
+ *   - *Fmu: the FMU
+ *   - error: an error if the FMU could not be loaded
+ */
+func (w *Worker) New(filename string, fmiType fmi2.Type) (*Fmu, error) {
+
+	md, err := fmi2.ReadModelDescription(filename, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Fmu{worker: w, filename: filename, fmiType: fmiType, modelDescription: md}
+
+	if _, _, err := f.load(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// load loads the FMU in the current worker process unless it has already been loaded there
+func (f *Fmu) load() (*process, int, error) {
+
+	p, err := f.worker.current()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if p == f.process {
+		return p, f.id, nil
+	}
+
+	var reply Response
+	if err := f.worker.call(p, "Load", "Load", LoadArgs{Filename: f.filename, Type: f.fmiType}, &reply); err != nil {
+		return nil, 0, err
+	}
+
+	if reply.Error != nil {
+		return nil, 0, reply.Error.err()
+	}
+
+	f.process, f.id, f.resourceLocation = p, reply.Id, reply.Strings[0]
+
+	return p, f.id, nil
+}
+
+// ModelDescription returns the model description of the FMU
+func (f *Fmu) ModelDescription() *fmi2.ModelDescription {
+	return f.modelDescription
+}
+
+// Instantiate creates an instance in the worker process like fmi2.Fmu2.Instantiate. If resourceLocation
+// is empty the resources of the FMU that was extracted by the worker are used.
+func (f *Fmu) Instantiate(instanceName string, fmuType fmi2.Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool) (*Component, error) {
+
+	p, id, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	args := InstantiateArgs{
+		Fmu:              id,
+		InstanceName:     instanceName,
+		Type:             fmuType,
+		Guid:             fmuGuid,
+		ResourceLocation: resourceLocation,
+		Visible:          visible,
+		LoggingOn:        loggingOn,
+	}
+
+	var reply Response
+	if err := f.worker.call(p, "fmi2Instantiate", "Instantiate", args, &reply); err != nil {
+		return nil, err
+	}
+
+	if reply.Error != nil {
+		return nil, reply.Error.err()
+	}
+
+	return &Component{worker: f.worker, process: p, id: reply.Id, state: reply.State}, nil
+}
+
+// Close unloads the FMU in the worker process. All instances must have been freed.
+func (f *Fmu) Close() error {
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	p := f.process
+	f.process = nil
+
+	if p == nil || p.dead.Load() {
+		return nil
+	}
+
+	var reply Response
+	if err := f.worker.call(p, "Unload", "Unload", f.id, &reply); err != nil {
+		return err
+	}
+
+	return reply.Error.err()
+}
+
+// Component is an instance in the worker process. It has the same methods as fmi2.Component except for
+// the functions for the FMU state and asynchronous steps. All methods return ErrCrashed after the worker
+// process has crashed or has been killed.
+type Component struct {
+	worker  *Worker
+	process *process
+	id      int
+	state   fmi2.State // the state of the instance after the last call
+}
+
+// call calls a method of the instance in the worker process
+func (c *Component) call(req Request) (*Response, error) {
+
+	req.Instance = c.id
+
+	var reply Response
+	if err := c.worker.call(c.process, req.Function, "Call", req, &reply); err != nil {
+		return nil, err
+	}
+
+	c.state = reply.State
+
+	if reply.Error != nil {
+		return &reply, reply.Error.err()
+	}
+
+	return &reply, nil
+}
+
+// State returns the state of the instance after the last call
+func (c *Component) State() fmi2.State {
+	return c.state
+}
+
+func (c *Component) SetDebugLogging(loggingOn bool, categories []string) error {
+	_, err := c.call(Request{Function: "SetDebugLogging", Flag: loggingOn, Categories: categories})
+	return err
+}
+
+func (c *Component) SetupExperiment(tStart float64, opts ...fmi2.SetupExperimentOption) error {
+
+	options := &fmi2.SetupExperimentOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	req := Request{Function: "SetupExperiment", Time: tStart}
+
+	if tolerance, ok := options.RelativeTolerance(); ok {
+		req.Tolerance = &tolerance
+	}
+
+	if stopTime, ok := options.StopTime(); ok {
+		req.StopTime = &stopTime
+	}
+
+	_, err := c.call(req)
+	return err
+}
+
+func (c *Component) EnterInitializationMode() error {
+	_, err := c.call(Request{Function: "EnterInitializationMode"})
+	return err
+}
+
+func (c *Component) ExitInitializationMode() error {
+	_, err := c.call(Request{Function: "ExitInitializationMode"})
+	return err
+}
+
+func (c *Component) Terminate() error {
+	_, err := c.call(Request{Function: "Terminate"})
+	return err
+}
+
+func (c *Component) Reset() error {
+	_, err := c.call(Request{Function: "Reset"})
+	return err
+}
+
+// FreeInstance frees the instance in the worker process. The errors of a crashed worker are ignored.
+func (c *Component) FreeInstance() {
+	c.call(Request{Function: "FreeInstance"})
+}
+
+func (c *Component) GetReal(vr []fmi2.ValueReference) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetReal", VR: vr})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) GetInteger(vr []fmi2.ValueReference) ([]int, error) {
+	reply, err := c.call(Request{Function: "GetInteger", VR: vr})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Integers, nil
+}
+
+func (c *Component) GetBoolean(vr []fmi2.ValueReference) ([]bool, error) {
+	reply, err := c.call(Request{Function: "GetBoolean", VR: vr})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Booleans, nil
+}
+
+func (c *Component) GetString(vr []fmi2.ValueReference) ([]string, error) {
+	reply, err := c.call(Request{Function: "GetString", VR: vr})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Strings, nil
+}
+
+func (c *Component) SetReal(vr []fmi2.ValueReference, value []float64) error {
+	_, err := c.call(Request{Function: "SetReal", VR: vr, Reals: value})
+	return err
+}
+
+func (c *Component) SetInteger(vr []fmi2.ValueReference, value []int) error {
+	_, err := c.call(Request{Function: "SetInteger", VR: vr, Integers: value})
+	return err
+}
+
+func (c *Component) SetBoolean(vr []fmi2.ValueReference, value []bool) error {
+	_, err := c.call(Request{Function: "SetBoolean", VR: vr, Booleans: value})
+	return err
+}
+
+func (c *Component) SetString(vr []fmi2.ValueReference, value []string) error {
+	_, err := c.call(Request{Function: "SetString", VR: vr, Strings: value})
+	return err
+}
+
+// Get returns the value of a variable by name like fmi2.Component.Get
+func (c *Component) Get(name string) (any, error) {
+	reply, err := c.call(Request{Function: "GetValues", Names: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Values[name], nil
+}
+
+// GetValues returns the values of variables by name like fmi2.Component.GetValues
+func (c *Component) GetValues(names ...string) (map[string]any, error) {
+	reply, err := c.call(Request{Function: "GetValues", Names: names})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Values, nil
+}
+
+// Set sets the values of variables by name like fmi2.Component.Set
+func (c *Component) Set(values map[string]any) error {
+	_, err := c.call(Request{Function: "Set", Values: values})
+	return err
+}
+
+func (c *Component) GetEventIndicators(ni int) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetEventIndicators", Count: ni})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) GetDerivatives(nx int) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetDerivatives", Count: nx})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) SetTime(time float64) error {
+	_, err := c.call(Request{Function: "SetTime", Time: time})
+	return err
+}
+
+func (c *Component) EnterContinuousTimeMode() error {
+	_, err := c.call(Request{Function: "EnterContinuousTimeMode"})
+	return err
+}
+
+func (c *Component) EnterEventMode() error {
+	_, err := c.call(Request{Function: "EnterEventMode"})
+	return err
+}
+
+func (c *Component) NewDiscreteStates() (*fmi2.EventInfo, error) {
+	reply, err := c.call(Request{Function: "NewDiscreteStates"})
+	if err != nil {
+		return nil, err
+	}
+	return reply.EventInfo, nil
+}
+
+func (c *Component) GetContinuousStates(nx int) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetContinuousStates", Count: nx})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) SetContinuousStates(x []float64) error {
+	_, err := c.call(Request{Function: "SetContinuousStates", Reals: x})
+	return err
+}
+
+func (c *Component) GetNominalsOfContinuousStates(nx int) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetNominalsOfContinuousStates", Count: nx})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) CompletedIntegratorStep(noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error) {
+	reply, err := c.call(Request{Function: "CompletedIntegratorStep", Flag: noSetFMUStatePriorToCurrentPoint})
+	if err != nil {
+		return false, false, err
+	}
+	return reply.Flags[0], reply.Flags[1], nil
+}
+
+func (c *Component) GetDirectionalDerivative(zRef []fmi2.ValueReference, vRef []fmi2.ValueReference, dv []float64) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetDirectionalDerivative", VR: zRef, KnownVR: vRef, Reals: dv})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) DoStep(currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) error {
+	_, err := c.call(Request{Function: "DoStep", Time: currentCommunicationPoint, StepSize: communicationStepSize, Flag: noSetFMUStatePriorToCurrentPoint})
+	return err
+}
+
+func (c *Component) CancelStep() error {
+	_, err := c.call(Request{Function: "CancelStep"})
+	return err
+}
+
+func (c *Component) GetStatus(s fmi2.StatusKind) (fmi2.Status, error) {
+	reply, err := c.call(Request{Function: "GetStatus", Kind: s})
+	if err != nil {
+		return fmi2.Error, err
+	}
+	return reply.Status, nil
+}
+
+func (c *Component) GetRealStatus(s fmi2.StatusKind) (float64, error) {
+	reply, err := c.call(Request{Function: "GetRealStatus", Kind: s})
+	if err != nil {
+		return 0, err
+	}
+	return reply.Reals[0], nil
+}
+
+func (c *Component) GetIntegerStatus(s fmi2.StatusKind) (int, error) {
+	reply, err := c.call(Request{Function: "GetIntegerStatus", Kind: s})
+	if err != nil {
+		return 0, err
+	}
+	return reply.Integers[0], nil
+}
+
+func (c *Component) GetBooleanStatus(s fmi2.StatusKind) (bool, error) {
+	reply, err := c.call(Request{Function: "GetBooleanStatus", Kind: s})
+	if err != nil {
+		return false, err
+	}
+	return reply.Booleans[0], nil
+}
+
+func (c *Component) GetStringStatus(s fmi2.StatusKind) (string, error) {
+	reply, err := c.call(Request{Function: "GetStringStatus", Kind: s})
+	if err != nil {
+		return "", err
+	}
+	return reply.Strings[0], nil
+}
+
+func (c *Component) GetRealOutputDerivatives(vr []fmi2.ValueReference, order []int) ([]float64, error) {
+	reply, err := c.call(Request{Function: "GetRealOutputDerivatives", VR: vr, Order: order})
+	if err != nil {
+		return nil, err
+	}
+	return reply.Reals, nil
+}
+
+func (c *Component) SetRealInputDerivatives(vr []fmi2.ValueReference, order []int, value []float64) error {
+	_, err := c.call(Request{Function: "SetRealInputDerivatives", VR: vr, Order: order, Reals: value})
+	return err
+}
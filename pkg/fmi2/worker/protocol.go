@@ -0,0 +1,140 @@
+// Package worker runs FMI 2.0 FMUs in a separate process. A crash of the C code of an FMU only
+// terminates the worker process, which is restarted by the client, and FMUs with the capability
+// canBeInstantiatedOnlyOncePerProcess can be instantiated in several workers in parallel.
+//
+// The worker process is started with the command "go-fmu worker" (see Main) and communicates with
+// the client over a Unix socket that is passed as file descriptor 3.
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"strings"
+)
+
+// LoadArgs are the arguments of Worker.Load
+type LoadArgs struct {
+	Filename string
+	Type     fmi2.Type
+}
+
+// InstantiateArgs are the arguments of Worker.Instantiate
+type InstantiateArgs struct {
+	Fmu              int // the id of the FMU that was returned by Worker.Load
+	InstanceName     string
+	Type             fmi2.Type
+	Guid             string
+	ResourceLocation string
+	Visible          bool
+	LoggingOn        bool
+}
+
+// Request is a call of a Component method. Function is the name of the method and only the
+// fields that are arguments of the method are set.
+type Request struct {
+	Instance   int
+	Function   string
+	VR         []fmi2.ValueReference
+	KnownVR    []fmi2.ValueReference // the knowns of GetDirectionalDerivative
+	Reals      []float64
+	Integers   []int
+	Booleans   []bool
+	Strings    []string
+	Order      []int
+	Time       float64
+	StepSize   float64
+	Flag       bool
+	Count      int
+	Kind       fmi2.StatusKind
+	Categories []string
+	Names      []string
+	Values     map[string]any
+	Tolerance  *float64
+	StopTime   *float64
+}
+
+// LogMessage is a message that an instance passed to the logger of the worker
+type LogMessage struct {
+	InstanceName string
+	Status       fmi2.Status
+	Category     string
+	Message      string
+}
+
+// Response is the result of a call. The messages that were logged during the call are returned
+// with the response and passed to the logger of the client.
+type Response struct {
+	Id        int // the id of a loaded FMU or a new instance
+	Reals     []float64
+	Integers  []int
+	Booleans  []bool
+	Strings   []string
+	Flags     [2]bool
+	Status    fmi2.Status
+	EventInfo *fmi2.EventInfo
+	Values    map[string]any
+	State     fmi2.State
+	Messages  []LogMessage
+	Error     *RemoteError
+}
+
+// RemoteError is an error that was returned by the FMU in the worker. It is converted back to the
+// error type of the fmi2 package, so errors.Is and errors.As work as for FMUs in the same process.
+type RemoteError struct {
+	Kind     string // "status", "unavailable", "illegal", "fatal" or "other"
+	Function string
+	Status   fmi2.Status
+	Type     fmi2.Type
+	State    fmi2.State
+	Allowed  fmi2.State
+	Message  string
+}
+
+// remoteError converts an error of the fmi2 package for the response
+func remoteError(err error) *RemoteError {
+
+	if err == nil {
+		return nil
+	}
+
+	var (
+		statusError      *fmi2.StatusError
+		unavailableError *fmi2.FunctionNotAvailableError
+		illegalCallError *fmi2.IllegalCallError
+	)
+
+	switch {
+	case errors.As(err, &statusError):
+		return &RemoteError{Kind: "status", Function: statusError.Function, Status: statusError.Status}
+	case errors.As(err, &unavailableError):
+		return &RemoteError{Kind: "unavailable", Function: unavailableError.Function}
+	case errors.As(err, &illegalCallError):
+		return &RemoteError{Kind: "illegal", Function: illegalCallError.Function, Type: illegalCallError.Type, State: illegalCallError.State, Allowed: illegalCallError.Allowed}
+	case errors.Is(err, fmi2.ErrFatal):
+		return &RemoteError{Kind: "fatal", Message: strings.TrimSuffix(err.Error(), ": "+fmi2.ErrFatal.Error())}
+	default:
+		return &RemoteError{Kind: "other", Message: err.Error()}
+	}
+}
+
+// err converts the remote error back to an error of the fmi2 package
+func (e *RemoteError) err() error {
+
+	if e == nil {
+		return nil
+	}
+
+	switch e.Kind {
+	case "status":
+		return &fmi2.StatusError{Function: e.Function, Status: e.Status}
+	case "unavailable":
+		return &fmi2.FunctionNotAvailableError{Function: e.Function}
+	case "illegal":
+		return &fmi2.IllegalCallError{Function: e.Function, Type: e.Type, State: e.State, Allowed: e.Allowed}
+	case "fatal":
+		return fmt.Errorf("%s: %w", e.Message, fmi2.ErrFatal)
+	default:
+		return errors.New(e.Message)
+	}
+}
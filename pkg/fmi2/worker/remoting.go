@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"slices"
+)
+
+// Remoting is an fmi2.RemotingBackend that loads every FMU in its own worker process. The worker command
+// must be built for the platform, e.g. a go-fmu that was built with GOARCH=386 for linux32:
+//
+//	fmi2.RegisterRemotingBackend(worker.NewRemoting("linux32", worker.WithCommand("/opt/go-fmu-386", "worker")))
+type Remoting struct {
+	platform string
+	options  []Option
+}
+
+// NewRemoting returns a remoting backend for the binaries of platform. The options are passed to Start.
+func NewRemoting(platform string, opts ...Option) *Remoting {
+	return &Remoting{platform: platform, options: opts}
+}
+
+func (r *Remoting) Platforms() []string {
+	return []string{r.platform}
+}
+
+// Load starts a worker process and loads the FMU in it
+func (r *Remoting) Load(filename string, platform string, fmiType fmi2.Type, logger fmi2.Logger) (fmi2.RemoteFmu, error) {
+
+	if platform != r.platform {
+		return nil, fmt.Errorf("the remoting backend for %s cannot load the binaries for %s", r.platform, platform)
+	}
+
+	opts := r.options
+	if logger != nil {
+		opts = append(slices.Clip(opts), WithLogger(logger))
+	}
+
+	w, err := Start(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	fmu, err := w.New(filename, fmiType)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	return &remoteFmu{worker: w, fmu: fmu}, nil
+}
+
+// remoteFmu is an FMU in the worker process that was started for it
+type remoteFmu struct {
+	worker *Worker
+	fmu    *Fmu
+}
+
+func (f *remoteFmu) Instantiate(instanceName string, fmuType fmi2.Type, fmuGuid string, resourceLocation string, visible bool, loggingOn bool) (fmi2.Instance, error) {
+
+	c, err := f.fmu.Instantiate(instanceName, fmuType, fmuGuid, resourceLocation, visible, loggingOn)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close unloads the FMU and terminates the worker process
+func (f *remoteFmu) Close() error {
+	err := f.fmu.Close()
+	f.worker.Close()
+	return err
+}
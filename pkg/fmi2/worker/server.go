@@ -0,0 +1,303 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"sync"
+)
+
+// server is the RPC service of the worker process, which holds the loaded FMUs and their instances by id
+type server struct {
+	mutex     sync.Mutex
+	fmus      map[int]*fmi2.Fmu2
+	instances map[int]*instance
+	nextId    int
+}
+
+// instance is an instance in the worker and the messages it logged since the last response of its calls
+type instance struct {
+	component *fmi2.Component
+	messages  *messageBuffer
+}
+
+// messageBuffer holds the messages of an instance until they are returned with the response of a call of
+// the instance, so the messages of concurrent calls are returned to the caller of the instance that logged them
+type messageBuffer struct {
+	mutex    sync.Mutex
+	messages []LogMessage
+}
+
+// log is the logger of the instance
+func (b *messageBuffer) log(instanceName string, status fmi2.Status, category string, message string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.messages = append(b.messages, LogMessage{InstanceName: instanceName, Status: status, Category: category, Message: message})
+}
+
+func (b *messageBuffer) take() []LogMessage {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	messages := b.messages
+	b.messages = nil
+	return messages
+}
+
+func newServer() *server {
+	return &server{
+		fmus:      make(map[int]*fmi2.Fmu2),
+		instances: make(map[int]*instance),
+	}
+}
+
+func (s *server) add(fmu *fmi2.Fmu2, instance *instance) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextId++
+	if fmu != nil {
+		s.fmus[s.nextId] = fmu
+	} else {
+		s.instances[s.nextId] = instance
+	}
+	return s.nextId
+}
+
+// Load loads an FMU and returns its id and resource location
+func (s *server) Load(args LoadArgs, reply *Response) error {
+
+	fmu, err := fmi2.New(args.Filename, args.Type)
+	if err != nil {
+		reply.Error = remoteError(err)
+		return nil
+	}
+
+	reply.Id = s.add(fmu, nil)
+	reply.Strings = []string{fmu.ResourceLocation()}
+
+	return nil
+}
+
+// Unload closes an FMU, whose instances must have been freed
+func (s *server) Unload(id int, reply *Response) error {
+
+	s.mutex.Lock()
+	fmu := s.fmus[id]
+	delete(s.fmus, id)
+	s.mutex.Unlock()
+
+	if fmu != nil {
+		reply.Error = remoteError(fmu.Close())
+	}
+
+	return nil
+}
+
+// Instantiate creates an instance of a loaded FMU and returns its id
+func (s *server) Instantiate(args InstantiateArgs, reply *Response) error {
+
+	s.mutex.Lock()
+	fmu := s.fmus[args.Fmu]
+	s.mutex.Unlock()
+
+	if fmu == nil {
+		reply.Error = remoteError(fmt.Errorf("unknown FMU %d", args.Fmu))
+		return nil
+	}
+
+	resourceLocation := args.ResourceLocation
+	if resourceLocation == "" {
+		resourceLocation = fmu.ResourceLocation()
+	}
+
+	messages := &messageBuffer{}
+
+	component := fmu.Instantiate(args.InstanceName, args.Type, args.Guid, resourceLocation, args.Visible, args.LoggingOn, fmi2.WithLogger(messages.log))
+
+	reply.Messages = messages.take()
+
+	if component == nil {
+		reply.Error = remoteError(errors.New("failed to instantiate the FMU"))
+		return nil
+	}
+
+	reply.Id = s.add(nil, &instance{component: component, messages: messages})
+	reply.State = component.State()
+
+	return nil
+}
+
+// Call calls a method of an instance
+func (s *server) Call(req Request, reply *Response) error {
+
+	s.mutex.Lock()
+	i := s.instances[req.Instance]
+	s.mutex.Unlock()
+
+	if i == nil {
+		reply.Error = remoteError(fmt.Errorf("unknown instance %d", req.Instance))
+		return nil
+	}
+
+	c := i.component
+
+	var err error
+
+	switch req.Function {
+	case "SetDebugLogging":
+		err = c.SetDebugLogging(req.Flag, req.Categories)
+	case "SetupExperiment":
+		var opts []fmi2.SetupExperimentOption
+		if req.Tolerance != nil {
+			opts = append(opts, fmi2.WithRelativeTolerance(*req.Tolerance))
+		}
+		if req.StopTime != nil {
+			opts = append(opts, fmi2.WithStopTime(*req.StopTime))
+		}
+		err = c.SetupExperiment(req.Time, opts...)
+	case "EnterInitializationMode":
+		err = c.EnterInitializationMode()
+	case "ExitInitializationMode":
+		err = c.ExitInitializationMode()
+	case "Terminate":
+		err = c.Terminate()
+	case "Reset":
+		err = c.Reset()
+	case "FreeInstance":
+		c.FreeInstance()
+		s.mutex.Lock()
+		delete(s.instances, req.Instance)
+		s.mutex.Unlock()
+	case "GetReal":
+		reply.Reals, err = c.GetReal(req.VR)
+	case "GetInteger":
+		reply.Integers, err = c.GetInteger(req.VR)
+	case "GetBoolean":
+		reply.Booleans, err = c.GetBoolean(req.VR)
+	case "GetString":
+		reply.Strings, err = c.GetString(req.VR)
+	case "SetReal":
+		err = c.SetReal(req.VR, req.Reals)
+	case "SetInteger":
+		err = c.SetInteger(req.VR, req.Integers)
+	case "SetBoolean":
+		err = c.SetBoolean(req.VR, req.Booleans)
+	case "SetString":
+		err = c.SetString(req.VR, req.Strings)
+	case "GetValues":
+		reply.Values, err = c.GetValues(req.Names...)
+	case "Set":
+		err = c.Set(req.Values)
+	case "GetEventIndicators":
+		reply.Reals, err = c.GetEventIndicators(req.Count)
+	case "GetDerivatives":
+		reply.Reals, err = c.GetDerivatives(req.Count)
+	case "SetTime":
+		err = c.SetTime(req.Time)
+	case "EnterContinuousTimeMode":
+		err = c.EnterContinuousTimeMode()
+	case "EnterEventMode":
+		err = c.EnterEventMode()
+	case "NewDiscreteStates":
+		reply.EventInfo, err = c.NewDiscreteStates()
+	case "GetContinuousStates":
+		reply.Reals, err = c.GetContinuousStates(req.Count)
+	case "SetContinuousStates":
+		err = c.SetContinuousStates(req.Reals)
+	case "GetNominalsOfContinuousStates":
+		reply.Reals, err = c.GetNominalsOfContinuousStates(req.Count)
+	case "CompletedIntegratorStep":
+		reply.Flags[0], reply.Flags[1], err = c.CompletedIntegratorStep(req.Flag)
+	case "GetDirectionalDerivative":
+		reply.Reals, err = c.GetDirectionalDerivative(req.VR, req.KnownVR, req.Reals)
+	case "DoStep":
+		err = c.DoStep(req.Time, req.StepSize, req.Flag)
+	case "CancelStep":
+		err = c.CancelStep()
+	case "GetStatus":
+		reply.Status, err = c.GetStatus(req.Kind)
+	case "GetRealStatus":
+		var value float64
+		value, err = c.GetRealStatus(req.Kind)
+		reply.Reals = []float64{value}
+	case "GetIntegerStatus":
+		var value int
+		value, err = c.GetIntegerStatus(req.Kind)
+		reply.Integers = []int{value}
+	case "GetBooleanStatus":
+		var value bool
+		value, err = c.GetBooleanStatus(req.Kind)
+		reply.Booleans = []bool{value}
+	case "GetStringStatus":
+		var value string
+		value, err = c.GetStringStatus(req.Kind)
+		reply.Strings = []string{value}
+	case "GetRealOutputDerivatives":
+		reply.Reals, err = c.GetRealOutputDerivatives(req.VR, req.Order)
+	case "SetRealInputDerivatives":
+		err = c.SetRealInputDerivatives(req.VR, req.Order, req.Reals)
+	default:
+		err = fmt.Errorf("unknown function %s", req.Function)
+	}
+
+	reply.State = c.State()
+	reply.Messages = i.messages.take()
+	reply.Error = remoteError(err)
+
+	return nil
+}
+
+// close frees the remaining instances and closes the FMUs, which removes the extracted files
+func (s *server) close() {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, i := range s.instances {
+		i.component.FreeInstance()
+		delete(s.instances, id)
+	}
+
+	for id, fmu := range s.fmus {
+		fmu.Close()
+		delete(s.fmus, id)
+	}
+}
+
+// Serve serves the worker service on conn until the client closes the connection
+func Serve(conn io.ReadWriteCloser) error {
+
+	s := newServer()
+	defer s.close()
+
+	service := rpc.NewServer()
+	if err := service.RegisterName("Worker", s); err != nil {
+		return err
+	}
+
+	service.ServeConn(conn)
+
+	return nil
+}
+
+// Main serves the client that started the worker process on the socket that was passed as file
+// descriptor 3 and returns when the client closes the connection. It is the entry point of the
+// command "go-fmu worker", but can be called by any executable that is passed to WithCommand.
+func Main() error {
+
+	file := os.NewFile(3, "worker")
+	if file == nil {
+		return errors.New("the worker was not started by a client")
+	}
+
+	conn, err := net.FileConn(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("the worker was not started by a client: %w", err)
+	}
+
+	return Serve(conn)
+}
@@ -0,0 +1,235 @@
+package worker_test
+
+import (
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmi2/worker"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the test binary is started as worker process when GO_FMU_TEST_WORKER is set
+func TestMain(m *testing.M) {
+
+	if os.Getenv("GO_FMU_TEST_WORKER") == "1" {
+		if err := worker.Main(); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func startWorker(t *testing.T, opts ...worker.Option) *worker.Worker {
+
+	t.Setenv("GO_FMU_TEST_WORKER", "1")
+
+	w, err := worker.Start(append([]worker.Option{worker.WithCommand(os.Args[0])}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Close() })
+
+	return w
+}
+
+func TestCoSimulation(t *testing.T) {
+
+	w := startWorker(t)
+
+	fmu, err := w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	c, err := fmu.Instantiate("controller", fmi2.CoSimulationType, fmu.ModelDescription().Guid, "", false, false)
+	require.NoError(t, err)
+	defer c.FreeInstance()
+
+	require.Equal(t, fmi2.StateInstantiated, c.State())
+
+	// the errors of the fmi2 package are passed through
+	require.ErrorIs(t, c.DoStep(0, 1, false), fmi2.ErrIllegalCall)
+
+	require.NoError(t, c.SetupExperiment(0, fmi2.WithStopTime(1)))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.Set(map[string]any{"u_s": 0.0}))
+	require.NoError(t, c.ExitInitializationMode())
+	require.Equal(t, fmi2.StateStepComplete, c.State())
+
+	for i := range 10 {
+		require.NoError(t, c.DoStep(float64(i)*0.1, 0.1, true))
+	}
+
+	require.NoError(t, c.Set(map[string]any{"u_s": 2.0}))
+
+	u, err := c.Get("u_s")
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, u, 1e-9)
+
+	_, err = c.Get("unknown")
+	require.ErrorContains(t, err, "unknown")
+
+	require.NoError(t, c.Terminate())
+	require.Equal(t, fmi2.StateTerminated, c.State())
+}
+
+func TestCrash(t *testing.T) {
+
+	w := startWorker(t)
+
+	fmu, err := w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	guid := fmu.ModelDescription().Guid
+
+	c, err := fmu.Instantiate("controller", fmi2.CoSimulationType, guid, "", false, false)
+	require.NoError(t, err)
+
+	pid := w.Pid()
+	require.NoError(t, syscall.Kill(pid, syscall.SIGKILL))
+
+	require.ErrorIs(t, c.SetupExperiment(0), worker.ErrCrashed)
+	require.ErrorIs(t, c.Reset(), worker.ErrCrashed)
+	c.FreeInstance()
+
+	// the worker is restarted and the FMU is loaded again
+	c, err = fmu.Instantiate("controller", fmi2.CoSimulationType, guid, "", false, false)
+	require.NoError(t, err)
+	defer c.FreeInstance()
+
+	require.NotEqual(t, pid, w.Pid())
+	require.Equal(t, 1, w.Restarts())
+	require.NoError(t, c.SetupExperiment(0))
+}
+
+func TestMemoryLimit(t *testing.T) {
+
+	w := startWorker(t, worker.WithMemoryLimit(256<<20))
+
+	// an FMU that does not allocate memory runs under the limit
+	controller, err := w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+
+	c, err := controller.Instantiate("controller", fmi2.CoSimulationType, controller.ModelDescription().Guid, "", false, false)
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.ExitInitializationMode())
+
+	for i := range 100 {
+		require.NoError(t, c.DoStep(float64(i)*0.01, 0.01, true))
+	}
+
+	c.FreeInstance()
+	require.NoError(t, controller.Close())
+
+	allocator, err := w.New("../../../examples/Allocator.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer allocator.Close()
+
+	c, err = allocator.Instantiate("allocator", fmi2.CoSimulationType, allocator.ModelDescription().Guid, "", false, false)
+	require.NoError(t, err)
+	defer c.FreeInstance()
+
+	require.NoError(t, c.SetupExperiment(0))
+	require.NoError(t, c.EnterInitializationMode())
+	require.NoError(t, c.Set(map[string]any{"bytes": 32 << 20}))
+	require.NoError(t, c.ExitInitializationMode())
+
+	// every step allocates another 32 MiB until the worker is killed
+	for i := 0; i < 32 && err == nil; i++ {
+		err = c.DoStep(float64(i)*0.1, 0.1, true)
+	}
+
+	require.ErrorIs(t, err, worker.ErrCrashed)
+	require.ErrorContains(t, err, "the memory usage exceeded the limit of 268435456 bytes")
+}
+
+func TestLogMessages(t *testing.T) {
+
+	var (
+		mutex    sync.Mutex
+		messages = make(map[string]int) // the number of messages by instance name
+	)
+
+	w := startWorker(t, worker.WithLogger(func(instanceName string, status fmi2.Status, category string, message string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		messages[instanceName]++
+	}))
+
+	fmu, err := w.New("../../../examples/Allocator.fmu", fmi2.CoSimulationType)
+	require.NoError(t, err)
+	defer fmu.Close()
+
+	var wg sync.WaitGroup
+
+	for _, name := range []string{"a", "b", "c"} {
+
+		c, err := fmu.Instantiate(name, fmi2.CoSimulationType, fmu.ModelDescription().Guid, "", false, true)
+		require.NoError(t, err)
+		defer c.FreeInstance()
+
+		require.NoError(t, c.SetupExperiment(0))
+		require.NoError(t, c.EnterInitializationMode())
+		require.NoError(t, c.Set(map[string]any{"bytes": 1024}))
+		require.NoError(t, c.ExitInitializationMode())
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 20 {
+				assert.NoError(t, c.DoStep(float64(i)*0.1, 0.1, true))
+
+				// the message of the step is returned with the call of the instance that logged it
+				mutex.Lock()
+				assert.Equal(t, i+1, messages[name])
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestTimeout(t *testing.T) {
+
+	w := startWorker(t, worker.WithTimeout(time.Nanosecond), worker.WithMaxRestarts(1))
+
+	_, err := w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.ErrorIs(t, err, worker.ErrTimeout)
+	require.Equal(t, 0, w.Pid())
+
+	_, err = w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.ErrorIs(t, err, worker.ErrTimeout)
+
+	// the worker is not restarted more than once
+	_, err = w.New("../../../examples/Controller.fmu", fmi2.CoSimulationType)
+	require.ErrorIs(t, err, worker.ErrCrashed)
+}
+
+func TestRemoting(t *testing.T) {
+
+	t.Setenv("GO_FMU_TEST_WORKER", "1")
+
+	// the worker loads the binary of the current platform, so the remote platform is the current platform
+	platform := fmi2.CurrentMachine().Platform
+	t.Cleanup(fmi2.RegisterRemotingBackend(worker.NewRemoting(platform, worker.WithCommand(os.Args[0]))))
+
+	stopTime := 3.0
+
+	result, err := fmi2.SimulateFmu("../../../examples/Ticker.fmu", fmi2.SimulationOptions{
+		StopTime:       &stopTime,
+		RemotePlatform: platform,
+		Initialize:     true,
+		Terminate:      true,
+	})
+	require.NoError(t, err)
+	require.InDelta(t, 3.0, result.Column("ticks").Real[len(result.Time)-1], 1e-4)
+}
@@ -0,0 +1,187 @@
+package fmi3
+
+/*
+#include "headers/fmi3Functions.h"
+*/
+import "C"
+
+import (
+	"go-fmu/pkg/internal/fmi"
+	"log/slog"
+	"runtime/cgo"
+	"sync"
+)
+
+// preemptionMutex is locked by the lockPreemption callback of Scheduled Execution instances
+var preemptionMutex sync.Mutex
+
+// Logger receives the log messages of an FMU instance
+type Logger = fmi.Logger[Status]
+
+// NewSlogLogger returns a Logger that passes the messages to handler. The level of the records
+// is derived from the status, the instance name, status and category are added as attributes.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return fmi.NewSlogLogger[Status](handler)
+}
+
+// IntermediateUpdateInfo is passed to the IntermediateUpdateCallback of a Co-Simulation instance
+type IntermediateUpdateInfo struct {
+	Time                 float64 // the internal time of the FMU
+	VariableSetRequested bool    // the FMU requests values of the intermediate input variables
+	VariableGetAllowed   bool    // the intermediate output variables can be read
+	StepFinished         bool    // the internal solver step is finished
+	CanReturnEarly       bool    // the FMU can return early from DoStep
+}
+
+// IntermediateUpdateCallback is called by a Co-Simulation FMU during DoStep. It may get and set the
+// intermediate variables and request the FMU to return early at earlyReturnTime.
+type IntermediateUpdateCallback func(instance *Instance, info IntermediateUpdateInfo) (earlyReturnRequested bool, earlyReturnTime float64)
+
+// ClockUpdateCallback is called by a Scheduled Execution FMU when an input clock ticks or the interval of
+// a clock has changed
+type ClockUpdateCallback func(instance *Instance)
+
+type InstantiateOption func(*InstantiateOptions)
+
+type InstantiateOptions struct {
+	log fmi.LogOptions[Status]
+
+	// Co-Simulation
+	eventModeUsed                 bool
+	earlyReturnAllowed            bool
+	requiredIntermediateVariables []ValueReference
+	intermediateUpdate            IntermediateUpdateCallback
+
+	// Scheduled Execution
+	clockUpdate ClockUpdateCallback
+}
+
+// WithLogger sets the logger that receives the messages of the instance instead of stdout
+func WithLogger(logger Logger) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Logger = logger
+	}
+}
+
+// WithLogCategories passes only the messages of the given categories to the logger.
+// The categories that an FMU supports are listed by ModelDescription.LogCategoryNames.
+func WithLogCategories(categories ...string) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.log.Categories = categories
+	}
+}
+
+// WithEventMode tells a Co-Simulation FMU that the importer supports Event Mode, which requires hasEventMode
+func WithEventMode() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.eventModeUsed = true
+	}
+}
+
+// WithEarlyReturn allows a Co-Simulation FMU to return early from DoStep
+func WithEarlyReturn() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.earlyReturnAllowed = true
+	}
+}
+
+// WithIntermediateUpdate sets the callback that a Co-Simulation FMU calls during DoStep. The FMU may only
+// request values of the intermediate variables in requiredIntermediateVariables.
+func WithIntermediateUpdate(callback IntermediateUpdateCallback, requiredIntermediateVariables ...ValueReference) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.intermediateUpdate = callback
+		o.requiredIntermediateVariables = requiredIntermediateVariables
+	}
+}
+
+// WithClockUpdate sets the callback that a Scheduled Execution FMU calls when a clock ticks
+func WithClockUpdate(callback ClockUpdateCallback) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.clockUpdate = callback
+	}
+}
+
+// instanceEnvironment is passed to the callback functions of an instance as instanceEnvironment
+type instanceEnvironment struct {
+	instanceName       string
+	instance           *Instance // the instance or nil while it is being created
+	logger             Logger    // passes only the messages of the categories to log
+	intermediateUpdate IntermediateUpdateCallback
+	clockUpdate        ClockUpdateCallback
+}
+
+func newInstanceEnvironment(instanceName string, options *InstantiateOptions) *instanceEnvironment {
+
+	env := &instanceEnvironment{
+		instanceName:       instanceName,
+		logger:             options.log.NewLogger(),
+		intermediateUpdate: options.intermediateUpdate,
+		clockUpdate:        options.clockUpdate,
+	}
+
+	return env
+}
+
+// environmentOf returns the environment of an instance from the handle that was passed to the FMU
+func environmentOf(handle uintptr) *instanceEnvironment {
+	if handle == 0 {
+		return nil
+	}
+
+	env, _ := cgo.Handle(handle).Value().(*instanceEnvironment)
+	return env
+}
+
+//export goLogMessage
+func goLogMessage(handle C.uintptr_t, status C.fmi3Status, category C.fmi3String, message C.fmi3String) {
+
+	env := environmentOf(uintptr(handle))
+	if env == nil {
+		fmi.DefaultLogger("", Status(status), C.GoString(category), C.GoString(message))
+		return
+	}
+
+	env.logger(env.instanceName, Status(status), C.GoString(category), C.GoString(message))
+}
+
+//export goIntermediateUpdate
+func goIntermediateUpdate(handle C.uintptr_t, intermediateUpdateTime C.fmi3Float64, intermediateVariableSetRequested C.fmi3Boolean, intermediateVariableGetAllowed C.fmi3Boolean, intermediateStepFinished C.fmi3Boolean, canReturnEarly C.fmi3Boolean, earlyReturnRequested *C.fmi3Boolean, earlyReturnTime *C.fmi3Float64) {
+
+	*earlyReturnRequested = false
+
+	env := environmentOf(uintptr(handle))
+	if env == nil || env.intermediateUpdate == nil || env.instance == nil {
+		return
+	}
+
+	info := IntermediateUpdateInfo{
+		Time:                 float64(intermediateUpdateTime),
+		VariableSetRequested: bool(intermediateVariableSetRequested),
+		VariableGetAllowed:   bool(intermediateVariableGetAllowed),
+		StepFinished:         bool(intermediateStepFinished),
+		CanReturnEarly:       bool(canReturnEarly),
+	}
+
+	requested, t := env.intermediateUpdate(env.instance, info)
+	if requested && info.CanReturnEarly {
+		*earlyReturnRequested = true
+		*earlyReturnTime = C.fmi3Float64(t)
+	}
+}
+
+//export goClockUpdate
+func goClockUpdate(handle C.uintptr_t) {
+	if env := environmentOf(uintptr(handle)); env != nil && env.clockUpdate != nil && env.instance != nil {
+		env.clockUpdate(env.instance)
+	}
+}
+
+//export goLockPreemption
+func goLockPreemption() {
+	preemptionMutex.Lock()
+}
+
+//export goUnlockPreemption
+func goUnlockPreemption() {
+	preemptionMutex.Unlock()
+}
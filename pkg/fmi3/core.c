@@ -0,0 +1,338 @@
+#include "headers/fmi3Functions.h"
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+extern void goLogMessage(uintptr_t environment, fmi3Status status, fmi3String category, fmi3String message);
+extern void goIntermediateUpdate(uintptr_t environment, fmi3Float64 intermediateUpdateTime, fmi3Boolean intermediateVariableSetRequested, fmi3Boolean intermediateVariableGetAllowed, fmi3Boolean intermediateStepFinished, fmi3Boolean canReturnEarly, fmi3Boolean *earlyReturnRequested, fmi3Float64 *earlyReturnTime);
+extern void goClockUpdate(uintptr_t environment);
+extern void goLockPreemption(void);
+extern void goUnlockPreemption(void);
+
+void LogMessage(fmi3InstanceEnvironment instanceEnvironment, fmi3Status status, fmi3String category, fmi3String message) {
+	goLogMessage((uintptr_t)instanceEnvironment, status, category, message);
+}
+
+void IntermediateUpdate(fmi3InstanceEnvironment instanceEnvironment, fmi3Float64 intermediateUpdateTime, fmi3Boolean intermediateVariableSetRequested, fmi3Boolean intermediateVariableGetAllowed, fmi3Boolean intermediateStepFinished, fmi3Boolean canReturnEarly, fmi3Boolean *earlyReturnRequested, fmi3Float64 *earlyReturnTime) {
+	goIntermediateUpdate((uintptr_t)instanceEnvironment, intermediateUpdateTime, intermediateVariableSetRequested, intermediateVariableGetAllowed, intermediateStepFinished, canReturnEarly, earlyReturnRequested, earlyReturnTime);
+}
+
+void ClockUpdate(fmi3InstanceEnvironment instanceEnvironment) {
+	goClockUpdate((uintptr_t)instanceEnvironment);
+}
+
+void LockPreemption(void) {
+	goLockPreemption();
+}
+
+void UnlockPreemption(void) {
+	goUnlockPreemption();
+}
+
+// OpenLibrary loads the shared library and returns the message of dlerror if loading fails.
+// dlerror has to be called on the same thread as dlopen, which cannot be guaranteed from Go.
+void *Fmi3OpenLibrary(const char *filename, const char **error) {
+	void *handle = dlopen(filename, RTLD_LAZY);
+	*error = handle ? NULL : dlerror();
+	return handle;
+}
+
+fmi3Instance Fmi3InstantiateModelExchange(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, uintptr_t environment) {
+	return ((fmi3InstantiateModelExchangeTYPE *)f)(instanceName, instantiationToken, resourcePath, visible, loggingOn, (fmi3InstanceEnvironment)environment, LogMessage);
+}
+
+fmi3Instance Fmi3InstantiateCoSimulation(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, fmi3Boolean eventModeUsed, fmi3Boolean earlyReturnAllowed, const fmi3ValueReference requiredIntermediateVariables[], size_t nRequiredIntermediateVariables, uintptr_t environment, fmi3Boolean intermediateUpdate) {
+	return ((fmi3InstantiateCoSimulationTYPE *)f)(instanceName, instantiationToken, resourcePath, visible, loggingOn, eventModeUsed, earlyReturnAllowed, requiredIntermediateVariables, nRequiredIntermediateVariables, (fmi3InstanceEnvironment)environment, LogMessage, intermediateUpdate ? IntermediateUpdate : NULL);
+}
+
+fmi3Instance Fmi3InstantiateScheduledExecution(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, uintptr_t environment) {
+	return ((fmi3InstantiateScheduledExecutionTYPE *)f)(instanceName, instantiationToken, resourcePath, visible, loggingOn, (fmi3InstanceEnvironment)environment, LogMessage, ClockUpdate, LockPreemption, UnlockPreemption);
+}
+
+const char *Fmi3GetVersion(void *f) {
+	return ((fmi3GetVersionTYPE *)f)();
+}
+
+fmi3Status Fmi3SetDebugLogging(void *f, fmi3Instance instance, fmi3Boolean loggingOn, size_t nCategories, const fmi3String categories[]) {
+	return ((fmi3SetDebugLoggingTYPE *)f)(instance, loggingOn, nCategories, categories);
+}
+
+void Fmi3FreeInstance(void *f, fmi3Instance instance) {
+	((fmi3FreeInstanceTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3EnterInitializationMode(void *f, fmi3Instance instance, fmi3Boolean toleranceDefined, fmi3Float64 tolerance, fmi3Float64 startTime, fmi3Boolean stopTimeDefined, fmi3Float64 stopTime) {
+	return ((fmi3EnterInitializationModeTYPE *)f)(instance, toleranceDefined, tolerance, startTime, stopTimeDefined, stopTime);
+}
+
+fmi3Status Fmi3ExitInitializationMode(void *f, fmi3Instance instance) {
+	return ((fmi3ExitInitializationModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3EnterEventMode(void *f, fmi3Instance instance) {
+	return ((fmi3EnterEventModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3Terminate(void *f, fmi3Instance instance) {
+	return ((fmi3TerminateTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3Reset(void *f, fmi3Instance instance) {
+	return ((fmi3ResetTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3GetFloat32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float32 values[], size_t nValues) {
+	return ((fmi3GetFloat32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetFloat64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 values[], size_t nValues) {
+	return ((fmi3GetFloat64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int8 values[], size_t nValues) {
+	return ((fmi3GetInt8TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetUInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt8 values[], size_t nValues) {
+	return ((fmi3GetUInt8TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int16 values[], size_t nValues) {
+	return ((fmi3GetInt16TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetUInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt16 values[], size_t nValues) {
+	return ((fmi3GetUInt16TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int32 values[], size_t nValues) {
+	return ((fmi3GetInt32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetUInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt32 values[], size_t nValues) {
+	return ((fmi3GetUInt32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int64 values[], size_t nValues) {
+	return ((fmi3GetInt64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetUInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 values[], size_t nValues) {
+	return ((fmi3GetUInt64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetBoolean(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Boolean values[], size_t nValues) {
+	return ((fmi3GetBooleanTYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetString(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3String values[], size_t nValues) {
+	return ((fmi3GetStringTYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3GetBinary(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, size_t valueSizes[], fmi3Binary values[], size_t nValues) {
+	return ((fmi3GetBinaryTYPE *)f)(instance, valueReferences, nValueReferences, valueSizes, values, nValues);
+}
+
+fmi3Status Fmi3GetClock(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Clock values[]) {
+	return ((fmi3GetClockTYPE *)f)(instance, valueReferences, nValueReferences, values);
+}
+
+fmi3Status Fmi3SetFloat32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float32 values[], size_t nValues) {
+	return ((fmi3SetFloat32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetFloat64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 values[], size_t nValues) {
+	return ((fmi3SetFloat64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int8 values[], size_t nValues) {
+	return ((fmi3SetInt8TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetUInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt8 values[], size_t nValues) {
+	return ((fmi3SetUInt8TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int16 values[], size_t nValues) {
+	return ((fmi3SetInt16TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetUInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt16 values[], size_t nValues) {
+	return ((fmi3SetUInt16TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 values[], size_t nValues) {
+	return ((fmi3SetInt32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetUInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt32 values[], size_t nValues) {
+	return ((fmi3SetUInt32TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int64 values[], size_t nValues) {
+	return ((fmi3SetInt64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetUInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 values[], size_t nValues) {
+	return ((fmi3SetUInt64TYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetBoolean(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Boolean values[], size_t nValues) {
+	return ((fmi3SetBooleanTYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetString(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3String values[], size_t nValues) {
+	return ((fmi3SetStringTYPE *)f)(instance, valueReferences, nValueReferences, values, nValues);
+}
+
+fmi3Status Fmi3SetBinary(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const size_t valueSizes[], const fmi3Binary values[], size_t nValues) {
+	return ((fmi3SetBinaryTYPE *)f)(instance, valueReferences, nValueReferences, valueSizes, values, nValues);
+}
+
+fmi3Status Fmi3SetClock(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Clock values[]) {
+	return ((fmi3SetClockTYPE *)f)(instance, valueReferences, nValueReferences, values);
+}
+
+fmi3Status Fmi3GetNumberOfVariableDependencies(void *f, fmi3Instance instance, fmi3ValueReference valueReference, size_t* nDependencies) {
+	return ((fmi3GetNumberOfVariableDependenciesTYPE *)f)(instance, valueReference, nDependencies);
+}
+
+fmi3Status Fmi3GetVariableDependencies(void *f, fmi3Instance instance, fmi3ValueReference dependent, size_t elementIndicesOfDependent[], fmi3ValueReference independents[], size_t elementIndicesOfIndependents[], fmi3DependencyKind dependencyKinds[], size_t nDependencies) {
+	return ((fmi3GetVariableDependenciesTYPE *)f)(instance, dependent, elementIndicesOfDependent, independents, elementIndicesOfIndependents, dependencyKinds, nDependencies);
+}
+
+fmi3Status Fmi3GetFMUState(void *f, fmi3Instance instance, fmi3FMUState* FMUState) {
+	return ((fmi3GetFMUStateTYPE *)f)(instance, FMUState);
+}
+
+fmi3Status Fmi3SetFMUState(void *f, fmi3Instance instance, fmi3FMUState FMUState) {
+	return ((fmi3SetFMUStateTYPE *)f)(instance, FMUState);
+}
+
+fmi3Status Fmi3FreeFMUState(void *f, fmi3Instance instance, fmi3FMUState* FMUState) {
+	return ((fmi3FreeFMUStateTYPE *)f)(instance, FMUState);
+}
+
+fmi3Status Fmi3SerializedFMUStateSize(void *f, fmi3Instance instance, fmi3FMUState FMUState, size_t* size) {
+	return ((fmi3SerializedFMUStateSizeTYPE *)f)(instance, FMUState, size);
+}
+
+fmi3Status Fmi3SerializeFMUState(void *f, fmi3Instance instance, fmi3FMUState FMUState, fmi3Byte serializedState[], size_t size) {
+	return ((fmi3SerializeFMUStateTYPE *)f)(instance, FMUState, serializedState, size);
+}
+
+fmi3Status Fmi3DeserializeFMUState(void *f, fmi3Instance instance, const fmi3Byte serializedState[], size_t size, fmi3FMUState* FMUState) {
+	return ((fmi3DeserializeFMUStateTYPE *)f)(instance, serializedState, size, FMUState);
+}
+
+fmi3Status Fmi3GetDirectionalDerivative(void *f, fmi3Instance instance, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[], size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity) {
+	return ((fmi3GetDirectionalDerivativeTYPE *)f)(instance, unknowns, nUnknowns, knowns, nKnowns, seed, nSeed, sensitivity, nSensitivity);
+}
+
+fmi3Status Fmi3GetAdjointDerivative(void *f, fmi3Instance instance, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[], size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity) {
+	return ((fmi3GetAdjointDerivativeTYPE *)f)(instance, unknowns, nUnknowns, knowns, nKnowns, seed, nSeed, sensitivity, nSensitivity);
+}
+
+fmi3Status Fmi3EnterConfigurationMode(void *f, fmi3Instance instance) {
+	return ((fmi3EnterConfigurationModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3ExitConfigurationMode(void *f, fmi3Instance instance) {
+	return ((fmi3ExitConfigurationModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3GetIntervalDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 intervals[], fmi3IntervalQualifier qualifiers[]) {
+	return ((fmi3GetIntervalDecimalTYPE *)f)(instance, valueReferences, nValueReferences, intervals, qualifiers);
+}
+
+fmi3Status Fmi3GetIntervalFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[], fmi3UInt64 resolutions[], fmi3IntervalQualifier qualifiers[]) {
+	return ((fmi3GetIntervalFractionTYPE *)f)(instance, valueReferences, nValueReferences, counters, resolutions, qualifiers);
+}
+
+fmi3Status Fmi3GetShiftDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 shifts[]) {
+	return ((fmi3GetShiftDecimalTYPE *)f)(instance, valueReferences, nValueReferences, shifts);
+}
+
+fmi3Status Fmi3GetShiftFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[], fmi3UInt64 resolutions[]) {
+	return ((fmi3GetShiftFractionTYPE *)f)(instance, valueReferences, nValueReferences, counters, resolutions);
+}
+
+fmi3Status Fmi3SetIntervalDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 intervals[]) {
+	return ((fmi3SetIntervalDecimalTYPE *)f)(instance, valueReferences, nValueReferences, intervals);
+}
+
+fmi3Status Fmi3SetIntervalFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[], const fmi3UInt64 resolutions[]) {
+	return ((fmi3SetIntervalFractionTYPE *)f)(instance, valueReferences, nValueReferences, counters, resolutions);
+}
+
+fmi3Status Fmi3SetShiftDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 shifts[]) {
+	return ((fmi3SetShiftDecimalTYPE *)f)(instance, valueReferences, nValueReferences, shifts);
+}
+
+fmi3Status Fmi3SetShiftFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[], const fmi3UInt64 resolutions[]) {
+	return ((fmi3SetShiftFractionTYPE *)f)(instance, valueReferences, nValueReferences, counters, resolutions);
+}
+
+fmi3Status Fmi3EvaluateDiscreteStates(void *f, fmi3Instance instance) {
+	return ((fmi3EvaluateDiscreteStatesTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3UpdateDiscreteStates(void *f, fmi3Instance instance, fmi3Boolean* discreteStatesNeedUpdate, fmi3Boolean* terminateSimulation, fmi3Boolean* nominalsOfContinuousStatesChanged, fmi3Boolean* valuesOfContinuousStatesChanged, fmi3Boolean* nextEventTimeDefined, fmi3Float64* nextEventTime) {
+	return ((fmi3UpdateDiscreteStatesTYPE *)f)(instance, discreteStatesNeedUpdate, terminateSimulation, nominalsOfContinuousStatesChanged, valuesOfContinuousStatesChanged, nextEventTimeDefined, nextEventTime);
+}
+
+fmi3Status Fmi3EnterContinuousTimeMode(void *f, fmi3Instance instance) {
+	return ((fmi3EnterContinuousTimeModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3CompletedIntegratorStep(void *f, fmi3Instance instance, fmi3Boolean noSetFMUStatePriorToCurrentPoint, fmi3Boolean* enterEventMode, fmi3Boolean* terminateSimulation) {
+	return ((fmi3CompletedIntegratorStepTYPE *)f)(instance, noSetFMUStatePriorToCurrentPoint, enterEventMode, terminateSimulation);
+}
+
+fmi3Status Fmi3SetTime(void *f, fmi3Instance instance, fmi3Float64 time) {
+	return ((fmi3SetTimeTYPE *)f)(instance, time);
+}
+
+fmi3Status Fmi3SetContinuousStates(void *f, fmi3Instance instance, const fmi3Float64 continuousStates[], size_t nContinuousStates) {
+	return ((fmi3SetContinuousStatesTYPE *)f)(instance, continuousStates, nContinuousStates);
+}
+
+fmi3Status Fmi3GetContinuousStateDerivatives(void *f, fmi3Instance instance, fmi3Float64 derivatives[], size_t nContinuousStates) {
+	return ((fmi3GetContinuousStateDerivativesTYPE *)f)(instance, derivatives, nContinuousStates);
+}
+
+fmi3Status Fmi3GetEventIndicators(void *f, fmi3Instance instance, fmi3Float64 eventIndicators[], size_t nEventIndicators) {
+	return ((fmi3GetEventIndicatorsTYPE *)f)(instance, eventIndicators, nEventIndicators);
+}
+
+fmi3Status Fmi3GetContinuousStates(void *f, fmi3Instance instance, fmi3Float64 continuousStates[], size_t nContinuousStates) {
+	return ((fmi3GetContinuousStatesTYPE *)f)(instance, continuousStates, nContinuousStates);
+}
+
+fmi3Status Fmi3GetNominalsOfContinuousStates(void *f, fmi3Instance instance, fmi3Float64 nominals[], size_t nContinuousStates) {
+	return ((fmi3GetNominalsOfContinuousStatesTYPE *)f)(instance, nominals, nContinuousStates);
+}
+
+fmi3Status Fmi3GetNumberOfEventIndicators(void *f, fmi3Instance instance, size_t* nEventIndicators) {
+	return ((fmi3GetNumberOfEventIndicatorsTYPE *)f)(instance, nEventIndicators);
+}
+
+fmi3Status Fmi3GetNumberOfContinuousStates(void *f, fmi3Instance instance, size_t* nContinuousStates) {
+	return ((fmi3GetNumberOfContinuousStatesTYPE *)f)(instance, nContinuousStates);
+}
+
+fmi3Status Fmi3EnterStepMode(void *f, fmi3Instance instance) {
+	return ((fmi3EnterStepModeTYPE *)f)(instance);
+}
+
+fmi3Status Fmi3GetOutputDerivatives(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 orders[], fmi3Float64 values[], size_t nValues) {
+	return ((fmi3GetOutputDerivativesTYPE *)f)(instance, valueReferences, nValueReferences, orders, values, nValues);
+}
+
+fmi3Status Fmi3DoStep(void *f, fmi3Instance instance, fmi3Float64 currentCommunicationPoint, fmi3Float64 communicationStepSize, fmi3Boolean noSetFMUStatePriorToCurrentPoint, fmi3Boolean* eventHandlingNeeded, fmi3Boolean* terminateSimulation, fmi3Boolean* earlyReturn, fmi3Float64* lastSuccessfulTime) {
+	return ((fmi3DoStepTYPE *)f)(instance, currentCommunicationPoint, communicationStepSize, noSetFMUStatePriorToCurrentPoint, eventHandlingNeeded, terminateSimulation, earlyReturn, lastSuccessfulTime);
+}
+
+fmi3Status Fmi3ActivateModelPartition(void *f, fmi3Instance instance, fmi3ValueReference clockReference, fmi3Float64 activationTime) {
+	return ((fmi3ActivateModelPartitionTYPE *)f)(instance, clockReference, activationTime);
+}
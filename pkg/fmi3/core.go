@@ -0,0 +1,1736 @@
+package fmi3
+
+/*
+#cgo LDFLAGS: -ldl
+#include "core.h"
+*/
+import "C"
+
+import (
+	"archive/zip"
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/cgo"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+type Fmu3 struct {
+	Directory     string
+	ownsDirectory bool // the directory is removed by Close
+	moduleHandle  unsafe.Pointer
+
+	modelDescription *ModelDescription
+	variables        map[string]*ModelVariable // the model variables by name
+
+	// Common Functions
+	getVersionPtr                      unsafe.Pointer
+	setDebugLoggingPtr                 unsafe.Pointer
+	instantiateModelExchangePtr        unsafe.Pointer
+	instantiateCoSimulationPtr         unsafe.Pointer
+	instantiateScheduledExecutionPtr   unsafe.Pointer
+	freeInstancePtr                    unsafe.Pointer
+	enterInitializationModePtr         unsafe.Pointer
+	exitInitializationModePtr          unsafe.Pointer
+	enterEventModePtr                  unsafe.Pointer
+	terminatePtr                       unsafe.Pointer
+	resetPtr                           unsafe.Pointer
+	getFloat32Ptr                      unsafe.Pointer
+	getFloat64Ptr                      unsafe.Pointer
+	getInt8Ptr                         unsafe.Pointer
+	getUInt8Ptr                        unsafe.Pointer
+	getInt16Ptr                        unsafe.Pointer
+	getUInt16Ptr                       unsafe.Pointer
+	getInt32Ptr                        unsafe.Pointer
+	getUInt32Ptr                       unsafe.Pointer
+	getInt64Ptr                        unsafe.Pointer
+	getUInt64Ptr                       unsafe.Pointer
+	getBooleanPtr                      unsafe.Pointer
+	getStringPtr                       unsafe.Pointer
+	getBinaryPtr                       unsafe.Pointer
+	getClockPtr                        unsafe.Pointer
+	setFloat32Ptr                      unsafe.Pointer
+	setFloat64Ptr                      unsafe.Pointer
+	setInt8Ptr                         unsafe.Pointer
+	setUInt8Ptr                        unsafe.Pointer
+	setInt16Ptr                        unsafe.Pointer
+	setUInt16Ptr                       unsafe.Pointer
+	setInt32Ptr                        unsafe.Pointer
+	setUInt32Ptr                       unsafe.Pointer
+	setInt64Ptr                        unsafe.Pointer
+	setUInt64Ptr                       unsafe.Pointer
+	setBooleanPtr                      unsafe.Pointer
+	setStringPtr                       unsafe.Pointer
+	setBinaryPtr                       unsafe.Pointer
+	setClockPtr                        unsafe.Pointer
+	getNumberOfVariableDependenciesPtr unsafe.Pointer
+	getVariableDependenciesPtr         unsafe.Pointer
+	getFMUStatePtr                     unsafe.Pointer
+	setFMUStatePtr                     unsafe.Pointer
+	freeFMUStatePtr                    unsafe.Pointer
+	serializedFMUStateSizePtr          unsafe.Pointer
+	serializeFMUStatePtr               unsafe.Pointer
+	deserializeFMUStatePtr             unsafe.Pointer
+	getDirectionalDerivativePtr        unsafe.Pointer
+	getAdjointDerivativePtr            unsafe.Pointer
+	enterConfigurationModePtr          unsafe.Pointer
+	exitConfigurationModePtr           unsafe.Pointer
+	getIntervalDecimalPtr              unsafe.Pointer
+	getIntervalFractionPtr             unsafe.Pointer
+	getShiftDecimalPtr                 unsafe.Pointer
+	getShiftFractionPtr                unsafe.Pointer
+	setIntervalDecimalPtr              unsafe.Pointer
+	setIntervalFractionPtr             unsafe.Pointer
+	setShiftDecimalPtr                 unsafe.Pointer
+	setShiftFractionPtr                unsafe.Pointer
+	evaluateDiscreteStatesPtr          unsafe.Pointer
+	updateDiscreteStatesPtr            unsafe.Pointer
+
+	// Functions for Model Exchange
+	enterContinuousTimeModePtr       unsafe.Pointer
+	completedIntegratorStepPtr       unsafe.Pointer
+	setTimePtr                       unsafe.Pointer
+	setContinuousStatesPtr           unsafe.Pointer
+	getContinuousStateDerivativesPtr unsafe.Pointer
+	getEventIndicatorsPtr            unsafe.Pointer
+	getContinuousStatesPtr           unsafe.Pointer
+	getNominalsOfContinuousStatesPtr unsafe.Pointer
+	getNumberOfEventIndicatorsPtr    unsafe.Pointer
+	getNumberOfContinuousStatesPtr   unsafe.Pointer
+
+	// Functions for Co-Simulation
+	enterStepModePtr        unsafe.Pointer
+	getOutputDerivativesPtr unsafe.Pointer
+	doStepPtr               unsafe.Pointer
+
+	// Functions for Scheduled Execution
+	activateModelPartitionPtr unsafe.Pointer
+	warningHandler            WarningHandler
+}
+
+// ModelDescription returns the model description of the FMU
+func (f *Fmu3) ModelDescription() *ModelDescription {
+	return f.modelDescription
+}
+
+// Close unloads the shared library and removes the extracted files if they were extracted by New
+func (f *Fmu3) Close() error {
+	if f.moduleHandle != nil {
+		C.dlclose(f.moduleHandle)
+		f.moduleHandle = nil
+	}
+
+	if f.ownsDirectory {
+		f.ownsDirectory = false
+		return os.RemoveAll(f.Directory)
+	}
+
+	return nil
+}
+
+// ResourcePath returns the absolute path of the resources directory of the extracted FMU that is passed to
+// the Instantiate functions. Unlike FMI 2.0, which expects a URI, FMI 3.0 expects a native path with a trailing separator.
+func (f *Fmu3) ResourcePath() string {
+	return filepath.Join(f.Directory, "resources") + string(filepath.Separator)
+}
+
+/* GetVersion returns the version of the "fmi3Functions.h" header file which was used to compile the functions of the FMU.
+ * The function returns "fmi3Version" which is defined in this header file, e.g. "3.0".
+ */
+func (f *Fmu3) GetVersion() string {
+	return C.GoString(C.Fmi3GetVersion(f.getVersionPtr))
+}
+
+/* SetDebugLogging controls the debug logging that is output via the logMessage callback function by the FMU.
+ * If loggingOn = true, debug logging is enabled for the log categories specified in
+ * categories, otherwise it is disabled.
+ * If len(categories) == 0, loggingOn applies to all log categories.
+ * The allowed values of categories are defined in the modelDescription.xml file via element "<LogCategories>".
+ */
+func (f *Fmu3) SetDebugLogging(c *Instance, loggingOn bool, categories []string) error {
+
+	if err := f.available(c, f.setDebugLoggingPtr, "fmi3SetDebugLogging"); err != nil {
+		return err
+	}
+
+	cats := fmi.Transform(categories, func(i int, v string) C.fmi3String { return C.CString(v) })
+
+	defer func() {
+		for _, v := range cats {
+			C.free(unsafe.Pointer(v))
+		}
+	}()
+
+	return f.check(c, "fmi3SetDebugLogging", C.Fmi3SetDebugLogging(f.setDebugLoggingPtr, c.instance, C.fmi3Boolean(loggingOn), C.size_t(len(cats)), fmi.First(cats)))
+}
+
+// instantiate creates the environment of a new instance and calls the instantiate function of the interface type
+func (f *Fmu3) instantiate(fmuType Type, instanceName string, options *InstantiateOptions, required *C.fmi3ValueReference, call func(environment C.uintptr_t) C.fmi3Instance) *Instance {
+
+	env := newInstanceEnvironment(instanceName, options)
+
+	// the FMU passes the handle to the callback functions as long as the instance exists
+	handle := cgo.NewHandle(env)
+
+	c := &Instance{
+		fmu:                           f,
+		fmuType:                       fmuType,
+		handle:                        handle,
+		requiredIntermediateVariables: required,
+	}
+
+	c.instance = call(C.uintptr_t(handle))
+	if c.instance == nil {
+		c.release()
+		return nil
+	}
+
+	env.instance = c
+
+	return c
+}
+
+/* InstantiateModelExchange returns a new instance of an FMU for Model Exchange. If nil is returned, then instantiation failed.
+ * In that case, the logger is called with detailed information about the reason.
+ * resourcePath is the absolute path of the resources directory with a trailing separator, see ResourcePath.
+ * The messages of the instance are printed to stdout unless a logger is passed with WithLogger.
+ */
+func (f *Fmu3) InstantiateModelExchange(instanceName string, instantiationToken string, resourcePath string, visible bool, loggingOn bool, opts ...InstantiateOption) *Instance {
+
+	if f.instantiateModelExchangePtr == nil {
+		return nil
+	}
+
+	options := &InstantiateOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	cInstanceName := C.CString(instanceName)
+	defer C.free(unsafe.Pointer(cInstanceName))
+	cToken := C.CString(instantiationToken)
+	defer C.free(unsafe.Pointer(cToken))
+	cResourcePath := C.CString(resourcePath)
+	defer C.free(unsafe.Pointer(cResourcePath))
+
+	return f.instantiate(ModelExchangeType, instanceName, options, nil, func(environment C.uintptr_t) C.fmi3Instance {
+		return C.Fmi3InstantiateModelExchange(f.instantiateModelExchangePtr, cInstanceName, cToken, cResourcePath, C.fmi3Boolean(visible), C.fmi3Boolean(loggingOn), environment)
+	})
+}
+
+/* InstantiateCoSimulation returns a new instance of an FMU for Co-Simulation. If nil is returned, then instantiation failed.
+ * Event Mode, early return and the intermediate update callback are enabled with WithEventMode, WithEarlyReturn
+ * and WithIntermediateUpdate.
+ */
+func (f *Fmu3) InstantiateCoSimulation(instanceName string, instantiationToken string, resourcePath string, visible bool, loggingOn bool, opts ...InstantiateOption) *Instance {
+
+	if f.instantiateCoSimulationPtr == nil {
+		return nil
+	}
+
+	options := &InstantiateOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	cInstanceName := C.CString(instanceName)
+	defer C.free(unsafe.Pointer(cInstanceName))
+	cToken := C.CString(instantiationToken)
+	defer C.free(unsafe.Pointer(cToken))
+	cResourcePath := C.CString(resourcePath)
+	defer C.free(unsafe.Pointer(cResourcePath))
+
+	// the FMU may keep the pointer to the required intermediate variables, so they are copied to C memory
+	// that is freed with the instance
+	var required *C.fmi3ValueReference
+	n := len(options.requiredIntermediateVariables)
+
+	if n > 0 {
+		required = (*C.fmi3ValueReference)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.fmi3ValueReference(0)))))
+		refs := unsafe.Slice(required, n)
+		for i, vr := range options.requiredIntermediateVariables {
+			refs[i] = C.fmi3ValueReference(vr)
+		}
+	}
+
+	return f.instantiate(CoSimulationType, instanceName, options, required, func(environment C.uintptr_t) C.fmi3Instance {
+		return C.Fmi3InstantiateCoSimulation(f.instantiateCoSimulationPtr, cInstanceName, cToken, cResourcePath, C.fmi3Boolean(visible), C.fmi3Boolean(loggingOn),
+			C.fmi3Boolean(options.eventModeUsed), C.fmi3Boolean(options.earlyReturnAllowed), required, C.size_t(n),
+			environment, C.fmi3Boolean(options.intermediateUpdate != nil))
+	})
+}
+
+/* InstantiateScheduledExecution returns a new instance of an FMU for Scheduled Execution. If nil is returned, then instantiation failed.
+ * The callback that is called when a clock ticks is set with WithClockUpdate.
+ */
+func (f *Fmu3) InstantiateScheduledExecution(instanceName string, instantiationToken string, resourcePath string, visible bool, loggingOn bool, opts ...InstantiateOption) *Instance {
+
+	if f.instantiateScheduledExecutionPtr == nil {
+		return nil
+	}
+
+	options := &InstantiateOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	cInstanceName := C.CString(instanceName)
+	defer C.free(unsafe.Pointer(cInstanceName))
+	cToken := C.CString(instantiationToken)
+	defer C.free(unsafe.Pointer(cToken))
+	cResourcePath := C.CString(resourcePath)
+	defer C.free(unsafe.Pointer(cResourcePath))
+
+	return f.instantiate(ScheduledExecutionType, instanceName, options, nil, func(environment C.uintptr_t) C.fmi3Instance {
+		return C.Fmi3InstantiateScheduledExecution(f.instantiateScheduledExecutionPtr, cInstanceName, cToken, cResourcePath, C.fmi3Boolean(visible), C.fmi3Boolean(loggingOn), environment)
+	})
+}
+
+/* FreeInstance disposes the given instance, unloads the loaded model, and frees all the allocated memory and other resources that have been allocated by the functions of the FMU interface.
+ * If the passed instance is nil, the function call is ignored (does not have an effect).
+ */
+func (f *Fmu3) FreeInstance(c *Instance) {
+	if c == nil || c.instance == nil {
+		return
+	}
+
+	// after Fatal no function of the instance may be called, not even FreeInstance
+	if !c.fatal {
+		C.Fmi3FreeInstance(f.freeInstancePtr, c.instance)
+	}
+
+	c.instance = nil
+	c.release()
+}
+
+type InitializationOption func(*InitializationOptions)
+
+type InitializationOptions struct {
+	toleranceDefined bool
+	tolerance        float64
+	stopTimeDefined  bool
+	stopTime         float64
+}
+
+// WithTolerance passes the relative tolerance of the importer to the FMU
+func WithTolerance(tolerance float64) InitializationOption {
+	return func(o *InitializationOptions) {
+		o.toleranceDefined = true
+		o.tolerance = tolerance
+	}
+}
+
+// WithStopTime passes the stop time of the simulation to the FMU
+func WithStopTime(stopTime float64) InitializationOption {
+	return func(o *InitializationOptions) {
+		o.stopTimeDefined = true
+		o.stopTime = stopTime
+	}
+}
+
+/* EnterInitializationMode informs the FMU to enter Initialization Mode.
+ * Before calling this function, all variables with attribute initial = "exact" or "approx" can be set.
+ * The experiment is set up with the start time and the tolerance and stop time that are passed with
+ * WithTolerance and WithStopTime (fmi2SetupExperiment does not exist anymore).
+ */
+func (f *Fmu3) EnterInitializationMode(c *Instance, startTime float64, opts ...InitializationOption) error {
+
+	if err := f.available(c, f.enterInitializationModePtr, "fmi3EnterInitializationMode"); err != nil {
+		return err
+	}
+
+	options := &InitializationOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return f.check(c, "fmi3EnterInitializationMode", C.Fmi3EnterInitializationMode(f.enterInitializationModePtr, c.instance,
+		C.fmi3Boolean(options.toleranceDefined), C.fmi3Float64(options.tolerance), C.fmi3Float64(startTime),
+		C.fmi3Boolean(options.stopTimeDefined), C.fmi3Float64(options.stopTime)))
+}
+
+/* ExitInitializationMode informs the FMU to exit Initialization Mode. An FMU for Model Exchange enters Event Mode,
+ * an FMU for Co-Simulation enters Event Mode if it was instantiated with WithEventMode and Step Mode otherwise.
+ */
+func (f *Fmu3) ExitInitializationMode(c *Instance) error {
+	if err := f.available(c, f.exitInitializationModePtr, "fmi3ExitInitializationMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3ExitInitializationMode", C.Fmi3ExitInitializationMode(f.exitInitializationModePtr, c.instance))
+}
+
+/* EnterEventMode causes the FMU to enter Event Mode, where discrete-time equations may become active
+ * (and relations are not "frozen").
+ */
+func (f *Fmu3) EnterEventMode(c *Instance) error {
+	if err := f.available(c, f.enterEventModePtr, "fmi3EnterEventMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3EnterEventMode", C.Fmi3EnterEventMode(f.enterEventModePtr, c.instance))
+}
+
+/* Terminate informs the FMU that the simulation run is terminated.
+ * After calling this function, the final values of all variables can be inquired with the Get functions.
+ */
+func (f *Fmu3) Terminate(c *Instance) error {
+	if err := f.available(c, f.terminatePtr, "fmi3Terminate"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3Terminate", C.Fmi3Terminate(f.terminatePtr, c.instance))
+}
+
+/* Reset is called by the environment to reset the FMU after a simulation run.
+ * The FMU goes into the same state as if it was instantiated. All variables have their default values.
+ */
+func (f *Fmu3) Reset(c *Instance) error {
+	if err := f.available(c, f.resetPtr, "fmi3Reset"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3Reset", C.Fmi3Reset(f.resetPtr, c.instance))
+}
+
+// getValues calls a get function of the FMU and converts the nValues values it returns
+func getValues[Value any, CValue any](f *Fmu3, c *Instance, ptr unsafe.Pointer, function string, vr []ValueReference, nValues int,
+	get func(refs *C.fmi3ValueReference, nRefs C.size_t, values *CValue, nValues C.size_t) C.fmi3Status, convert func(CValue) Value) ([]Value, error) {
+
+	if err := f.available(c, ptr, function); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	values := make([]CValue, nValues)
+
+	if err := f.check(c, function, get(fmi.First(refs), C.size_t(len(refs)), fmi.First(values), C.size_t(nValues))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(values, func(i int, v CValue) Value { return convert(v) }), nil
+}
+
+// setValues converts the values and passes them to a set function of the FMU
+func setValues[Value any, CValue any](f *Fmu3, c *Instance, ptr unsafe.Pointer, function string, vr []ValueReference, value []Value,
+	set func(refs *C.fmi3ValueReference, nRefs C.size_t, values *CValue, nValues C.size_t) C.fmi3Status, convert func(Value) CValue) error {
+
+	if err := f.available(c, ptr, function); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	values := fmi.Transform(value, func(i int, v Value) CValue { return convert(v) })
+
+	return f.check(c, function, set(fmi.First(refs), C.size_t(len(refs)), fmi.First(values), C.size_t(len(values))))
+}
+
+/* GetFloat32 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetFloat32(c *Instance, vr []ValueReference, nValues int) ([]float32, error) {
+	return getValues(f, c, f.getFloat32Ptr, "fmi3GetFloat32", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Float32, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetFloat32(f.getFloat32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Float32) float32 { return float32(v) })
+}
+
+/* GetFloat64 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetFloat64(c *Instance, vr []ValueReference, nValues int) ([]float64, error) {
+	return getValues(f, c, f.getFloat64Ptr, "fmi3GetFloat64", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetFloat64(f.getFloat64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Float64) float64 { return float64(v) })
+}
+
+/* GetInt8 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetInt8(c *Instance, vr []ValueReference, nValues int) ([]int8, error) {
+	return getValues(f, c, f.getInt8Ptr, "fmi3GetInt8", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int8, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetInt8(f.getInt8Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Int8) int8 { return int8(v) })
+}
+
+/* GetUInt8 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetUInt8(c *Instance, vr []ValueReference, nValues int) ([]uint8, error) {
+	return getValues(f, c, f.getUInt8Ptr, "fmi3GetUInt8", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt8, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetUInt8(f.getUInt8Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3UInt8) uint8 { return uint8(v) })
+}
+
+/* GetInt16 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetInt16(c *Instance, vr []ValueReference, nValues int) ([]int16, error) {
+	return getValues(f, c, f.getInt16Ptr, "fmi3GetInt16", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int16, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetInt16(f.getInt16Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Int16) int16 { return int16(v) })
+}
+
+/* GetUInt16 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetUInt16(c *Instance, vr []ValueReference, nValues int) ([]uint16, error) {
+	return getValues(f, c, f.getUInt16Ptr, "fmi3GetUInt16", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt16, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetUInt16(f.getUInt16Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3UInt16) uint16 { return uint16(v) })
+}
+
+/* GetInt32 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetInt32(c *Instance, vr []ValueReference, nValues int) ([]int32, error) {
+	return getValues(f, c, f.getInt32Ptr, "fmi3GetInt32", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int32, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetInt32(f.getInt32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Int32) int32 { return int32(v) })
+}
+
+/* GetUInt32 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetUInt32(c *Instance, vr []ValueReference, nValues int) ([]uint32, error) {
+	return getValues(f, c, f.getUInt32Ptr, "fmi3GetUInt32", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt32, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetUInt32(f.getUInt32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3UInt32) uint32 { return uint32(v) })
+}
+
+/* GetInt64 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetInt64(c *Instance, vr []ValueReference, nValues int) ([]int64, error) {
+	return getValues(f, c, f.getInt64Ptr, "fmi3GetInt64", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetInt64(f.getInt64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Int64) int64 { return int64(v) })
+}
+
+/* GetUInt64 gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetUInt64(c *Instance, vr []ValueReference, nValues int) ([]uint64, error) {
+	return getValues(f, c, f.getUInt64Ptr, "fmi3GetUInt64", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetUInt64(f.getUInt64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3UInt64) uint64 { return uint64(v) })
+}
+
+/* GetBoolean gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetBoolean(c *Instance, vr []ValueReference, nValues int) ([]bool, error) {
+	return getValues(f, c, f.getBooleanPtr, "fmi3GetBoolean", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Boolean, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetBoolean(f.getBooleanPtr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3Boolean) bool { return bool(v) })
+}
+
+/* GetString gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetString(c *Instance, vr []ValueReference, nValues int) ([]string, error) {
+	// the strings are owned by the FMU and copied before the next call
+	return getValues(f, c, f.getStringPtr, "fmi3GetString", vr, nValues, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3String, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetString(f.getStringPtr, c.instance, refs, nRefs, values, n)
+	}, func(v C.fmi3String) string { return C.GoString(v) })
+}
+
+/* GetBinary gets actual values of variables by providing their value references.
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ * The values are copied, so they remain valid after the next call to the FMU.
+ */
+func (f *Fmu3) GetBinary(c *Instance, vr []ValueReference, nValues int) ([][]byte, error) {
+
+	if err := f.available(c, f.getBinaryPtr, "fmi3GetBinary"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	sizes := make([]C.size_t, nValues)
+	values := make([]C.fmi3Binary, nValues)
+
+	if err := f.check(c, "fmi3GetBinary", C.Fmi3GetBinary(f.getBinaryPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(sizes), fmi.First(values), C.size_t(nValues))); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(values, func(i int, v C.fmi3Binary) []byte { return C.GoBytes(unsafe.Pointer(v), C.int(sizes[i])) })
+	return result, nil
+}
+
+/* GetClock gets the values of clocks by providing their value references. A clock is active (true) or inactive (false).
+ * The FMU deactivates the output clocks that are active when they are read.
+ */
+func (f *Fmu3) GetClock(c *Instance, vr []ValueReference) ([]bool, error) {
+
+	if err := f.available(c, f.getClockPtr, "fmi3GetClock"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	values := make([]C.fmi3Clock, len(vr))
+
+	if err := f.check(c, "fmi3GetClock", C.Fmi3GetClock(f.getClockPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(values))); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(values, func(i int, v C.fmi3Clock) bool { return bool(v) })
+	return result, nil
+}
+
+/* SetFloat32 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetFloat32(c *Instance, vr []ValueReference, value []float32) error {
+	return setValues(f, c, f.setFloat32Ptr, "fmi3SetFloat32", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Float32, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetFloat32(f.setFloat32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v float32) C.fmi3Float32 { return C.fmi3Float32(v) })
+}
+
+/* SetFloat64 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetFloat64(c *Instance, vr []ValueReference, value []float64) error {
+	return setValues(f, c, f.setFloat64Ptr, "fmi3SetFloat64", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetFloat64(f.setFloat64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v float64) C.fmi3Float64 { return C.fmi3Float64(v) })
+}
+
+/* SetInt8 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetInt8(c *Instance, vr []ValueReference, value []int8) error {
+	return setValues(f, c, f.setInt8Ptr, "fmi3SetInt8", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int8, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetInt8(f.setInt8Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v int8) C.fmi3Int8 { return C.fmi3Int8(v) })
+}
+
+/* SetUInt8 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetUInt8(c *Instance, vr []ValueReference, value []uint8) error {
+	return setValues(f, c, f.setUInt8Ptr, "fmi3SetUInt8", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt8, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetUInt8(f.setUInt8Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v uint8) C.fmi3UInt8 { return C.fmi3UInt8(v) })
+}
+
+/* SetInt16 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetInt16(c *Instance, vr []ValueReference, value []int16) error {
+	return setValues(f, c, f.setInt16Ptr, "fmi3SetInt16", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int16, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetInt16(f.setInt16Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v int16) C.fmi3Int16 { return C.fmi3Int16(v) })
+}
+
+/* SetUInt16 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetUInt16(c *Instance, vr []ValueReference, value []uint16) error {
+	return setValues(f, c, f.setUInt16Ptr, "fmi3SetUInt16", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt16, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetUInt16(f.setUInt16Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v uint16) C.fmi3UInt16 { return C.fmi3UInt16(v) })
+}
+
+/* SetInt32 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetInt32(c *Instance, vr []ValueReference, value []int32) error {
+	return setValues(f, c, f.setInt32Ptr, "fmi3SetInt32", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int32, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetInt32(f.setInt32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v int32) C.fmi3Int32 { return C.fmi3Int32(v) })
+}
+
+/* SetUInt32 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetUInt32(c *Instance, vr []ValueReference, value []uint32) error {
+	return setValues(f, c, f.setUInt32Ptr, "fmi3SetUInt32", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt32, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetUInt32(f.setUInt32Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v uint32) C.fmi3UInt32 { return C.fmi3UInt32(v) })
+}
+
+/* SetInt64 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetInt64(c *Instance, vr []ValueReference, value []int64) error {
+	return setValues(f, c, f.setInt64Ptr, "fmi3SetInt64", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Int64, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetInt64(f.setInt64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v int64) C.fmi3Int64 { return C.fmi3Int64(v) })
+}
+
+/* SetUInt64 sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetUInt64(c *Instance, vr []ValueReference, value []uint64) error {
+	return setValues(f, c, f.setUInt64Ptr, "fmi3SetUInt64", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3UInt64, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetUInt64(f.setUInt64Ptr, c.instance, refs, nRefs, values, n)
+	}, func(v uint64) C.fmi3UInt64 { return C.fmi3UInt64(v) })
+}
+
+/* SetBoolean sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetBoolean(c *Instance, vr []ValueReference, value []bool) error {
+	return setValues(f, c, f.setBooleanPtr, "fmi3SetBoolean", vr, value, func(refs *C.fmi3ValueReference, nRefs C.size_t, values *C.fmi3Boolean, n C.size_t) C.fmi3Status {
+		return C.Fmi3SetBoolean(f.setBooleanPtr, c.instance, refs, nRefs, values, n)
+	}, func(v bool) C.fmi3Boolean { return C.fmi3Boolean(v) })
+}
+
+/* SetString sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetString(c *Instance, vr []ValueReference, value []string) error {
+
+	if err := f.available(c, f.setStringPtr, "fmi3SetString"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	vals := fmi.Transform(value, func(i int, v string) C.fmi3String { return C.CString(v) })
+
+	defer func() {
+		for _, v := range vals {
+			C.free(unsafe.Pointer(v))
+		}
+	}()
+
+	return f.check(c, "fmi3SetString", C.Fmi3SetString(f.setStringPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(vals), C.size_t(len(vals))))
+}
+
+/* SetBinary sets parameters, inputs, and start values, and re-initializes caching of variables that depend on these variables.
+ * value holds all elements of the variables, the elements of an array variable in row major order.
+ */
+func (f *Fmu3) SetBinary(c *Instance, vr []ValueReference, value [][]byte) error {
+
+	if err := f.available(c, f.setBinaryPtr, "fmi3SetBinary"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	sizes := fmi.Transform(value, func(i int, v []byte) C.size_t { return C.size_t(len(v)) })
+
+	// the array of pointers is passed to C, so the values must be copied to C memory
+	vals := fmi.Transform(value, func(i int, v []byte) C.fmi3Binary { return C.fmi3Binary(C.CBytes(v)) })
+
+	defer func() {
+		for _, v := range vals {
+			C.free(unsafe.Pointer(v))
+		}
+	}()
+
+	return f.check(c, "fmi3SetBinary", C.Fmi3SetBinary(f.setBinaryPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(sizes), fmi.First(vals), C.size_t(len(vals))))
+}
+
+/* SetClock activates (true) or deactivates (false) input clocks by providing their value references. */
+func (f *Fmu3) SetClock(c *Instance, vr []ValueReference, value []bool) error {
+
+	if err := f.available(c, f.setClockPtr, "fmi3SetClock"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	vals := fmi.Transform(value, func(i int, v bool) C.fmi3Clock { return C.fmi3Clock(v) })
+
+	return f.check(c, "fmi3SetClock", C.Fmi3SetClock(f.setClockPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(vals)))
+}
+
+/* GetVariableDependencies returns the dependencies of the variable dependent at the current state of the FMU.
+ * Unlike the ModelStructure of the model description, the dependencies can be resolved per element of array variables
+ * and may change after a change of the structural parameters.
+ */
+func (f *Fmu3) GetVariableDependencies(c *Instance, dependent ValueReference) ([]Dependency, error) {
+
+	if err := f.available(c, f.getNumberOfVariableDependenciesPtr, "fmi3GetNumberOfVariableDependencies"); err != nil {
+		return nil, err
+	}
+
+	if err := f.available(c, f.getVariableDependenciesPtr, "fmi3GetVariableDependencies"); err != nil {
+		return nil, err
+	}
+
+	var n C.size_t
+	if err := f.check(c, "fmi3GetNumberOfVariableDependencies", C.Fmi3GetNumberOfVariableDependencies(f.getNumberOfVariableDependenciesPtr, c.instance, C.fmi3ValueReference(dependent), &n)); err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return []Dependency{}, nil
+	}
+
+	elementIndicesOfDependent := make([]C.size_t, n)
+	independents := make([]C.fmi3ValueReference, n)
+	elementIndicesOfIndependents := make([]C.size_t, n)
+	kinds := make([]C.fmi3DependencyKind, n)
+
+	if err := f.check(c, "fmi3GetVariableDependencies", C.Fmi3GetVariableDependencies(f.getVariableDependenciesPtr, c.instance, C.fmi3ValueReference(dependent),
+		&elementIndicesOfDependent[0], &independents[0], &elementIndicesOfIndependents[0], &kinds[0], n)); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(kinds, func(i int, v C.fmi3DependencyKind) Dependency {
+		return Dependency{
+			ElementIndexOfDependent:   int(elementIndicesOfDependent[i]),
+			Independent:               ValueReference(independents[i]),
+			ElementIndexOfIndependent: int(elementIndicesOfIndependents[i]),
+			Kind:                      DependencyKind(v),
+		}
+	})
+
+	return result, nil
+}
+
+/* GetFMUState makes a copy of the internal FMU state and returns a pointer to this copy (FMUState). */
+func (f *Fmu3) GetFMUState(c *Instance) (FMUState, error) {
+
+	if err := f.available(c, f.getFMUStatePtr, "fmi3GetFMUState"); err != nil {
+		return FMUState{}, err
+	}
+
+	var state C.fmi3FMUState
+
+	if err := f.check(c, "fmi3GetFMUState", C.Fmi3GetFMUState(f.getFMUStatePtr, c.instance, &state)); err != nil {
+		return FMUState{}, err
+	}
+
+	return FMUState{unsafe.Pointer(state)}, nil
+}
+
+/* SetFMUState copies the content of the previously copied FMUState back and uses it as actual new FMU state.
+ * The FMUState copy still exists.
+ */
+func (f *Fmu3) SetFMUState(c *Instance, state FMUState) error {
+
+	if err := f.available(c, f.setFMUStatePtr, "fmi3SetFMUState"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3SetFMUState", C.Fmi3SetFMUState(f.setFMUStatePtr, c.instance, C.fmi3FMUState(state.state)))
+}
+
+/* FreeFMUState frees all memory and other resources allocated with the GetFMUState call for this FMUState.
+ * If the state is nil, the call is ignored. The state is set to nil.
+ */
+func (f *Fmu3) FreeFMUState(c *Instance, state *FMUState) error {
+
+	if err := f.available(c, f.freeFMUStatePtr, "fmi3FreeFMUState"); err != nil {
+		return err
+	}
+
+	s := C.fmi3FMUState(state.state)
+
+	if err := f.check(c, "fmi3FreeFMUState", C.Fmi3FreeFMUState(f.freeFMUStatePtr, c.instance, &s)); err != nil {
+		return err
+	}
+
+	state.state = unsafe.Pointer(s)
+	return nil
+}
+
+/* SerializeFMUState serializes the data which is referenced by FMUState and copies this data in to the returned byte slice. */
+func (f *Fmu3) SerializeFMUState(c *Instance, state FMUState) ([]byte, error) {
+
+	if err := f.available(c, f.serializedFMUStateSizePtr, "fmi3SerializedFMUStateSize"); err != nil {
+		return nil, err
+	}
+
+	if err := f.available(c, f.serializeFMUStatePtr, "fmi3SerializeFMUState"); err != nil {
+		return nil, err
+	}
+
+	var size C.size_t
+	if err := f.check(c, "fmi3SerializedFMUStateSize", C.Fmi3SerializedFMUStateSize(f.serializedFMUStateSizePtr, c.instance, C.fmi3FMUState(state.state), &size)); err != nil {
+		return nil, err
+	}
+
+	serialized := make([]C.fmi3Byte, int(size))
+
+	if err := f.check(c, "fmi3SerializeFMUState", C.Fmi3SerializeFMUState(f.serializeFMUStatePtr, c.instance, C.fmi3FMUState(state.state), fmi.First(serialized), size)); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(serialized, func(i int, v C.fmi3Byte) byte { return byte(v) }), nil
+}
+
+/* DeserializeFMUState deserializes the byte slice and constructs a copy of the FMU state. If state holds an FMU state,
+ * it is overwritten, otherwise a new FMU state is allocated. [The simulation is restarted at this state, when calling SetFMUState with it.]
+ */
+func (f *Fmu3) DeserializeFMUState(c *Instance, state *FMUState, serializedState []byte) error {
+
+	if err := f.available(c, f.deserializeFMUStatePtr, "fmi3DeserializeFMUState"); err != nil {
+		return err
+	}
+
+	serialized := fmi.Transform(serializedState, func(i int, v byte) C.fmi3Byte { return C.fmi3Byte(v) })
+	s := C.fmi3FMUState(state.state)
+
+	if err := f.check(c, "fmi3DeserializeFMUState", C.Fmi3DeserializeFMUState(f.deserializeFMUStatePtr, c.instance, fmi.First(serialized), C.size_t(len(serialized)), &s)); err != nil {
+		return err
+	}
+
+	state.state = unsafe.Pointer(s)
+	return nil
+}
+
+/* GetDirectionalDerivative computes the directional derivatives (the partial derivatives of the unknowns with respect
+ * to the knowns multiplied by the seed vector). nSensitivity is the total number of elements of the unknowns.
+ */
+func (f *Fmu3) GetDirectionalDerivative(c *Instance, unknowns []ValueReference, knowns []ValueReference, seed []float64, nSensitivity int) ([]float64, error) {
+	return f.derivative(c, f.getDirectionalDerivativePtr, "fmi3GetDirectionalDerivative", unknowns, knowns, seed, nSensitivity)
+}
+
+/* GetAdjointDerivative computes the adjoint derivatives (the transposed partial derivatives of the unknowns with respect
+ * to the knowns multiplied by the seed vector). nSensitivity is the total number of elements of the knowns.
+ */
+func (f *Fmu3) GetAdjointDerivative(c *Instance, unknowns []ValueReference, knowns []ValueReference, seed []float64, nSensitivity int) ([]float64, error) {
+	return f.derivative(c, f.getAdjointDerivativePtr, "fmi3GetAdjointDerivative", unknowns, knowns, seed, nSensitivity)
+}
+
+// derivative calls fmi3GetDirectionalDerivative or fmi3GetAdjointDerivative, which have the same signature
+func (f *Fmu3) derivative(c *Instance, ptr unsafe.Pointer, function string, unknowns []ValueReference, knowns []ValueReference, seed []float64, nSensitivity int) ([]float64, error) {
+
+	if err := f.available(c, ptr, function); err != nil {
+		return nil, err
+	}
+
+	unknownRefs := fmi.Transform(unknowns, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	knownRefs := fmi.Transform(knowns, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	seeds := fmi.Transform(seed, func(i int, v float64) C.fmi3Float64 { return C.fmi3Float64(v) })
+	sensitivity := make([]C.fmi3Float64, nSensitivity)
+
+	var status C.fmi3Status
+	if ptr == f.getAdjointDerivativePtr {
+		status = C.Fmi3GetAdjointDerivative(ptr, c.instance, fmi.First(unknownRefs), C.size_t(len(unknownRefs)), fmi.First(knownRefs), C.size_t(len(knownRefs)), fmi.First(seeds), C.size_t(len(seeds)), fmi.First(sensitivity), C.size_t(nSensitivity))
+	} else {
+		status = C.Fmi3GetDirectionalDerivative(ptr, c.instance, fmi.First(unknownRefs), C.size_t(len(unknownRefs)), fmi.First(knownRefs), C.size_t(len(knownRefs)), fmi.First(seeds), C.size_t(len(seeds)), fmi.First(sensitivity), C.size_t(nSensitivity))
+	}
+
+	if err := f.check(c, function, status); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(sensitivity, func(i int, v C.fmi3Float64) float64 { return float64(v) })
+	return result, nil
+}
+
+/* EnterConfigurationMode causes the FMU to enter Configuration Mode (from Instantiated) or Reconfiguration Mode
+ * (from Event Mode or Step Mode), in which the structural parameters with causality = "structuralParameter" can be changed.
+ */
+func (f *Fmu3) EnterConfigurationMode(c *Instance) error {
+	if err := f.available(c, f.enterConfigurationModePtr, "fmi3EnterConfigurationMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3EnterConfigurationMode", C.Fmi3EnterConfigurationMode(f.enterConfigurationModePtr, c.instance))
+}
+
+/* ExitConfigurationMode exits Configuration Mode or Reconfiguration Mode and returns to the mode it was entered from. */
+func (f *Fmu3) ExitConfigurationMode(c *Instance) error {
+	if err := f.available(c, f.exitConfigurationModePtr, "fmi3ExitConfigurationMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3ExitConfigurationMode", C.Fmi3ExitConfigurationMode(f.exitConfigurationModePtr, c.instance))
+}
+
+/* GetIntervalDecimal returns the intervals of the clocks and whether they are not yet known, unchanged or changed
+ * since the last call.
+ */
+func (f *Fmu3) GetIntervalDecimal(c *Instance, vr []ValueReference) ([]float64, []IntervalQualifier, error) {
+
+	if err := f.available(c, f.getIntervalDecimalPtr, "fmi3GetIntervalDecimal"); err != nil {
+		return nil, nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	intervals := make([]C.fmi3Float64, len(vr))
+	qualifiers := make([]C.fmi3IntervalQualifier, len(vr))
+
+	if err := f.check(c, "fmi3GetIntervalDecimal", C.Fmi3GetIntervalDecimal(f.getIntervalDecimalPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(intervals), fmi.First(qualifiers))); err != nil {
+		return nil, nil, err
+	}
+
+	return fmi.Transform(intervals, func(i int, v C.fmi3Float64) float64 { return float64(v) }),
+		fmi.Transform(qualifiers, func(i int, v C.fmi3IntervalQualifier) IntervalQualifier { return IntervalQualifier(v) }), nil
+}
+
+/* GetIntervalFraction returns the intervals of the clocks as counter / resolution and whether they are not yet known,
+ * unchanged or changed since the last call.
+ */
+func (f *Fmu3) GetIntervalFraction(c *Instance, vr []ValueReference) ([]uint64, []uint64, []IntervalQualifier, error) {
+
+	if err := f.available(c, f.getIntervalFractionPtr, "fmi3GetIntervalFraction"); err != nil {
+		return nil, nil, nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	counters := make([]C.fmi3UInt64, len(vr))
+	resolutions := make([]C.fmi3UInt64, len(vr))
+	qualifiers := make([]C.fmi3IntervalQualifier, len(vr))
+
+	if err := f.check(c, "fmi3GetIntervalFraction", C.Fmi3GetIntervalFraction(f.getIntervalFractionPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(counters), fmi.First(resolutions), fmi.First(qualifiers))); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fmi.Transform(counters, func(i int, v C.fmi3UInt64) uint64 { return uint64(v) }),
+		fmi.Transform(resolutions, func(i int, v C.fmi3UInt64) uint64 { return uint64(v) }),
+		fmi.Transform(qualifiers, func(i int, v C.fmi3IntervalQualifier) IntervalQualifier { return IntervalQualifier(v) }), nil
+}
+
+/* GetShiftDecimal returns the shifts of the clocks, which is the time of their first tick relative to the start time. */
+func (f *Fmu3) GetShiftDecimal(c *Instance, vr []ValueReference) ([]float64, error) {
+
+	if err := f.available(c, f.getShiftDecimalPtr, "fmi3GetShiftDecimal"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	shifts := make([]C.fmi3Float64, len(vr))
+
+	if err := f.check(c, "fmi3GetShiftDecimal", C.Fmi3GetShiftDecimal(f.getShiftDecimalPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(shifts))); err != nil {
+		return nil, err
+	}
+
+	return fmi.Transform(shifts, func(i int, v C.fmi3Float64) float64 { return float64(v) }), nil
+}
+
+/* GetShiftFraction returns the shifts of the clocks as counter / resolution. */
+func (f *Fmu3) GetShiftFraction(c *Instance, vr []ValueReference) ([]uint64, []uint64, error) {
+
+	if err := f.available(c, f.getShiftFractionPtr, "fmi3GetShiftFraction"); err != nil {
+		return nil, nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	counters := make([]C.fmi3UInt64, len(vr))
+	resolutions := make([]C.fmi3UInt64, len(vr))
+
+	if err := f.check(c, "fmi3GetShiftFraction", C.Fmi3GetShiftFraction(f.getShiftFractionPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(counters), fmi.First(resolutions))); err != nil {
+		return nil, nil, err
+	}
+
+	return fmi.Transform(counters, func(i int, v C.fmi3UInt64) uint64 { return uint64(v) }),
+		fmi.Transform(resolutions, func(i int, v C.fmi3UInt64) uint64 { return uint64(v) }), nil
+}
+
+/* SetIntervalDecimal sets the intervals of input clocks with intervalVariability = "tunable" or "changing". */
+func (f *Fmu3) SetIntervalDecimal(c *Instance, vr []ValueReference, intervals []float64) error {
+
+	if err := f.available(c, f.setIntervalDecimalPtr, "fmi3SetIntervalDecimal"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	values := fmi.Transform(intervals, func(i int, v float64) C.fmi3Float64 { return C.fmi3Float64(v) })
+
+	return f.check(c, "fmi3SetIntervalDecimal", C.Fmi3SetIntervalDecimal(f.setIntervalDecimalPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+/* SetIntervalFraction sets the intervals of input clocks as counter / resolution. */
+func (f *Fmu3) SetIntervalFraction(c *Instance, vr []ValueReference, counters []uint64, resolutions []uint64) error {
+
+	if err := f.available(c, f.setIntervalFractionPtr, "fmi3SetIntervalFraction"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	cs := fmi.Transform(counters, func(i int, v uint64) C.fmi3UInt64 { return C.fmi3UInt64(v) })
+	rs := fmi.Transform(resolutions, func(i int, v uint64) C.fmi3UInt64 { return C.fmi3UInt64(v) })
+
+	return f.check(c, "fmi3SetIntervalFraction", C.Fmi3SetIntervalFraction(f.setIntervalFractionPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(cs), fmi.First(rs)))
+}
+
+/* SetShiftDecimal sets the shifts of input clocks. */
+func (f *Fmu3) SetShiftDecimal(c *Instance, vr []ValueReference, shifts []float64) error {
+
+	if err := f.available(c, f.setShiftDecimalPtr, "fmi3SetShiftDecimal"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	values := fmi.Transform(shifts, func(i int, v float64) C.fmi3Float64 { return C.fmi3Float64(v) })
+
+	return f.check(c, "fmi3SetShiftDecimal", C.Fmi3SetShiftDecimal(f.setShiftDecimalPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(values)))
+}
+
+/* SetShiftFraction sets the shifts of input clocks as counter / resolution. */
+func (f *Fmu3) SetShiftFraction(c *Instance, vr []ValueReference, counters []uint64, resolutions []uint64) error {
+
+	if err := f.available(c, f.setShiftFractionPtr, "fmi3SetShiftFraction"); err != nil {
+		return err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	cs := fmi.Transform(counters, func(i int, v uint64) C.fmi3UInt64 { return C.fmi3UInt64(v) })
+	rs := fmi.Transform(resolutions, func(i int, v uint64) C.fmi3UInt64 { return C.fmi3UInt64(v) })
+
+	return f.check(c, "fmi3SetShiftFraction", C.Fmi3SetShiftFraction(f.setShiftFractionPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(cs), fmi.First(rs)))
+}
+
+/* EvaluateDiscreteStates evaluates the discrete states at the current time instant without incrementing the
+ * super dense time. It requires the capability flag providesEvaluateDiscreteStates.
+ */
+func (f *Fmu3) EvaluateDiscreteStates(c *Instance) error {
+	if err := f.available(c, f.evaluateDiscreteStatesPtr, "fmi3EvaluateDiscreteStates"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3EvaluateDiscreteStates", C.Fmi3EvaluateDiscreteStates(f.evaluateDiscreteStatesPtr, c.instance))
+}
+
+/* UpdateDiscreteStates is called in Event Mode and increments the super dense time.
+ * If DiscreteStatesNeedUpdate is true, the FMU should stay in Event Mode and UpdateDiscreteStates has to be called again.
+ */
+func (f *Fmu3) UpdateDiscreteStates(c *Instance) (*DiscreteStatesInfo, error) {
+
+	if err := f.available(c, f.updateDiscreteStatesPtr, "fmi3UpdateDiscreteStates"); err != nil {
+		return nil, err
+	}
+
+	var discreteStatesNeedUpdate, terminateSimulation, nominalsChanged, valuesChanged, nextEventTimeDefined C.fmi3Boolean
+	var nextEventTime C.fmi3Float64
+
+	if err := f.check(c, "fmi3UpdateDiscreteStates", C.Fmi3UpdateDiscreteStates(f.updateDiscreteStatesPtr, c.instance,
+		&discreteStatesNeedUpdate, &terminateSimulation, &nominalsChanged, &valuesChanged, &nextEventTimeDefined, &nextEventTime)); err != nil {
+		return nil, err
+	}
+
+	result := &DiscreteStatesInfo{
+		DiscreteStatesNeedUpdate:          bool(discreteStatesNeedUpdate),
+		TerminateSimulation:               bool(terminateSimulation),
+		NominalsOfContinuousStatesChanged: bool(nominalsChanged),
+		ValuesOfContinuousStatesChanged:   bool(valuesChanged),
+		NextEventTimeDefined:              bool(nextEventTimeDefined),
+		NextEventTime:                     float64(nextEventTime),
+	}
+
+	return result, nil
+}
+
+/* EnterContinuousTimeMode causes the model to enter Continuous-Time Mode and all discrete-time equations
+ * to become inactive and all relations to become "frozen".
+ */
+func (f *Fmu3) EnterContinuousTimeMode(c *Instance) error {
+	if err := f.available(c, f.enterContinuousTimeModePtr, "fmi3EnterContinuousTimeMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3EnterContinuousTimeMode", C.Fmi3EnterContinuousTimeMode(f.enterContinuousTimeModePtr, c.instance))
+}
+
+/* CompletedIntegratorStep must be called by the environment after every completed step of the integrator
+ * provided the capability flag needsCompletedIntegratorStep = true. It returns whether the FMU requests
+ * to enter Event Mode and to terminate the simulation.
+ */
+func (f *Fmu3) CompletedIntegratorStep(c *Instance, noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error) {
+
+	if err := f.available(c, f.completedIntegratorStepPtr, "fmi3CompletedIntegratorStep"); err != nil {
+		return false, false, err
+	}
+
+	var enterEventMode, terminateSimulation C.fmi3Boolean
+
+	if err := f.check(c, "fmi3CompletedIntegratorStep", C.Fmi3CompletedIntegratorStep(f.completedIntegratorStepPtr, c.instance, C.fmi3Boolean(noSetFMUStatePriorToCurrentPoint), &enterEventMode, &terminateSimulation)); err != nil {
+		return false, false, err
+	}
+
+	return bool(enterEventMode), bool(terminateSimulation), nil
+}
+
+/* SetTime sets a new time instant and re-initializes caching of variables that depend on time. */
+func (f *Fmu3) SetTime(c *Instance, time float64) error {
+	if err := f.available(c, f.setTimePtr, "fmi3SetTime"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3SetTime", C.Fmi3SetTime(f.setTimePtr, c.instance, C.fmi3Float64(time)))
+}
+
+/* SetContinuousStates sets a new (continuous) state vector and re-initializes caching of
+ * variables that depend on the states.
+ */
+func (f *Fmu3) SetContinuousStates(c *Instance, x []float64) error {
+
+	if err := f.available(c, f.setContinuousStatesPtr, "fmi3SetContinuousStates"); err != nil {
+		return err
+	}
+
+	if len(x) == 0 {
+		return nil
+	}
+
+	states := fmi.Transform(x, func(i int, v float64) C.fmi3Float64 { return C.fmi3Float64(v) })
+
+	return f.check(c, "fmi3SetContinuousStates", C.Fmi3SetContinuousStates(f.setContinuousStatesPtr, c.instance, &states[0], C.size_t(len(x))))
+}
+
+// getReals calls one of the Model Exchange functions that return nx values
+func (f *Fmu3) getReals(c *Instance, ptr unsafe.Pointer, function string, nx int, get func(values *C.fmi3Float64, n C.size_t) C.fmi3Status) ([]float64, error) {
+
+	if err := f.available(c, ptr, function); err != nil {
+		return nil, err
+	}
+
+	if nx == 0 {
+		return []float64{}, nil
+	}
+
+	values := make([]C.fmi3Float64, nx)
+
+	if err := f.check(c, function, get(&values[0], C.size_t(nx))); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(values, func(i int, v C.fmi3Float64) float64 { return float64(v) })
+	return result, nil
+}
+
+/* GetContinuousStateDerivatives computes the state derivatives at the current time instant and for the current states.
+ * The derivatives are returned as a slice with "nx" elements.
+ */
+func (f *Fmu3) GetContinuousStateDerivatives(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getContinuousStateDerivativesPtr, "fmi3GetContinuousStateDerivatives", nx, func(values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetContinuousStateDerivatives(f.getContinuousStateDerivativesPtr, c.instance, values, n)
+	})
+}
+
+/* GetEventIndicators computes event indicators at the current time instant and for the current states.
+ * The event indicators are returned as a slice with "ni" elements.
+ */
+func (f *Fmu3) GetEventIndicators(c *Instance, ni int) ([]float64, error) {
+	return f.getReals(c, f.getEventIndicatorsPtr, "fmi3GetEventIndicators", ni, func(values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetEventIndicators(f.getEventIndicatorsPtr, c.instance, values, n)
+	})
+}
+
+/* GetContinuousStates returns the (continuous) state vector x with "nx" elements. */
+func (f *Fmu3) GetContinuousStates(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getContinuousStatesPtr, "fmi3GetContinuousStates", nx, func(values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetContinuousStates(f.getContinuousStatesPtr, c.instance, values, n)
+	})
+}
+
+/* GetNominalsOfContinuousStates returns the nominal values of the continuous states.
+ * If the FMU does not have information about the nominal value of a continuous state, 1.0 is returned.
+ */
+func (f *Fmu3) GetNominalsOfContinuousStates(c *Instance, nx int) ([]float64, error) {
+	return f.getReals(c, f.getNominalsOfContinuousStatesPtr, "fmi3GetNominalsOfContinuousStates", nx, func(values *C.fmi3Float64, n C.size_t) C.fmi3Status {
+		return C.Fmi3GetNominalsOfContinuousStates(f.getNominalsOfContinuousStatesPtr, c.instance, values, n)
+	})
+}
+
+/* GetNumberOfEventIndicators returns the number of event indicators, which depends on the structural parameters. */
+func (f *Fmu3) GetNumberOfEventIndicators(c *Instance) (int, error) {
+
+	if err := f.available(c, f.getNumberOfEventIndicatorsPtr, "fmi3GetNumberOfEventIndicators"); err != nil {
+		return 0, err
+	}
+
+	var n C.size_t
+
+	if err := f.check(c, "fmi3GetNumberOfEventIndicators", C.Fmi3GetNumberOfEventIndicators(f.getNumberOfEventIndicatorsPtr, c.instance, &n)); err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+/* GetNumberOfContinuousStates returns the number of continuous states, which depends on the structural parameters. */
+func (f *Fmu3) GetNumberOfContinuousStates(c *Instance) (int, error) {
+
+	if err := f.available(c, f.getNumberOfContinuousStatesPtr, "fmi3GetNumberOfContinuousStates"); err != nil {
+		return 0, err
+	}
+
+	var n C.size_t
+
+	if err := f.check(c, "fmi3GetNumberOfContinuousStates", C.Fmi3GetNumberOfContinuousStates(f.getNumberOfContinuousStatesPtr, c.instance, &n)); err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+/* EnterStepMode causes a Co-Simulation FMU that was instantiated with WithEventMode to leave Event Mode and enter Step Mode. */
+func (f *Fmu3) EnterStepMode(c *Instance) error {
+	if err := f.available(c, f.enterStepModePtr, "fmi3EnterStepMode"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3EnterStepMode", C.Fmi3EnterStepMode(f.enterStepModePtr, c.instance))
+}
+
+/* GetOutputDerivatives retrieves the n-th derivative of output values.
+ * order contains the order of the respective derivative (1 means the first derivative, 0 is not allowed).
+ * nValues is the total number of elements of the variables, which is len(vr) if none of them is an array.
+ */
+func (f *Fmu3) GetOutputDerivatives(c *Instance, vr []ValueReference, order []int, nValues int) ([]float64, error) {
+
+	if err := f.available(c, f.getOutputDerivativesPtr, "fmi3GetOutputDerivatives"); err != nil {
+		return nil, err
+	}
+
+	refs := fmi.Transform(vr, func(i int, v ValueReference) C.fmi3ValueReference { return C.fmi3ValueReference(v) })
+	orders := fmi.Transform(order, func(i int, v int) C.fmi3Int32 { return C.fmi3Int32(v) })
+	values := make([]C.fmi3Float64, nValues)
+
+	if err := f.check(c, "fmi3GetOutputDerivatives", C.Fmi3GetOutputDerivatives(f.getOutputDerivativesPtr, c.instance, fmi.First(refs), C.size_t(len(refs)), fmi.First(orders), fmi.First(values), C.size_t(nValues))); err != nil {
+		return nil, err
+	}
+
+	result := fmi.Transform(values, func(i int, v C.fmi3Float64) float64 { return float64(v) })
+	return result, nil
+}
+
+/* DoStep causes the computation of a time step to be started. If the FMU returns early, the step was only computed
+ * until StepResult.LastSuccessfulTime.
+ */
+func (f *Fmu3) DoStep(c *Instance, currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) (*StepResult, error) {
+
+	if err := f.available(c, f.doStepPtr, "fmi3DoStep"); err != nil {
+		return nil, err
+	}
+
+	var eventHandlingNeeded, terminateSimulation, earlyReturn C.fmi3Boolean
+	var lastSuccessfulTime C.fmi3Float64
+
+	if err := f.check(c, "fmi3DoStep", C.Fmi3DoStep(f.doStepPtr, c.instance, C.fmi3Float64(currentCommunicationPoint), C.fmi3Float64(communicationStepSize), C.fmi3Boolean(noSetFMUStatePriorToCurrentPoint),
+		&eventHandlingNeeded, &terminateSimulation, &earlyReturn, &lastSuccessfulTime)); err != nil {
+		return nil, err
+	}
+
+	result := &StepResult{
+		EventHandlingNeeded: bool(eventHandlingNeeded),
+		TerminateSimulation: bool(terminateSimulation),
+		EarlyReturn:         bool(earlyReturn),
+		LastSuccessfulTime:  float64(lastSuccessfulTime),
+	}
+
+	return result, nil
+}
+
+/* ActivateModelPartition executes the model partition of a Scheduled Execution FMU that is associated with the
+ * input clock clockReference at activationTime.
+ */
+func (f *Fmu3) ActivateModelPartition(c *Instance, clockReference ValueReference, activationTime float64) error {
+	if err := f.available(c, f.activateModelPartitionPtr, "fmi3ActivateModelPartition"); err != nil {
+		return err
+	}
+
+	return f.check(c, "fmi3ActivateModelPartition", C.Fmi3ActivateModelPartition(f.activateModelPartitionPtr, c.instance, C.fmi3ValueReference(clockReference), C.fmi3Float64(activationTime)))
+}
+
+func resolveFunction(handle unsafe.Pointer, name string) unsafe.Pointer {
+	str := C.CString(name)
+	defer C.free(unsafe.Pointer(str))
+	return C.dlsym(handle, str)
+}
+
+// libraryPath returns the path of the shared library in the FMU that implements the interface type
+func libraryPath(md *ModelDescription, fmiType Type, machine Machine) (string, error) {
+
+	identifier, err := md.ModelIdentifier(fmiType)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join("binaries", machine.Platform, identifier+"."+machine.LibrarySuffix), nil
+}
+
+// selectLibrary reads the model description and returns the path of the shared library that implements fmiType
+func selectLibrary(fsys fs.FS, fmiType Type) (*ModelDescription, string, error) {
+
+	md, err := ReadModelDescriptionFS(fsys)
+	if err != nil {
+		return nil, "", err
+	}
+
+	library, err := libraryPath(md, fmiType, CurrentMachine())
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := fs.Stat(fsys, library); err != nil {
+		return nil, "", fmt.Errorf("the FMU contains no shared library for %s (tried %s, the FMU contains binaries for the platforms: %s)",
+			CurrentMachine().Platform, library, strings.Join(SupportedPlatformsFS(fsys), ", "))
+	}
+
+	return md, library, nil
+}
+
+// New loads the shared library of the FMU that implements the interface type fmiType.
+// The FMU is extracted into a temporary directory that is removed by Close.
+func New(filename string, fmiType Type) (*Fmu3, error) {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	md, library, err := selectLibrary(r, fmiType)
+	if err != nil {
+		return nil, err
+	}
+
+	directory, _, err := fmi.Extract(&r.Reader, fmi.ExtractOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	fmu, err := load(md, directory, library, fmiType)
+	if err != nil {
+		os.RemoveAll(directory)
+		return nil, err
+	}
+
+	fmu.ownsDirectory = true
+
+	return fmu, nil
+}
+
+// NewFromDirectory loads an FMU that has already been extracted into directory.
+// The directory is not removed by Close.
+func NewFromDirectory(directory string, fmiType Type) (*Fmu3, error) {
+
+	md, library, err := selectLibrary(os.DirFS(directory), fmiType)
+	if err != nil {
+		return nil, err
+	}
+
+	return load(md, directory, library, fmiType)
+}
+
+// load loads the shared library of the FMU that has been extracted into directory
+func load(md *ModelDescription, directory string, library string, fmiType Type) (*Fmu3, error) {
+
+	modulePath := filepath.Join(directory, filepath.FromSlash(library))
+	moduleString := C.CString(modulePath)
+	defer C.free(unsafe.Pointer(moduleString))
+
+	var loadError *C.char
+	handle := C.Fmi3OpenLibrary(moduleString, &loadError)
+	if handle == nil {
+		return nil, fmt.Errorf("error loading %s: %s", library, C.GoString(loadError))
+	}
+
+	// Common Functions
+	getVersionPtr := resolveFunction(handle, "fmi3GetVersion")
+	setDebugLoggingPtr := resolveFunction(handle, "fmi3SetDebugLogging")
+	instantiateModelExchangePtr := resolveFunction(handle, "fmi3InstantiateModelExchange")
+	instantiateCoSimulationPtr := resolveFunction(handle, "fmi3InstantiateCoSimulation")
+	instantiateScheduledExecutionPtr := resolveFunction(handle, "fmi3InstantiateScheduledExecution")
+	freeInstancePtr := resolveFunction(handle, "fmi3FreeInstance")
+	enterInitializationModePtr := resolveFunction(handle, "fmi3EnterInitializationMode")
+	exitInitializationModePtr := resolveFunction(handle, "fmi3ExitInitializationMode")
+	enterEventModePtr := resolveFunction(handle, "fmi3EnterEventMode")
+	terminatePtr := resolveFunction(handle, "fmi3Terminate")
+	resetPtr := resolveFunction(handle, "fmi3Reset")
+	getFloat32Ptr := resolveFunction(handle, "fmi3GetFloat32")
+	getFloat64Ptr := resolveFunction(handle, "fmi3GetFloat64")
+	getInt8Ptr := resolveFunction(handle, "fmi3GetInt8")
+	getUInt8Ptr := resolveFunction(handle, "fmi3GetUInt8")
+	getInt16Ptr := resolveFunction(handle, "fmi3GetInt16")
+	getUInt16Ptr := resolveFunction(handle, "fmi3GetUInt16")
+	getInt32Ptr := resolveFunction(handle, "fmi3GetInt32")
+	getUInt32Ptr := resolveFunction(handle, "fmi3GetUInt32")
+	getInt64Ptr := resolveFunction(handle, "fmi3GetInt64")
+	getUInt64Ptr := resolveFunction(handle, "fmi3GetUInt64")
+	getBooleanPtr := resolveFunction(handle, "fmi3GetBoolean")
+	getStringPtr := resolveFunction(handle, "fmi3GetString")
+	getBinaryPtr := resolveFunction(handle, "fmi3GetBinary")
+	getClockPtr := resolveFunction(handle, "fmi3GetClock")
+	setFloat32Ptr := resolveFunction(handle, "fmi3SetFloat32")
+	setFloat64Ptr := resolveFunction(handle, "fmi3SetFloat64")
+	setInt8Ptr := resolveFunction(handle, "fmi3SetInt8")
+	setUInt8Ptr := resolveFunction(handle, "fmi3SetUInt8")
+	setInt16Ptr := resolveFunction(handle, "fmi3SetInt16")
+	setUInt16Ptr := resolveFunction(handle, "fmi3SetUInt16")
+	setInt32Ptr := resolveFunction(handle, "fmi3SetInt32")
+	setUInt32Ptr := resolveFunction(handle, "fmi3SetUInt32")
+	setInt64Ptr := resolveFunction(handle, "fmi3SetInt64")
+	setUInt64Ptr := resolveFunction(handle, "fmi3SetUInt64")
+	setBooleanPtr := resolveFunction(handle, "fmi3SetBoolean")
+	setStringPtr := resolveFunction(handle, "fmi3SetString")
+	setBinaryPtr := resolveFunction(handle, "fmi3SetBinary")
+	setClockPtr := resolveFunction(handle, "fmi3SetClock")
+	getNumberOfVariableDependenciesPtr := resolveFunction(handle, "fmi3GetNumberOfVariableDependencies")
+	getVariableDependenciesPtr := resolveFunction(handle, "fmi3GetVariableDependencies")
+	getFMUStatePtr := resolveFunction(handle, "fmi3GetFMUState")
+	setFMUStatePtr := resolveFunction(handle, "fmi3SetFMUState")
+	freeFMUStatePtr := resolveFunction(handle, "fmi3FreeFMUState")
+	serializedFMUStateSizePtr := resolveFunction(handle, "fmi3SerializedFMUStateSize")
+	serializeFMUStatePtr := resolveFunction(handle, "fmi3SerializeFMUState")
+	deserializeFMUStatePtr := resolveFunction(handle, "fmi3DeserializeFMUState")
+	getDirectionalDerivativePtr := resolveFunction(handle, "fmi3GetDirectionalDerivative")
+	getAdjointDerivativePtr := resolveFunction(handle, "fmi3GetAdjointDerivative")
+	enterConfigurationModePtr := resolveFunction(handle, "fmi3EnterConfigurationMode")
+	exitConfigurationModePtr := resolveFunction(handle, "fmi3ExitConfigurationMode")
+	getIntervalDecimalPtr := resolveFunction(handle, "fmi3GetIntervalDecimal")
+	getIntervalFractionPtr := resolveFunction(handle, "fmi3GetIntervalFraction")
+	getShiftDecimalPtr := resolveFunction(handle, "fmi3GetShiftDecimal")
+	getShiftFractionPtr := resolveFunction(handle, "fmi3GetShiftFraction")
+	setIntervalDecimalPtr := resolveFunction(handle, "fmi3SetIntervalDecimal")
+	setIntervalFractionPtr := resolveFunction(handle, "fmi3SetIntervalFraction")
+	setShiftDecimalPtr := resolveFunction(handle, "fmi3SetShiftDecimal")
+	setShiftFractionPtr := resolveFunction(handle, "fmi3SetShiftFraction")
+	evaluateDiscreteStatesPtr := resolveFunction(handle, "fmi3EvaluateDiscreteStates")
+	updateDiscreteStatesPtr := resolveFunction(handle, "fmi3UpdateDiscreteStates")
+
+	// Functions for Model Exchange
+	enterContinuousTimeModePtr := resolveFunction(handle, "fmi3EnterContinuousTimeMode")
+	completedIntegratorStepPtr := resolveFunction(handle, "fmi3CompletedIntegratorStep")
+	setTimePtr := resolveFunction(handle, "fmi3SetTime")
+	setContinuousStatesPtr := resolveFunction(handle, "fmi3SetContinuousStates")
+	getContinuousStateDerivativesPtr := resolveFunction(handle, "fmi3GetContinuousStateDerivatives")
+	getEventIndicatorsPtr := resolveFunction(handle, "fmi3GetEventIndicators")
+	getContinuousStatesPtr := resolveFunction(handle, "fmi3GetContinuousStates")
+	getNominalsOfContinuousStatesPtr := resolveFunction(handle, "fmi3GetNominalsOfContinuousStates")
+	getNumberOfEventIndicatorsPtr := resolveFunction(handle, "fmi3GetNumberOfEventIndicators")
+	getNumberOfContinuousStatesPtr := resolveFunction(handle, "fmi3GetNumberOfContinuousStates")
+
+	// Functions for Co-Simulation
+	enterStepModePtr := resolveFunction(handle, "fmi3EnterStepMode")
+	getOutputDerivativesPtr := resolveFunction(handle, "fmi3GetOutputDerivatives")
+	doStepPtr := resolveFunction(handle, "fmi3DoStep")
+
+	// Functions for Scheduled Execution
+	activateModelPartitionPtr := resolveFunction(handle, "fmi3ActivateModelPartition")
+	fmu := &Fmu3{
+		Directory:    directory,
+		moduleHandle: handle,
+
+		// Common Functions
+		getVersionPtr:                      getVersionPtr,
+		setDebugLoggingPtr:                 setDebugLoggingPtr,
+		instantiateModelExchangePtr:        instantiateModelExchangePtr,
+		instantiateCoSimulationPtr:         instantiateCoSimulationPtr,
+		instantiateScheduledExecutionPtr:   instantiateScheduledExecutionPtr,
+		freeInstancePtr:                    freeInstancePtr,
+		enterInitializationModePtr:         enterInitializationModePtr,
+		exitInitializationModePtr:          exitInitializationModePtr,
+		enterEventModePtr:                  enterEventModePtr,
+		terminatePtr:                       terminatePtr,
+		resetPtr:                           resetPtr,
+		getFloat32Ptr:                      getFloat32Ptr,
+		getFloat64Ptr:                      getFloat64Ptr,
+		getInt8Ptr:                         getInt8Ptr,
+		getUInt8Ptr:                        getUInt8Ptr,
+		getInt16Ptr:                        getInt16Ptr,
+		getUInt16Ptr:                       getUInt16Ptr,
+		getInt32Ptr:                        getInt32Ptr,
+		getUInt32Ptr:                       getUInt32Ptr,
+		getInt64Ptr:                        getInt64Ptr,
+		getUInt64Ptr:                       getUInt64Ptr,
+		getBooleanPtr:                      getBooleanPtr,
+		getStringPtr:                       getStringPtr,
+		getBinaryPtr:                       getBinaryPtr,
+		getClockPtr:                        getClockPtr,
+		setFloat32Ptr:                      setFloat32Ptr,
+		setFloat64Ptr:                      setFloat64Ptr,
+		setInt8Ptr:                         setInt8Ptr,
+		setUInt8Ptr:                        setUInt8Ptr,
+		setInt16Ptr:                        setInt16Ptr,
+		setUInt16Ptr:                       setUInt16Ptr,
+		setInt32Ptr:                        setInt32Ptr,
+		setUInt32Ptr:                       setUInt32Ptr,
+		setInt64Ptr:                        setInt64Ptr,
+		setUInt64Ptr:                       setUInt64Ptr,
+		setBooleanPtr:                      setBooleanPtr,
+		setStringPtr:                       setStringPtr,
+		setBinaryPtr:                       setBinaryPtr,
+		setClockPtr:                        setClockPtr,
+		getNumberOfVariableDependenciesPtr: getNumberOfVariableDependenciesPtr,
+		getVariableDependenciesPtr:         getVariableDependenciesPtr,
+		getFMUStatePtr:                     getFMUStatePtr,
+		setFMUStatePtr:                     setFMUStatePtr,
+		freeFMUStatePtr:                    freeFMUStatePtr,
+		serializedFMUStateSizePtr:          serializedFMUStateSizePtr,
+		serializeFMUStatePtr:               serializeFMUStatePtr,
+		deserializeFMUStatePtr:             deserializeFMUStatePtr,
+		getDirectionalDerivativePtr:        getDirectionalDerivativePtr,
+		getAdjointDerivativePtr:            getAdjointDerivativePtr,
+		enterConfigurationModePtr:          enterConfigurationModePtr,
+		exitConfigurationModePtr:           exitConfigurationModePtr,
+		getIntervalDecimalPtr:              getIntervalDecimalPtr,
+		getIntervalFractionPtr:             getIntervalFractionPtr,
+		getShiftDecimalPtr:                 getShiftDecimalPtr,
+		getShiftFractionPtr:                getShiftFractionPtr,
+		setIntervalDecimalPtr:              setIntervalDecimalPtr,
+		setIntervalFractionPtr:             setIntervalFractionPtr,
+		setShiftDecimalPtr:                 setShiftDecimalPtr,
+		setShiftFractionPtr:                setShiftFractionPtr,
+		evaluateDiscreteStatesPtr:          evaluateDiscreteStatesPtr,
+		updateDiscreteStatesPtr:            updateDiscreteStatesPtr,
+
+		// Functions for Model Exchange
+		enterContinuousTimeModePtr:       enterContinuousTimeModePtr,
+		completedIntegratorStepPtr:       completedIntegratorStepPtr,
+		setTimePtr:                       setTimePtr,
+		setContinuousStatesPtr:           setContinuousStatesPtr,
+		getContinuousStateDerivativesPtr: getContinuousStateDerivativesPtr,
+		getEventIndicatorsPtr:            getEventIndicatorsPtr,
+		getContinuousStatesPtr:           getContinuousStatesPtr,
+		getNominalsOfContinuousStatesPtr: getNominalsOfContinuousStatesPtr,
+		getNumberOfEventIndicatorsPtr:    getNumberOfEventIndicatorsPtr,
+		getNumberOfContinuousStatesPtr:   getNumberOfContinuousStatesPtr,
+
+		// Functions for Co-Simulation
+		enterStepModePtr:        enterStepModePtr,
+		getOutputDerivativesPtr: getOutputDerivativesPtr,
+		doStepPtr:               doStepPtr,
+
+		// Functions for Scheduled Execution
+		activateModelPartitionPtr: activateModelPartitionPtr,
+	}
+
+	fmu.modelDescription = md
+	fmu.variables = md.variablesByName()
+
+	if err := fmu.checkFunctions(md, fmiType); err != nil {
+		fmu.Close()
+		return nil, err
+	}
+
+	runtime.SetFinalizer(fmu, (*Fmu3).Close)
+
+	return fmu, nil
+}
+
+// commonFunctions returns the functions that every FMU must export. The functions to get and set clocks,
+// the FMU state and the derivatives depend on clocks or capability flags and are optional.
+func (f *Fmu3) commonFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi3GetVersion":              f.getVersionPtr,
+		"fmi3SetDebugLogging":         f.setDebugLoggingPtr,
+		"fmi3FreeInstance":            f.freeInstancePtr,
+		"fmi3EnterInitializationMode": f.enterInitializationModePtr,
+		"fmi3ExitInitializationMode":  f.exitInitializationModePtr,
+		"fmi3EnterEventMode":          f.enterEventModePtr,
+		"fmi3Terminate":               f.terminatePtr,
+		"fmi3Reset":                   f.resetPtr,
+		"fmi3GetFloat32":              f.getFloat32Ptr,
+		"fmi3GetFloat64":              f.getFloat64Ptr,
+		"fmi3GetInt8":                 f.getInt8Ptr,
+		"fmi3GetUInt8":                f.getUInt8Ptr,
+		"fmi3GetInt16":                f.getInt16Ptr,
+		"fmi3GetUInt16":               f.getUInt16Ptr,
+		"fmi3GetInt32":                f.getInt32Ptr,
+		"fmi3GetUInt32":               f.getUInt32Ptr,
+		"fmi3GetInt64":                f.getInt64Ptr,
+		"fmi3GetUInt64":               f.getUInt64Ptr,
+		"fmi3GetBoolean":              f.getBooleanPtr,
+		"fmi3GetString":               f.getStringPtr,
+		"fmi3GetBinary":               f.getBinaryPtr,
+		"fmi3SetFloat32":              f.setFloat32Ptr,
+		"fmi3SetFloat64":              f.setFloat64Ptr,
+		"fmi3SetInt8":                 f.setInt8Ptr,
+		"fmi3SetUInt8":                f.setUInt8Ptr,
+		"fmi3SetInt16":                f.setInt16Ptr,
+		"fmi3SetUInt16":               f.setUInt16Ptr,
+		"fmi3SetInt32":                f.setInt32Ptr,
+		"fmi3SetUInt32":               f.setUInt32Ptr,
+		"fmi3SetInt64":                f.setInt64Ptr,
+		"fmi3SetUInt64":               f.setUInt64Ptr,
+		"fmi3SetBoolean":              f.setBooleanPtr,
+		"fmi3SetString":               f.setStringPtr,
+		"fmi3SetBinary":               f.setBinaryPtr,
+		"fmi3UpdateDiscreteStates":    f.updateDiscreteStatesPtr,
+	}
+}
+
+// modelExchangeFunctions returns the functions that an FMU for Model Exchange must export
+func (f *Fmu3) modelExchangeFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi3InstantiateModelExchange":      f.instantiateModelExchangePtr,
+		"fmi3EnterContinuousTimeMode":       f.enterContinuousTimeModePtr,
+		"fmi3CompletedIntegratorStep":       f.completedIntegratorStepPtr,
+		"fmi3SetTime":                       f.setTimePtr,
+		"fmi3SetContinuousStates":           f.setContinuousStatesPtr,
+		"fmi3GetContinuousStateDerivatives": f.getContinuousStateDerivativesPtr,
+		"fmi3GetEventIndicators":            f.getEventIndicatorsPtr,
+		"fmi3GetContinuousStates":           f.getContinuousStatesPtr,
+		"fmi3GetNominalsOfContinuousStates": f.getNominalsOfContinuousStatesPtr,
+		"fmi3GetNumberOfEventIndicators":    f.getNumberOfEventIndicatorsPtr,
+		"fmi3GetNumberOfContinuousStates":   f.getNumberOfContinuousStatesPtr,
+	}
+}
+
+// coSimulationFunctions returns the functions that an FMU for Co-Simulation must export
+func (f *Fmu3) coSimulationFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi3InstantiateCoSimulation": f.instantiateCoSimulationPtr,
+		"fmi3EnterStepMode":           f.enterStepModePtr,
+		"fmi3GetOutputDerivatives":    f.getOutputDerivativesPtr,
+		"fmi3DoStep":                  f.doStepPtr,
+	}
+}
+
+// scheduledExecutionFunctions returns the functions that an FMU for Scheduled Execution must export
+func (f *Fmu3) scheduledExecutionFunctions() map[string]unsafe.Pointer {
+	return map[string]unsafe.Pointer{
+		"fmi3InstantiateScheduledExecution": f.instantiateScheduledExecutionPtr,
+		"fmi3ActivateModelPartition":        f.activateModelPartitionPtr,
+		"fmi3GetClock":                      f.getClockPtr,
+		"fmi3SetClock":                      f.setClockPtr,
+	}
+}
+
+// checkFunctions returns an error if the library does not export all common functions and the functions
+// of the interface types it implements. Besides fmiType the library implements every interface type
+// that the model description declares with the same model identifier.
+func (f *Fmu3) checkFunctions(md *ModelDescription, fmiType Type) error {
+
+	identifier, err := md.ModelIdentifier(fmiType)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+
+	collect := func(functions map[string]unsafe.Pointer) {
+		for name, ptr := range functions {
+			if ptr == nil {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	collect(f.commonFunctions())
+
+	for _, t := range md.InterfaceTypes() {
+		if id, _ := md.ModelIdentifier(t); id != identifier {
+			continue
+		}
+
+		switch t {
+		case ModelExchangeType:
+			collect(f.modelExchangeFunctions())
+		case CoSimulationType:
+			collect(f.coSimulationFunctions())
+		case ScheduledExecutionType:
+			collect(f.scheduledExecutionFunctions())
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the shared library of %s does not export the mandatory functions: %s", identifier, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// Extract extracts the FMU into a new temporary directory
+func Extract(filename string) (string, error) {
+	return fmi.ExtractFile(filename)
+}
@@ -0,0 +1,93 @@
+
+#ifndef CORE_H_
+#define CORE_H_
+
+#include "headers/fmi3Functions.h"
+#include <dlfcn.h>
+#include <stdint.h>
+#include <stdlib.h>
+
+extern void LogMessage(fmi3InstanceEnvironment instanceEnvironment, fmi3Status status, fmi3String category, fmi3String message);
+extern void IntermediateUpdate(fmi3InstanceEnvironment instanceEnvironment, fmi3Float64 intermediateUpdateTime, fmi3Boolean intermediateVariableSetRequested, fmi3Boolean intermediateVariableGetAllowed, fmi3Boolean intermediateStepFinished, fmi3Boolean canReturnEarly, fmi3Boolean *earlyReturnRequested, fmi3Float64 *earlyReturnTime);
+extern void ClockUpdate(fmi3InstanceEnvironment instanceEnvironment);
+extern void LockPreemption(void);
+extern void UnlockPreemption(void);
+// the functions are prefixed with Fmi3 because the C symbols of all FMI versions are linked into the same binary
+extern void *Fmi3OpenLibrary(const char *filename, const char **error);
+extern fmi3Instance Fmi3InstantiateModelExchange(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, uintptr_t environment);
+extern fmi3Instance Fmi3InstantiateCoSimulation(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, fmi3Boolean eventModeUsed, fmi3Boolean earlyReturnAllowed, const fmi3ValueReference requiredIntermediateVariables[], size_t nRequiredIntermediateVariables, uintptr_t environment, fmi3Boolean intermediateUpdate);
+extern fmi3Instance Fmi3InstantiateScheduledExecution(void *f, fmi3String instanceName, fmi3String instantiationToken, fmi3String resourcePath, fmi3Boolean visible, fmi3Boolean loggingOn, uintptr_t environment);
+extern const char *Fmi3GetVersion(void *f);
+extern fmi3Status Fmi3SetDebugLogging(void *f, fmi3Instance instance, fmi3Boolean loggingOn, size_t nCategories, const fmi3String categories[]);
+extern void Fmi3FreeInstance(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3EnterInitializationMode(void *f, fmi3Instance instance, fmi3Boolean toleranceDefined, fmi3Float64 tolerance, fmi3Float64 startTime, fmi3Boolean stopTimeDefined, fmi3Float64 stopTime);
+extern fmi3Status Fmi3ExitInitializationMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3EnterEventMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3Terminate(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3Reset(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3GetFloat32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float32 values[], size_t nValues);
+extern fmi3Status Fmi3GetFloat64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 values[], size_t nValues);
+extern fmi3Status Fmi3GetInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int8 values[], size_t nValues);
+extern fmi3Status Fmi3GetUInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt8 values[], size_t nValues);
+extern fmi3Status Fmi3GetInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int16 values[], size_t nValues);
+extern fmi3Status Fmi3GetUInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt16 values[], size_t nValues);
+extern fmi3Status Fmi3GetInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int32 values[], size_t nValues);
+extern fmi3Status Fmi3GetUInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt32 values[], size_t nValues);
+extern fmi3Status Fmi3GetInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Int64 values[], size_t nValues);
+extern fmi3Status Fmi3GetUInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 values[], size_t nValues);
+extern fmi3Status Fmi3GetBoolean(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Boolean values[], size_t nValues);
+extern fmi3Status Fmi3GetString(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3String values[], size_t nValues);
+extern fmi3Status Fmi3GetBinary(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, size_t valueSizes[], fmi3Binary values[], size_t nValues);
+extern fmi3Status Fmi3GetClock(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Clock values[]);
+extern fmi3Status Fmi3SetFloat32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float32 values[], size_t nValues);
+extern fmi3Status Fmi3SetFloat64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 values[], size_t nValues);
+extern fmi3Status Fmi3SetInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int8 values[], size_t nValues);
+extern fmi3Status Fmi3SetUInt8(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt8 values[], size_t nValues);
+extern fmi3Status Fmi3SetInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int16 values[], size_t nValues);
+extern fmi3Status Fmi3SetUInt16(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt16 values[], size_t nValues);
+extern fmi3Status Fmi3SetInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 values[], size_t nValues);
+extern fmi3Status Fmi3SetUInt32(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt32 values[], size_t nValues);
+extern fmi3Status Fmi3SetInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int64 values[], size_t nValues);
+extern fmi3Status Fmi3SetUInt64(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 values[], size_t nValues);
+extern fmi3Status Fmi3SetBoolean(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Boolean values[], size_t nValues);
+extern fmi3Status Fmi3SetString(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3String values[], size_t nValues);
+extern fmi3Status Fmi3SetBinary(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const size_t valueSizes[], const fmi3Binary values[], size_t nValues);
+extern fmi3Status Fmi3SetClock(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Clock values[]);
+extern fmi3Status Fmi3GetNumberOfVariableDependencies(void *f, fmi3Instance instance, fmi3ValueReference valueReference, size_t* nDependencies);
+extern fmi3Status Fmi3GetVariableDependencies(void *f, fmi3Instance instance, fmi3ValueReference dependent, size_t elementIndicesOfDependent[], fmi3ValueReference independents[], size_t elementIndicesOfIndependents[], fmi3DependencyKind dependencyKinds[], size_t nDependencies);
+extern fmi3Status Fmi3GetFMUState(void *f, fmi3Instance instance, fmi3FMUState* FMUState);
+extern fmi3Status Fmi3SetFMUState(void *f, fmi3Instance instance, fmi3FMUState FMUState);
+extern fmi3Status Fmi3FreeFMUState(void *f, fmi3Instance instance, fmi3FMUState* FMUState);
+extern fmi3Status Fmi3SerializedFMUStateSize(void *f, fmi3Instance instance, fmi3FMUState FMUState, size_t* size);
+extern fmi3Status Fmi3SerializeFMUState(void *f, fmi3Instance instance, fmi3FMUState FMUState, fmi3Byte serializedState[], size_t size);
+extern fmi3Status Fmi3DeserializeFMUState(void *f, fmi3Instance instance, const fmi3Byte serializedState[], size_t size, fmi3FMUState* FMUState);
+extern fmi3Status Fmi3GetDirectionalDerivative(void *f, fmi3Instance instance, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[], size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity);
+extern fmi3Status Fmi3GetAdjointDerivative(void *f, fmi3Instance instance, const fmi3ValueReference unknowns[], size_t nUnknowns, const fmi3ValueReference knowns[], size_t nKnowns, const fmi3Float64 seed[], size_t nSeed, fmi3Float64 sensitivity[], size_t nSensitivity);
+extern fmi3Status Fmi3EnterConfigurationMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3ExitConfigurationMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3GetIntervalDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 intervals[], fmi3IntervalQualifier qualifiers[]);
+extern fmi3Status Fmi3GetIntervalFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[], fmi3UInt64 resolutions[], fmi3IntervalQualifier qualifiers[]);
+extern fmi3Status Fmi3GetShiftDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3Float64 shifts[]);
+extern fmi3Status Fmi3GetShiftFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, fmi3UInt64 counters[], fmi3UInt64 resolutions[]);
+extern fmi3Status Fmi3SetIntervalDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 intervals[]);
+extern fmi3Status Fmi3SetIntervalFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[], const fmi3UInt64 resolutions[]);
+extern fmi3Status Fmi3SetShiftDecimal(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Float64 shifts[]);
+extern fmi3Status Fmi3SetShiftFraction(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3UInt64 counters[], const fmi3UInt64 resolutions[]);
+extern fmi3Status Fmi3EvaluateDiscreteStates(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3UpdateDiscreteStates(void *f, fmi3Instance instance, fmi3Boolean* discreteStatesNeedUpdate, fmi3Boolean* terminateSimulation, fmi3Boolean* nominalsOfContinuousStatesChanged, fmi3Boolean* valuesOfContinuousStatesChanged, fmi3Boolean* nextEventTimeDefined, fmi3Float64* nextEventTime);
+extern fmi3Status Fmi3EnterContinuousTimeMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3CompletedIntegratorStep(void *f, fmi3Instance instance, fmi3Boolean noSetFMUStatePriorToCurrentPoint, fmi3Boolean* enterEventMode, fmi3Boolean* terminateSimulation);
+extern fmi3Status Fmi3SetTime(void *f, fmi3Instance instance, fmi3Float64 time);
+extern fmi3Status Fmi3SetContinuousStates(void *f, fmi3Instance instance, const fmi3Float64 continuousStates[], size_t nContinuousStates);
+extern fmi3Status Fmi3GetContinuousStateDerivatives(void *f, fmi3Instance instance, fmi3Float64 derivatives[], size_t nContinuousStates);
+extern fmi3Status Fmi3GetEventIndicators(void *f, fmi3Instance instance, fmi3Float64 eventIndicators[], size_t nEventIndicators);
+extern fmi3Status Fmi3GetContinuousStates(void *f, fmi3Instance instance, fmi3Float64 continuousStates[], size_t nContinuousStates);
+extern fmi3Status Fmi3GetNominalsOfContinuousStates(void *f, fmi3Instance instance, fmi3Float64 nominals[], size_t nContinuousStates);
+extern fmi3Status Fmi3GetNumberOfEventIndicators(void *f, fmi3Instance instance, size_t* nEventIndicators);
+extern fmi3Status Fmi3GetNumberOfContinuousStates(void *f, fmi3Instance instance, size_t* nContinuousStates);
+extern fmi3Status Fmi3EnterStepMode(void *f, fmi3Instance instance);
+extern fmi3Status Fmi3GetOutputDerivatives(void *f, fmi3Instance instance, const fmi3ValueReference valueReferences[], size_t nValueReferences, const fmi3Int32 orders[], fmi3Float64 values[], size_t nValues);
+extern fmi3Status Fmi3DoStep(void *f, fmi3Instance instance, fmi3Float64 currentCommunicationPoint, fmi3Float64 communicationStepSize, fmi3Boolean noSetFMUStatePriorToCurrentPoint, fmi3Boolean* eventHandlingNeeded, fmi3Boolean* terminateSimulation, fmi3Boolean* earlyReturn, fmi3Float64* lastSuccessfulTime);
+extern fmi3Status Fmi3ActivateModelPartition(void *f, fmi3Instance instance, fmi3ValueReference clockReference, fmi3Float64 activationTime);
+
+#endif
@@ -0,0 +1,387 @@
+package fmi3_test
+
+import (
+	"go-fmu/pkg/fmi3"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	feedthroughToken = "{37b954f1-7a0b-4c3a-9a5d-3f7e2f1c8d21}"
+
+	vrFloat32Input  fmi3.ValueReference = 1
+	vrFloat32Output fmi3.ValueReference = 2
+	vrFloat64Input  fmi3.ValueReference = 3
+	vrFloat64Output fmi3.ValueReference = 4
+	vrInt8Output    fmi3.ValueReference = 6
+	vrUInt8Output   fmi3.ValueReference = 8
+	vrInt16Output   fmi3.ValueReference = 10
+	vrUInt16Output  fmi3.ValueReference = 12
+	vrInt32Output   fmi3.ValueReference = 14
+	vrUInt32Output  fmi3.ValueReference = 16
+	vrInt64Output   fmi3.ValueReference = 18
+	vrUInt64Output  fmi3.ValueReference = 20
+	vrBooleanOutput fmi3.ValueReference = 22
+	vrStringOutput  fmi3.ValueReference = 24
+	vrBinaryInput   fmi3.ValueReference = 25
+	vrBinaryOutput  fmi3.ValueReference = 26
+	vrX             fmi3.ValueReference = 27
+	vrTicks         fmi3.ValueReference = 29
+	vrInClock       fmi3.ValueReference = 30
+	vrCounter       fmi3.ValueReference = 31
+	vrOutClock      fmi3.ValueReference = 32
+)
+
+func newFeedthrough(t *testing.T, fmiType fmi3.Type) *fmi3.Fmu3 {
+
+	fmu, err := fmi3.New("../../examples/Feedthrough.fmu", fmiType)
+	require.NoError(t, err)
+	t.Cleanup(func() { fmu.Close() })
+
+	return fmu
+}
+
+// requireStatus asserts that err is the StatusError of a function that returned status
+func requireStatus(t *testing.T, err error, status fmi3.Status) {
+	var statusError *fmi3.StatusError
+	require.ErrorAs(t, err, &statusError)
+	require.Equal(t, status, statusError.Status)
+}
+
+func TestModelExchange(t *testing.T) {
+
+	fmu := newFeedthrough(t, fmi3.ModelExchangeType)
+
+	require.Equal(t, "3.0", fmu.GetVersion())
+
+	c := fmu.InstantiateModelExchange("feedthrough", feedthroughToken, fmu.ResourcePath(), false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.Equal(t, fmi3.ModelExchangeType, c.Type())
+
+	// the instance cannot be created with a wrong token
+	require.Nil(t, fmu.InstantiateModelExchange("wrong", "{}", fmu.ResourcePath(), false, false, fmi3.WithLogger(func(string, fmi3.Status, string, string) {})))
+
+	require.NoError(t, c.EnterInitializationMode(0, fmi3.WithStopTime(2)))
+
+	// every type is passed from the input to the output
+	require.NoError(t, c.SetFloat32([]fmi3.ValueReference{vrFloat32Input}, []float32{1.5}))
+	require.NoError(t, c.SetFloat64([]fmi3.ValueReference{vrFloat64Input}, []float64{2}))
+	require.NoError(t, c.SetInt8([]fmi3.ValueReference{5}, []int8{-128}))
+	require.NoError(t, c.SetUInt8([]fmi3.ValueReference{7}, []uint8{255}))
+	require.NoError(t, c.SetInt16([]fmi3.ValueReference{9}, []int16{-32768}))
+	require.NoError(t, c.SetUInt16([]fmi3.ValueReference{11}, []uint16{65535}))
+	require.NoError(t, c.SetInt32([]fmi3.ValueReference{13}, []int32{-2147483648}))
+	require.NoError(t, c.SetUInt32([]fmi3.ValueReference{15}, []uint32{4294967295}))
+	require.NoError(t, c.SetInt64([]fmi3.ValueReference{17}, []int64{-9223372036854775808}))
+	require.NoError(t, c.SetUInt64([]fmi3.ValueReference{19}, []uint64{18446744073709551615}))
+	require.NoError(t, c.SetBoolean([]fmi3.ValueReference{21}, []bool{true}))
+	require.NoError(t, c.SetString([]fmi3.ValueReference{23}, []string{"äöü"}))
+	require.NoError(t, c.SetBinary([]fmi3.ValueReference{vrBinaryInput}, [][]byte{{0x00, 0xff, 0x10}}))
+
+	require.NoError(t, c.ExitInitializationMode())
+
+	float32s, err := c.GetFloat32([]fmi3.ValueReference{vrFloat32Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []float32{1.5}, float32s)
+
+	float64s, err := c.GetFloat64([]fmi3.ValueReference{vrFloat64Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []float64{2}, float64s)
+
+	int8s, err := c.GetInt8([]fmi3.ValueReference{vrInt8Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int8{-128}, int8s)
+
+	uint8s, err := c.GetUInt8([]fmi3.ValueReference{vrUInt8Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint8{255}, uint8s)
+
+	int16s, err := c.GetInt16([]fmi3.ValueReference{vrInt16Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int16{-32768}, int16s)
+
+	uint16s, err := c.GetUInt16([]fmi3.ValueReference{vrUInt16Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint16{65535}, uint16s)
+
+	int32s, err := c.GetInt32([]fmi3.ValueReference{vrInt32Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int32{-2147483648}, int32s)
+
+	uint32s, err := c.GetUInt32([]fmi3.ValueReference{vrUInt32Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{4294967295}, uint32s)
+
+	int64s, err := c.GetInt64([]fmi3.ValueReference{vrInt64Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int64{-9223372036854775808}, int64s)
+
+	uint64s, err := c.GetUInt64([]fmi3.ValueReference{vrUInt64Output}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{18446744073709551615}, uint64s)
+
+	booleans, err := c.GetBoolean([]fmi3.ValueReference{vrBooleanOutput}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, booleans)
+
+	strings, err := c.GetString([]fmi3.ValueReference{vrStringOutput}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"äöü"}, strings)
+
+	// an invalid value reference is reported by the FMU
+	_, err = c.GetFloat64([]fmi3.ValueReference{1000}, 1)
+	requireStatus(t, err, fmi3.Error)
+
+	// the instance is in Event Mode after the initialization
+	info, err := c.UpdateDiscreteStates()
+	require.NoError(t, err)
+	require.False(t, info.DiscreteStatesNeedUpdate)
+	require.True(t, info.NextEventTimeDefined)
+	require.InDelta(t, 0.5, info.NextEventTime, 1e-9)
+
+	require.NoError(t, c.EnterContinuousTimeMode())
+
+	nx, err := c.GetNumberOfContinuousStates()
+	require.NoError(t, err)
+	require.Equal(t, 1, nx)
+
+	ni, err := c.GetNumberOfEventIndicators()
+	require.NoError(t, err)
+	require.Equal(t, 0, ni)
+
+	// integrate x with the explicit Euler method to the time event
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, c.SetTime(float64(i)*0.1))
+
+		x, err := c.GetContinuousStates(nx)
+		require.NoError(t, err)
+
+		dx, err := c.GetContinuousStateDerivatives(nx)
+		require.NoError(t, err)
+		require.Equal(t, []float64{2}, dx)
+
+		require.NoError(t, c.SetContinuousStates([]float64{x[0] + 0.1*dx[0]}))
+
+		enterEventMode, terminateSimulation, err := c.CompletedIntegratorStep(true)
+		require.NoError(t, err)
+		require.False(t, enterEventMode)
+		require.False(t, terminateSimulation)
+	}
+
+	x, err := c.GetFloat64([]fmi3.ValueReference{vrX}, 1)
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, x[0], 1e-9)
+
+	// the time event and an activation of the input clock are handled in the same event iteration
+	require.NoError(t, c.EnterEventMode())
+	require.NoError(t, c.SetClock([]fmi3.ValueReference{vrInClock}, []bool{true}))
+
+	clocks, err := c.GetClock([]fmi3.ValueReference{vrInClock})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true}, clocks)
+
+	info, err = c.UpdateDiscreteStates()
+	require.NoError(t, err)
+	require.InDelta(t, 1.0, info.NextEventTime, 1e-9)
+
+	discrete, err := c.GetInt32([]fmi3.ValueReference{vrTicks, vrCounter}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []int32{1, 1}, discrete)
+
+	require.NoError(t, c.EnterContinuousTimeMode())
+
+	// the FMU does not implement the FMU state
+	_, err = c.GetFMUState()
+	requireStatus(t, err, fmi3.Error)
+
+	require.NoError(t, c.Terminate())
+	require.NoError(t, c.Reset())
+
+	discrete, err = c.GetInt32([]fmi3.ValueReference{vrTicks, vrCounter}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []int32{0, 0}, discrete)
+}
+
+func TestBinary(t *testing.T) {
+
+	fmu := newFeedthrough(t, fmi3.CoSimulationType)
+
+	c := fmu.InstantiateCoSimulation("feedthrough", feedthroughToken, fmu.ResourcePath(), false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.NoError(t, c.EnterInitializationMode(0))
+	require.NoError(t, c.ExitInitializationMode())
+
+	// the start value
+	values, err := c.GetBinary([]fmi3.ValueReference{vrBinaryInput, vrBinaryOutput}, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("foo"), []byte("foo")}, values)
+
+	// the FMU frees the buffer that GetBinary returned when the value is set again, so the values
+	// must have been copied
+	input := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	require.NoError(t, c.SetBinary([]fmi3.ValueReference{vrBinaryInput}, [][]byte{input}))
+	require.Equal(t, []byte("foo"), values[0])
+
+	// the FMU copied the value that was passed to SetBinary
+	input[0] = 0
+
+	values, err = c.GetBinary([]fmi3.ValueReference{vrBinaryOutput}, 1)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1, 2, 3, 4, 5, 6, 7, 8}}, values)
+
+	require.NoError(t, c.SetBinary([]fmi3.ValueReference{vrBinaryInput}, [][]byte{{}}))
+
+	values, err = c.GetBinary([]fmi3.ValueReference{vrBinaryOutput}, 1)
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	require.Empty(t, values[0])
+
+	// the output cannot be set
+	requireStatus(t, c.SetBinary([]fmi3.ValueReference{vrBinaryOutput}, [][]byte{{1}}), fmi3.Error)
+}
+
+func TestCoSimulation(t *testing.T) {
+
+	fmu := newFeedthrough(t, fmi3.CoSimulationType)
+
+	c := fmu.InstantiateCoSimulation("feedthrough", feedthroughToken, fmu.ResourcePath(), false, false)
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.Equal(t, fmi3.CoSimulationType, c.Type())
+
+	require.NoError(t, c.EnterInitializationMode(0))
+	require.NoError(t, c.SetFloat64([]fmi3.ValueReference{vrFloat64Input}, []float64{1}))
+	require.NoError(t, c.ExitInitializationMode())
+
+	time := 0.0
+	for range 12 {
+		result, err := c.DoStep(time, 0.1, true)
+		require.NoError(t, err)
+		require.False(t, result.EarlyReturn)
+		require.False(t, result.TerminateSimulation)
+		time += 0.1
+		require.InDelta(t, time, result.LastSuccessfulTime, 1e-9)
+	}
+
+	values, err := c.GetFloat64([]fmi3.ValueReference{vrX}, 1)
+	require.NoError(t, err)
+	require.InDelta(t, 1.2, values[0], 1e-9)
+
+	ticks, err := c.GetInt32([]fmi3.ValueReference{vrTicks}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int32{2}, ticks)
+
+	derivatives, err := c.GetOutputDerivatives([]fmi3.ValueReference{vrX}, []int{1}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1}, derivatives)
+
+	require.NoError(t, c.Terminate())
+}
+
+func TestEarlyReturn(t *testing.T) {
+
+	fmu := newFeedthrough(t, fmi3.CoSimulationType)
+
+	var updates []fmi3.IntermediateUpdateInfo
+
+	// the step returns at the first time event that allows an early return
+	intermediateUpdate := func(instance *fmi3.Instance, info fmi3.IntermediateUpdateInfo) (bool, float64) {
+		updates = append(updates, info)
+
+		ticks, err := instance.GetInt32([]fmi3.ValueReference{vrTicks}, 1)
+		require.NoError(t, err)
+		require.Equal(t, int32(len(updates)), ticks[0])
+
+		return true, info.Time
+	}
+
+	c := fmu.InstantiateCoSimulation("feedthrough", feedthroughToken, fmu.ResourcePath(), false, false,
+		fmi3.WithEarlyReturn(), fmi3.WithIntermediateUpdate(intermediateUpdate))
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.NoError(t, c.EnterInitializationMode(0))
+	require.NoError(t, c.ExitInitializationMode())
+
+	result, err := c.DoStep(0, 2, true)
+	require.NoError(t, err)
+	require.True(t, result.EarlyReturn)
+	require.InDelta(t, 0.5, result.LastSuccessfulTime, 1e-9)
+
+	require.Len(t, updates, 1)
+	require.InDelta(t, 0.5, updates[0].Time, 1e-9)
+	require.True(t, updates[0].CanReturnEarly)
+	require.True(t, updates[0].VariableGetAllowed)
+	require.True(t, updates[0].StepFinished)
+
+	// the time event at the end of the step does not allow an early return
+	result, err = c.DoStep(0.5, 0.5, true)
+	require.NoError(t, err)
+	require.False(t, result.EarlyReturn)
+	require.InDelta(t, 1.0, result.LastSuccessfulTime, 1e-9)
+
+	require.Len(t, updates, 2)
+	require.False(t, updates[1].CanReturnEarly)
+}
+
+func TestScheduledExecution(t *testing.T) {
+
+	fmu := newFeedthrough(t, fmi3.ScheduledExecutionType)
+
+	var ticks []float64
+
+	var c *fmi3.Instance
+
+	// the output clock is read in the callback
+	clockUpdate := func(instance *fmi3.Instance) {
+		require.Same(t, c, instance)
+
+		clocks, err := instance.GetClock([]fmi3.ValueReference{vrOutClock})
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, clocks)
+
+		time, err := instance.GetFloat64([]fmi3.ValueReference{0}, 1)
+		require.NoError(t, err)
+		ticks = append(ticks, time[0])
+	}
+
+	c = fmu.InstantiateScheduledExecution("feedthrough", feedthroughToken, fmu.ResourcePath(), false, false, fmi3.WithClockUpdate(clockUpdate))
+	require.NotNil(t, c)
+	defer c.FreeInstance()
+
+	require.Equal(t, fmi3.ScheduledExecutionType, c.Type())
+
+	require.NoError(t, c.EnterInitializationMode(0))
+	require.NoError(t, c.ExitInitializationMode())
+
+	intervals, qualifiers, err := fmu.GetIntervalDecimal(c, []fmi3.ValueReference{vrInClock})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1}, intervals)
+	require.Equal(t, []fmi3.IntervalQualifier{fmi3.IntervalUnchanged}, qualifiers)
+
+	for i := 1; i <= 7; i++ {
+		require.NoError(t, c.ActivateModelPartition(vrInClock, float64(i)*0.1))
+	}
+
+	counter, err := c.GetInt32([]fmi3.ValueReference{vrCounter}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []int32{7}, counter)
+
+	// outClock ticks with every third activation and is deactivated when it has been read
+	require.Len(t, ticks, 2)
+	require.InDelta(t, 0.3, ticks[0], 1e-9)
+	require.InDelta(t, 0.6, ticks[1], 1e-9)
+
+	clocks, err := c.GetClock([]fmi3.ValueReference{vrOutClock})
+	require.NoError(t, err)
+	require.Equal(t, []bool{false}, clocks)
+
+	// only input clocks have a model partition
+	requireStatus(t, c.ActivateModelPartition(vrOutClock, 0.8), fmi3.Error)
+}
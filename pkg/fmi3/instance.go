@@ -0,0 +1,319 @@
+package fmi3
+
+/*
+#include <stdlib.h>
+#include "headers/fmi3Functions.h"
+*/
+import "C"
+
+import (
+	"runtime/cgo"
+	"unsafe"
+)
+
+type Instance struct {
+	fmu      *Fmu3
+	instance C.fmi3Instance
+	fmuType  Type
+	fatal    bool // the instance returned Fatal and must not be called anymore
+	handle   cgo.Handle
+
+	requiredIntermediateVariables *C.fmi3ValueReference // C memory that the FMU may reference until it is freed
+}
+
+// Type returns the interface type the instance was instantiated as
+func (c *Instance) Type() Type {
+	return c.fmuType
+}
+
+// release frees the required intermediate variables and the environment of the instance
+func (c *Instance) release() {
+	if c.requiredIntermediateVariables != nil {
+		C.free(unsafe.Pointer(c.requiredIntermediateVariables))
+		c.requiredIntermediateVariables = nil
+	}
+
+	if c.handle != 0 {
+		c.handle.Delete()
+		c.handle = 0
+	}
+}
+
+/* SetDebugLogging controls the debug logging that is output via the logMessage callback function by the FMU.
+ * If len(categories) == 0, loggingOn applies to all log categories.
+ */
+func (c *Instance) SetDebugLogging(loggingOn bool, categories []string) error {
+	return c.fmu.SetDebugLogging(c, loggingOn, categories)
+}
+
+/* EnterInitializationMode informs the FMU to enter Initialization Mode. */
+func (c *Instance) EnterInitializationMode(startTime float64, opts ...InitializationOption) error {
+	return c.fmu.EnterInitializationMode(c, startTime, opts...)
+}
+
+/* ExitInitializationMode informs the FMU to exit Initialization Mode. */
+func (c *Instance) ExitInitializationMode() error {
+	return c.fmu.ExitInitializationMode(c)
+}
+
+/* EnterEventMode causes the FMU to enter Event Mode. */
+func (c *Instance) EnterEventMode() error {
+	return c.fmu.EnterEventMode(c)
+}
+
+/* Terminate informs the FMU that the simulation run is terminated. */
+func (c *Instance) Terminate() error {
+	return c.fmu.Terminate(c)
+}
+
+/* Reset resets the FMU after a simulation run. */
+func (c *Instance) Reset() error {
+	return c.fmu.Reset(c)
+}
+
+func (c *Instance) GetFloat32(vr []ValueReference, nValues int) ([]float32, error) {
+	return c.fmu.GetFloat32(c, vr, nValues)
+}
+
+func (c *Instance) GetFloat64(vr []ValueReference, nValues int) ([]float64, error) {
+	return c.fmu.GetFloat64(c, vr, nValues)
+}
+
+func (c *Instance) GetInt8(vr []ValueReference, nValues int) ([]int8, error) {
+	return c.fmu.GetInt8(c, vr, nValues)
+}
+
+func (c *Instance) GetUInt8(vr []ValueReference, nValues int) ([]uint8, error) {
+	return c.fmu.GetUInt8(c, vr, nValues)
+}
+
+func (c *Instance) GetInt16(vr []ValueReference, nValues int) ([]int16, error) {
+	return c.fmu.GetInt16(c, vr, nValues)
+}
+
+func (c *Instance) GetUInt16(vr []ValueReference, nValues int) ([]uint16, error) {
+	return c.fmu.GetUInt16(c, vr, nValues)
+}
+
+func (c *Instance) GetInt32(vr []ValueReference, nValues int) ([]int32, error) {
+	return c.fmu.GetInt32(c, vr, nValues)
+}
+
+func (c *Instance) GetUInt32(vr []ValueReference, nValues int) ([]uint32, error) {
+	return c.fmu.GetUInt32(c, vr, nValues)
+}
+
+func (c *Instance) GetInt64(vr []ValueReference, nValues int) ([]int64, error) {
+	return c.fmu.GetInt64(c, vr, nValues)
+}
+
+func (c *Instance) GetUInt64(vr []ValueReference, nValues int) ([]uint64, error) {
+	return c.fmu.GetUInt64(c, vr, nValues)
+}
+
+func (c *Instance) GetBoolean(vr []ValueReference, nValues int) ([]bool, error) {
+	return c.fmu.GetBoolean(c, vr, nValues)
+}
+
+func (c *Instance) GetString(vr []ValueReference, nValues int) ([]string, error) {
+	return c.fmu.GetString(c, vr, nValues)
+}
+
+func (c *Instance) GetBinary(vr []ValueReference, nValues int) ([][]byte, error) {
+	return c.fmu.GetBinary(c, vr, nValues)
+}
+
+func (c *Instance) GetClock(vr []ValueReference) ([]bool, error) {
+	return c.fmu.GetClock(c, vr)
+}
+
+func (c *Instance) SetFloat32(vr []ValueReference, value []float32) error {
+	return c.fmu.SetFloat32(c, vr, value)
+}
+
+func (c *Instance) SetFloat64(vr []ValueReference, value []float64) error {
+	return c.fmu.SetFloat64(c, vr, value)
+}
+
+func (c *Instance) SetInt8(vr []ValueReference, value []int8) error {
+	return c.fmu.SetInt8(c, vr, value)
+}
+
+func (c *Instance) SetUInt8(vr []ValueReference, value []uint8) error {
+	return c.fmu.SetUInt8(c, vr, value)
+}
+
+func (c *Instance) SetInt16(vr []ValueReference, value []int16) error {
+	return c.fmu.SetInt16(c, vr, value)
+}
+
+func (c *Instance) SetUInt16(vr []ValueReference, value []uint16) error {
+	return c.fmu.SetUInt16(c, vr, value)
+}
+
+func (c *Instance) SetInt32(vr []ValueReference, value []int32) error {
+	return c.fmu.SetInt32(c, vr, value)
+}
+
+func (c *Instance) SetUInt32(vr []ValueReference, value []uint32) error {
+	return c.fmu.SetUInt32(c, vr, value)
+}
+
+func (c *Instance) SetInt64(vr []ValueReference, value []int64) error {
+	return c.fmu.SetInt64(c, vr, value)
+}
+
+func (c *Instance) SetUInt64(vr []ValueReference, value []uint64) error {
+	return c.fmu.SetUInt64(c, vr, value)
+}
+
+func (c *Instance) SetBoolean(vr []ValueReference, value []bool) error {
+	return c.fmu.SetBoolean(c, vr, value)
+}
+
+func (c *Instance) SetString(vr []ValueReference, value []string) error {
+	return c.fmu.SetString(c, vr, value)
+}
+
+func (c *Instance) SetBinary(vr []ValueReference, value [][]byte) error {
+	return c.fmu.SetBinary(c, vr, value)
+}
+
+func (c *Instance) SetClock(vr []ValueReference, value []bool) error {
+	return c.fmu.SetClock(c, vr, value)
+}
+
+/* GetFMUState makes a copy of the internal FMU state and returns a pointer to this copy (FMUState). */
+func (c *Instance) GetFMUState() (FMUState, error) {
+	return c.fmu.GetFMUState(c)
+}
+
+/* SetFMUState copies the content of the previously copied FMUState back and uses it as actual new FMU state. */
+func (c *Instance) SetFMUState(state FMUState) error {
+	return c.fmu.SetFMUState(c, state)
+}
+
+/* FreeFMUState frees all memory and other resources allocated with the GetFMUState call for this FMUState. */
+func (c *Instance) FreeFMUState(state *FMUState) error {
+	return c.fmu.FreeFMUState(c, state)
+}
+
+/* SerializeFMUState serializes the data which is referenced by FMUState. */
+func (c *Instance) SerializeFMUState(state FMUState) ([]byte, error) {
+	return c.fmu.SerializeFMUState(c, state)
+}
+
+/* DeserializeFMUState deserializes the byte slice and constructs a copy of the FMU state. */
+func (c *Instance) DeserializeFMUState(state *FMUState, serializedState []byte) error {
+	return c.fmu.DeserializeFMUState(c, state, serializedState)
+}
+
+/* GetVariableDependencies returns the dependencies of the variable dependent at the current state of the FMU. */
+func (c *Instance) GetVariableDependencies(dependent ValueReference) ([]Dependency, error) {
+	return c.fmu.GetVariableDependencies(c, dependent)
+}
+
+/* GetDirectionalDerivative computes the directional derivatives. */
+func (c *Instance) GetDirectionalDerivative(unknowns []ValueReference, knowns []ValueReference, seed []float64, nSensitivity int) ([]float64, error) {
+	return c.fmu.GetDirectionalDerivative(c, unknowns, knowns, seed, nSensitivity)
+}
+
+/* GetAdjointDerivative computes the adjoint derivatives. */
+func (c *Instance) GetAdjointDerivative(unknowns []ValueReference, knowns []ValueReference, seed []float64, nSensitivity int) ([]float64, error) {
+	return c.fmu.GetAdjointDerivative(c, unknowns, knowns, seed, nSensitivity)
+}
+
+/* EnterConfigurationMode causes the FMU to enter Configuration Mode or Reconfiguration Mode. */
+func (c *Instance) EnterConfigurationMode() error {
+	return c.fmu.EnterConfigurationMode(c)
+}
+
+/* ExitConfigurationMode exits Configuration Mode or Reconfiguration Mode. */
+func (c *Instance) ExitConfigurationMode() error {
+	return c.fmu.ExitConfigurationMode(c)
+}
+
+/* EvaluateDiscreteStates evaluates the discrete states at the current time instant. */
+func (c *Instance) EvaluateDiscreteStates() error {
+	return c.fmu.EvaluateDiscreteStates(c)
+}
+
+/* UpdateDiscreteStates is called in Event Mode and increments the super dense time. */
+func (c *Instance) UpdateDiscreteStates() (*DiscreteStatesInfo, error) {
+	return c.fmu.UpdateDiscreteStates(c)
+}
+
+/* EnterContinuousTimeMode causes the model to enter Continuous-Time Mode. */
+func (c *Instance) EnterContinuousTimeMode() error {
+	return c.fmu.EnterContinuousTimeMode(c)
+}
+
+/* CompletedIntegratorStep must be called by the environment after every completed step of the integrator. */
+func (c *Instance) CompletedIntegratorStep(noSetFMUStatePriorToCurrentPoint bool) (bool, bool, error) {
+	return c.fmu.CompletedIntegratorStep(c, noSetFMUStatePriorToCurrentPoint)
+}
+
+/* SetTime sets a new time instant. */
+func (c *Instance) SetTime(time float64) error {
+	return c.fmu.SetTime(c, time)
+}
+
+/* SetContinuousStates sets a new (continuous) state vector. */
+func (c *Instance) SetContinuousStates(x []float64) error {
+	return c.fmu.SetContinuousStates(c, x)
+}
+
+/* GetContinuousStateDerivatives computes the state derivatives at the current time instant and for the current states. */
+func (c *Instance) GetContinuousStateDerivatives(nx int) ([]float64, error) {
+	return c.fmu.GetContinuousStateDerivatives(c, nx)
+}
+
+/* GetEventIndicators computes event indicators at the current time instant and for the current states. */
+func (c *Instance) GetEventIndicators(ni int) ([]float64, error) {
+	return c.fmu.GetEventIndicators(c, ni)
+}
+
+/* GetContinuousStates returns the (continuous) state vector. */
+func (c *Instance) GetContinuousStates(nx int) ([]float64, error) {
+	return c.fmu.GetContinuousStates(c, nx)
+}
+
+/* GetNominalsOfContinuousStates returns the nominal values of the continuous states. */
+func (c *Instance) GetNominalsOfContinuousStates(nx int) ([]float64, error) {
+	return c.fmu.GetNominalsOfContinuousStates(c, nx)
+}
+
+/* GetNumberOfEventIndicators returns the number of event indicators. */
+func (c *Instance) GetNumberOfEventIndicators() (int, error) {
+	return c.fmu.GetNumberOfEventIndicators(c)
+}
+
+/* GetNumberOfContinuousStates returns the number of continuous states. */
+func (c *Instance) GetNumberOfContinuousStates() (int, error) {
+	return c.fmu.GetNumberOfContinuousStates(c)
+}
+
+/* EnterStepMode causes a Co-Simulation FMU to leave Event Mode and enter Step Mode. */
+func (c *Instance) EnterStepMode() error {
+	return c.fmu.EnterStepMode(c)
+}
+
+/* GetOutputDerivatives retrieves the n-th derivative of output values. */
+func (c *Instance) GetOutputDerivatives(vr []ValueReference, order []int, nValues int) ([]float64, error) {
+	return c.fmu.GetOutputDerivatives(c, vr, order, nValues)
+}
+
+/* DoStep causes the computation of a time step to be started. */
+func (c *Instance) DoStep(currentCommunicationPoint float64, communicationStepSize float64, noSetFMUStatePriorToCurrentPoint bool) (*StepResult, error) {
+	return c.fmu.DoStep(c, currentCommunicationPoint, communicationStepSize, noSetFMUStatePriorToCurrentPoint)
+}
+
+/* ActivateModelPartition executes the model partition that is associated with the input clock. */
+func (c *Instance) ActivateModelPartition(clockReference ValueReference, activationTime float64) error {
+	return c.fmu.ActivateModelPartition(c, clockReference, activationTime)
+}
+
+/* FreeInstance disposes the instance and frees all the allocated memory and other resources. */
+func (c *Instance) FreeInstance() {
+	c.fmu.FreeInstance(c)
+}
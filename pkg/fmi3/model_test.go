@@ -0,0 +1,150 @@
+package fmi3_test
+
+import (
+	"go-fmu/pkg/fmi3"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const modelDescription = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiModelDescription fmiVersion="3.0" modelName="Clocked" instantiationToken="{8c4e810f-3df3-4a00-8276-176fa3c9f000}" variableNamingConvention="structured">
+  <ModelExchange modelIdentifier="Clocked" canGetAndSetFMUState="true"/>
+  <CoSimulation modelIdentifier="Clocked" hasEventMode="true" canReturnEarlyAfterIntermediateUpdate="true"/>
+  <ScheduledExecution modelIdentifier="Clocked"/>
+  <UnitDefinitions>
+    <Unit name="m"><BaseUnit m="1"/></Unit>
+  </UnitDefinitions>
+  <TypeDefinitions>
+    <Float64Type name="Position" quantity="Length" unit="m"/>
+    <EnumerationType name="Mode">
+      <Item name="off" value="0"/>
+      <Item name="on" value="1"/>
+    </EnumerationType>
+  </TypeDefinitions>
+  <LogCategories>
+    <Category name="logEvents"/>
+  </LogCategories>
+  <DefaultExperiment startTime="0" stopTime="3" stepSize="0.01"/>
+  <ModelVariables>
+    <Float64 name="time" valueReference="0" causality="independent" variability="continuous"/>
+    <Clock name="inClock" valueReference="1" causality="input" intervalVariability="constant" intervalDecimal="0.5"/>
+    <UInt8 name="n" valueReference="2" causality="structuralParameter" variability="tunable" start="3"/>
+    <Float64 name="x" valueReference="3" declaredType="Position" causality="output" start="1 2 3" initial="exact">
+      <Dimension valueReference="2"/>
+    </Float64>
+    <Float64 name="der(x)" valueReference="4" derivative="3">
+      <Dimension valueReference="2"/>
+    </Float64>
+    <Int16 name="counter" valueReference="5" causality="output" variability="discrete" clocks="1" initial="exact" start="0"/>
+    <Enumeration name="mode" valueReference="6" declaredType="Mode" causality="parameter" variability="fixed" start="1"/>
+    <String name="labels" valueReference="7" causality="parameter" variability="fixed">
+      <Dimension start="2"/>
+      <Start value="a"/>
+      <Start value="b"/>
+    </String>
+    <Binary name="blob" valueReference="8" causality="parameter" variability="fixed" mimeType="application/octet-stream">
+      <Start value="00ff"/>
+    </Binary>
+  </ModelVariables>
+  <ModelStructure>
+    <Output valueReference="3" dependencies="2" dependenciesKind="constant"/>
+    <Output valueReference="5"/>
+    <ContinuousStateDerivative valueReference="4"/>
+    <ClockedState valueReference="5"/>
+    <InitialUnknown valueReference="3"/>
+  </ModelStructure>
+</fmiModelDescription>
+`
+
+const terminalsAndIcons = `<?xml version="1.0" encoding="UTF-8"?>
+<fmiTerminalsAndIcons fmiVersion="3.0">
+  <Terminals>
+    <Terminal name="flange" matchingRule="plug" terminalKind="org.modelica.mechanics.translational">
+      <TerminalMemberVariable variableName="x" memberName="s" variableKind="signal"/>
+    </Terminal>
+  </Terminals>
+</fmiTerminalsAndIcons>
+`
+
+func TestReadModelDescriptionFS(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml":                    {Data: []byte(modelDescription)},
+		"binaries/x86_64-linux/Clocked.so":        {},
+		"binaries/x86_64-windows/Clocked.dll":     {},
+		"binaries/x86_64-linux/README.txt":        {},
+		"sources/buildDescription.xml":            {},
+		"terminalsAndIcons/terminalsAndIcons.xml": {Data: []byte(terminalsAndIcons)},
+	}
+
+	md, err := fmi3.ReadModelDescriptionFS(fsys)
+
+	require.NoError(t, err)
+	require.Equal(t, "3.0", md.FmiVersion)
+	require.Equal(t, "{8c4e810f-3df3-4a00-8276-176fa3c9f000}", md.InstantiationToken)
+	require.Equal(t, []fmi3.Type{fmi3.ModelExchangeType, fmi3.CoSimulationType, fmi3.ScheduledExecutionType}, md.InterfaceTypes())
+	require.True(t, md.CoSimulation.HasEventMode)
+
+	identifier, err := md.ModelIdentifier(fmi3.ScheduledExecutionType)
+	require.NoError(t, err)
+	require.Equal(t, "Clocked", identifier)
+
+	require.Equal(t, []string{"logEvents"}, md.LogCategoryNames())
+	require.Equal(t, fmi3.Float64Variable, md.TypeDefinition("Position").VariableType())
+	require.Equal(t, "on", md.TypeDefinition("Mode").ItemByValue(1).Name)
+
+	// the variables keep the order of the model description although they have different types
+	variables := md.Variables()
+	require.Len(t, variables, 9)
+	require.Equal(t, "time", variables[0].Name)
+	require.Equal(t, fmi3.ClockVariable, variables[1].Type())
+	require.Equal(t, fmi3.UInt8Variable, variables[2].Type())
+	require.Equal(t, "labels", variables[7].Name)
+
+	clock := md.VariableByName("inClock")
+	require.Equal(t, "constant", clock.IntervalVariability)
+	require.InDelta(t, 0.5, *clock.IntervalDecimal, 1e-9)
+
+	x := md.VariableByName("x")
+	require.True(t, x.IsArray())
+	require.Equal(t, fmi3.ValueReference(2), *x.Dimensions[0].ValueReference)
+	require.Equal(t, []string{"1", "2", "3"}, x.StartValueStrings())
+
+	require.Equal(t, fmi3.ValueReference(3), *md.VariableByName("der(x)").Derivative)
+	require.Equal(t, fmi3.ValueReferenceList{1}, md.VariableByValueReference(5).Clocks)
+	require.Equal(t, []string{"a", "b"}, md.VariableByName("labels").StartValueStrings())
+	require.Equal(t, uint64(2), *md.VariableByName("labels").Dimensions[0].Start)
+	require.Equal(t, []string{"00ff"}, md.VariableByName("blob").StartValueStrings())
+
+	require.Equal(t, 1, md.NumberOfContinuousStates())
+	require.Equal(t, 0, md.NumberOfEventIndicators())
+	require.Equal(t, fmi3.ValueReferenceList{2}, md.ModelStructure.Output[0].Dependencies)
+	require.Equal(t, fmi3.StringList{"constant"}, md.ModelStructure.Output[0].DependenciesKind)
+	require.Nil(t, md.ModelStructure.Output[1].Dependencies)
+	require.Len(t, md.ModelStructure.ClockedState, 1)
+
+	require.ElementsMatch(t, []string{"x86_64-linux", "x86_64-windows"}, fmi3.SupportedPlatformsFS(fsys))
+
+	terminals, err := fmi3.ReadTerminalsAndIconsFS(fsys)
+	require.NoError(t, err)
+	flange := terminals.TerminalByName("flange")
+	require.NotNil(t, flange)
+	require.Equal(t, "plug", flange.MatchingRule)
+	require.Equal(t, "x", flange.TerminalMemberVariable[0].VariableName)
+}
+
+func TestReadModelDescriptionVersion(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"modelDescription.xml": {Data: []byte(`<fmiModelDescription fmiVersion="2.0" modelName="m" guid="{}"><CoSimulation modelIdentifier="m"/></fmiModelDescription>`)},
+	}
+
+	_, err := fmi3.ReadModelDescriptionFS(fsys)
+	require.ErrorContains(t, err, "unsupported FMI version")
+
+	terminals, err := fmi3.ReadTerminalsAndIconsFS(fsys)
+	require.NoError(t, err)
+	require.Nil(t, terminals)
+}
@@ -0,0 +1,493 @@
+package fmi3
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ModelIdentifier returns the model identifier of the interface type, which is the name of its shared library
+func (md *ModelDescription) ModelIdentifier(fmiType Type) (string, error) {
+	switch {
+	case fmiType == ModelExchangeType && md.ModelExchange != nil:
+		return md.ModelExchange.ModelIdentifier, nil
+	case fmiType == CoSimulationType && md.CoSimulation != nil:
+		return md.CoSimulation.ModelIdentifier, nil
+	case fmiType == ScheduledExecutionType && md.ScheduledExecution != nil:
+		return md.ScheduledExecution.ModelIdentifier, nil
+	default:
+		return "", fmt.Errorf("the FMU does not support %s", fmiType)
+	}
+}
+
+// InterfaceTypes returns the interface types that the FMU supports
+func (md *ModelDescription) InterfaceTypes() []Type {
+	types := make([]Type, 0)
+	if md.ModelExchange != nil {
+		types = append(types, ModelExchangeType)
+	}
+	if md.CoSimulation != nil {
+		types = append(types, CoSimulationType)
+	}
+	if md.ScheduledExecution != nil {
+		types = append(types, ScheduledExecutionType)
+	}
+	return types
+}
+
+// TypeDefinition returns the type definition with the given name or nil
+func (md *ModelDescription) TypeDefinition(name string) *TypeDefinition {
+	if md.TypeDefinitions == nil {
+		return nil
+	}
+
+	for i := range md.TypeDefinitions.Types {
+		if t := &md.TypeDefinitions.Types[i]; t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Variables returns the model variables in the order of the model description
+func (md *ModelDescription) Variables() []ModelVariable {
+	if md.ModelVariables == nil {
+		return nil
+	}
+
+	return md.ModelVariables.Variables
+}
+
+// VariableByName returns the variable with the given name or nil
+func (md *ModelDescription) VariableByName(name string) *ModelVariable {
+	variables := md.Variables()
+	for i := range variables {
+		if variables[i].Name == name {
+			return &variables[i]
+		}
+	}
+	return nil
+}
+
+// VariableByValueReference returns the variable with the given value reference or nil.
+// In FMI 3.0 the value references are unique, so there is at most one such variable.
+func (md *ModelDescription) VariableByValueReference(vr ValueReference) *ModelVariable {
+	variables := md.Variables()
+	for i := range variables {
+		if variables[i].ValueReference == vr {
+			return &variables[i]
+		}
+	}
+	return nil
+}
+
+// variablesByName returns the model variables by name
+func (md *ModelDescription) variablesByName() map[string]*ModelVariable {
+	variables := md.Variables()
+	result := make(map[string]*ModelVariable, len(variables))
+	for i := range variables {
+		result[variables[i].Name] = &variables[i]
+	}
+	return result
+}
+
+// NumberOfContinuousStates returns the number of continuous state variables, which is defined by the
+// number of ModelStructure/ContinuousStateDerivative elements. Array variables count as one variable,
+// the number of elements is returned by GetNumberOfContinuousStates.
+func (md *ModelDescription) NumberOfContinuousStates() int {
+	if md.ModelStructure == nil {
+		return 0
+	}
+
+	return len(md.ModelStructure.ContinuousStateDerivative)
+}
+
+// NumberOfEventIndicators returns the number of event indicator variables, which is defined by the
+// number of ModelStructure/EventIndicator elements
+func (md *ModelDescription) NumberOfEventIndicators() int {
+	if md.ModelStructure == nil {
+		return 0
+	}
+
+	return len(md.ModelStructure.EventIndicator)
+}
+
+// LogCategoryNames returns the names of the log categories that are declared in the model description
+func (md *ModelDescription) LogCategoryNames() []string {
+	names := make([]string, 0)
+	if md.LogCategories != nil {
+		for _, category := range md.LogCategories.Category {
+			names = append(names, category.Name)
+		}
+	}
+	return names
+}
+
+type ModelDescription struct {
+	XMLName                  xml.Name            `xml:"fmiModelDescription"`
+	FmiVersion               string              `xml:"fmiVersion,attr"`
+	ModelName                string              `xml:"modelName,attr"`
+	InstantiationToken       string              `xml:"instantiationToken,attr"`
+	Description              string              `xml:"description,attr,omitempty"`
+	Author                   string              `xml:"author,attr,omitempty"`
+	Version                  string              `xml:"version,attr,omitempty"`
+	Copyright                string              `xml:"copyright,attr,omitempty"`
+	License                  string              `xml:"license,attr,omitempty"`
+	GenerationTool           string              `xml:"generationTool,attr,omitempty"`
+	GenerationDateAndTime    string              `xml:"generationDateAndTime,attr,omitempty"`
+	VariableNamingConvention string              `xml:"variableNamingConvention,attr,omitempty"`
+	ModelExchange            *ModelExchange      `xml:"ModelExchange"`
+	CoSimulation             *CoSimulation       `xml:"CoSimulation"`
+	ScheduledExecution       *ScheduledExecution `xml:"ScheduledExecution"`
+	UnitDefinitions          *UnitDefinitions    `xml:"UnitDefinitions"`
+	TypeDefinitions          *TypeDefinitions    `xml:"TypeDefinitions"`
+	LogCategories            *LogCategories      `xml:"LogCategories"`
+	DefaultExperiment        *DefaultExperiment  `xml:"DefaultExperiment"`
+	Annotations              *Annotations        `xml:"Annotations"`
+	ModelVariables           *ModelVariables     `xml:"ModelVariables"`
+	ModelStructure           *ModelStructure     `xml:"ModelStructure"`
+}
+
+// InterfaceType holds the attributes that are common to all interface types
+type InterfaceType struct {
+	ModelIdentifier                     string       `xml:"modelIdentifier,attr"`
+	NeedsExecutionTool                  bool         `xml:"needsExecutionTool,attr,omitempty"`
+	CanBeInstantiatedOnlyOncePerProcess bool         `xml:"canBeInstantiatedOnlyOncePerProcess,attr,omitempty"`
+	CanGetAndSetFMUState                bool         `xml:"canGetAndSetFMUState,attr,omitempty"`
+	CanSerializeFMUState                bool         `xml:"canSerializeFMUState,attr,omitempty"`
+	ProvidesDirectionalDerivatives      bool         `xml:"providesDirectionalDerivatives,attr,omitempty"`
+	ProvidesAdjointDerivatives          bool         `xml:"providesAdjointDerivatives,attr,omitempty"`
+	ProvidesPerElementDependencies      bool         `xml:"providesPerElementDependencies,attr,omitempty"`
+	Annotations                         *Annotations `xml:"Annotations"`
+}
+
+type ModelExchange struct {
+	InterfaceType
+	NeedsCompletedIntegratorStep   bool `xml:"needsCompletedIntegratorStep,attr,omitempty"`
+	ProvidesEvaluateDiscreteStates bool `xml:"providesEvaluateDiscreteStates,attr,omitempty"`
+}
+
+type CoSimulation struct {
+	InterfaceType
+	CanHandleVariableCommunicationStepSize bool     `xml:"canHandleVariableCommunicationStepSize,attr,omitempty"`
+	FixedInternalStepSize                  *float64 `xml:"fixedInternalStepSize,attr,omitempty"`
+	MaxOutputDerivativeOrder               uint32   `xml:"maxOutputDerivativeOrder,attr,omitempty"`
+	RecommendedIntermediateInputSmoothness int32    `xml:"recommendedIntermediateInputSmoothness,attr,omitempty"`
+	ProvidesIntermediateUpdate             bool     `xml:"providesIntermediateUpdate,attr,omitempty"`
+	MightReturnEarlyFromDoStep             bool     `xml:"mightReturnEarlyFromDoStep,attr,omitempty"`
+	CanReturnEarlyAfterIntermediateUpdate  bool     `xml:"canReturnEarlyAfterIntermediateUpdate,attr,omitempty"`
+	HasEventMode                           bool     `xml:"hasEventMode,attr,omitempty"`
+	ProvidesEvaluateDiscreteStates         bool     `xml:"providesEvaluateDiscreteStates,attr,omitempty"`
+}
+
+type ScheduledExecution struct {
+	InterfaceType
+}
+
+type BaseUnit struct {
+	Kg     int     `xml:"kg,attr,omitempty"`
+	M      int     `xml:"m,attr,omitempty"`
+	S      int     `xml:"s,attr,omitempty"`
+	A      int     `xml:"A,attr,omitempty"`
+	K      int     `xml:"K,attr,omitempty"`
+	Mol    int     `xml:"mol,attr,omitempty"`
+	Cd     int     `xml:"cd,attr,omitempty"`
+	Rad    int     `xml:"rad,attr,omitempty"`
+	Factor float64 `xml:"factor,attr,omitempty"`
+	Offset float64 `xml:"offset,attr,omitempty"`
+}
+
+type DisplayUnit struct {
+	Name    string  `xml:"name,attr"`
+	Factor  float64 `xml:"factor,attr,omitempty"`
+	Offset  float64 `xml:"offset,attr,omitempty"`
+	Inverse bool    `xml:"inverse,attr,omitempty"`
+}
+
+// Unit is Unit definition (with respect to SI base units) and default display units
+type Unit struct {
+	Name        string        `xml:"name,attr"`
+	BaseUnit    *BaseUnit     `xml:"BaseUnit"`
+	DisplayUnit []DisplayUnit `xml:"DisplayUnit"`
+}
+
+type UnitDefinitions struct {
+	Unit []Unit `xml:"Unit"`
+}
+
+type Item struct {
+	Name        string `xml:"name,attr"`
+	Value       int64  `xml:"value,attr"`
+	Description string `xml:"description,attr,omitempty"`
+}
+
+// TypeDefinition is one of the elements Float32Type, Float64Type, Int8Type ... UInt64Type, BooleanType,
+// StringType, BinaryType, EnumerationType or ClockType. The kind of the type is the name of the element.
+type TypeDefinition struct {
+	XMLName     xml.Name
+	Name        string `xml:"name,attr"`
+	Description string `xml:"description,attr,omitempty"`
+
+	// Float, Int and Enumeration types
+	Quantity string `xml:"quantity,attr,omitempty"`
+	Min      string `xml:"min,attr,omitempty"`
+	Max      string `xml:"max,attr,omitempty"`
+
+	// Float types
+	Unit             string `xml:"unit,attr,omitempty"`
+	DisplayUnit      string `xml:"displayUnit,attr,omitempty"`
+	RelativeQuantity bool   `xml:"relativeQuantity,attr,omitempty"`
+	Unbounded        bool   `xml:"unbounded,attr,omitempty"`
+	Nominal          string `xml:"nominal,attr,omitempty"`
+
+	// BinaryType
+	MimeType string  `xml:"mimeType,attr,omitempty"`
+	MaxSize  *uint64 `xml:"maxSize,attr,omitempty"`
+
+	// EnumerationType
+	Item []Item `xml:"Item"`
+
+	ClockAttributes
+}
+
+// VariableType returns the type of the variables that are declared with this type definition
+func (t *TypeDefinition) VariableType() VariableType {
+	return VariableType(strings.TrimSuffix(t.XMLName.Local, "Type"))
+}
+
+// ItemByName returns the item of an EnumerationType with the given name or nil
+func (t *TypeDefinition) ItemByName(name string) *Item {
+	for i := range t.Item {
+		if t.Item[i].Name == name {
+			return &t.Item[i]
+		}
+	}
+	return nil
+}
+
+// ItemByValue returns the item of an EnumerationType with the given value or nil
+func (t *TypeDefinition) ItemByValue(value int64) *Item {
+	for i := range t.Item {
+		if t.Item[i].Value == value {
+			return &t.Item[i]
+		}
+	}
+	return nil
+}
+
+type TypeDefinitions struct {
+	Types []TypeDefinition `xml:",any"`
+}
+
+type Category struct {
+	Name        string `xml:"name,attr"`
+	Description string `xml:"description,attr,omitempty"`
+}
+
+type LogCategories struct {
+	Category []Category `xml:"Category"`
+}
+
+type DefaultExperiment struct {
+	StartTime *float64 `xml:"startTime,attr,omitempty"`
+	StopTime  *float64 `xml:"stopTime,attr,omitempty"`
+	Tolerance *float64 `xml:"tolerance,attr,omitempty"`
+	StepSize  *float64 `xml:"stepSize,attr,omitempty"`
+}
+
+// Annotation is a tool specific annotation, the content is kept as raw XML
+type Annotation struct {
+	Type     string `xml:"type,attr"`
+	InnerXML string `xml:",innerxml"`
+}
+
+type Annotations struct {
+	Annotation []Annotation `xml:"Annotation"`
+}
+
+// VariableType is the type of a model variable, which is the name of its element in the model description
+type VariableType string
+
+const (
+	Float32Variable     VariableType = "Float32"
+	Float64Variable     VariableType = "Float64"
+	Int8Variable        VariableType = "Int8"
+	UInt8Variable       VariableType = "UInt8"
+	Int16Variable       VariableType = "Int16"
+	UInt16Variable      VariableType = "UInt16"
+	Int32Variable       VariableType = "Int32"
+	UInt32Variable      VariableType = "UInt32"
+	Int64Variable       VariableType = "Int64"
+	UInt64Variable      VariableType = "UInt64"
+	BooleanVariable     VariableType = "Boolean"
+	StringVariable      VariableType = "String"
+	BinaryVariable      VariableType = "Binary"
+	EnumerationVariable VariableType = "Enumeration"
+	ClockVariable       VariableType = "Clock"
+)
+
+// ValueReferenceList is a space separated list of value references
+type ValueReferenceList []ValueReference
+
+func (l *ValueReferenceList) UnmarshalXMLAttr(attr xml.Attr) error {
+	fields := strings.Fields(attr.Value)
+
+	list := make(ValueReferenceList, len(fields))
+	for i, field := range fields {
+		vr, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid value reference %q in attribute %s", field, attr.Name.Local)
+		}
+		list[i] = ValueReference(vr)
+	}
+
+	*l = list
+	return nil
+}
+
+func (l ValueReferenceList) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	fields := make([]string, len(l))
+	for i, vr := range l {
+		fields[i] = strconv.FormatUint(uint64(vr), 10)
+	}
+
+	return xml.Attr{Name: name, Value: strings.Join(fields, " ")}, nil
+}
+
+// StringList is a space separated list of strings
+type StringList []string
+
+func (l *StringList) UnmarshalXMLAttr(attr xml.Attr) error {
+	*l = strings.Fields(attr.Value)
+	return nil
+}
+
+func (l StringList) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: strings.Join(l, " ")}, nil
+}
+
+// Dimension is a dimension of an array variable. The size is either the constant Start or the value
+// of the structural parameter with the value reference ValueReference.
+type Dimension struct {
+	Start          *uint64         `xml:"start,attr,omitempty"`
+	ValueReference *ValueReference `xml:"valueReference,attr,omitempty"`
+}
+
+// StartValue is the start value of a String or Binary variable, which has one element per array element
+type StartValue struct {
+	Value string `xml:"value,attr"`
+}
+
+// ClockAttributes are the attributes of Clock variables and ClockType definitions
+type ClockAttributes struct {
+	CanBeDeactivated    bool     `xml:"canBeDeactivated,attr,omitempty"`
+	Priority            *uint32  `xml:"priority,attr,omitempty"`
+	IntervalVariability string   `xml:"intervalVariability,attr,omitempty"`
+	IntervalDecimal     *float64 `xml:"intervalDecimal,attr,omitempty"`
+	ShiftDecimal        float64  `xml:"shiftDecimal,attr,omitempty"`
+	SupportsFraction    bool     `xml:"supportsFraction,attr,omitempty"`
+	Resolution          *uint64  `xml:"resolution,attr,omitempty"`
+	IntervalCounter     *uint64  `xml:"intervalCounter,attr,omitempty"`
+	ShiftCounter        uint64   `xml:"shiftCounter,attr,omitempty"`
+}
+
+// ModelVariable is one of the elements Float32, Float64, Int8 ... UInt64, Boolean, String, Binary, Enumeration
+// or Clock of ModelVariables. The type of the variable is the name of the element. Variables with Dimension
+// elements are arrays and their start value is a space separated list of the values of the elements in row major order.
+type ModelVariable struct {
+	XMLName                            xml.Name
+	Name                               string             `xml:"name,attr"`
+	ValueReference                     ValueReference     `xml:"valueReference,attr"`
+	Description                        string             `xml:"description,attr,omitempty"`
+	Causality                          string             `xml:"causality,attr,omitempty"`
+	Variability                        string             `xml:"variability,attr,omitempty"`
+	Initial                            string             `xml:"initial,attr,omitempty"`
+	CanHandleMultipleSetPerTimeInstant *bool              `xml:"canHandleMultipleSetPerTimeInstant,attr,omitempty"`
+	IntermediateUpdate                 bool               `xml:"intermediateUpdate,attr,omitempty"`
+	Previous                           *ValueReference    `xml:"previous,attr,omitempty"`
+	Clocks                             ValueReferenceList `xml:"clocks,attr,omitempty"`
+	DeclaredType                       string             `xml:"declaredType,attr,omitempty"`
+
+	// Float, Int and Enumeration variables
+	Quantity string `xml:"quantity,attr,omitempty"`
+	Min      string `xml:"min,attr,omitempty"`
+	Max      string `xml:"max,attr,omitempty"`
+
+	// Float variables
+	Unit             string          `xml:"unit,attr,omitempty"`
+	DisplayUnit      string          `xml:"displayUnit,attr,omitempty"`
+	RelativeQuantity bool            `xml:"relativeQuantity,attr,omitempty"`
+	Unbounded        bool            `xml:"unbounded,attr,omitempty"`
+	Nominal          string          `xml:"nominal,attr,omitempty"`
+	Derivative       *ValueReference `xml:"derivative,attr,omitempty"`
+	Reinit           bool            `xml:"reinit,attr,omitempty"`
+
+	// Binary variables
+	MimeType string  `xml:"mimeType,attr,omitempty"`
+	MaxSize  *uint64 `xml:"maxSize,attr,omitempty"`
+
+	// Clock variables
+	ClockAttributes
+
+	// the start value of all variables except String and Binary
+	Start *string `xml:"start,attr,omitempty"`
+	// the start values of String and Binary variables
+	StartValues []StartValue `xml:"Start"`
+
+	Dimensions  []Dimension  `xml:"Dimension"`
+	Annotations *Annotations `xml:"Annotations"`
+}
+
+// Type returns the type of the variable
+func (v *ModelVariable) Type() VariableType {
+	return VariableType(v.XMLName.Local)
+}
+
+// IsArray returns whether the variable is an array
+func (v *ModelVariable) IsArray() bool {
+	return len(v.Dimensions) > 0
+}
+
+// StartValueStrings returns the start value of every element of the variable as a string
+// or nil if the variable has no start value. Binary start values are hex encoded.
+func (v *ModelVariable) StartValueStrings() []string {
+	switch {
+	case v.Type() == StringVariable || v.Type() == BinaryVariable:
+		if len(v.StartValues) == 0 {
+			return nil
+		}
+		values := make([]string, len(v.StartValues))
+		for i, s := range v.StartValues {
+			values[i] = s.Value
+		}
+		return values
+	case v.Start != nil:
+		return strings.Fields(*v.Start)
+	default:
+		return nil
+	}
+}
+
+// ModelVariables holds the variables in the order of the model description
+type ModelVariables struct {
+	Variables []ModelVariable `xml:",any"`
+}
+
+// Unknown is an unknown of the model structure and the variables it depends on.
+// Dependencies is nil if the attribute is not present, which means the unknown depends on all knowns.
+// DependenciesKind holds the kind ("dependent", "constant", "fixed", "tunable" or "discrete") of every dependency.
+type Unknown struct {
+	ValueReference   ValueReference     `xml:"valueReference,attr"`
+	Dependencies     ValueReferenceList `xml:"dependencies,attr,omitempty"`
+	DependenciesKind StringList         `xml:"dependenciesKind,attr,omitempty"`
+	Annotations      *Annotations       `xml:"Annotations"`
+}
+
+type ModelStructure struct {
+	Output                    []Unknown `xml:"Output"`
+	ContinuousStateDerivative []Unknown `xml:"ContinuousStateDerivative"`
+	ClockedState              []Unknown `xml:"ClockedState"`
+	InitialUnknown            []Unknown `xml:"InitialUnknown"`
+	EventIndicator            []Unknown `xml:"EventIndicator"`
+}
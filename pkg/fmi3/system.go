@@ -0,0 +1,20 @@
+package fmi3
+
+import "go-fmu/pkg/internal/fmi"
+
+type Machine struct {
+	Architecture  string
+	Platform      string // the platform tuple, e.g. x86_64-linux, which is the directory of the binaries
+	LibrarySuffix string
+}
+
+func CurrentMachine() Machine {
+
+	machine := fmi.CurrentMachine()
+
+	return Machine{
+		Architecture:  machine.Architecture,
+		Platform:      machine.PlatformTuple(),
+		LibrarySuffix: machine.LibrarySuffix,
+	}
+}
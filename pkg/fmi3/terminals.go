@@ -0,0 +1,96 @@
+package fmi3
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"io/fs"
+
+	"golang.org/x/net/html/charset"
+)
+
+// terminalsAndIconsFile is the path of the optional file that declares the terminals of the FMU
+const terminalsAndIconsFile = "terminalsAndIcons/terminalsAndIcons.xml"
+
+type TerminalsAndIcons struct {
+	XMLName    xml.Name   `xml:"fmiTerminalsAndIcons"`
+	FmiVersion string     `xml:"fmiVersion,attr"`
+	Terminals  []Terminal `xml:"Terminals>Terminal"`
+}
+
+// Terminal is a group of variables that are connected together, e.g. the pins of an electrical connector.
+// Terminals can be nested.
+type Terminal struct {
+	Name                         string                         `xml:"name,attr"`
+	MatchingRule                 string                         `xml:"matchingRule,attr"`
+	TerminalKind                 string                         `xml:"terminalKind,attr,omitempty"`
+	Description                  string                         `xml:"description,attr,omitempty"`
+	TerminalMemberVariable       []TerminalMemberVariable       `xml:"TerminalMemberVariable"`
+	TerminalStreamMemberVariable []TerminalStreamMemberVariable `xml:"TerminalStreamMemberVariable"`
+	Terminal                     []Terminal                     `xml:"Terminal"`
+	Annotations                  *Annotations                   `xml:"Annotations"`
+}
+
+type TerminalMemberVariable struct {
+	VariableName string `xml:"variableName,attr"`
+	MemberName   string `xml:"memberName,attr,omitempty"`
+	VariableKind string `xml:"variableKind,attr"`
+}
+
+type TerminalStreamMemberVariable struct {
+	InStreamMemberName    string `xml:"inStreamMemberName,attr"`
+	OutStreamMemberName   string `xml:"outStreamMemberName,attr"`
+	InStreamVariableName  string `xml:"inStreamVariableName,attr"`
+	OutStreamVariableName string `xml:"outStreamVariableName,attr"`
+}
+
+// TerminalByName returns the top level terminal with the given name or nil
+func (t *TerminalsAndIcons) TerminalByName(name string) *Terminal {
+	for i := range t.Terminals {
+		if t.Terminals[i].Name == name {
+			return &t.Terminals[i]
+		}
+	}
+	return nil
+}
+
+// ReadTerminalsAndIcons reads the terminals of an FMU without extracting it.
+// It returns nil and no error if the FMU does not declare terminals.
+func ReadTerminalsAndIcons(filename string) (*TerminalsAndIcons, error) {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	return ReadTerminalsAndIconsFS(r)
+}
+
+// ReadTerminalsAndIconsFS reads the terminals from the files of an FMU, e.g. a *zip.Reader for an FMU in memory
+// or os.DirFS for an extracted FMU. It returns nil and no error if the FMU does not declare terminals.
+func ReadTerminalsAndIconsFS(fsys fs.FS) (*TerminalsAndIcons, error) {
+
+	f, err := fsys.Open(terminalsAndIconsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var terminals TerminalsAndIcons
+	decoder := xml.NewDecoder(bufio.NewReader(f))
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&terminals); err != nil {
+		return nil, err
+	}
+
+	return &terminals, nil
+}
@@ -0,0 +1,133 @@
+package fmi3
+
+/*
+#include "headers/fmi3Functions.h"
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// FMUState is a pointer to an internal state of the FMU
+type FMUState struct {
+	state unsafe.Pointer
+}
+
+type ValueReference uint32 // handle to the value of a variable
+
+type Status int
+
+const (
+	OK      Status = C.fmi3OK
+	Warning Status = C.fmi3Warning
+	Discard Status = C.fmi3Discard
+	Error   Status = C.fmi3Error
+	Fatal   Status = C.fmi3Fatal
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "OK"
+	case Warning:
+		return "Warning"
+	case Discard:
+		return "Discard"
+	case Error:
+		return "Error"
+	case Fatal:
+		return "Fatal"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Type is the interface type of an FMU instance
+type Type int
+
+const (
+	ModelExchangeType Type = iota
+	CoSimulationType
+	ScheduledExecutionType
+)
+
+func (t Type) String() string {
+	switch t {
+	case ModelExchangeType:
+		return "Model Exchange"
+	case CoSimulationType:
+		return "Co-Simulation"
+	case ScheduledExecutionType:
+		return "Scheduled Execution"
+	default:
+		return "unknown interface type"
+	}
+}
+
+// DependencyKind is the kind of a dependency returned by GetVariableDependencies
+type DependencyKind int
+
+const (
+	Independent DependencyKind = C.fmi3Independent
+	Constant    DependencyKind = C.fmi3Constant
+	Fixed       DependencyKind = C.fmi3Fixed
+	Tunable     DependencyKind = C.fmi3Tunable
+	Discrete    DependencyKind = C.fmi3Discrete
+	Dependent   DependencyKind = C.fmi3Dependent
+)
+
+func (k DependencyKind) String() string {
+	switch k {
+	case Independent:
+		return "independent"
+	case Constant:
+		return "constant"
+	case Fixed:
+		return "fixed"
+	case Tunable:
+		return "tunable"
+	case Discrete:
+		return "discrete"
+	case Dependent:
+		return "dependent"
+	default:
+		return fmt.Sprintf("DependencyKind(%d)", int(k))
+	}
+}
+
+// IntervalQualifier describes the interval of a clock returned by GetIntervalDecimal and GetIntervalFraction
+type IntervalQualifier int
+
+const (
+	IntervalNotYetKnown IntervalQualifier = C.fmi3IntervalNotYetKnown
+	IntervalUnchanged   IntervalQualifier = C.fmi3IntervalUnchanged
+	IntervalChanged     IntervalQualifier = C.fmi3IntervalChanged
+)
+
+// Dependency is a dependency of a variable returned by GetVariableDependencies
+type Dependency struct {
+	ElementIndexOfDependent   int            // the element of an array variable (starting at 1) or 0 for all elements
+	Independent               ValueReference // the variable the dependent depends on
+	ElementIndexOfIndependent int            // the element of an array variable (starting at 1) or 0 for all elements
+	Kind                      DependencyKind
+}
+
+// DiscreteStatesInfo is returned by UpdateDiscreteStates
+type DiscreteStatesInfo struct {
+	DiscreteStatesNeedUpdate          bool
+	TerminateSimulation               bool
+	NominalsOfContinuousStatesChanged bool
+	ValuesOfContinuousStatesChanged   bool
+	NextEventTimeDefined              bool
+	NextEventTime                     float64
+}
+
+// StepResult is returned by DoStep
+type StepResult struct {
+	EventHandlingNeeded bool
+	TerminateSimulation bool
+	EarlyReturn         bool
+	LastSuccessfulTime  float64
+}
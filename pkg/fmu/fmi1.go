@@ -0,0 +1,283 @@
+package fmu
+
+import (
+	"errors"
+	"go-fmu/pkg/fmi1"
+	"go-fmu/pkg/fmi2"
+	"io/fs"
+)
+
+// fmi1Backend loads the shared library of an FMI 1.0 FMU, which implements either Model Exchange
+// or Co-Simulation, when the first instance is created. The model description is converted to
+// FMI 2.0 by the fmi2 package.
+type fmi1Backend struct {
+	filename         string
+	modelDescription *fmi2.ModelDescription
+	fmu              *fmi1.Fmu1
+}
+
+func openFmi1(filename string, fsys fs.FS) (Model, error) {
+
+	md, err := fmi2.ReadModelDescriptionFS(fsys, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m := modelFromFmi2(md)
+	m.platforms = fmi1.SupportedPlatformsFS(fsys)
+	m.backend = &fmi1Backend{filename: filename, modelDescription: md}
+
+	return m, nil
+}
+
+func (b *fmi1Backend) instantiate(m *model, instanceName string, interfaceType InterfaceType, options *InstantiateOptions) (Instance, error) {
+
+	if b.fmu == nil {
+		fmu, err := fmi1.New(b.filename)
+		if err != nil {
+			return nil, err
+		}
+		b.fmu = fmu
+	}
+
+	var opts []fmi1.InstantiateOption
+
+	if options.logger != nil {
+		opts = append(opts, fmi1.WithLogger(func(instanceName string, status fmi1.Status, category string, message string) {
+			options.logger(instanceName, Status(status), category, message)
+		}))
+	}
+
+	if options.categories != nil {
+		opts = append(opts, fmi1.WithLogCategories(options.categories...))
+	}
+
+	var instance *fmi1.Instance
+
+	if interfaceType == ModelExchange {
+		instance = b.fmu.InstantiateModel(instanceName, m.instantiationToken, options.loggingOn, opts...)
+	} else {
+		instance = b.fmu.InstantiateSlave(instanceName, m.instantiationToken, fmi2.FileURI(b.fmu.Directory), 0, options.visible, false, options.loggingOn, opts...)
+	}
+
+	if instance == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+
+	c := &fmi1Instance{model: m, modelDescription: b.modelDescription, instance: instance, interfaceType: interfaceType, options: options}
+	c.namedValues = namedValues{model: m, access: c}
+
+	return c, nil
+}
+
+func (b *fmi1Backend) close() error {
+	if b.fmu == nil {
+		return nil
+	}
+	err := b.fmu.Close()
+	b.fmu = nil
+	return err
+}
+
+// fmi1Instance is an Instance of an FMI 1.0 FMU
+type fmi1Instance struct {
+	namedValues
+	model            *model
+	modelDescription *fmi2.ModelDescription
+	instance         *fmi1.Instance
+	interfaceType    InterfaceType
+	options          *InstantiateOptions
+	integrator       *integrator // the integrator of a Model Exchange instance after Initialize
+}
+
+func (c *fmi1Instance) Type() InterfaceType {
+	return c.interfaceType
+}
+
+// Initialize sets all start values before the FMU is initialized, because FMI 1.0 has no Initialization Mode
+func (c *fmi1Instance) Initialize(startTime float64, opts ...InitializeOption) error {
+
+	options := &InitializeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if c.interfaceType == CoSimulation {
+
+		if err := c.Set(options.startValues); err != nil {
+			return err
+		}
+
+		var initializeOptions []fmi1.InitializeSlaveOption
+		if options.stopTime != nil {
+			initializeOptions = append(initializeOptions, fmi1.WithStopTime(*options.stopTime))
+		}
+
+		return c.instance.InitializeSlave(startTime, initializeOptions...)
+	}
+
+	// the Model Exchange instance is simulated with the FMI 2.0 functions of the fmi2 package
+	fmu := fmi2.NewFmi1Instance(c.instance)
+
+	var setupOptions []fmi2.SetupExperimentOption
+
+	if options.tolerance != nil {
+		setupOptions = append(setupOptions, fmi2.WithRelativeTolerance(*options.tolerance))
+	}
+
+	// sets the time of fmiInitialize
+	if err := fmu.SetupExperiment(startTime, setupOptions...); err != nil {
+		return err
+	}
+
+	if err := c.Set(options.startValues); err != nil {
+		return err
+	}
+
+	// calls fmiInitialize, which performs the event iteration at the start time
+	if err := fmu.ExitInitializationMode(); err != nil {
+		return err
+	}
+
+	var err error
+	c.integrator, err = newInstanceIntegrator(fmu, c.modelDescription, startTime, c.options)
+
+	return err
+}
+
+func (c *fmi1Instance) Step(currentTime float64, stepSize float64) error {
+
+	if c.interfaceType == ModelExchange {
+		if c.integrator == nil {
+			return errors.New("the instance has not been initialized")
+		}
+		return c.integrator.step(currentTime, stepSize)
+	}
+
+	// FMI 1.0 has no status to request the termination, a slave that cannot complete the step returns Discard
+	return c.instance.DoStep(currentTime, stepSize, true)
+}
+
+func (c *fmi1Instance) Terminate() error {
+	return c.instance.Terminate()
+}
+
+func (c *fmi1Instance) Free() {
+	c.instance.FreeInstance()
+}
+
+func (c *fmi1Instance) getVariables(variables []*Variable) ([]any, error) {
+
+	values := make([]any, len(variables))
+
+	var (
+		realIndices, integerIndices, booleanIndices, stringIndices []int
+		realVRs, integerVRs, booleanVRs, stringVRs                 []fmi1.ValueReference
+	)
+
+	for i, v := range variables {
+		vr := fmi1.ValueReference(v.ValueReference)
+		switch v.Type {
+		case Real:
+			realIndices, realVRs = append(realIndices, i), append(realVRs, vr)
+		case Integer, Enumeration:
+			integerIndices, integerVRs = append(integerIndices, i), append(integerVRs, vr)
+		case Boolean:
+			booleanIndices, booleanVRs = append(booleanIndices, i), append(booleanVRs, vr)
+		default:
+			stringIndices, stringVRs = append(stringIndices, i), append(stringVRs, vr)
+		}
+	}
+
+	if len(realVRs) > 0 {
+		reals, err := c.instance.GetReal(realVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, realIndices, reals)
+	}
+
+	if len(integerVRs) > 0 {
+		integers, err := c.instance.GetInteger(integerVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, integerIndices, integers)
+	}
+
+	if len(booleanVRs) > 0 {
+		booleans, err := c.instance.GetBoolean(booleanVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, booleanIndices, booleans)
+	}
+
+	if len(stringVRs) > 0 {
+		texts, err := c.instance.GetString(stringVRs)
+		if err != nil {
+			return nil, err
+		}
+		storeValues(values, stringIndices, texts)
+	}
+
+	return values, nil
+}
+
+func (c *fmi1Instance) setVariables(variables []*Variable, values []any) error {
+
+	var (
+		realVRs, integerVRs, booleanVRs, stringVRs []fmi1.ValueReference
+		reals                                      []float64
+		integers                                   []int
+		booleans                                   []bool
+		texts                                      []string
+	)
+
+	for i, v := range variables {
+		vr := fmi1.ValueReference(v.ValueReference)
+		switch value := values[i].(type) {
+		case float64:
+			realVRs, reals = append(realVRs, vr), append(reals, value)
+		case int:
+			integerVRs, integers = append(integerVRs, vr), append(integers, value)
+		case bool:
+			booleanVRs, booleans = append(booleanVRs, vr), append(booleans, value)
+		case string:
+			stringVRs, texts = append(stringVRs, vr), append(texts, value)
+		}
+	}
+
+	if len(realVRs) > 0 {
+		if err := c.instance.SetReal(realVRs, reals); err != nil {
+			return err
+		}
+	}
+
+	if len(integerVRs) > 0 {
+		if err := c.instance.SetInteger(integerVRs, integers); err != nil {
+			return err
+		}
+	}
+
+	if len(booleanVRs) > 0 {
+		if err := c.instance.SetBoolean(booleanVRs, booleans); err != nil {
+			return err
+		}
+	}
+
+	if len(stringVRs) > 0 {
+		if err := c.instance.SetString(stringVRs, texts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storeValues stores the results of a get function at their indices
+func storeValues[T any](values []any, indices []int, result []T) {
+	for i, index := range indices {
+		values[index] = result[i]
+	}
+}
@@ -0,0 +1,179 @@
+package fmu
+
+import (
+	"errors"
+	"go-fmu/pkg/fmi2"
+	"io/fs"
+)
+
+// fmi2Backend loads the shared library of an interface type of an FMI 2.0 FMU when the first
+// instance of the interface type is created
+type fmi2Backend struct {
+	filename string
+	fmus     map[InterfaceType]*fmi2.Fmu2
+}
+
+func openFmi2(filename string, fsys fs.FS) (Model, error) {
+
+	md, err := fmi2.ReadModelDescriptionFS(fsys, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	m := modelFromFmi2(md)
+	m.platforms = fmi2.SupportedPlatformsFS(fsys)
+	m.backend = &fmi2Backend{filename: filename, fmus: make(map[InterfaceType]*fmi2.Fmu2)}
+
+	return m, nil
+}
+
+func fmi2Type(interfaceType InterfaceType) fmi2.Type {
+	if interfaceType == ModelExchange {
+		return fmi2.ModelExchangeType
+	}
+	return fmi2.CoSimulationType
+}
+
+func (b *fmi2Backend) instantiate(m *model, instanceName string, interfaceType InterfaceType, options *InstantiateOptions) (Instance, error) {
+
+	fmu, ok := b.fmus[interfaceType]
+	if !ok {
+		var err error
+		if fmu, err = fmi2.New(b.filename, fmi2Type(interfaceType)); err != nil {
+			return nil, err
+		}
+		b.fmus[interfaceType] = fmu
+	}
+
+	var opts []fmi2.InstantiateOption
+
+	if options.logger != nil {
+		opts = append(opts, fmi2.WithLogger(fmi2.Logger(options.logger)))
+	}
+
+	if options.categories != nil {
+		opts = append(opts, fmi2.WithLogCategories(options.categories...))
+	}
+
+	component := fmu.Instantiate(instanceName, fmi2Type(interfaceType), m.instantiationToken, fmu.ResourceLocation(), options.visible, options.loggingOn, opts...)
+	if component == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+
+	return &fmi2Instance{model: m, modelDescription: fmu.ModelDescription(), component: component, interfaceType: interfaceType, options: options}, nil
+}
+
+func (b *fmi2Backend) close() error {
+	var errs []error
+	for _, fmu := range b.fmus {
+		errs = append(errs, fmu.Close())
+	}
+	clear(b.fmus)
+	return errors.Join(errs...)
+}
+
+// fmi2Instance is an Instance of an FMI 2.0 FMU
+type fmi2Instance struct {
+	model            *model
+	modelDescription *fmi2.ModelDescription
+	component        *fmi2.Component
+	interfaceType    InterfaceType
+	options          *InstantiateOptions
+	integrator       *integrator // the integrator of a Model Exchange instance after Initialize
+}
+
+func (c *fmi2Instance) Type() InterfaceType {
+	return c.interfaceType
+}
+
+func (c *fmi2Instance) Initialize(startTime float64, opts ...InitializeOption) error {
+
+	options := &InitializeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	instantiated, initialization, err := c.model.splitStartValues(options.startValues)
+	if err != nil {
+		return err
+	}
+
+	var setupOptions []fmi2.SetupExperimentOption
+
+	if options.tolerance != nil {
+		setupOptions = append(setupOptions, fmi2.WithRelativeTolerance(*options.tolerance))
+	}
+
+	if options.stopTime != nil {
+		setupOptions = append(setupOptions, fmi2.WithStopTime(*options.stopTime))
+	}
+
+	if err := c.component.SetupExperiment(startTime, setupOptions...); err != nil {
+		return err
+	}
+
+	if err := c.component.Set(instantiated); err != nil {
+		return err
+	}
+
+	if err := c.component.EnterInitializationMode(); err != nil {
+		return err
+	}
+
+	if err := c.component.Set(initialization); err != nil {
+		return err
+	}
+
+	if err := c.component.ExitInitializationMode(); err != nil {
+		return err
+	}
+
+	if c.interfaceType == CoSimulation {
+		return nil
+	}
+
+	c.integrator, err = newInstanceIntegrator(c.component, c.modelDescription, startTime, c.options)
+
+	return err
+}
+
+func (c *fmi2Instance) Step(currentTime float64, stepSize float64) error {
+
+	if c.interfaceType == ModelExchange {
+		if c.integrator == nil {
+			return errors.New("the instance has not been initialized")
+		}
+		return c.integrator.step(currentTime, stepSize)
+	}
+
+	err := c.component.DoStep(currentTime, stepSize, true)
+
+	// a slave that requests to terminate returns Discard and reports the request with the Terminated status
+	if fmi2.IsDiscard(err) {
+		if terminated, statusErr := c.component.GetBooleanStatus(fmi2.Terminated); statusErr == nil && terminated {
+			return ErrTerminated
+		}
+	}
+
+	return err
+}
+
+func (c *fmi2Instance) Get(name string) (any, error) {
+	return c.component.Get(name)
+}
+
+func (c *fmi2Instance) GetValues(names ...string) (map[string]any, error) {
+	return c.component.GetValues(names...)
+}
+
+func (c *fmi2Instance) Set(values map[string]any) error {
+	return c.component.Set(values)
+}
+
+func (c *fmi2Instance) Terminate() error {
+	return c.component.Terminate()
+}
+
+func (c *fmi2Instance) Free() {
+	c.component.FreeInstance()
+}
@@ -0,0 +1,447 @@
+package fmu
+
+import (
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi3"
+	"go-fmu/pkg/internal/fmi"
+	"io/fs"
+	"math"
+)
+
+// fmi3Backend loads the shared library of an interface type of an FMI 3.0 FMU when the first
+// instance of the interface type is created
+type fmi3Backend struct {
+	filename  string
+	variables map[string]*fmi3.ModelVariable // the variables by name, which have the exact FMI 3.0 types
+	fmus      map[InterfaceType]*fmi3.Fmu3
+}
+
+func openFmi3(filename string, fsys fs.FS) (Model, error) {
+
+	md, err := fmi3.ReadModelDescriptionFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := modelFromFmi3(md)
+	if err != nil {
+		return nil, err
+	}
+
+	m.platforms = fmi3.SupportedPlatformsFS(fsys)
+
+	b := &fmi3Backend{
+		filename:  filename,
+		variables: make(map[string]*fmi3.ModelVariable),
+		fmus:      make(map[InterfaceType]*fmi3.Fmu3),
+	}
+
+	variables := md.Variables()
+	for i := range variables {
+		b.variables[variables[i].Name] = &variables[i]
+	}
+
+	m.backend = b
+
+	return m, nil
+}
+
+func fmi3Type(interfaceType InterfaceType) fmi3.Type {
+	if interfaceType == ModelExchange {
+		return fmi3.ModelExchangeType
+	}
+	return fmi3.CoSimulationType
+}
+
+func (b *fmi3Backend) instantiate(m *model, instanceName string, interfaceType InterfaceType, options *InstantiateOptions) (Instance, error) {
+
+	fmu, ok := b.fmus[interfaceType]
+	if !ok {
+		var err error
+		if fmu, err = fmi3.New(b.filename, fmi3Type(interfaceType)); err != nil {
+			return nil, err
+		}
+		b.fmus[interfaceType] = fmu
+	}
+
+	var opts []fmi3.InstantiateOption
+
+	if options.logger != nil {
+		opts = append(opts, fmi3.WithLogger(func(instanceName string, status fmi3.Status, category string, message string) {
+			options.logger(instanceName, Status(status), category, message)
+		}))
+	}
+
+	if options.categories != nil {
+		opts = append(opts, fmi3.WithLogCategories(options.categories...))
+	}
+
+	var instance *fmi3.Instance
+
+	if interfaceType == ModelExchange {
+		instance = fmu.InstantiateModelExchange(instanceName, m.instantiationToken, fmu.ResourcePath(), options.visible, options.loggingOn, opts...)
+	} else {
+		instance = fmu.InstantiateCoSimulation(instanceName, m.instantiationToken, fmu.ResourcePath(), options.visible, options.loggingOn, opts...)
+	}
+
+	if instance == nil {
+		return nil, errors.New("failed to instantiate the FMU")
+	}
+
+	c := &fmi3Instance{backend: b, model: m, instance: instance, interfaceType: interfaceType, options: options}
+	c.namedValues = namedValues{model: m, access: c}
+
+	return c, nil
+}
+
+func (b *fmi3Backend) close() error {
+	var errs []error
+	for _, fmu := range b.fmus {
+		errs = append(errs, fmu.Close())
+	}
+	clear(b.fmus)
+	return errors.Join(errs...)
+}
+
+// fmi3Instance is an Instance of an FMI 3.0 FMU. Co-Simulation instances are stepped in Step Mode.
+type fmi3Instance struct {
+	namedValues
+	backend       *fmi3Backend
+	model         *model
+	instance      *fmi3.Instance
+	interfaceType InterfaceType
+	options       *InstantiateOptions
+	integrator    *integrator // the integrator of a Model Exchange instance after Initialize
+}
+
+func (c *fmi3Instance) Type() InterfaceType {
+	return c.interfaceType
+}
+
+func (c *fmi3Instance) Initialize(startTime float64, opts ...InitializeOption) error {
+
+	options := &InitializeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	instantiated, initialization, err := c.model.splitStartValues(options.startValues)
+	if err != nil {
+		return err
+	}
+
+	var initializationOptions []fmi3.InitializationOption
+
+	if options.tolerance != nil {
+		initializationOptions = append(initializationOptions, fmi3.WithTolerance(*options.tolerance))
+	}
+
+	if options.stopTime != nil {
+		initializationOptions = append(initializationOptions, fmi3.WithStopTime(*options.stopTime))
+	}
+
+	if err := c.Set(instantiated); err != nil {
+		return err
+	}
+
+	if err := c.instance.EnterInitializationMode(startTime, initializationOptions...); err != nil {
+		return err
+	}
+
+	if err := c.Set(initialization); err != nil {
+		return err
+	}
+
+	if err := c.instance.ExitInitializationMode(); err != nil {
+		return err
+	}
+
+	if c.interfaceType == CoSimulation {
+		return nil
+	}
+
+	system := &fmi3System{
+		instance: c.instance,
+		nx:       c.model.numberOfContinuousStates,
+		nz:       c.model.numberOfEventIndicators,
+	}
+
+	// the instance is in Event Mode after the initialization
+	nextEventTime, terminate, err := system.updateDiscreteStates()
+	if err != nil {
+		return err
+	}
+
+	if terminate {
+		c.integrator = &integrator{terminated: true}
+		return nil
+	}
+
+	c.integrator, err = newIntegrator(system, system, startTime, nextEventTime, c.options)
+
+	return err
+}
+
+func (c *fmi3Instance) Step(currentTime float64, stepSize float64) error {
+
+	if c.interfaceType == ModelExchange {
+		if c.integrator == nil {
+			return errors.New("the instance has not been initialized")
+		}
+		return c.integrator.step(currentTime, stepSize)
+	}
+
+	result, err := c.instance.DoStep(currentTime, stepSize, true)
+	if err != nil {
+		return err
+	}
+
+	if result.TerminateSimulation {
+		return ErrTerminated
+	}
+
+	return nil
+}
+
+func (c *fmi3Instance) Terminate() error {
+	return c.instance.Terminate()
+}
+
+func (c *fmi3Instance) Free() {
+	c.instance.FreeInstance()
+}
+
+// groupByType returns the indices of the variables by their FMI 3.0 type
+func (c *fmi3Instance) groupByType(variables []*Variable) (map[fmi3.VariableType][]int, error) {
+
+	groups := make(map[fmi3.VariableType][]int)
+
+	for i, v := range variables {
+		mv := c.backend.variables[v.Name]
+		if mv.IsArray() {
+			return nil, fmt.Errorf("the value of the array %s cannot be accessed by name", v.Name)
+		}
+		groups[mv.Type()] = append(groups[mv.Type()], i)
+	}
+
+	return groups, nil
+}
+
+func (c *fmi3Instance) getVariables(variables []*Variable) ([]any, error) {
+
+	groups, err := c.groupByType(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]any, len(variables))
+
+	for t, indices := range groups {
+
+		vr := fmi.Transform(indices, func(_ int, index int) fmi3.ValueReference {
+			return fmi3.ValueReference(variables[index].ValueReference)
+		})
+		n := len(vr)
+
+		var (
+			result []any
+			err    error
+		)
+
+		switch t {
+		case fmi3.Float32Variable:
+			result, err = floats(c.instance.GetFloat32(vr, n))
+		case fmi3.Float64Variable:
+			result, err = floats(c.instance.GetFloat64(vr, n))
+		case fmi3.Int8Variable:
+			result, err = integers(c.instance.GetInt8(vr, n))
+		case fmi3.UInt8Variable:
+			result, err = integers(c.instance.GetUInt8(vr, n))
+		case fmi3.Int16Variable:
+			result, err = integers(c.instance.GetInt16(vr, n))
+		case fmi3.UInt16Variable:
+			result, err = integers(c.instance.GetUInt16(vr, n))
+		case fmi3.Int32Variable:
+			result, err = integers(c.instance.GetInt32(vr, n))
+		case fmi3.UInt32Variable:
+			result, err = integers(c.instance.GetUInt32(vr, n))
+		case fmi3.Int64Variable, fmi3.EnumerationVariable:
+			result, err = integers(c.instance.GetInt64(vr, n))
+		case fmi3.UInt64Variable:
+			result, err = integers(c.instance.GetUInt64(vr, n))
+		case fmi3.BooleanVariable:
+			result, err = unchanged(c.instance.GetBoolean(vr, n))
+		case fmi3.StringVariable:
+			result, err = unchanged(c.instance.GetString(vr, n))
+		case fmi3.BinaryVariable:
+			result, err = unchanged(c.instance.GetBinary(vr, n))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for i, index := range indices {
+			values[index] = result[i]
+		}
+	}
+
+	return values, nil
+}
+
+func (c *fmi3Instance) setVariables(variables []*Variable, values []any) error {
+
+	groups, err := c.groupByType(variables)
+	if err != nil {
+		return err
+	}
+
+	for t, indices := range groups {
+
+		vr := fmi.Transform(indices, func(_ int, index int) fmi3.ValueReference {
+			return fmi3.ValueReference(variables[index].ValueReference)
+		})
+		value := fmi.Transform(indices, func(_ int, index int) any { return values[index] })
+
+		var err error
+
+		switch t {
+		case fmi3.Float32Variable:
+			err = c.instance.SetFloat32(vr, toFloats[float32](value))
+		case fmi3.Float64Variable:
+			err = c.instance.SetFloat64(vr, toFloats[float64](value))
+		case fmi3.Int8Variable:
+			err = c.instance.SetInt8(vr, toIntegers[int8](value))
+		case fmi3.UInt8Variable:
+			err = c.instance.SetUInt8(vr, toIntegers[uint8](value))
+		case fmi3.Int16Variable:
+			err = c.instance.SetInt16(vr, toIntegers[int16](value))
+		case fmi3.UInt16Variable:
+			err = c.instance.SetUInt16(vr, toIntegers[uint16](value))
+		case fmi3.Int32Variable:
+			err = c.instance.SetInt32(vr, toIntegers[int32](value))
+		case fmi3.UInt32Variable:
+			err = c.instance.SetUInt32(vr, toIntegers[uint32](value))
+		case fmi3.Int64Variable, fmi3.EnumerationVariable:
+			err = c.instance.SetInt64(vr, toIntegers[int64](value))
+		case fmi3.UInt64Variable:
+			err = c.instance.SetUInt64(vr, toIntegers[uint64](value))
+		case fmi3.BooleanVariable:
+			err = c.instance.SetBoolean(vr, toType[bool](value))
+		case fmi3.StringVariable:
+			err = c.instance.SetString(vr, toType[string](value))
+		case fmi3.BinaryVariable:
+			err = c.instance.SetBinary(vr, toType[[]byte](value))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// floats converts the result of a GetFloat function to float64 values
+func floats[T float32 | float64](values []T, err error) ([]any, error) {
+	return fmi.Transform(values, func(_ int, v T) any { return float64(v) }), err
+}
+
+// integers converts the result of a GetInt or GetUInt function to int values
+func integers[T int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64](values []T, err error) ([]any, error) {
+	return fmi.Transform(values, func(_ int, v T) any { return int(v) }), err
+}
+
+// unchanged returns the result of a get function whose values have the Go type of the variables
+func unchanged[T any](values []T, err error) ([]any, error) {
+	return fmi.Transform(values, func(_ int, v T) any { return v }), err
+}
+
+// toFloats converts float64 values for a SetFloat function
+func toFloats[T float32 | float64](values []any) []T {
+	return fmi.Transform(values, func(_ int, v any) T { return T(v.(float64)) })
+}
+
+// toIntegers converts int values for a SetInt or SetUInt function
+func toIntegers[T int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64](values []any) []T {
+	return fmi.Transform(values, func(_ int, v any) T { return T(v.(int)) })
+}
+
+// toType returns the values that have the Go type of the set function
+func toType[T any](values []any) []T {
+	return fmi.Transform(values, func(_ int, v any) T { return v.(T) })
+}
+
+// fmi3System adapts an FMI 3.0 Model Exchange instance to the OdeSystem and EventHandler interfaces of the fmi2 package
+type fmi3System struct {
+	instance *fmi3.Instance
+	nx       int
+	nz       int
+}
+
+func (s *fmi3System) NumberOfStates() int {
+	return s.nx
+}
+
+func (s *fmi3System) NumberOfEventIndicators() int {
+	return s.nz
+}
+
+func (s *fmi3System) SetTime(t float64) error {
+	return s.instance.SetTime(t)
+}
+
+func (s *fmi3System) GetContinuousStates() ([]float64, error) {
+	return s.instance.GetContinuousStates(s.nx)
+}
+
+func (s *fmi3System) SetContinuousStates(x []float64) error {
+	return s.instance.SetContinuousStates(x)
+}
+
+func (s *fmi3System) GetDerivatives() ([]float64, error) {
+	return s.instance.GetContinuousStateDerivatives(s.nx)
+}
+
+func (s *fmi3System) GetEventIndicators() ([]float64, error) {
+	return s.instance.GetEventIndicators(s.nz)
+}
+
+func (s *fmi3System) CompletedIntegratorStep() (bool, bool, error) {
+	return s.instance.CompletedIntegratorStep(true)
+}
+
+func (s *fmi3System) HandleEvent(t float64) (float64, bool, error) {
+	if err := s.instance.EnterEventMode(); err != nil {
+		return 0, false, err
+	}
+	return s.updateDiscreteStates()
+}
+
+// updateDiscreteStates performs the event iteration in Event Mode and enters Continuous-Time Mode
+func (s *fmi3System) updateDiscreteStates() (float64, bool, error) {
+
+	for {
+		info, err := s.instance.UpdateDiscreteStates()
+		if err != nil {
+			return 0, false, err
+		}
+
+		if info.TerminateSimulation {
+			return 0, true, nil
+		}
+
+		if !info.DiscreteStatesNeedUpdate {
+			if err := s.instance.EnterContinuousTimeMode(); err != nil {
+				return 0, false, err
+			}
+
+			if info.NextEventTimeDefined {
+				return info.NextEventTime, false, nil
+			}
+
+			return math.Inf(1), false, nil
+		}
+	}
+}
@@ -0,0 +1,285 @@
+// Package fmu provides a version independent interface to FMI 1.0, 2.0 and 3.0 FMUs.
+// Open detects the FMI version from the model description and returns a Model, which
+// creates instances that are initialized, stepped and accessed by variable name the same
+// way for all versions. Model Exchange instances are integrated with the solvers of fmi2.
+package fmu
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"io/fs"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// ErrTerminated is returned by Instance.Step when the FMU requested to terminate the simulation
+var ErrTerminated = errors.New("the FMU terminated the simulation")
+
+// InterfaceType is the interface type of an instance
+type InterfaceType int
+
+const (
+	ModelExchange InterfaceType = iota
+	CoSimulation
+)
+
+func (t InterfaceType) String() string {
+	switch t {
+	case ModelExchange:
+		return "Model Exchange"
+	case CoSimulation:
+		return "Co-Simulation"
+	default:
+		return fmt.Sprintf("InterfaceType(%d)", int(t))
+	}
+}
+
+// VariableType is the type of a variable. The Float and Int types of FMI 3.0 are mapped to Real and Integer.
+type VariableType string
+
+const (
+	Real        VariableType = "Real"
+	Integer     VariableType = "Integer"
+	Boolean     VariableType = "Boolean"
+	String      VariableType = "String"
+	Enumeration VariableType = "Enumeration"
+	Binary      VariableType = "Binary"
+	Clock       VariableType = "Clock"
+)
+
+// Variable is a model variable. The values of Real variables are float64, the values of Integer and
+// Enumeration variables are int, the values of Boolean, String and Binary variables are bool, string
+// and []byte. Start is nil if the variable has no start value. Initial is the initial attribute or its
+// default, which is empty for inputs and the independent variable. FMI 1.0 variables are described
+// with the causality, variability and initial attribute of their FMI 2.0 counterparts.
+type Variable struct {
+	Name           string
+	ValueReference uint32
+	Description    string
+	Type           VariableType
+	Causality      string
+	Variability    string
+	Initial        string
+	DeclaredType   string
+	Unit           string
+	Start          any
+}
+
+type DefaultExperiment struct {
+	StartTime *float64
+	StopTime  *float64
+	Tolerance *float64
+	StepSize  *float64
+}
+
+// Status is the status of a log message. The status codes of FMI 1.0, 2.0 and 3.0 have the same values.
+type Status = fmi2.Status
+
+// Logger receives the log messages of an instance
+type Logger func(instanceName string, status Status, category string, message string)
+
+// Model is the model description and the shared libraries of an FMU of any FMI version
+type Model interface {
+	FmiVersion() string
+	ModelName() string
+	Description() string
+
+	// InstantiationToken returns the instantiation token (FMI 3.0) or the GUID (FMI 1.0 and 2.0)
+	InstantiationToken() string
+
+	GenerationTool() string
+	InterfaceTypes() []InterfaceType
+
+	// Platforms returns the platforms of the shared libraries in the FMU, e.g. "x86_64-linux"
+	Platforms() []string
+
+	// Variables returns the variables in the order of the model description
+	Variables() []Variable
+
+	// VariableByName returns the variable with the given name or nil
+	VariableByName(name string) *Variable
+
+	// DefaultExperiment returns the default experiment or nil
+	DefaultExperiment() *DefaultExperiment
+
+	NumberOfContinuousStates() int
+	NumberOfEventIndicators() int
+
+	// Instantiate loads the shared library of the interface type and creates a new instance
+	Instantiate(instanceName string, interfaceType InterfaceType, opts ...InstantiateOption) (Instance, error)
+
+	// Close unloads the shared libraries and removes the extracted files. All instances must have been freed.
+	Close() error
+}
+
+// Instance is an instance of a Model. Initialize must be called before Step. Model Exchange instances
+// are integrated by Step with the solver that was selected by WithSolver and handle the time, state and
+// step events of the FMU.
+type Instance interface {
+	Type() InterfaceType
+
+	// Initialize sets the start values and initializes the instance at startTime
+	Initialize(startTime float64, opts ...InitializeOption) error
+
+	// Step advances the instance from currentTime to currentTime + stepSize. ErrTerminated is returned
+	// if the FMU requested to terminate the simulation.
+	Step(currentTime float64, stepSize float64) error
+
+	// Get returns the value of the variable with the given name
+	Get(name string) (any, error)
+
+	// GetValues returns the values of the variables with the given names by name
+	GetValues(names ...string) (map[string]any, error)
+
+	// Set sets the values of the variables by name. Numbers of any Go type can be used for Real and
+	// Integer variables and Enumeration variables can be set to the name of an item.
+	Set(values map[string]any) error
+
+	Terminate() error
+
+	// Free disposes the instance
+	Free()
+}
+
+type InstantiateOption func(*InstantiateOptions)
+
+type InstantiateOptions struct {
+	logger        Logger
+	categories    []string
+	loggingOn     bool
+	visible       bool
+	solver        string
+	solverOptions []fmi2.SolverOption
+}
+
+// WithLogger sets the logger that receives the messages of the instance instead of stdout
+func WithLogger(logger Logger) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.logger = logger
+	}
+}
+
+// WithLogCategories passes only the messages of the given categories to the logger
+func WithLogCategories(categories ...string) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.categories = categories
+	}
+}
+
+// WithDebugLogging enables the debug logging of the FMU
+func WithDebugLogging() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.loggingOn = true
+	}
+}
+
+// WithVisible allows Co-Simulation FMUs to interact with the user
+func WithVisible() InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.visible = true
+	}
+}
+
+// WithSolver selects the solver that integrates a Model Exchange instance (see fmi2.NewSolver), which is Euler by default
+func WithSolver(name string, opts ...fmi2.SolverOption) InstantiateOption {
+	return func(o *InstantiateOptions) {
+		o.solver = name
+		o.solverOptions = opts
+	}
+}
+
+type InitializeOption func(*InitializeOptions)
+
+type InitializeOptions struct {
+	stopTime    *float64
+	tolerance   *float64
+	startValues map[string]any
+}
+
+// WithStopTime passes the stop time of the simulation to the FMU
+func WithStopTime(stopTime float64) InitializeOption {
+	return func(o *InitializeOptions) {
+		o.stopTime = &stopTime
+	}
+}
+
+// WithTolerance passes the relative tolerance to the FMU
+func WithTolerance(tolerance float64) InitializeOption {
+	return func(o *InitializeOptions) {
+		o.tolerance = &tolerance
+	}
+}
+
+// WithStartValues sets the start values of variables by name. The values are set before or during the
+// initialization, depending on the causality, variability and initial attribute of the variables.
+func WithStartValues(startValues map[string]any) InitializeOption {
+	return func(o *InitializeOptions) {
+		o.startValues = startValues
+	}
+}
+
+/*
+Open an FMU of any FMI version
+
+Parameters:
+
+	filename  filename of the FMU
+
+Returns:
+
+	a Model, which must be closed
+*/
+func Open(filename string) (Model, error) {
+
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	version, err := readFmiVersion(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case version == "1.0":
+		return openFmi1(filename, r)
+	case version == "2.0":
+		return openFmi2(filename, r)
+	case strings.HasPrefix(version, "3."):
+		return openFmi3(filename, r)
+	default:
+		return nil, fmt.Errorf("unsupported FMI version: %s", version)
+	}
+}
+
+// readFmiVersion reads the fmiVersion attribute of the model description
+func readFmiVersion(fsys fs.FS) (string, error) {
+
+	f, err := fsys.Open("modelDescription.xml")
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	var md struct {
+		FmiVersion string `xml:"fmiVersion,attr"`
+	}
+
+	decoder := xml.NewDecoder(bufio.NewReader(f))
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	if err := decoder.Decode(&md); err != nil {
+		return "", err
+	}
+
+	return md.FmiVersion, nil
+}
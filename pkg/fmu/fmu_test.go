@@ -0,0 +1,418 @@
+package fmu_test
+
+import (
+	"go-fmu/pkg/fmi1"
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmu"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+
+	model, err := fmu.Open("../../examples/Ball.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	require.Equal(t, "2.0", model.FmiVersion())
+	require.Equal(t, "Ball", model.ModelName())
+	require.Equal(t, "{", model.InstantiationToken()[:1])
+	require.Equal(t, []fmu.InterfaceType{fmu.ModelExchange}, model.InterfaceTypes())
+	require.Equal(t, 2, model.NumberOfContinuousStates())
+	require.Equal(t, 2, model.NumberOfEventIndicators())
+	require.Len(t, model.Variables(), 8)
+	require.InDelta(t, 1.0, *model.DefaultExperiment().StopTime, 1e-9)
+
+	h0 := model.VariableByName("h0")
+	require.NotNil(t, h0)
+	require.Equal(t, fmu.Real, h0.Type)
+	require.Equal(t, "parameter", h0.Causality)
+	require.Equal(t, "exact", h0.Initial)
+
+	reset := model.VariableByName("reset")
+	require.Equal(t, fmu.Boolean, reset.Type)
+	require.Equal(t, false, reset.Start)
+	require.Empty(t, reset.Initial)
+
+	require.Nil(t, model.VariableByName("unknown"))
+
+	_, err = model.Instantiate("ball", fmu.CoSimulation)
+	require.ErrorContains(t, err, "does not support Co-Simulation")
+
+	_, err = fmu.Open("../../examples/unknown.fmu")
+	require.Error(t, err)
+}
+
+func TestModelExchange(t *testing.T) {
+
+	const delta = 1e-4
+
+	model, err := fmu.Open("../../examples/Ball.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	instance, err := model.Instantiate("ball", fmu.ModelExchange, fmu.WithSolver("RK4"))
+	require.NoError(t, err)
+
+	defer instance.Free()
+
+	require.Equal(t, fmu.ModelExchange, instance.Type())
+
+	err = instance.Initialize(0, fmu.WithStopTime(0.1), fmu.WithStartValues(map[string]any{"h0": 2, "v": 1.0}))
+	require.NoError(t, err)
+
+	h, err := instance.Get("h")
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, h, delta)
+
+	time := 0.0
+	for range 10 {
+		require.NoError(t, instance.Step(time, 0.01))
+		time += 0.01
+	}
+
+	values, err := instance.GetValues("h", "v")
+	require.NoError(t, err)
+
+	// h(t) = h0 + v0 t - g t^2 / 2
+	require.InDelta(t, 2.0+0.1-9.81*0.1*0.1/2, values["h"], 1e-3)
+	require.InDelta(t, 1.0-9.81*0.1, values["v"], 1e-3)
+
+	require.NoError(t, instance.Terminate())
+}
+
+func TestModelExchangeStartValues(t *testing.T) {
+
+	model, err := fmu.Open("../../examples/Ball.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	instance, err := model.Instantiate("ball", fmu.ModelExchange)
+	require.NoError(t, err)
+
+	defer instance.Free()
+
+	// the initial value of h is calculated from h0
+	err = instance.Initialize(0, fmu.WithStartValues(map[string]any{"h": 2.0}))
+	require.ErrorContains(t, err, "calculated")
+
+	err = instance.Initialize(0, fmu.WithStartValues(map[string]any{"unknown": 2.0}))
+	require.ErrorContains(t, err, "unknown")
+}
+
+func TestTimeEvents(t *testing.T) {
+
+	model, err := fmu.Open("../../examples/Ticker.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	instance, err := model.Instantiate("ticker", fmu.ModelExchange)
+	require.NoError(t, err)
+
+	defer instance.Free()
+
+	require.NoError(t, instance.Initialize(0))
+
+	// the steps end between the ticks, which are handled by the integrator
+	require.NoError(t, instance.Step(0, 1.25))
+	require.NoError(t, instance.Step(1.25, 1.25))
+	require.NoError(t, instance.Step(2.5, 0.75))
+
+	ticks, err := instance.Get("ticks")
+	require.NoError(t, err)
+	require.InDelta(t, 3.0, ticks, 1e-9)
+}
+
+func TestCoSimulation(t *testing.T) {
+
+	model, err := fmu.Open("../../examples/Controller.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	require.Equal(t, []fmu.InterfaceType{fmu.ModelExchange, fmu.CoSimulation}, model.InterfaceTypes())
+
+	instance, err := model.Instantiate("controller", fmu.CoSimulation)
+	require.NoError(t, err)
+
+	defer instance.Free()
+
+	require.NoError(t, instance.Initialize(0, fmu.WithStopTime(1), fmu.WithStartValues(map[string]any{"u_s": 0.0})))
+
+	for i := range 10 {
+		require.NoError(t, instance.Step(float64(i)*0.1, 0.1))
+	}
+
+	require.NoError(t, instance.Set(map[string]any{"u_s": 2}))
+
+	u, err := instance.Get("u_s")
+	require.NoError(t, err)
+	require.InDelta(t, 2.0, u, 1e-9)
+
+	require.ErrorContains(t, instance.Set(map[string]any{"u_s": "2"}), "must be a number")
+
+	require.NoError(t, instance.Terminate())
+}
+
+// requireValues compares the values of an instance with the expected values, numbers with a tolerance
+func requireValues(t *testing.T, instance fmu.Instance, expected map[string]any, delta float64) {
+
+	for name, value := range expected {
+		actual, err := instance.Get(name)
+		require.NoError(t, err)
+
+		switch value.(type) {
+		case float64, int:
+			require.InDelta(t, value, actual, delta, name)
+		default:
+			require.Equal(t, value, actual, name)
+		}
+	}
+}
+
+func TestOpenVersions(t *testing.T) {
+
+	tests := []struct {
+		filename        string
+		fmiVersion      string
+		modelName       string
+		interfaceTypes  []fmu.InterfaceType
+		platforms       []string
+		states          int
+		eventIndicators int
+		variables       int
+		stopTime        float64
+		starts          map[string]any // the start values of the variables
+	}{
+		{
+			filename:        "../../examples/BouncingBall.fmu",
+			fmiVersion:      "1.0",
+			modelName:       "BouncingBall",
+			interfaceTypes:  []fmu.InterfaceType{fmu.ModelExchange},
+			platforms:       []string{"linux64"},
+			states:          2,
+			eventIndicators: 1,
+			variables:       7,
+			stopTime:        3,
+			starts:          map[string]any{"h": 1.0, "e": 0.7, "bounces": 0, "der(v)": nil},
+		},
+		{
+			filename:       "../../examples/Integrator.fmu",
+			fmiVersion:     "1.0",
+			modelName:      "Integrator",
+			interfaceTypes: []fmu.InterfaceType{fmu.CoSimulation},
+			platforms:      []string{"linux64"},
+			variables:      7,
+			stopTime:       1,
+			starts:         map[string]any{"u": 1.0, "steps": 0, "enabled": true, "label": "integrator"},
+		},
+		{
+			filename:       "../../examples/Feedthrough.fmu",
+			fmiVersion:     "3.0",
+			modelName:      "Feedthrough",
+			interfaceTypes: []fmu.InterfaceType{fmu.ModelExchange, fmu.CoSimulation},
+			platforms:      []string{"x86_64-linux"},
+			states:         1,
+			variables:      33,
+			stopTime:       2,
+			starts: map[string]any{
+				"Float32_input":  0.0,
+				"UInt64_input":   0,
+				"Boolean_input":  false,
+				"String_input":   "Set me!",
+				"Binary_input":   []byte("foo"),
+				"Float64_output": nil,
+				"inClock":        nil,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.modelName, func(t *testing.T) {
+
+			model, err := fmu.Open(test.filename)
+			require.NoError(t, err)
+
+			defer model.Close()
+
+			require.Equal(t, test.fmiVersion, model.FmiVersion())
+			require.Equal(t, test.modelName, model.ModelName())
+			require.Equal(t, test.interfaceTypes, model.InterfaceTypes())
+			require.Equal(t, test.platforms, model.Platforms())
+			require.Equal(t, test.states, model.NumberOfContinuousStates())
+			require.Equal(t, test.eventIndicators, model.NumberOfEventIndicators())
+			require.Len(t, model.Variables(), test.variables)
+			require.InDelta(t, test.stopTime, *model.DefaultExperiment().StopTime, 1e-9)
+
+			for name, start := range test.starts {
+				v := model.VariableByName(name)
+				require.NotNil(t, v, name)
+				require.Equal(t, start, v.Start, name)
+			}
+		})
+	}
+}
+
+func TestModelExchangeVersions(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		filename    string
+		startValues map[string]any
+		expected    map[string]any // the values at t = 1
+	}{
+		{
+			name:        "FMI 1.0",
+			filename:    "../../examples/BouncingBall.fmu",
+			startValues: map[string]any{"v": 0, "e": 0.7},
+			// the ball hits the ground at t = sqrt(2 / g) and bounces off with 0.7 sqrt(2 g)
+			expected: map[string]any{"h": 0.22505, "v": -2.2800, "bounces": 1},
+		},
+		{
+			name:        "FMI 3.0",
+			filename:    "../../examples/Feedthrough.fmu",
+			startValues: map[string]any{"Float64_input": 2.0, "Int32_input": 3},
+			// the time events at t = 0.5 and t = 1 are handled by the integrator
+			expected: map[string]any{"x": 2.0, "Float64_output": 2.0, "Int32_output": 3, "ticks": 2},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			model, err := fmu.Open(test.filename)
+			require.NoError(t, err)
+
+			defer model.Close()
+
+			instance, err := model.Instantiate("instance", fmu.ModelExchange, fmu.WithSolver("RK4", fmi2.WithStepSize(1e-3)))
+			require.NoError(t, err)
+
+			defer instance.Free()
+
+			require.NoError(t, instance.Initialize(0, fmu.WithStopTime(1), fmu.WithStartValues(test.startValues)))
+
+			for i := range 10 {
+				require.NoError(t, instance.Step(float64(i)*0.1, 0.1))
+			}
+
+			requireValues(t, instance, test.expected, 1e-3)
+
+			_, err = instance.Get("unknown")
+			require.ErrorContains(t, err, "unknown")
+
+			require.NoError(t, instance.Terminate())
+		})
+	}
+}
+
+func TestCoSimulationVersions(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		filename    string
+		startValues map[string]any
+		inputs      map[string]any // the values that are set after the initialization
+		expected    map[string]any // the values at t = 1
+	}{
+		{
+			name:        "FMI 1.0",
+			filename:    "../../examples/Integrator.fmu",
+			startValues: map[string]any{"k": 2, "label": "test"},
+			inputs:      map[string]any{"u": 0.5, "enabled": true},
+			expected:    map[string]any{"y": 1.0, "steps": 10, "enabled": true, "label": "test"},
+		},
+		{
+			name:        "FMI 3.0",
+			filename:    "../../examples/Feedthrough.fmu",
+			startValues: map[string]any{"String_input": "start", "Binary_input": []byte("bar")},
+			inputs: map[string]any{
+				"Float32_input": 1.5,
+				"Float64_input": 2,
+				"Int8_input":    -8,
+				"UInt8_input":   8,
+				"Int16_input":   -16,
+				"UInt16_input":  16,
+				"Int32_input":   -32,
+				"UInt32_input":  32,
+				"Int64_input":   int64(-1) << 40,
+				"UInt64_input":  64,
+				"Boolean_input": true,
+			},
+			expected: map[string]any{
+				"Float32_output": 1.5,
+				"Float64_output": 2.0,
+				"Int8_output":    -8,
+				"UInt8_output":   8,
+				"Int16_output":   -16,
+				"UInt16_output":  16,
+				"Int32_output":   -32,
+				"UInt32_output":  32,
+				"Int64_output":   -1 << 40,
+				"UInt64_output":  64,
+				"Boolean_output": true,
+				"String_output":  "start",
+				"Binary_output":  []byte("bar"),
+				"x":              2.0,
+				"ticks":          2,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			model, err := fmu.Open(test.filename)
+			require.NoError(t, err)
+
+			defer model.Close()
+
+			instance, err := model.Instantiate("instance", fmu.CoSimulation)
+			require.NoError(t, err)
+
+			defer instance.Free()
+
+			require.Equal(t, fmu.CoSimulation, instance.Type())
+
+			require.NoError(t, instance.Initialize(0, fmu.WithStopTime(1), fmu.WithStartValues(test.startValues)))
+			require.NoError(t, instance.Set(test.inputs))
+
+			for i := range 10 {
+				require.NoError(t, instance.Step(float64(i)*0.1, 0.1))
+			}
+
+			requireValues(t, instance, test.expected, 1e-9)
+
+			require.NoError(t, instance.Terminate())
+		})
+	}
+}
+
+func TestCoSimulationDiscard(t *testing.T) {
+
+	model, err := fmu.Open("../../examples/Integrator.fmu")
+	require.NoError(t, err)
+
+	defer model.Close()
+
+	instance, err := model.Instantiate("integrator", fmu.CoSimulation)
+	require.NoError(t, err)
+
+	defer instance.Free()
+
+	require.NoError(t, instance.Initialize(0, fmu.WithStartValues(map[string]any{"discardTime": 0.5})))
+
+	for i := range 5 {
+		require.NoError(t, instance.Step(float64(i)*0.1, 0.1))
+	}
+
+	// an FMI 1.0 slave that cannot complete the step returns Discard
+	err = instance.Step(0.5, 0.1)
+	require.True(t, fmi1.IsDiscard(err))
+	require.NotErrorIs(t, err, fmu.ErrTerminated)
+}
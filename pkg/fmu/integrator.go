@@ -0,0 +1,71 @@
+package fmu
+
+import "go-fmu/pkg/fmi2"
+
+// integrator advances a Model Exchange instance over the steps of an Instance with the Integrator of
+// the fmi2 package, which handles the time, state and step events
+type integrator struct {
+	integrator *fmi2.Integrator
+	terminated bool
+}
+
+// newIntegrator creates the solver for an initialized instance at time t
+func newIntegrator(system fmi2.OdeSystem, events fmi2.EventHandler, t float64, nextEventTime float64, options *InstantiateOptions) (*integrator, error) {
+
+	solver, err := fmi2.NewSolver(options.solver, system, t, options.solverOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &integrator{integrator: fmi2.NewIntegrator(system, events, solver, nextEventTime)}, nil
+}
+
+// newInstanceIntegrator performs the event iteration of an FMI 1.0 or 2.0 instance after the
+// initialization and creates the integrator at time t
+func newInstanceIntegrator(fmu fmi2.Instance, md *fmi2.ModelDescription, t float64, options *InstantiateOptions) (*integrator, error) {
+
+	events := fmi2.NewInstanceEvents(fmu, md)
+
+	// the instance is in Event Mode after the initialization
+	nextEventTime, terminate, err := events.UpdateDiscreteStates()
+	if err != nil {
+		return nil, err
+	}
+
+	if terminate {
+		return &integrator{terminated: true}, nil
+	}
+
+	system, err := fmi2.NewSystem(fmu, md)
+	if err != nil {
+		return nil, err
+	}
+
+	return newIntegrator(system, events, t, nextEventTime, options)
+}
+
+// step integrates from t to t + h. The integration stops at every event, which is handled before
+// the integration is continued.
+func (i *integrator) step(t float64, h float64) error {
+
+	if i.terminated {
+		return ErrTerminated
+	}
+
+	tEnd := t + h
+
+	for t < tEnd && !fmi2.Float64IsClose(t, tEnd) {
+
+		var err error
+		if t, err = i.integrator.Step(t, tEnd, false); err != nil {
+			return err
+		}
+
+		if i.integrator.Terminated() {
+			i.terminated = true
+			return ErrTerminated
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package fmu
+
+import (
+	"encoding/hex"
+	"fmt"
+	"go-fmu/pkg/fmi2"
+	"go-fmu/pkg/fmi3"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// backend loads the shared libraries and creates the instances of one FMI version
+type backend interface {
+	instantiate(m *model, instanceName string, interfaceType InterfaceType, options *InstantiateOptions) (Instance, error)
+	close() error
+}
+
+// model implements Model for all FMI versions. The model description is converted when the FMU
+// is opened and the version specific parts are implemented by the backend.
+type model struct {
+	fmiVersion               string
+	modelName                string
+	description              string
+	instantiationToken       string
+	generationTool           string
+	interfaceTypes           []InterfaceType
+	platforms                []string
+	variables                []Variable
+	variablesByName          map[string]*Variable
+	items                    map[string]map[string]int // the values of the enumeration items by type and name
+	defaultExperiment        *DefaultExperiment
+	numberOfContinuousStates int
+	numberOfEventIndicators  int
+	backend                  backend
+}
+
+func (m *model) FmiVersion() string {
+	return m.fmiVersion
+}
+
+func (m *model) ModelName() string {
+	return m.modelName
+}
+
+func (m *model) Description() string {
+	return m.description
+}
+
+func (m *model) InstantiationToken() string {
+	return m.instantiationToken
+}
+
+func (m *model) GenerationTool() string {
+	return m.generationTool
+}
+
+func (m *model) InterfaceTypes() []InterfaceType {
+	return slices.Clone(m.interfaceTypes)
+}
+
+func (m *model) Platforms() []string {
+	return slices.Clone(m.platforms)
+}
+
+func (m *model) Variables() []Variable {
+	return slices.Clone(m.variables)
+}
+
+func (m *model) VariableByName(name string) *Variable {
+	v, ok := m.variablesByName[name]
+	if !ok {
+		return nil
+	}
+	variable := *v
+	return &variable
+}
+
+func (m *model) DefaultExperiment() *DefaultExperiment {
+	if m.defaultExperiment == nil {
+		return nil
+	}
+	experiment := *m.defaultExperiment
+	return &experiment
+}
+
+func (m *model) NumberOfContinuousStates() int {
+	return m.numberOfContinuousStates
+}
+
+func (m *model) NumberOfEventIndicators() int {
+	return m.numberOfEventIndicators
+}
+
+func (m *model) Instantiate(instanceName string, interfaceType InterfaceType, opts ...InstantiateOption) (Instance, error) {
+
+	if !slices.Contains(m.interfaceTypes, interfaceType) {
+		return nil, fmt.Errorf("the FMU does not support %v", interfaceType)
+	}
+
+	options := &InstantiateOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return m.backend.instantiate(m, instanceName, interfaceType, options)
+}
+
+func (m *model) Close() error {
+	return m.backend.close()
+}
+
+// variable returns the variable with the given name
+func (m *model) variable(name string) (*Variable, error) {
+	v, ok := m.variablesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown variable %s", name)
+	}
+	return v, nil
+}
+
+// index creates the lookup table of the variables by name
+func (m *model) index() {
+	m.variablesByName = make(map[string]*Variable, len(m.variables))
+	for i := range m.variables {
+		m.variablesByName[m.variables[i].Name] = &m.variables[i]
+	}
+}
+
+// initialOf returns the initial attribute or its default for the combination of causality and
+// variability, which is the same in FMI 2.0 and 3.0. Inputs and the independent variable have no
+// initial attribute.
+func initialOf(initial string, causality string, variability string) string {
+	if initial != "" {
+		return initial
+	}
+
+	switch causality {
+	case "parameter", "structuralParameter":
+		return "exact"
+	case "calculatedParameter":
+		return "calculated"
+	case "input", "independent":
+		return ""
+	default:
+		if variability == "constant" {
+			return "exact"
+		}
+		return "calculated"
+	}
+}
+
+// modelFromFmi2 converts an FMI 2.0 model description, which is also used for FMI 1.0
+// after the fmi2 package has converted it
+func modelFromFmi2(md *fmi2.ModelDescription) *model {
+
+	m := &model{
+		fmiVersion:               md.FmiVersion,
+		modelName:                md.ModelName,
+		description:              md.Description,
+		instantiationToken:       md.Guid,
+		generationTool:           md.GenerationTool,
+		items:                    make(map[string]map[string]int),
+		numberOfContinuousStates: md.NumberOfContinuousStates(),
+		numberOfEventIndicators:  int(md.NumberOfEventIndicators),
+	}
+
+	if md.ModelExchange != nil {
+		m.interfaceTypes = append(m.interfaceTypes, ModelExchange)
+	}
+
+	if md.CoSimulation != nil {
+		m.interfaceTypes = append(m.interfaceTypes, CoSimulation)
+	}
+
+	if e := md.DefaultExperiment; e != nil {
+		m.defaultExperiment = &DefaultExperiment{
+			StartTime: e.StartTime,
+			StopTime:  e.StopTime,
+			Tolerance: e.Tolerance,
+			StepSize:  e.StepSize,
+		}
+	}
+
+	for _, types := range md.TypeDefinitions {
+		for _, t := range types.SimpleType {
+			if t.Enumeration == nil {
+				continue
+			}
+			items := make(map[string]int, len(t.Enumeration.Item))
+			for _, item := range t.Enumeration.Item {
+				items[item.Name] = item.Value
+			}
+			m.items[t.Name] = items
+		}
+	}
+
+	if md.ModelVariables != nil {
+		for _, sv := range md.ModelVariables.ScalarVariable {
+
+			v := Variable{
+				Name:           sv.Name,
+				ValueReference: uint32(sv.ValueReference),
+				Description:    sv.Description,
+				Causality:      sv.Causality,
+				Variability:    sv.Variability,
+			}
+
+			if v.Causality == "" {
+				v.Causality = "local"
+			}
+
+			if v.Variability == "" {
+				v.Variability = "continuous"
+			}
+
+			v.Initial = initialOf(sv.Initial, v.Causality, v.Variability)
+
+			switch {
+			case sv.Real != nil:
+				v.Type, v.DeclaredType, v.Unit = Real, sv.Real.DeclaredType, sv.Real.Unit
+				if sv.Real.Start != nil {
+					v.Start = *sv.Real.Start
+				}
+			case sv.Integer != nil:
+				v.Type, v.DeclaredType = Integer, sv.Integer.DeclaredType
+				if sv.Integer.Start != nil {
+					v.Start = *sv.Integer.Start
+				}
+			case sv.Boolean != nil:
+				v.Type, v.DeclaredType = Boolean, sv.Boolean.DeclaredType
+				if sv.Boolean.Start != nil {
+					v.Start = *sv.Boolean.Start
+				}
+			case sv.String != nil:
+				v.Type, v.DeclaredType = String, sv.String.DeclaredType
+				if sv.String.Start != nil {
+					v.Start = *sv.String.Start
+				}
+			case sv.Enumeration != nil:
+				v.Type, v.DeclaredType = Enumeration, sv.Enumeration.DeclaredType
+				if sv.Enumeration.Start != nil {
+					v.Start = *sv.Enumeration.Start
+				}
+			default:
+				// variables without a type element are not valid and cannot be accessed
+				continue
+			}
+
+			m.variables = append(m.variables, v)
+		}
+	}
+
+	m.index()
+
+	return m
+}
+
+// fmi3VariableType maps the type of an FMI 3.0 variable to its VariableType
+func fmi3VariableType(t fmi3.VariableType) VariableType {
+	switch t {
+	case fmi3.Float32Variable, fmi3.Float64Variable:
+		return Real
+	case fmi3.BooleanVariable:
+		return Boolean
+	case fmi3.StringVariable:
+		return String
+	case fmi3.BinaryVariable:
+		return Binary
+	case fmi3.EnumerationVariable:
+		return Enumeration
+	case fmi3.ClockVariable:
+		return Clock
+	default:
+		return Integer
+	}
+}
+
+// modelFromFmi3 converts an FMI 3.0 model description
+func modelFromFmi3(md *fmi3.ModelDescription) (*model, error) {
+
+	m := &model{
+		fmiVersion:               md.FmiVersion,
+		modelName:                md.ModelName,
+		description:              md.Description,
+		instantiationToken:       md.InstantiationToken,
+		generationTool:           md.GenerationTool,
+		items:                    make(map[string]map[string]int),
+		numberOfContinuousStates: md.NumberOfContinuousStates(),
+		numberOfEventIndicators:  md.NumberOfEventIndicators(),
+	}
+
+	for _, t := range md.InterfaceTypes() {
+		switch t {
+		case fmi3.ModelExchangeType:
+			m.interfaceTypes = append(m.interfaceTypes, ModelExchange)
+		case fmi3.CoSimulationType:
+			m.interfaceTypes = append(m.interfaceTypes, CoSimulation)
+		}
+	}
+
+	if e := md.DefaultExperiment; e != nil {
+		m.defaultExperiment = &DefaultExperiment{
+			StartTime: e.StartTime,
+			StopTime:  e.StopTime,
+			Tolerance: e.Tolerance,
+			StepSize:  e.StepSize,
+		}
+	}
+
+	if md.TypeDefinitions != nil {
+		for _, t := range md.TypeDefinitions.Types {
+			if t.VariableType() != fmi3.EnumerationVariable {
+				continue
+			}
+			items := make(map[string]int, len(t.Item))
+			for _, item := range t.Item {
+				items[item.Name] = int(item.Value)
+			}
+			m.items[t.Name] = items
+		}
+	}
+
+	for _, mv := range md.Variables() {
+
+		v := Variable{
+			Name:           mv.Name,
+			ValueReference: uint32(mv.ValueReference),
+			Description:    mv.Description,
+			Type:           fmi3VariableType(mv.Type()),
+			Causality:      mv.Causality,
+			Variability:    mv.Variability,
+			DeclaredType:   mv.DeclaredType,
+			Unit:           mv.Unit,
+		}
+
+		if v.Causality == "" {
+			v.Causality = "local"
+		}
+
+		if v.Variability == "" {
+			// only Float variables can be continuous
+			if v.Type == Real {
+				v.Variability = "continuous"
+			} else {
+				v.Variability = "discrete"
+			}
+		}
+
+		v.Initial = initialOf(mv.Initial, v.Causality, v.Variability)
+
+		if start := mv.StartValueStrings(); len(start) == 1 && !mv.IsArray() {
+			value, err := parseFmi3Value(mv.Type(), start[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid start value of %s: %w", mv.Name, err)
+			}
+			v.Start = value
+		}
+
+		m.variables = append(m.variables, v)
+	}
+
+	m.index()
+
+	return m, nil
+}
+
+// parseFmi3Value parses the string representation of a value in an FMI 3.0 model description
+func parseFmi3Value(t fmi3.VariableType, s string) (any, error) {
+	switch fmi3VariableType(t) {
+	case Real:
+		return strconv.ParseFloat(s, 64)
+	case Integer, Enumeration:
+		if t == fmi3.UInt64Variable {
+			value, err := strconv.ParseUint(s, 10, 64)
+			return int(value), err
+		}
+		value, err := strconv.ParseInt(s, 10, 64)
+		return int(value), err
+	case Boolean:
+		return strconv.ParseBool(s)
+	case Binary:
+		return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	case Clock:
+		return nil, nil
+	default:
+		return s, nil
+	}
+}
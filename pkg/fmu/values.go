@@ -0,0 +1,170 @@
+package fmu
+
+import (
+	"fmt"
+	"go-fmu/pkg/internal/fmi"
+	"math"
+	"strings"
+)
+
+// variableAccess gets and sets the values of variables with the Go types of the variables
+// (see Variable). It is implemented by the instances of the FMI versions that have no
+// access by name, so that namedValues can implement Get, GetValues and Set for them.
+type variableAccess interface {
+	getVariables(variables []*Variable) ([]any, error)
+	setVariables(variables []*Variable, values []any) error
+}
+
+// namedValues implements the access by name of an Instance on top of a variableAccess
+type namedValues struct {
+	model  *model
+	access variableAccess
+}
+
+func (n *namedValues) Get(name string) (any, error) {
+	values, err := n.GetValues(name)
+	if err != nil {
+		return nil, err
+	}
+	return values[name], nil
+}
+
+func (n *namedValues) GetValues(names ...string) (map[string]any, error) {
+
+	variables, err := n.model.accessibleVariables(names)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := n.access.getVariables(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any, len(names))
+	for i, name := range names {
+		values[name] = result[i]
+	}
+
+	return values, nil
+}
+
+func (n *namedValues) Set(values map[string]any) error {
+
+	names := fmi.SortedNames(values)
+
+	variables, err := n.model.accessibleVariables(names)
+	if err != nil {
+		return err
+	}
+
+	converted := make([]any, len(names))
+	for i, v := range variables {
+		if converted[i], err = n.model.convertValue(v, values[v.Name]); err != nil {
+			return err
+		}
+	}
+
+	return n.access.setVariables(variables, converted)
+}
+
+// accessibleVariables returns the variables with the given names, which must be scalar variables
+// that are not clocks
+func (m *model) accessibleVariables(names []string) ([]*Variable, error) {
+
+	variables := make([]*Variable, len(names))
+
+	for i, name := range names {
+		v, err := m.variable(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if v.Type == Clock {
+			return nil, fmt.Errorf("the value of the clock %s cannot be accessed by name", name)
+		}
+
+		variables[i] = v
+	}
+
+	return variables, nil
+}
+
+// convertValue converts value to the Go type of the variable. Enumeration variables can also be set to
+// the name of an item. The values of Integer variables of FMI 1.0 and 2.0 FMUs are limited to 32 bits.
+func (m *model) convertValue(v *Variable, value any) (any, error) {
+
+	switch v.Type {
+	case Real:
+		f, ok := fmi.ToFloat64(value)
+		if !ok {
+			return nil, fmt.Errorf("the value of %s must be a number but is %T", v.Name, value)
+		}
+		return f, nil
+	case Integer, Enumeration:
+		if name, ok := value.(string); ok && v.Type == Enumeration {
+			item, ok := m.items[v.DeclaredType][name]
+			if !ok {
+				return nil, fmt.Errorf("the value of %s must be an item of %s but is %q", v.Name, v.DeclaredType, name)
+			}
+			return item, nil
+		}
+
+		f, ok := fmi.ToFloat64(value)
+		if !ok || f != math.Trunc(f) || (!strings.HasPrefix(m.fmiVersion, "3.") && (f < math.MinInt32 || f > math.MaxInt32)) {
+			return nil, fmt.Errorf("the value of %s must be an integer but is %v (%T)", v.Name, value, value)
+		}
+
+		// keep the precision of 64 bit integers
+		if i, ok := value.(int64); ok {
+			return int(i), nil
+		}
+		return int(f), nil
+	case Boolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("the value of %s must be a bool but is %T", v.Name, value)
+		}
+		return b, nil
+	case Binary:
+		b, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("the value of %s must be a []byte but is %T", v.Name, value)
+		}
+		return b, nil
+	default:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("the value of %s must be a string but is %T", v.Name, value)
+		}
+		return s, nil
+	}
+}
+
+// splitStartValues splits the start values of an FMI 2.0 or 3.0 instance into the values that are
+// set before the instance enters Initialization Mode, which are the values of the variables with
+// initial = "exact" or "approx", and the values of the inputs, which are set in Initialization Mode
+func (m *model) splitStartValues(startValues map[string]any) (map[string]any, map[string]any, error) {
+
+	instantiated := make(map[string]any)
+	initialization := make(map[string]any)
+
+	for _, name := range fmi.SortedNames(startValues) {
+
+		v, err := m.variable(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case v.Variability != "constant" && (v.Initial == "exact" || v.Initial == "approx"):
+			instantiated[name] = startValues[name]
+		case v.Causality == "input":
+			initialization[name] = startValues[name]
+		default:
+			return nil, nil, fmt.Errorf("the start value of %s cannot be set (causality = %q, variability = %q, initial = %q)", name, v.Causality, v.Variability, v.Initial)
+		}
+	}
+
+	return instantiated, initialization, nil
+}
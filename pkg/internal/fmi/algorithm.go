@@ -0,0 +1,63 @@
+package fmi
+
+import "sort"
+
+// Transform returns the results of f for the elements of source
+func Transform[To, From any](source []From, f func(int, From) To) []To {
+	vsm := make([]To, 0, len(source))
+	for i, v := range source {
+		vsm = append(vsm, f(i, v))
+	}
+	return vsm
+}
+
+// First returns a pointer to the first element of s or nil if s is empty, so that empty slices
+// can be passed to C functions
+func First[T any](s []T) *T {
+	if len(s) == 0 {
+		return nil
+	}
+	return &s[0]
+}
+
+// ToFloat64 converts a numeric value to float64
+func ToFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// SortedNames returns the keys of the map in ascending order
+func SortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
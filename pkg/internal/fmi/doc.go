@@ -0,0 +1,5 @@
+// Package fmi contains the parts of the FMI 1.0, 2.0 and 3.0 bindings that do not depend on the version
+// of the standard: the extraction of the archive, the detection of the current machine, the errors, the
+// loggers and their attribution to the loaded libraries and the helpers for the slices that are passed to
+// the C functions.
+package fmi
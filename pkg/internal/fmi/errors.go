@@ -0,0 +1,63 @@
+package fmi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Status is the status type of an FMI version. OK, Warning, Discard, Error and Fatal have the same
+// values in FMI 1.0, 2.0 and 3.0.
+type Status interface {
+	~int
+	String() string
+}
+
+const (
+	statusWarning = 1
+	statusDiscard = 2
+	statusError   = 3
+	statusFatal   = 4
+)
+
+// ErrFunctionNotAvailable is matched by errors.Is for every FunctionNotAvailableError
+var ErrFunctionNotAvailable = errors.New("function not available")
+
+// FunctionNotAvailableError is returned when the FMU does not export the FMI function that is called
+type FunctionNotAvailableError struct {
+	Function string // the name of the FMI function, e.g. "fmi2DoStep"
+}
+
+func (e *FunctionNotAvailableError) Error() string {
+	return fmt.Sprintf("%s function not available", e.Function)
+}
+
+func (e *FunctionNotAvailableError) Is(target error) bool {
+	return target == ErrFunctionNotAvailable
+}
+
+// ErrFatal is matched by errors.Is for the errors of all calls to an instance after it returned Fatal
+var ErrFatal = errors.New("the FMU instance returned Fatal and cannot be used anymore")
+
+// StatusError is returned when an FMI function returns a status other than OK or Warning
+type StatusError[S Status] struct {
+	Function string // the name of the FMI function, e.g. "fmi2DoStep"
+	Status   S
+}
+
+func (e *StatusError[S]) Error() string {
+	return fmt.Sprintf("%s returned %v", e.Function, e.Status)
+}
+
+// Is reports Fatal errors as ErrFatal
+func (e *StatusError[S]) Is(target error) bool {
+	return target == ErrFatal && int(e.Status) == statusFatal
+}
+
+// HasStatus returns whether err is a StatusError with the status
+func HasStatus[S Status](err error, status S) bool {
+	var statusError *StatusError[S]
+	return errors.As(err, &statusError) && statusError.Status == status
+}
+
+// WarningHandler is called when an FMI function returns Warning
+type WarningHandler[I any] func(instance I, function string)
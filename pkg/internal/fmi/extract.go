@@ -0,0 +1,180 @@
+package fmi
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ExtractOptions control where and which files of an FMU are extracted
+type ExtractOptions struct {
+	CacheDirectory string                 // the directory of the extraction cache or "" for a new temporary directory
+	Hash           func() (string, error) // returns the hash of the FMU, which is the key in the cache
+	Suffix         string                 // appended to the key, e.g. for partially extracted FMUs
+	Filter         func(name string) bool // selects the files to extract or nil to extract all files
+}
+
+// Extract extracts the FMU into a new temporary directory or into the cache.
+// It returns whether the directory is owned by the caller and has to be removed.
+func Extract(r *zip.Reader, options ExtractOptions) (string, bool, error) {
+
+	if options.CacheDirectory == "" {
+		dir, err := os.MkdirTemp("", "go-fmu-*")
+		if err != nil {
+			return "", false, err
+		}
+
+		if err := Unzip(r, dir, options.Filter); err != nil {
+			os.RemoveAll(dir)
+			return "", false, err
+		}
+
+		return dir, true, nil
+	}
+
+	dir, err := extractCached(r, options)
+	return dir, false, err
+}
+
+// ExtractFile extracts the FMU into a new temporary directory
+func ExtractFile(filename string) (string, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Close()
+
+	dir, _, err := Extract(&r.Reader, ExtractOptions{})
+	return dir, err
+}
+
+// extractCached extracts the FMU into the cache unless another process or instance did so before.
+// The FMU is extracted into a temporary directory that is renamed when it is complete, so the
+// directory in the cache is either complete or does not exist.
+func extractCached(r *zip.Reader, options ExtractOptions) (string, error) {
+
+	key, err := options.Hash()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(options.CacheDirectory, 0755); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(options.CacheDirectory, key+options.Suffix)
+
+	unlock, err := lockFile(dir + ".lock")
+	if err != nil {
+		return "", err
+	}
+
+	defer unlock()
+
+	if _, err := os.Stat(dir); err == nil {
+		return dir, nil
+	}
+
+	tmp, err := os.MkdirTemp(options.CacheDirectory, key+options.Suffix+".tmp-*")
+	if err != nil {
+		return "", err
+	}
+
+	if err := Unzip(r, tmp, options.Filter); err == nil {
+		if err = os.Chmod(tmp, 0755); err == nil {
+			err = os.Rename(tmp, dir)
+		}
+	}
+
+	if err != nil {
+		os.RemoveAll(tmp)
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// Unzip extracts the files for which filter returns true or all files if filter is nil
+func Unzip(r *zip.Reader, dest string, filter func(name string) bool) error {
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	extractAndWriteFile := func(f *zip.File) error {
+		path := filepath.Join(dest, f.Name)
+
+		// Check for ZipSlip (Directory traversal)
+		if !strings.HasPrefix(path, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path: %s", path)
+		}
+
+		if f.FileInfo().IsDir() {
+			return os.MkdirAll(path, 0755)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode()|0600)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(out, rc); err != nil {
+			out.Close()
+			return err
+		}
+
+		return out.Close()
+	}
+
+	for _, f := range r.File {
+		if filter != nil && !filter(f.Name) {
+			continue
+		}
+
+		if err := extractAndWriteFile(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FileHash returns the hex encoded SHA-256 hash of the file
+func FileHash(filename string) (string, error) {
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	return ReaderHash(f)
+}
+
+// ReaderHash returns the hex encoded SHA-256 hash of the data read from r
+func ReaderHash(r io.Reader) (string, error) {
+
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
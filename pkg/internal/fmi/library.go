@@ -0,0 +1,105 @@
+package fmi
+
+/*
+#cgo CFLAGS: -D_GNU_SOURCE
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdint.h>
+
+// libraryBase returns the base address of the shared library that contains the address or 0
+static uintptr_t libraryBase(uintptr_t address) {
+	Dl_info info;
+	return dladdr((void *)address, &info) ? (uintptr_t)info.dli_fbase : 0;
+}
+*/
+import "C"
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Library tracks the instance that a loaded shared library is creating. Some FMUs call the logger without
+// componentEnvironment before Instantiate returns. Such a message is routed to the instance that is being
+// created only if it was logged by the same library for the same instance name. All other messages without
+// componentEnvironment cannot be attributed to an instance.
+type Library struct {
+	base          uintptr
+	mutex         sync.Mutex // serializes the instantiation
+	instantiating atomic.Pointer[instantiation]
+	references    int // the number of FMUs that loaded the library, guarded by librariesMutex
+}
+
+// instantiation is the instance that a library is creating
+type instantiation struct {
+	instanceName string
+	environment  any
+}
+
+var (
+	librariesMutex sync.Mutex
+	libraries      = make(map[uintptr]*Library) // the loaded libraries by base address
+)
+
+// OpenLibrary returns the Library that contains the address, e.g. of an FMI function. FMUs that load the
+// same file share the library, because the dynamic linker loads a file only once.
+func OpenLibrary(address uintptr) *Library {
+
+	base := uintptr(C.libraryBase(C.uintptr_t(address)))
+
+	librariesMutex.Lock()
+	defer librariesMutex.Unlock()
+
+	l, ok := libraries[base]
+	if !ok {
+		l = &Library{base: base}
+		libraries[base] = l
+	}
+
+	l.references++
+
+	return l
+}
+
+// Close releases the library when the FMU is closed
+func (l *Library) Close() {
+	librariesMutex.Lock()
+	defer librariesMutex.Unlock()
+
+	if l.references--; l.references == 0 {
+		delete(libraries, l.base)
+	}
+}
+
+// Instantiate calls instantiate and routes the messages that the library logs without componentEnvironment
+// for instanceName to environment until instantiate returns
+func (l *Library) Instantiate(instanceName string, environment any, instantiate func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.instantiating.Store(&instantiation{instanceName: instanceName, environment: environment})
+	defer l.instantiating.Store(nil)
+
+	instantiate()
+}
+
+// InstantiatingEnvironment returns the environment of the instance with instanceName that the library
+// which contains the address of the caller is creating or nil if the message cannot be attributed
+func InstantiatingEnvironment(caller uintptr, instanceName string) any {
+
+	base := uintptr(C.libraryBase(C.uintptr_t(caller)))
+
+	librariesMutex.Lock()
+	l := libraries[base]
+	librariesMutex.Unlock()
+
+	if l == nil {
+		return nil
+	}
+
+	if i := l.instantiating.Load(); i != nil && i.instanceName == instanceName {
+		return i.environment
+	}
+
+	return nil
+}
@@ -0,0 +1,9 @@
+package fmi
+
+// some FMUs use libm without declaring it as a dependency, so make sure it is loaded into every
+// process that loads FMUs
+
+/*
+#cgo linux LDFLAGS: -Wl,--no-as-needed -lm
+*/
+import "C"
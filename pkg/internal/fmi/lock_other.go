@@ -0,0 +1,10 @@
+//go:build !unix
+
+package fmi
+
+import "errors"
+
+// lockFile is not implemented on this platform, so the extraction cache cannot be used
+func lockFile(filename string) (func(), error) {
+	return nil, errors.New("the extraction cache requires file locks, which are not supported on this platform")
+}
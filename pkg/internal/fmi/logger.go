@@ -0,0 +1,71 @@
+package fmi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Logger receives the log messages of an FMU instance
+type Logger[S Status] func(instanceName string, status S, category string, message string)
+
+// DefaultLogger prints the messages to stdout
+func DefaultLogger[S Status](instanceName string, status S, category string, message string) {
+	fmt.Printf("[Name: %s, Status: %v, Category: %s] %s\n", instanceName, status, category, message)
+}
+
+// NewSlogLogger returns a Logger that passes the messages to handler. The level of the records
+// is derived from the status, the instance name, status and category are added as attributes.
+func NewSlogLogger[S Status](handler slog.Handler) Logger[S] {
+	return func(instanceName string, status S, category string, message string) {
+
+		level := slog.LevelInfo
+		switch int(status) {
+		case statusWarning, statusDiscard:
+			level = slog.LevelWarn
+		case statusError, statusFatal:
+			level = slog.LevelError
+		}
+
+		ctx := context.Background()
+		if !handler.Enabled(ctx, level) {
+			return
+		}
+
+		logger := slog.New(handler)
+		logger.LogAttrs(ctx, level, message,
+			slog.String("instance", instanceName),
+			slog.String("status", status.String()),
+			slog.String("category", category))
+	}
+}
+
+// LogOptions are the logger and the log categories that are passed to Instantiate
+type LogOptions[S Status] struct {
+	Logger     Logger[S] // the logger or nil to print the messages to stdout
+	Categories []string  // the categories to log or nil to log all
+}
+
+// NewLogger returns the logger of an instance, which passes only the messages of the categories
+func (o LogOptions[S]) NewLogger() Logger[S] {
+
+	logger := o.Logger
+	if logger == nil {
+		logger = DefaultLogger[S]
+	}
+
+	if o.Categories == nil {
+		return logger
+	}
+
+	categories := make(map[string]bool)
+	for _, category := range o.Categories {
+		categories[category] = true
+	}
+
+	return func(instanceName string, status S, category string, message string) {
+		if categories[category] {
+			logger(instanceName, status, category, message)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package fmi
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Machine describes the current machine for the selection of the binaries of an FMU
+type Machine struct {
+	Architecture    string // aarch64, x86, x86_64 or "" if the architecture is not known
+	OperatingSystem string // linux, darwin, windows, ...
+	Bits            int    // 32, 64 or 0 if the architecture is not known
+	LibrarySuffix   string // so, dylib or dll
+}
+
+func CurrentMachine() Machine {
+
+	system := strings.ToLower(runtime.GOOS)
+	machine := strings.ToLower(runtime.GOARCH)
+
+	intSize := 32 << (^uint(0) >> 63) // 32 or 64
+
+	m := Machine{OperatingSystem: system}
+
+	switch system {
+	case "windows":
+		m.LibrarySuffix = "dll"
+	case "darwin":
+		m.LibrarySuffix = "dylib"
+	default:
+		m.LibrarySuffix = "so"
+	}
+
+	switch machine {
+	case "aarch64", "arm64":
+		m.Architecture = "aarch64"
+		m.Bits = 64
+	case "amd64", "i386", "i686", "x86", "x86_64", "x86pc":
+		switch intSize {
+		case 32:
+			m.Architecture = "x86"
+		case 64:
+			m.Architecture = "x86_64"
+		}
+		m.Bits = intSize
+	}
+
+	return m
+}
+
+// Platform returns the platform of FMI 1.0 and 2.0, e.g. linux64
+func (m Machine) Platform() string {
+	if m.Bits == 0 {
+		return m.OperatingSystem
+	}
+	return m.OperatingSystem + strconv.Itoa(m.Bits)
+}
+
+// PlatformTuple returns the platform tuple of FMI 3.0, e.g. x86_64-linux
+func (m Machine) PlatformTuple() string {
+	return m.Architecture + "-" + m.OperatingSystem
+}